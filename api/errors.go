@@ -1,6 +1,7 @@
 package api
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/go-chi/render"
@@ -42,3 +43,9 @@ func Error(err error) *ErrorResponse {
 }
 
 var ErrNotFound = &ErrorResponse{Code: 404, Status: "Resource not found."}
+
+var ErrUnauthorized = &ErrorResponse{
+	Err:    errors.New("unauthorized"),
+	Code:   401,
+	Status: http.StatusText(401),
+}
@@ -0,0 +1,312 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	spectypes "github.com/ssvlabs/ssv-spec/types"
+
+	"github.com/ssvlabs/ssv/api"
+	"github.com/ssvlabs/ssv/eth/eventsyncer"
+	"github.com/ssvlabs/ssv/exporter/backfill"
+	"github.com/ssvlabs/ssv/logging"
+	"github.com/ssvlabs/ssv/network/peers"
+	"github.com/ssvlabs/ssv/protocol/v2/message"
+	"github.com/ssvlabs/ssv/registry/audit"
+	registrystorage "github.com/ssvlabs/ssv/registry/storage"
+)
+
+type MetadataRefresher interface {
+	RefreshValidatorsMetadata(pubKeys [][]byte) (int, error)
+}
+
+type EventResyncer interface {
+	Resync(ctx context.Context, fromBlock uint64) error
+}
+
+type Drainer interface {
+	SetDraining(draining bool)
+	Draining() bool
+}
+
+type Backfiller interface {
+	BackfillValidator(ctx context.Context, pubKey []byte, role spectypes.BeaconRole, from, to phase0.Slot) (backfill.Result, error)
+}
+
+type PeerBanner interface {
+	Bans() []peers.Ban
+	Ban(target, reason string) (peers.Ban, error)
+	Unban(target string) (bool, error)
+}
+
+type RegistryAuditor interface {
+	Start(ctx context.Context, fromBlock uint64) error
+	Status() audit.Status
+}
+
+// Admin handles the operational endpoints, which must only be served to authenticated clients.
+type Admin struct {
+	Shares            registrystorage.Shares
+	MetadataRefresher MetadataRefresher
+	EventResyncer     EventResyncer
+	Drainer           Drainer
+	Backfiller        Backfiller
+	PeerBanner        PeerBanner
+	RegistryAuditor   RegistryAuditor
+}
+
+// RefreshMetadata fetches the beacon metadata of the requested validators right away,
+// or of all non-liquidated validators if none are requested.
+func (h *Admin) RefreshMetadata(w http.ResponseWriter, r *http.Request) error {
+	var request struct {
+		PubKeys api.HexSlice `json:"pubkeys" form:"pubkeys"`
+	}
+	var response struct {
+		Refreshed int `json:"refreshed"`
+	}
+
+	if err := api.Bind(r, &request); err != nil {
+		return api.InvalidRequestError(err)
+	}
+	pubKeys := make([][]byte, 0, len(request.PubKeys))
+	for _, pk := range request.PubKeys {
+		if len(pk) != phase0.PublicKeyLength {
+			return api.InvalidRequestError(fmt.Errorf("invalid validator public key length %d", len(pk)))
+		}
+		if _, found := h.Shares.Get(nil, pk); !found {
+			return api.InvalidRequestError(fmt.Errorf("validator %x not found", []byte(pk)))
+		}
+		pubKeys = append(pubKeys, pk)
+	}
+
+	refreshed, err := h.MetadataRefresher.RefreshValidatorsMetadata(pubKeys)
+	if err != nil {
+		return err
+	}
+	response.Refreshed = refreshed
+	return api.Render(w, r, response)
+}
+
+// Resync makes the registry event sync restart from the requested block.
+func (h *Admin) Resync(w http.ResponseWriter, r *http.Request) error {
+	var request struct {
+		FromBlock *uint64 `json:"from_block" form:"from_block"`
+	}
+	var response struct {
+		FromBlock uint64 `json:"from_block"`
+	}
+
+	if err := api.Bind(r, &request); err != nil {
+		return api.InvalidRequestError(err)
+	}
+	if request.FromBlock == nil {
+		return api.InvalidRequestError(errors.New("from_block is required"))
+	}
+
+	err := h.EventResyncer.Resync(r.Context(), *request.FromBlock)
+	if errors.Is(err, eventsyncer.ErrNotSyncingOngoing) || errors.Is(err, eventsyncer.ErrResyncAhead) {
+		return api.InvalidRequestError(err)
+	}
+	if err != nil {
+		return err
+	}
+	response.FromBlock = *request.FromBlock
+	return api.Render(w, r, response)
+}
+
+type logLevelJSON struct {
+	Level string `json:"level" form:"level"`
+}
+
+// LogLevel responds with the current log level.
+func (h *Admin) LogLevel(w http.ResponseWriter, r *http.Request) error {
+	return api.Render(w, r, logLevelJSON{Level: logging.Level().String()})
+}
+
+// SetLogLevel changes the log level until the node restarts.
+func (h *Admin) SetLogLevel(w http.ResponseWriter, r *http.Request) error {
+	var request logLevelJSON
+	if err := api.Bind(r, &request); err != nil {
+		return api.InvalidRequestError(err)
+	}
+	if err := logging.SetLevel(request.Level); err != nil {
+		return api.InvalidRequestError(err)
+	}
+	return api.Render(w, r, logLevelJSON{Level: logging.Level().String()})
+}
+
+type drainJSON struct {
+	Draining bool `json:"draining"`
+}
+
+// DrainState responds with whether the node is draining.
+func (h *Admin) DrainState(w http.ResponseWriter, r *http.Request) error {
+	return api.Render(w, r, drainJSON{Draining: h.Drainer.Draining()})
+}
+
+// Drain makes the node stop executing new duties, e.g. before maintenance.
+// Duties which are already running are completed.
+func (h *Admin) Drain(w http.ResponseWriter, r *http.Request) error {
+	h.Drainer.SetDraining(true)
+	return api.Render(w, r, drainJSON{Draining: h.Drainer.Draining()})
+}
+
+// Undrain makes the node resume executing duties.
+func (h *Admin) Undrain(w http.ResponseWriter, r *http.Request) error {
+	h.Drainer.SetDraining(false)
+	return api.Render(w, r, drainJSON{Draining: h.Drainer.Draining()})
+}
+
+// Backfill fills the gaps in the decided history of a validator's duties of the requested role and slot range
+// with the decided messages of peers.
+func (h *Admin) Backfill(w http.ResponseWriter, r *http.Request) error {
+	var request struct {
+		PubKey api.Hex `json:"pubkey" form:"pubkey"`
+		Role   string  `json:"role" form:"role"`
+		From   *uint64 `json:"from" form:"from"`
+		To     *uint64 `json:"to" form:"to"`
+	}
+	var response struct {
+		Instances    int `json:"instances"`
+		Participants int `json:"participants"`
+	}
+
+	if err := api.Bind(r, &request); err != nil {
+		return api.InvalidRequestError(err)
+	}
+	if len(request.PubKey) != phase0.PublicKeyLength {
+		return api.InvalidRequestError(fmt.Errorf("invalid validator public key length %d", len(request.PubKey)))
+	}
+	if _, found := h.Shares.Get(nil, request.PubKey); !found {
+		return api.InvalidRequestError(fmt.Errorf("validator %x not found", []byte(request.PubKey)))
+	}
+	role, err := message.BeaconRoleFromString(request.Role)
+	if err != nil {
+		return api.InvalidRequestError(err)
+	}
+	if request.From == nil || request.To == nil {
+		return api.InvalidRequestError(errors.New("from and to are required"))
+	}
+	from, to := phase0.Slot(*request.From), phase0.Slot(*request.To)
+	if from > to {
+		return api.InvalidRequestError(errors.New("from must not be after to"))
+	}
+	if to-from >= backfill.MaxRange {
+		return api.InvalidRequestError(fmt.Errorf("range must not exceed %d slots", backfill.MaxRange))
+	}
+
+	result, err := h.Backfiller.BackfillValidator(r.Context(), request.PubKey, role, from, to)
+	if err != nil {
+		return err
+	}
+	response.Instances = result.Instances
+	response.Participants = result.Participants
+	return api.Render(w, r, response)
+}
+
+// StartRegistryAudit starts auditing the registry state against a replay of the registry events
+// from the requested block, or from the sync offset if none is requested.
+// The audit runs in the background and its report is served by RegistryAudit.
+func (h *Admin) StartRegistryAudit(w http.ResponseWriter, r *http.Request) error {
+	var request struct {
+		FromBlock uint64 `json:"from_block" form:"from_block"`
+	}
+	if err := api.Bind(r, &request); err != nil {
+		return api.InvalidRequestError(err)
+	}
+
+	// The audit outlives the request.
+	err := h.RegistryAuditor.Start(context.WithoutCancel(r.Context()), request.FromBlock)
+	if errors.Is(err, audit.ErrInProgress) || errors.Is(err, audit.ErrFromBlockAhead) || errors.Is(err, audit.ErrNotSynced) {
+		return api.InvalidRequestError(err)
+	}
+	if err != nil {
+		return err
+	}
+	return api.Render(w, r, h.RegistryAuditor.Status())
+}
+
+// RegistryAudit responds with the status of the last registry audit and its report once it's done.
+func (h *Admin) RegistryAudit(w http.ResponseWriter, r *http.Request) error {
+	return api.Render(w, r, h.RegistryAuditor.Status())
+}
+
+type peerBanJSON struct {
+	Target  string    `json:"target"`
+	Reason  string    `json:"reason,omitempty"`
+	Created time.Time `json:"created"`
+}
+
+func newPeerBanJSON(ban peers.Ban) peerBanJSON {
+	return peerBanJSON{Target: ban.Target, Reason: ban.Reason, Created: ban.Created}
+}
+
+// PeerBans responds with the banned peer IDs, IP addresses and CIDR ranges.
+func (h *Admin) PeerBans(w http.ResponseWriter, r *http.Request) error {
+	var response struct {
+		Data []peerBanJSON `json:"data"`
+	}
+	response.Data = make([]peerBanJSON, 0)
+	for _, ban := range h.PeerBanner.Bans() {
+		response.Data = append(response.Data, newPeerBanJSON(ban))
+	}
+	return api.Render(w, r, response)
+}
+
+// BanPeer bans a peer ID, IP address or CIDR range until it's unbanned,
+// and disconnects from the peers it matches.
+func (h *Admin) BanPeer(w http.ResponseWriter, r *http.Request) error {
+	var request struct {
+		Target string `json:"target" form:"target"`
+		Reason string `json:"reason" form:"reason"`
+	}
+	if err := api.Bind(r, &request); err != nil {
+		return api.InvalidRequestError(err)
+	}
+	if request.Target == "" {
+		return api.InvalidRequestError(errors.New("target is required"))
+	}
+	if _, err := peers.ParseBan(request.Target, request.Reason); err != nil {
+		return api.InvalidRequestError(err)
+	}
+
+	ban, err := h.PeerBanner.Ban(request.Target, request.Reason)
+	if err != nil {
+		return err
+	}
+	return api.Render(w, r, newPeerBanJSON(ban))
+}
+
+// UnbanPeer removes the ban of a peer ID, IP address or CIDR range.
+func (h *Admin) UnbanPeer(w http.ResponseWriter, r *http.Request) error {
+	var request struct {
+		Target string `json:"target" form:"target"`
+	}
+	var response struct {
+		Target string `json:"target"`
+	}
+	if err := api.Bind(r, &request); err != nil {
+		return api.InvalidRequestError(err)
+	}
+	if request.Target == "" {
+		return api.InvalidRequestError(errors.New("target is required"))
+	}
+	ban, err := peers.ParseBan(request.Target, "")
+	if err != nil {
+		return api.InvalidRequestError(err)
+	}
+
+	unbanned, err := h.PeerBanner.Unban(request.Target)
+	if err != nil {
+		return err
+	}
+	if !unbanned {
+		return api.ErrNotFound
+	}
+	response.Target = ban.Target
+	return api.Render(w, r, response)
+}
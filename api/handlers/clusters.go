@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/go-chi/chi/v5"
+	specqbft "github.com/ssvlabs/ssv-spec/qbft"
+	spectypes "github.com/ssvlabs/ssv-spec/types"
+
+	"github.com/ssvlabs/ssv/api"
+	"github.com/ssvlabs/ssv/operator/clusterhealth"
+	"github.com/ssvlabs/ssv/protocol/v2/ssv/queue"
+	"github.com/ssvlabs/ssv/protocol/v2/ssv/validator"
+	ssvtypes "github.com/ssvlabs/ssv/protocol/v2/types"
+	registrystorage "github.com/ssvlabs/ssv/registry/storage"
+)
+
+// QueueDumper returns the messages in the queues of running committees.
+type QueueDumper interface {
+	DumpCommitteeQueues(committeeID spectypes.CommitteeID) ([]validator.QueueDump, bool)
+}
+
+type Clusters struct {
+	Validators  registrystorage.BaseValidatorStore
+	Checker     *clusterhealth.Checker
+	QueueDumper QueueDumper
+}
+
+type operatorHealthJSON struct {
+	ID             spectypes.OperatorID `json:"id"`
+	Status         clusterhealth.Status `json:"status"`
+	Signed         int                  `json:"signed"`
+	Late           int                  `json:"late"`
+	Missing        int                  `json:"missing"`
+	LastSignedSlot phase0.Slot          `json:"last_signed_slot"`
+}
+
+type clusterHealthJSON struct {
+	CommitteeID     api.Hex                `json:"committee_id"`
+	Operators       []spectypes.OperatorID `json:"operators"`
+	FromSlot        phase0.Slot            `json:"from_slot"`
+	ToSlot          phase0.Slot            `json:"to_slot"`
+	Duties          int                    `json:"duties"`
+	Quorum          uint64                 `json:"quorum"`
+	Responsive      uint64                 `json:"responsive"`
+	QuorumReachable bool                   `json:"quorum_reachable"`
+	Health          []*operatorHealthJSON  `json:"health"`
+}
+
+// Health responds with the participation of each operator of a committee in its duties
+// over the recent epochs given by the epochs parameter.
+// The committee is identified by its hex-encoded ID or by its comma-separated operator IDs.
+func (h *Clusters) Health(w http.ResponseWriter, r *http.Request) error {
+	var request struct {
+		Epochs int `json:"epochs" form:"epochs"`
+	}
+	if err := api.Bind(r, &request); err != nil {
+		return api.InvalidRequestError(err)
+	}
+	if request.Epochs < 0 || request.Epochs > clusterhealth.MaxEpochs {
+		return api.InvalidRequestError(fmt.Errorf("epochs must be between 0 and %d", clusterhealth.MaxEpochs))
+	}
+
+	committeeID, err := parseCommitteeID(chi.URLParam(r, "id"))
+	if err != nil {
+		return api.InvalidRequestError(err)
+	}
+	committee, found := h.Validators.Committee(committeeID)
+	if !found {
+		return api.ErrNotFound
+	}
+
+	health, err := h.Checker.CommitteeHealth(committee, uint64(request.Epochs))
+	if errors.Is(err, clusterhealth.ErrWindowTooLarge) {
+		return api.InvalidRequestError(err)
+	}
+	if err != nil {
+		return err
+	}
+	response := clusterHealthJSON{
+		CommitteeID:     health.CommitteeID[:],
+		Operators:       committee.Operators,
+		FromSlot:        health.FromSlot,
+		ToSlot:          health.ToSlot,
+		Duties:          health.Duties,
+		Quorum:          health.Quorum,
+		Responsive:      health.Responsive(),
+		QuorumReachable: health.Responsive() >= health.Quorum,
+		Health:          make([]*operatorHealthJSON, len(health.Operators)),
+	}
+	for i, operator := range health.Operators {
+		response.Health[i] = &operatorHealthJSON{
+			ID:             operator.OperatorID,
+			Status:         operator.Status,
+			Signed:         operator.Signed,
+			Late:           operator.Late,
+			Missing:        operator.Missing,
+			LastSignedSlot: operator.LastSignedSlot,
+		}
+	}
+	return api.Render(w, r, response)
+}
+
+type queueMessageJSON struct {
+	MsgID   api.Hex                `json:"msg_id"`
+	Kind    queue.MessageKind      `json:"kind"`
+	Slot    phase0.Slot            `json:"slot,omitempty"`
+	Round   specqbft.Round         `json:"round,omitempty"`
+	Signers []spectypes.OperatorID `json:"signers,omitempty"`
+}
+
+type queueJSON struct {
+	Role      string              `json:"role"`
+	Slot      phase0.Slot         `json:"slot,omitempty"`
+	Validator api.Hex             `json:"validator,omitempty"`
+	Messages  []*queueMessageJSON `json:"messages"`
+}
+
+type clusterQueuesJSON struct {
+	CommitteeID api.Hex      `json:"committee_id"`
+	Queues      []*queueJSON `json:"queues"`
+}
+
+// Queues responds with the messages waiting in the queues of a committee and of its validators, for debugging.
+// The committee is identified by its hex-encoded ID or by its comma-separated operator IDs,
+// and must be running on this node.
+func (h *Clusters) Queues(w http.ResponseWriter, r *http.Request) error {
+	committeeID, err := parseCommitteeID(chi.URLParam(r, "id"))
+	if err != nil {
+		return api.InvalidRequestError(err)
+	}
+	dumps, found := h.QueueDumper.DumpCommitteeQueues(committeeID)
+	if !found {
+		return api.ErrNotFound
+	}
+
+	response := clusterQueuesJSON{
+		CommitteeID: committeeID[:],
+		Queues:      make([]*queueJSON, len(dumps)),
+	}
+	for i, dump := range dumps {
+		q := &queueJSON{
+			Role:     dump.Role.String(),
+			Slot:     dump.Slot,
+			Messages: make([]*queueMessageJSON, len(dump.Messages)),
+		}
+		if dump.ValidatorPubKey != (spectypes.ValidatorPK{}) {
+			q.Validator = dump.ValidatorPubKey[:]
+		}
+		for j, msg := range dump.Messages {
+			q.Messages[j] = newQueueMessageJSON(msg)
+		}
+		response.Queues[i] = q
+	}
+	return api.Render(w, r, response)
+}
+
+func newQueueMessageJSON(msg *queue.SSVMessage) *queueMessageJSON {
+	m := &queueMessageJSON{
+		MsgID: msg.MsgID[:],
+		Kind:  queue.Kind(msg),
+	}
+	if slot, err := msg.Slot(); err == nil {
+		m.Slot = slot
+	}
+	if qbftMsg, ok := msg.Body.(*specqbft.Message); ok {
+		m.Round = qbftMsg.Round
+	}
+	if msg.SignedSSVMessage != nil {
+		m.Signers = msg.SignedSSVMessage.OperatorIDs
+	}
+	return m
+}
+
+// parseCommitteeID parses either a hex-encoded committee ID or comma-separated operator IDs.
+func parseCommitteeID(id string) (spectypes.CommitteeID, error) {
+	if strings.Contains(id, ",") {
+		var operators []spectypes.OperatorID
+		for _, s := range strings.Split(id, ",") {
+			operator, err := strconv.ParseUint(s, 10, 64)
+			if err != nil {
+				return spectypes.CommitteeID{}, fmt.Errorf("invalid operator ID: %w", err)
+			}
+			operators = append(operators, operator)
+		}
+		return ssvtypes.ComputeCommitteeID(operators), nil
+	}
+
+	b, err := hex.DecodeString(strings.TrimPrefix(id, "0x"))
+	if err != nil {
+		return spectypes.CommitteeID{}, fmt.Errorf("invalid committee ID: %w", err)
+	}
+	if len(b) != len(spectypes.CommitteeID{}) {
+		return spectypes.CommitteeID{}, fmt.Errorf("invalid committee ID length %d", len(b))
+	}
+	return spectypes.CommitteeID(b), nil
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/go-chi/chi/v5"
+	specqbft "github.com/ssvlabs/ssv-spec/qbft"
+	spectypes "github.com/ssvlabs/ssv-spec/types"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ssvlabs/ssv/api"
+	"github.com/ssvlabs/ssv/exporter/convert"
+	ibftstorage "github.com/ssvlabs/ssv/ibft/storage"
+	"github.com/ssvlabs/ssv/logging"
+	"github.com/ssvlabs/ssv/networkconfig"
+	"github.com/ssvlabs/ssv/operator/clusterhealth"
+	"github.com/ssvlabs/ssv/protocol/v2/ssv/queue"
+	"github.com/ssvlabs/ssv/protocol/v2/ssv/validator"
+	ssvtypes "github.com/ssvlabs/ssv/protocol/v2/types"
+	registrystorage "github.com/ssvlabs/ssv/registry/storage"
+	"github.com/ssvlabs/ssv/registry/storage/mocks"
+	"github.com/ssvlabs/ssv/storage/basedb"
+	"github.com/ssvlabs/ssv/storage/kv"
+)
+
+func TestClusterHealth(t *testing.T) {
+	db, err := kv.NewInMemory(logging.TestLogger(t), basedb.Options{})
+	require.NoError(t, err)
+	defer db.Close()
+
+	network := networkconfig.TestNetwork
+	stores := ibftstorage.NewStoresFromRoles(db, convert.RoleAttester)
+	share := &ssvtypes.SSVShare{}
+	share.ValidatorPubKey = spectypes.ValidatorPK{1}
+	committee := &registrystorage.Committee{
+		ID:         ssvtypes.ComputeCommitteeID([]spectypes.OperatorID{1, 2, 3, 4}),
+		Operators:  []spectypes.OperatorID{1, 2, 3, 4},
+		Validators: []*ssvtypes.SSVShare{share},
+	}
+	msgID := convert.NewMsgID(network.DomainType(), share.ValidatorPubKey[:], convert.RoleAttester)
+	require.NoError(t, stores.Get(convert.RoleAttester).SaveParticipants(msgID, network.Beacon.EstimatedCurrentSlot()-2, []spectypes.OperatorID{1, 2, 3}))
+
+	validators := mocks.NewMockBaseValidatorStore(gomock.NewController(t))
+	validators.EXPECT().Committee(gomock.Any()).DoAndReturn(func(id spectypes.CommitteeID) (*registrystorage.Committee, bool) {
+		return committee, id == committee.ID
+	}).AnyTimes()
+
+	router := chi.NewRouter()
+	router.Get("/v1/clusters/{id}/health", api.Handler((&Clusters{
+		Validators: validators,
+		Checker:    clusterhealth.NewChecker(network.Beacon, network.DomainType(), stores),
+	}).Health))
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/clusters/"+path, nil))
+		return rec
+	}
+
+	for _, id := range []string{"4,3,2,1", fmt.Sprintf("0x%x", committee.ID[:])} {
+		rec := get(id + "/health?epochs=2")
+		require.Equal(t, http.StatusOK, rec.Code)
+		var response clusterHealthJSON
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		require.Equal(t, 1, response.Duties)
+		require.Equal(t, uint64(3), response.Responsive)
+		require.True(t, response.QuorumReachable)
+		require.Equal(t, clusterhealth.StatusHealthy, response.Health[0].Status)
+		require.Equal(t, clusterhealth.StatusMissing, response.Health[3].Status)
+	}
+
+	require.Equal(t, http.StatusNotFound, get("1,2,3,5/health").Code)
+	require.Equal(t, http.StatusBadRequest, get("0102/health").Code)
+	require.Equal(t, http.StatusBadRequest, get("1,2,3,4/health?epochs=5").Code)
+}
+
+type testQueueDumper map[spectypes.CommitteeID][]validator.QueueDump
+
+func (d testQueueDumper) DumpCommitteeQueues(committeeID spectypes.CommitteeID) ([]validator.QueueDump, bool) {
+	dumps, ok := d[committeeID]
+	return dumps, ok
+}
+
+func TestClusterQueues(t *testing.T) {
+	committeeID := ssvtypes.ComputeCommitteeID([]spectypes.OperatorID{1, 2, 3, 4})
+	msg := &queue.SSVMessage{
+		SignedSSVMessage: &spectypes.SignedSSVMessage{OperatorIDs: []spectypes.OperatorID{1, 2, 3}},
+		SSVMessage:       &spectypes.SSVMessage{MsgType: spectypes.SSVConsensusMsgType},
+		Body:             &specqbft.Message{MsgType: specqbft.CommitMsgType, Height: 100, Round: 2},
+	}
+	router := chi.NewRouter()
+	router.Get("/v1/clusters/{id}/queues", api.Handler((&Clusters{
+		QueueDumper: testQueueDumper{committeeID: {
+			{Role: spectypes.RoleCommittee, Slot: 100, Messages: []*queue.SSVMessage{msg}},
+			{Role: spectypes.RoleProposer, ValidatorPubKey: spectypes.ValidatorPK{1}},
+		}},
+	}).Queues))
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/clusters/"+path, nil))
+		return rec
+	}
+
+	rec := get("1,2,3,4/queues")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response clusterQueuesJSON
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	require.Len(t, response.Queues, 2)
+	require.Equal(t, "COMMITTEE_RUNNER", response.Queues[0].Role)
+	require.Equal(t, phase0.Slot(100), response.Queues[0].Slot)
+	require.Equal(t, &queueMessageJSON{
+		MsgID:   make(api.Hex, len(spectypes.MessageID{})),
+		Kind:    queue.KindDecided,
+		Slot:    100,
+		Round:   2,
+		Signers: []spectypes.OperatorID{1, 2, 3},
+	}, response.Queues[0].Messages[0])
+	require.Equal(t, "PROPOSER_RUNNER", response.Queues[1].Role)
+	require.NotEmpty(t, response.Queues[1].Validator)
+	require.Empty(t, response.Queues[1].Messages)
+
+	require.Equal(t, http.StatusNotFound, get("1,2,3,5/queues").Code)
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+
+	"github.com/ssvlabs/ssv/api"
+	"github.com/ssvlabs/ssv/operator/doppelganger"
+)
+
+type Doppelganger struct {
+	// Handler is nil when doppelganger protection is disabled.
+	Handler doppelganger.Handler
+}
+
+type doppelgangerStateJSON struct {
+	PubKey        api.Hex               `json:"public_key"`
+	Index         phase0.ValidatorIndex `json:"index"`
+	Status        doppelganger.Status   `json:"status"`
+	StartEpoch    phase0.Epoch          `json:"start_epoch"`
+	EpochsChecked uint64                `json:"epochs_checked"`
+	DetectedEpoch *phase0.Epoch         `json:"detected_epoch,omitempty"`
+	LiveAtStart   bool                  `json:"live_at_start"`
+}
+
+func (h *Doppelganger) States(w http.ResponseWriter, r *http.Request) error {
+	var response struct {
+		Enabled bool                     `json:"enabled"`
+		Data    []*doppelgangerStateJSON `json:"data"`
+	}
+	response.Data = []*doppelgangerStateJSON{}
+	if h.Handler == nil {
+		return api.Render(w, r, response)
+	}
+
+	response.Enabled = true
+	for _, state := range h.Handler.States() {
+		s := &doppelgangerStateJSON{
+			PubKey:        api.Hex(state.ValidatorPubKey[:]),
+			Index:         state.ValidatorIndex,
+			Status:        state.Status,
+			StartEpoch:    state.StartEpoch,
+			EpochsChecked: state.EpochsChecked,
+			LiveAtStart:   state.LiveAtStart,
+		}
+		if state.Status == doppelganger.StatusDetected {
+			detectedEpoch := state.DetectedEpoch
+			s.DetectedEpoch = &detectedEpoch
+		}
+		response.Data = append(response.Data, s)
+	}
+	return api.Render(w, r, response)
+}
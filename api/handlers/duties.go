@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/go-chi/chi/v5"
+	specqbft "github.com/ssvlabs/ssv-spec/qbft"
+	spectypes "github.com/ssvlabs/ssv-spec/types"
+
+	"github.com/ssvlabs/ssv/api"
+	"github.com/ssvlabs/ssv/operator/dutyhistory"
+)
+
+type DutyHistory struct {
+	// Store is nil when the duty history is disabled.
+	Store *dutyhistory.Store
+}
+
+type dutyRecordJSON struct {
+	Role         string                 `json:"role"`
+	Slot         phase0.Slot            `json:"slot"`
+	Round        specqbft.Round         `json:"round"`
+	Rounds       []roundStartJSON       `json:"rounds"`
+	DecidedAt    *time.Time             `json:"decided_at,omitempty"`
+	Participants []spectypes.OperatorID `json:"participants"`
+	Submitted    bool                   `json:"submitted"`
+	Error        string                 `json:"error,omitempty"`
+}
+
+type roundStartJSON struct {
+	Round     specqbft.Round `json:"round"`
+	StartedAt time.Time      `json:"started_at"`
+}
+
+// Duties responds with the recorded duties of a validator between the from and to slots (inclusive),
+// which default to the whole retained history.
+func (h *DutyHistory) Duties(w http.ResponseWriter, r *http.Request) error {
+	var response struct {
+		Enabled bool              `json:"enabled"`
+		Data    []*dutyRecordJSON `json:"data"`
+	}
+
+	pubKey, err := hex.DecodeString(strings.TrimPrefix(chi.URLParam(r, "pubkey"), "0x"))
+	if err != nil {
+		return api.InvalidRequestError(fmt.Errorf("invalid validator public key: %w", err))
+	}
+	if len(pubKey) != phase0.PublicKeyLength {
+		return api.InvalidRequestError(fmt.Errorf("invalid validator public key length %d", len(pubKey)))
+	}
+	from, err := slotParam(r, "from", 0)
+	if err != nil {
+		return err
+	}
+	to, err := slotParam(r, "to", math.MaxUint64)
+	if err != nil {
+		return err
+	}
+	if from > to {
+		return api.InvalidRequestError(fmt.Errorf("from slot %d is after to slot %d", from, to))
+	}
+
+	response.Data = []*dutyRecordJSON{}
+	if h.Store == nil {
+		return api.Render(w, r, response)
+	}
+	response.Enabled = true
+
+	records, err := h.Store.Duties(spectypes.ValidatorPK(pubKey), from, to)
+	if err != nil {
+		return err
+	}
+	for _, record := range records {
+		duty := &dutyRecordJSON{
+			Role:         record.Role.String(),
+			Slot:         record.Slot,
+			Round:        record.Round,
+			Rounds:       []roundStartJSON{},
+			Participants: record.Participants,
+			Submitted:    record.Submitted,
+			Error:        record.Error,
+		}
+		for _, round := range record.Rounds {
+			duty.Rounds = append(duty.Rounds, roundStartJSON{Round: round.Round, StartedAt: round.StartedAt})
+		}
+		if duty.Participants == nil {
+			duty.Participants = []spectypes.OperatorID{}
+		}
+		if !record.DecidedAt.IsZero() {
+			decidedAt := record.DecidedAt
+			duty.DecidedAt = &decidedAt
+		}
+		response.Data = append(response.Data, duty)
+	}
+	return api.Render(w, r, response)
+}
+
+func slotParam(r *http.Request, name string, defaultSlot phase0.Slot) (phase0.Slot, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return defaultSlot, nil
+	}
+	slot, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, api.InvalidRequestError(fmt.Errorf("invalid %s slot: %w", name, err))
+	}
+	return phase0.Slot(slot), nil
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	spectypes "github.com/ssvlabs/ssv-spec/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ssvlabs/ssv/api"
+	"github.com/ssvlabs/ssv/logging"
+	"github.com/ssvlabs/ssv/operator/dutyhistory"
+	"github.com/ssvlabs/ssv/protocol/v2/blockchain/beacon"
+	"github.com/ssvlabs/ssv/storage/basedb"
+	"github.com/ssvlabs/ssv/storage/kv"
+)
+
+func TestDuties(t *testing.T) {
+	db, err := kv.NewInMemory(logging.TestLogger(t), basedb.Options{})
+	require.NoError(t, err)
+	defer db.Close()
+
+	network := beacon.NewNetwork(spectypes.MainNetwork)
+	store := dutyhistory.New(logging.TestLogger(t), db, network, 10)
+	pk := spectypes.ValidatorPK{1}
+	slot := network.EstimatedCurrentSlot()
+	startedAt := time.Unix(1700000000, 0).UTC()
+	rounds := []dutyhistory.RoundStart{{Round: 1, StartedAt: startedAt}}
+	require.NoError(t, store.Save(pk, &dutyhistory.Record{Role: spectypes.BNRoleAttester, Slot: slot - 1, Round: 1, Rounds: rounds, Participants: []spectypes.OperatorID{1, 2, 3}, Submitted: true}))
+	require.NoError(t, store.Save(pk, &dutyhistory.Record{Role: spectypes.BNRoleProposer, Slot: slot, Error: "failed to get beacon block"}))
+
+	router := chi.NewRouter()
+	router.Get("/v1/validators/{pubkey}/duties", api.Handler((&DutyHistory{Store: store}).Duties))
+	get := func(query string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/validators/"+query, nil))
+		return rec
+	}
+
+	var response struct {
+		Enabled bool              `json:"enabled"`
+		Data    []*dutyRecordJSON `json:"data"`
+	}
+	rec := get(fmt.Sprintf("%x/duties", pk[:]))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	require.True(t, response.Enabled)
+	require.Equal(t, []*dutyRecordJSON{
+		{Role: "ATTESTER", Slot: slot - 1, Round: 1, Rounds: []roundStartJSON{{Round: 1, StartedAt: startedAt}}, Participants: []spectypes.OperatorID{1, 2, 3}, Submitted: true},
+		{Role: "PROPOSER", Slot: slot, Rounds: []roundStartJSON{}, Participants: []spectypes.OperatorID{}, Error: "failed to get beacon block"},
+	}, response.Data)
+
+	rec = get(fmt.Sprintf("0x%x/duties?from=%d&to=%d", pk[:], slot, slot))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	require.Len(t, response.Data, 1)
+	require.Equal(t, "PROPOSER", response.Data[0].Role)
+
+	require.Equal(t, http.StatusBadRequest, get("0102/duties").Code)
+	require.Equal(t, http.StatusBadRequest, get(fmt.Sprintf("%x/duties?from=x", pk[:])).Code)
+	require.Equal(t, http.StatusBadRequest, get(fmt.Sprintf("%x/duties?from=%d&to=%d", pk[:], slot, slot-1)).Code)
+}
@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	spectypes "github.com/ssvlabs/ssv-spec/types"
+
+	"github.com/ssvlabs/ssv/api"
+	"github.com/ssvlabs/ssv/operator/eventstream"
+)
+
+// eventsKeepAliveInterval is the interval of comments sent to keep idle event streams open.
+const eventsKeepAliveInterval = 15 * time.Second
+
+var eventTypes = []eventstream.Type{
+	eventstream.TypeDecided,
+	eventstream.TypeDuty,
+	eventstream.TypeValidatorAdded,
+	eventstream.TypeValidatorRemoved,
+	eventstream.TypeValidatorLiquidated,
+	eventstream.TypeValidatorReactivated,
+}
+
+type Events struct {
+	Stream *eventstream.Stream
+}
+
+type eventJSON struct {
+	ID         uint64                 `json:"id"`
+	Type       eventstream.Type       `json:"type"`
+	Slot       phase0.Slot            `json:"slot"`
+	Role       string                 `json:"role,omitempty"`
+	Validators []api.Hex              `json:"validators"`
+	Owner      api.Hex                `json:"owner,omitempty"`
+	Operators  []spectypes.OperatorID `json:"operators"`
+	Data       any                    `json:"data"`
+}
+
+// requestStrings is a comma-separated list of strings.
+type requestStrings []string
+
+func (s *requestStrings) Bind(value string) error {
+	if value == "" {
+		return nil
+	}
+	*s = append(*s, strings.Split(value, ",")...)
+	return nil
+}
+
+// Subscribe streams the events matching the request as Server-Sent Events until the client disconnects.
+// Recent events are sent first, starting from the from_slot slot or after the Last-Event-ID of a reconnecting client.
+func (h *Events) Subscribe(w http.ResponseWriter, r *http.Request) error {
+	var request struct {
+		Types    requestStrings  `json:"types" form:"types"`
+		PubKeys  api.HexSlice    `json:"pubkeys" form:"pubkeys"`
+		Clusters requestClusters `json:"clusters" form:"clusters"`
+		Owners   api.HexSlice    `json:"owners" form:"owners"`
+		Roles    requestStrings  `json:"roles" form:"roles"`
+	}
+	if err := api.Bind(r, &request); err != nil {
+		return api.InvalidRequestError(err)
+	}
+
+	filter := eventstream.Filter{
+		Clusters: request.Clusters,
+	}
+	for _, t := range request.Types {
+		if !slices.Contains(eventTypes, eventstream.Type(t)) {
+			return api.InvalidRequestError(fmt.Errorf("unknown event type %q", t))
+		}
+		filter.Types = append(filter.Types, eventstream.Type(t))
+	}
+	for _, pk := range request.PubKeys {
+		if len(pk) != phase0.PublicKeyLength {
+			return api.InvalidRequestError(fmt.Errorf("invalid validator public key length %d", len(pk)))
+		}
+		filter.Validators = append(filter.Validators, spectypes.ValidatorPK(pk))
+	}
+	for _, owner := range request.Owners {
+		if len(owner) != ethcommon.AddressLength {
+			return api.InvalidRequestError(fmt.Errorf("invalid owner address length %d", len(owner)))
+		}
+		filter.Owners = append(filter.Owners, ethcommon.BytesToAddress(owner))
+	}
+	for _, role := range request.Roles {
+		filter.Roles = append(filter.Roles, strings.ToUpper(role))
+	}
+
+	fromSlot, err := slotParam(r, "from_slot", 0)
+	if err != nil {
+		return err
+	}
+	var lastEventID uint64
+	if header := r.Header.Get("Last-Event-ID"); header != "" {
+		lastEventID, err = strconv.ParseUint(header, 10, 64)
+		if err != nil {
+			return api.InvalidRequestError(fmt.Errorf("invalid Last-Event-ID: %w", err))
+		}
+	}
+
+	// The stream outlives the server's write timeout.
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+
+	recent, sub := h.Stream.Subscribe(filter, fromSlot, lastEventID)
+	defer h.Stream.Unsubscribe(sub)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	// Errors are not returned once the stream started, since the response can't be changed anymore.
+	for _, event := range recent {
+		if err := writeEvent(w, event); err != nil {
+			return nil
+		}
+	}
+	if err := rc.Flush(); err != nil {
+		return nil
+	}
+
+	keepAlive := time.NewTicker(eventsKeepAliveInterval)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return nil
+		case event, ok := <-sub.Events():
+			if !ok {
+				// The subscriber fell behind, so it should reconnect and resume from the last event it received.
+				return nil
+			}
+			if err := writeEvent(w, event); err != nil {
+				return nil
+			}
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return nil
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return nil
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, event *eventstream.Event) error {
+	e := eventJSON{
+		ID:         event.ID,
+		Type:       event.Type,
+		Slot:       event.Slot,
+		Role:       event.Role,
+		Validators: make([]api.Hex, len(event.Validators)),
+		Operators:  event.Operators,
+		Data:       event.Data,
+	}
+	for i, pk := range event.Validators {
+		e.Validators[i] = api.Hex(pk[:])
+	}
+	if event.Owner != (ethcommon.Address{}) {
+		e.Owner = api.Hex(event.Owner[:])
+	}
+	if e.Operators == nil {
+		e.Operators = []spectypes.OperatorID{}
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	spectypes "github.com/ssvlabs/ssv-spec/types"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ssvlabs/ssv/api"
+	"github.com/ssvlabs/ssv/logging"
+	"github.com/ssvlabs/ssv/operator/dutyhistory"
+	"github.com/ssvlabs/ssv/operator/eventstream"
+	"github.com/ssvlabs/ssv/registry/storage/mocks"
+)
+
+func TestEvents(t *testing.T) {
+	validators := mocks.NewMockBaseValidatorStore(gomock.NewController(t))
+	validators.EXPECT().Validator(gomock.Any()).Return(nil, false).AnyTimes()
+	stream := eventstream.New(logging.TestLogger(t), validators, 10)
+	server := httptest.NewServer(api.Handler((&Events{Stream: stream}).Subscribe))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "?types=unknown")
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	pk := spectypes.ValidatorPK{1}
+	stream.RecordDuty(pk, &dutyhistory.Record{Role: spectypes.BNRoleAttester, Slot: 1})
+	stream.RecordDuty(pk, &dutyhistory.Record{Role: spectypes.BNRoleProposer, Slot: 2})
+	stream.RecordDuty(pk, &dutyhistory.Record{Role: spectypes.BNRoleAttester, Slot: 3})
+	recent, sub := stream.Subscribe(eventstream.Filter{}, 0, 0)
+	stream.Unsubscribe(sub)
+	firstID := recent[0].ID
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"?types=duty&roles=attester&from_slot=2", nil)
+	require.NoError(t, err)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	reader := bufio.NewReader(resp.Body)
+	readEvent := func() (lines []string) {
+		for {
+			line, err := reader.ReadString('\n')
+			require.NoError(t, err)
+			line = strings.TrimSuffix(line, "\n")
+			if line == "" {
+				return lines
+			}
+			lines = append(lines, line)
+		}
+	}
+
+	// The recent event is replayed, then new events are streamed.
+	lines := readEvent()
+	require.Equal(t, []string{fmt.Sprintf("id: %d", firstID+2), "event: duty"}, lines[:2])
+	var event eventJSON
+	require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(lines[2], "data: ")), &event))
+	require.Equal(t, firstID+2, event.ID)
+	require.Equal(t, "ATTESTER", event.Role)
+	require.Equal(t, []api.Hex{pk[:]}, event.Validators)
+
+	stream.RecordDuty(pk, &dutyhistory.Record{Role: spectypes.BNRoleProposer, Slot: 4})
+	stream.RecordDuty(pk, &dutyhistory.Record{Role: spectypes.BNRoleAttester, Slot: 4})
+	require.Equal(t, []string{fmt.Sprintf("id: %d", firstID+4), "event: duty"}, readEvent()[:2])
+}
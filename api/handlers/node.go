@@ -66,16 +66,24 @@ func (h healthStatus) MarshalJSON() ([]byte, error) {
 	return json.Marshal(fmt.Sprintf("bad: %s", h.err.Error()))
 }
 
+type nodeStatusJSON struct {
+	Node     string       `json:"node"`
+	Endpoint string       `json:"endpoint,omitempty"`
+	Status   healthStatus `json:"status"`
+	Latency  string       `json:"latency"`
+}
+
 type healthCheckJSON struct {
 	P2P           healthStatus `json:"p2p"`
 	BeaconNode    healthStatus `json:"beacon_node"`
 	ExecutionNode healthStatus `json:"execution_node"`
 	EventSyncer   healthStatus `json:"event_syncer"`
 	Advanced      struct {
-		Peers           int      `json:"peers"`
-		InboundConns    int      `json:"inbound_conns"`
-		OutboundConns   int      `json:"outbound_conns"`
-		ListenAddresses []string `json:"p2p_listen_addresses"`
+		Peers           int              `json:"peers"`
+		InboundConns    int              `json:"inbound_conns"`
+		OutboundConns   int              `json:"outbound_conns"`
+		ListenAddresses []string         `json:"p2p_listen_addresses"`
+		Nodes           []nodeStatusJSON `json:"nodes"`
 	} `json:"advanced"`
 }
 
@@ -163,6 +171,16 @@ func (h *Node) Health(w http.ResponseWriter, r *http.Request) error {
 	resp.ExecutionNode = healthStatus{h.NodeProber.CheckExecutionNodeHealth(ctx)}
 	resp.EventSyncer = healthStatus{h.NodeProber.CheckEventSyncerHealth(ctx)}
 
+	// Report the latest probe of each node and each of its endpoints.
+	for _, s := range h.NodeProber.Statuses() {
+		resp.Advanced.Nodes = append(resp.Advanced.Nodes, nodeStatusJSON{
+			Node:     s.Node,
+			Endpoint: s.Endpoint,
+			Status:   healthStatus{s.Err},
+			Latency:  s.Latency.String(),
+		})
+	}
+
 	return api.Render(w, r, resp)
 }
 
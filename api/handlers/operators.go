@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	spectypes "github.com/ssvlabs/ssv-spec/types"
+
+	"github.com/ssvlabs/ssv/api"
+	"github.com/ssvlabs/ssv/operator/performance"
+)
+
+// PerformanceTracker returns the performance of operators this node shares committees with.
+type PerformanceTracker interface {
+	Performance(operatorID spectypes.OperatorID) (performance.Performance, bool)
+}
+
+type Operators struct {
+	Tracker PerformanceTracker
+}
+
+type operatorPerformanceJSON struct {
+	ID                    spectypes.OperatorID `json:"id"`
+	PrepareDelayMS        float64              `json:"prepare_delay_ms"`
+	CommitDelayMS         float64              `json:"commit_delay_ms"`
+	Messages              int                  `json:"messages"`
+	Rounds                uint64               `json:"rounds"`
+	MissedRounds          uint64               `json:"missed_rounds"`
+	RoundChangesInitiated uint64               `json:"round_changes_initiated"`
+	Reliability           float64              `json:"reliability"`
+}
+
+// Performance responds with how quickly and reliably an operator took part in the consensus
+// of the committees it shares with this node since the node started.
+func (h *Operators) Performance(w http.ResponseWriter, r *http.Request) error {
+	operatorID, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return api.InvalidRequestError(fmt.Errorf("invalid operator ID: %w", err))
+	}
+	p, found := h.Tracker.Performance(operatorID)
+	if !found {
+		return api.ErrNotFound
+	}
+	return api.Render(w, r, operatorPerformanceJSON{
+		ID:                    p.OperatorID,
+		PrepareDelayMS:        float64(p.PrepareDelay.Microseconds()) / 1000,
+		CommitDelayMS:         float64(p.CommitDelay.Microseconds()) / 1000,
+		Messages:              p.Messages,
+		Rounds:                p.Rounds,
+		MissedRounds:          p.MissedRounds,
+		RoundChangesInitiated: p.RoundChangesInitiated,
+		Reliability:           p.Reliability(),
+	})
+}
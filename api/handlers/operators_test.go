@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	specqbft "github.com/ssvlabs/ssv-spec/qbft"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ssvlabs/ssv/api"
+	"github.com/ssvlabs/ssv/operator/performance"
+)
+
+func TestOperatorPerformance(t *testing.T) {
+	tracker := performance.NewTracker()
+	tracker.MessageDelay(2, specqbft.PrepareMsgType, 150*time.Millisecond)
+	tracker.MessageDelay(2, specqbft.CommitMsgType, 300*time.Millisecond)
+	tracker.RoundObserved(2, false)
+	tracker.RoundObserved(2, true)
+	tracker.RoundChangeInitiated(2)
+
+	router := chi.NewRouter()
+	router.Get("/v1/operators/{id}/performance", api.Handler((&Operators{Tracker: tracker}).Performance))
+	get := func(id string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/operators/"+id+"/performance", nil))
+		return rec
+	}
+
+	rec := get("2")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response operatorPerformanceJSON
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	require.Equal(t, operatorPerformanceJSON{
+		ID:                    2,
+		PrepareDelayMS:        150,
+		CommitDelayMS:         300,
+		Messages:              2,
+		Rounds:                2,
+		MissedRounds:          1,
+		RoundChangesInitiated: 1,
+		Reliability:           0.5,
+	}, response)
+
+	require.Equal(t, http.StatusNotFound, get("3").Code)
+	require.Equal(t, http.StatusBadRequest, get("x").Code)
+}
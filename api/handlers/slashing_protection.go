@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+
+	"github.com/ssvlabs/ssv/api"
+	"github.com/ssvlabs/ssv/ekm/interchange"
+	"github.com/ssvlabs/ssv/ekm/slashinghistory"
+	"github.com/ssvlabs/ssv/protocol/v2/types"
+	registrystorage "github.com/ssvlabs/ssv/registry/storage"
+)
+
+type SlashingProtection struct {
+	// Store is the key manager's slashing protection storage, so that imports are serialized with signing.
+	Store                 interchange.Store
+	GenesisValidatorsRoot phase0.Root
+
+	// SlashingHistory is nil unless the full slashing protection history is enabled.
+	SlashingHistory *slashinghistory.History
+	Shares          registrystorage.Shares
+}
+
+// Export responds with the slashing protection data of the operator's shares as an EIP-3076 interchange document
+// of their validators.
+func (h *SlashingProtection) Export(w http.ResponseWriter, r *http.Request) error {
+	exported, err := interchange.Export(h.Store, h.keys(), h.GenesisValidatorsRoot)
+	if err != nil {
+		return err
+	}
+	return api.Render(w, r, exported)
+}
+
+// Import merges an EIP-3076 interchange document from the request body into the slashing protection data,
+// keeping the higher watermark of the operator's share of each validator.
+func (h *SlashingProtection) Import(w http.ResponseWriter, r *http.Request) error {
+	var request interchange.Interchange
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return api.InvalidRequestError(fmt.Errorf("invalid interchange document: %w", err))
+	}
+	if err := request.Validate(h.GenesisValidatorsRoot); err != nil {
+		return api.InvalidRequestError(err)
+	}
+
+	result, err := interchange.Import(h.Store, h.keys(), &request, h.GenesisValidatorsRoot)
+	if err != nil {
+		return err
+	}
+	return api.Render(w, r, result)
+}
+
+// keys maps the validators to the operator's shares of them.
+func (h *SlashingProtection) keys() *interchange.Keys {
+	keys := interchange.NewKeys()
+	h.Shares.Range(nil, func(share *types.SSVShare) bool {
+		if len(share.SharePubKey) != 0 {
+			keys.Add(share.ValidatorPubKey[:], share.SharePubKey)
+		}
+		return true
+	})
+	return keys
+}
+
+type signedAttestationJSON struct {
+	SourceEpoch phase0.Epoch `json:"source_epoch"`
+	TargetEpoch phase0.Epoch `json:"target_epoch"`
+	SigningRoot api.Hex      `json:"signing_root"`
+}
+
+type signedBlockJSON struct {
+	Slot        phase0.Slot `json:"slot"`
+	SigningRoot api.Hex     `json:"signing_root"`
+}
+
+// History responds with the attestations and blocks signed by the operator's share of a validator
+// within the slashing history window, for reviewing incidents.
+func (h *SlashingProtection) History(w http.ResponseWriter, r *http.Request) error {
+	var request struct {
+		PubKey api.Hex `json:"pubkey" form:"pubkey"`
+	}
+	var response struct {
+		Enabled            bool                    `json:"enabled"`
+		SharePubKey        api.Hex                 `json:"share_pubkey,omitempty"`
+		SignedAttestations []signedAttestationJSON `json:"signed_attestations"`
+		SignedBlocks       []signedBlockJSON       `json:"signed_blocks"`
+	}
+
+	if err := api.Bind(r, &request); err != nil {
+		return err
+	}
+	if len(request.PubKey) != phase0.PublicKeyLength {
+		return api.InvalidRequestError(fmt.Errorf("invalid validator public key length %d", len(request.PubKey)))
+	}
+
+	response.SignedAttestations = []signedAttestationJSON{}
+	response.SignedBlocks = []signedBlockJSON{}
+	if h.SlashingHistory == nil {
+		return api.Render(w, r, response)
+	}
+	response.Enabled = true
+
+	share, found := h.Shares.Get(nil, request.PubKey)
+	if !found || len(share.SharePubKey) == 0 {
+		return api.ErrNotFound
+	}
+	response.SharePubKey = api.Hex(share.SharePubKey)
+
+	attestations, err := h.SlashingHistory.Attestations(share.SharePubKey)
+	if err != nil {
+		return err
+	}
+	for _, att := range attestations {
+		response.SignedAttestations = append(response.SignedAttestations, signedAttestationJSON{
+			SourceEpoch: att.SourceEpoch,
+			TargetEpoch: att.TargetEpoch,
+			SigningRoot: att.SigningRoot[:],
+		})
+	}
+	blocks, err := h.SlashingHistory.Proposals(share.SharePubKey)
+	if err != nil {
+		return err
+	}
+	for _, block := range blocks {
+		response.SignedBlocks = append(response.SignedBlocks, signedBlockJSON{
+			Slot:        block.Slot,
+			SigningRoot: block.SigningRoot[:],
+		})
+	}
+	return api.Render(w, r, response)
+}
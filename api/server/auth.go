@@ -0,0 +1,100 @@
+package server
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/go-chi/render"
+
+	"github.com/ssvlabs/ssv/api"
+)
+
+// AuthConfig configures the authentication of the admin routes, which can only be served
+// when a bearer token or a client CA is configured.
+type AuthConfig struct {
+	BearerTokenFile string `yaml:"BearerTokenFile" env:"SSV_API_BEARER_TOKEN_FILE" env-description:"Path to a file with the bearer token which authenticates admin requests to the SSV API"`
+	TLSCertFile     string `yaml:"TLSCertFile" env:"SSV_API_TLS_CERT_FILE" env-description:"Path to the certificate to serve the SSV API over TLS with"`
+	TLSKeyFile      string `yaml:"TLSKeyFile" env:"SSV_API_TLS_KEY_FILE" env-description:"Path to the private key of the TLS certificate"`
+	ClientCAFile    string `yaml:"ClientCAFile" env:"SSV_API_CLIENT_CA_FILE" env-description:"Path to the CA certificate of clients which authenticate admin requests with TLS client certificates (requires TLS)"`
+}
+
+// Enabled tells whether the admin routes can be authenticated.
+func (c AuthConfig) Enabled() bool {
+	return c.BearerTokenFile != "" || c.ClientCAFile != ""
+}
+
+// tlsConfig returns the TLS configuration to serve with, or nil to serve plain HTTP.
+func (c AuthConfig) tlsConfig() (*tls.Config, error) {
+	if c.TLSCertFile == "" && c.TLSKeyFile == "" {
+		if c.ClientCAFile != "" {
+			return nil, fmt.Errorf("client CA requires a TLS certificate")
+		}
+		return nil, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(c.TLSCertFile, c.TLSKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not load TLS certificate: %w", err)
+	}
+	tlsConfig := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+	}
+	if c.ClientCAFile != "" {
+		// nolint: gosec
+		caCert, err := os.ReadFile(c.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not read client CA certificate: %w", err)
+		}
+		tlsConfig.ClientCAs = x509.NewCertPool()
+		if !tlsConfig.ClientCAs.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("invalid client CA certificate")
+		}
+		// Read-only routes stay available to clients without a certificate.
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+	return tlsConfig, nil
+}
+
+// middlewareAuth rejects requests which neither carry the bearer token nor a verified client certificate.
+func middlewareAuth(config AuthConfig) (func(next http.Handler) http.Handler, error) {
+	var token []byte
+	if config.BearerTokenFile != "" {
+		// nolint: gosec
+		b, err := os.ReadFile(config.BearerTokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not read bearer token: %w", err)
+		}
+		token = bytes.TrimSpace(b)
+		if len(token) == 0 {
+			return nil, fmt.Errorf("bearer token file is empty")
+		}
+	}
+	clientCerts := config.ClientCAFile != ""
+
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			if clientCerts && r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if token != nil {
+				provided, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+				if ok && subtle.ConstantTimeCompare([]byte(provided), token) == 1 {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+			if err := render.Render(w, r, api.ErrUnauthorized); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+		}
+		return http.HandlerFunc(fn)
+	}, nil
+}
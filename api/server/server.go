@@ -1,6 +1,8 @@
 package server
 
 import (
+	_ "embed"
+	"fmt"
 	"net/http"
 	"runtime"
 	"time"
@@ -13,12 +15,37 @@ import (
 	"github.com/ssvlabs/ssv/api/handlers"
 )
 
+//go:embed openapi/v1.yaml
+var openAPISpec []byte
+
 type Server struct {
 	logger *zap.Logger
 	addr   string
+	auth   AuthConfig
+
+	node         *handlers.Node
+	validators   *handlers.Validators
+	doppelganger *handlers.Doppelganger
+
+	slashingProtection *handlers.SlashingProtection
+	dutyHistory        *handlers.DutyHistory
+	events             *handlers.Events
+	clusters           *handlers.Clusters
+	operators          *handlers.Operators
+
+	// admin is nil unless set with WithAdmin.
+	admin *handlers.Admin
+}
+
+// Option defines a Server configuration option.
+type Option func(*Server)
 
-	node       *handlers.Node
-	validators *handlers.Validators
+// WithAdmin serves the admin routes with the given authentication, which must be enabled.
+func WithAdmin(admin *handlers.Admin, auth AuthConfig) Option {
+	return func(s *Server) {
+		s.admin = admin
+		s.auth = auth
+	}
 }
 
 func New(
@@ -26,39 +53,125 @@ func New(
 	addr string,
 	node *handlers.Node,
 	validators *handlers.Validators,
+	doppelganger *handlers.Doppelganger,
+	slashingProtection *handlers.SlashingProtection,
+	dutyHistory *handlers.DutyHistory,
+	events *handlers.Events,
+	clusters *handlers.Clusters,
+	operators *handlers.Operators,
+	opts ...Option,
 ) *Server {
-	return &Server{
-		logger:     logger,
-		addr:       addr,
-		node:       node,
-		validators: validators,
+	s := &Server{
+		logger:       logger,
+		addr:         addr,
+		node:         node,
+		validators:   validators,
+		doppelganger: doppelganger,
+
+		slashingProtection: slashingProtection,
+		dutyHistory:        dutyHistory,
+		events:             events,
+		clusters:           clusters,
+		operators:          operators,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func (s *Server) Run() error {
-	router := chi.NewRouter()
-	router.Use(middleware.Recoverer)
-	router.Use(middleware.Throttle(runtime.NumCPU() * 4))
-	router.Use(middleware.Compress(5, "application/json"))
-	router.Use(middlewareLogger(s.logger))
-
-	router.Get("/v1/node/identity", api.Handler(s.node.Identity))
-	router.Get("/v1/node/peers", api.Handler(s.node.Peers))
-	router.Get("/v1/node/topics", api.Handler(s.node.Topics))
-	router.Get("/v1/node/health", api.Handler(s.node.Health))
-	router.Get("/v1/validators", api.Handler(s.validators.List))
+	router, err := s.router()
+	if err != nil {
+		return err
+	}
+	tlsConfig, err := s.auth.tlsConfig()
+	if err != nil {
+		return err
+	}
 
-	s.logger.Info("Serving SSV API", zap.String("addr", s.addr))
+	s.logger.Info("Serving SSV API", zap.String("addr", s.addr), zap.Bool("tls", tlsConfig != nil))
 
 	server := &http.Server{
 		Addr:         s.addr,
 		Handler:      router,
 		ReadTimeout:  12 * time.Second,
 		WriteTimeout: 12 * time.Second,
+		TLSConfig:    tlsConfig,
+	}
+	if tlsConfig != nil {
+		// Certificates are already loaded into TLSConfig.
+		return server.ListenAndServeTLS("", "")
 	}
 	return server.ListenAndServe()
 }
 
+func (s *Server) router() (chi.Router, error) {
+	var auth func(http.Handler) http.Handler
+	if s.admin != nil {
+		if !s.auth.Enabled() {
+			return nil, fmt.Errorf("admin routes require authentication, configure a bearer token or a client CA")
+		}
+		var err error
+		if auth, err = middlewareAuth(s.auth); err != nil {
+			return nil, err
+		}
+	}
+
+	router := chi.NewRouter()
+	router.Use(middleware.Recoverer)
+	router.Use(middlewareLogger(s.logger))
+
+	// Event streams are long-lived, so they're neither throttled nor compressed.
+	router.Get("/v1/events", api.Handler(s.events.Subscribe))
+
+	router.Group(func(router chi.Router) {
+		router.Use(middleware.Throttle(runtime.NumCPU() * 4))
+		router.Use(middleware.Compress(5, "application/json"))
+
+		router.Get("/v1/node/identity", api.Handler(s.node.Identity))
+		router.Get("/v1/node/peers", api.Handler(s.node.Peers))
+		router.Get("/v1/node/topics", api.Handler(s.node.Topics))
+		router.Get("/v1/node/health", api.Handler(s.node.Health))
+		router.Get("/v1/validators", api.Handler(s.validators.List))
+		router.Get("/v1/validators/doppelganger", api.Handler(s.doppelganger.States))
+		router.Get("/v1/validators/{pubkey}/duties", api.Handler(s.dutyHistory.Duties))
+		router.Get("/v1/clusters/{id}/health", api.Handler(s.clusters.Health))
+		router.Get("/v1/clusters/{id}/queues", api.Handler(s.clusters.Queues))
+		router.Get("/v1/operators/{id}/performance", api.Handler(s.operators.Performance))
+		router.Get("/v1/slashing-protection/export", api.Handler(s.slashingProtection.Export))
+		router.Get("/v1/slashing-protection/history", api.Handler(s.slashingProtection.History))
+		router.Get("/v1/openapi.yaml", serveOpenAPISpec)
+
+		if auth == nil {
+			return
+		}
+		router.Group(func(router chi.Router) {
+			router.Use(auth)
+			router.Post("/v1/slashing-protection/import", api.Handler(s.slashingProtection.Import))
+			router.Post("/v1/admin/validators/metadata/refresh", api.Handler(s.admin.RefreshMetadata))
+			router.Post("/v1/admin/events/resync", api.Handler(s.admin.Resync))
+			router.Get("/v1/admin/log-level", api.Handler(s.admin.LogLevel))
+			router.Put("/v1/admin/log-level", api.Handler(s.admin.SetLogLevel))
+			router.Get("/v1/admin/drain", api.Handler(s.admin.DrainState))
+			router.Post("/v1/admin/drain", api.Handler(s.admin.Drain))
+			router.Delete("/v1/admin/drain", api.Handler(s.admin.Undrain))
+			router.Post("/v1/admin/backfill", api.Handler(s.admin.Backfill))
+			router.Get("/v1/admin/peers/bans", api.Handler(s.admin.PeerBans))
+			router.Post("/v1/admin/peers/bans", api.Handler(s.admin.BanPeer))
+			router.Delete("/v1/admin/peers/bans", api.Handler(s.admin.UnbanPeer))
+			router.Get("/v1/admin/registry/audit", api.Handler(s.admin.RegistryAudit))
+			router.Post("/v1/admin/registry/audit", api.Handler(s.admin.StartRegistryAudit))
+		})
+	})
+	return router, nil
+}
+
+func serveOpenAPISpec(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/yaml")
+	_, _ = w.Write(openAPISpec)
+}
+
 func middlewareLogger(logger *zap.Logger) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		fn := func(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,133 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+
+	"github.com/ssvlabs/ssv/api/handlers"
+)
+
+type drainer struct {
+	draining bool
+}
+
+func (d *drainer) SetDraining(draining bool) { d.draining = draining }
+func (d *drainer) Draining() bool            { return d.draining }
+
+func testServer(t *testing.T, auth AuthConfig) (*Server, *drainer) {
+	d := &drainer{}
+	s := New(
+		zap.NewNop(),
+		":0",
+		&handlers.Node{},
+		&handlers.Validators{},
+		&handlers.Doppelganger{},
+		&handlers.SlashingProtection{},
+		&handlers.DutyHistory{},
+		&handlers.Events{},
+		&handlers.Clusters{},
+		&handlers.Operators{},
+		WithAdmin(&handlers.Admin{Drainer: d}, auth),
+	)
+	return s, d
+}
+
+func tokenFile(t *testing.T, token string) string {
+	path := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(path, []byte(token+"\n"), 0600))
+	return path
+}
+
+func TestOpenAPISpecCoversRoutes(t *testing.T) {
+	var spec struct {
+		Paths map[string]map[string]any `yaml:"paths"`
+	}
+	require.NoError(t, yaml.Unmarshal(openAPISpec, &spec))
+
+	s, _ := testServer(t, AuthConfig{BearerTokenFile: tokenFile(t, "secret")})
+	router, err := s.router()
+	require.NoError(t, err)
+
+	routes := map[string]bool{}
+	err = chi.Walk(router, func(method string, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		routes[method+" "+route] = true
+		_, ok := spec.Paths[route][strings.ToLower(method)]
+		require.True(t, ok, "route %s %s is missing from the OpenAPI spec", method, route)
+		return nil
+	})
+	require.NoError(t, err)
+
+	for path, operations := range spec.Paths {
+		for method := range operations {
+			require.True(t, routes[strings.ToUpper(method)+" "+path], "%s %s of the OpenAPI spec isn't served", method, path)
+		}
+	}
+}
+
+func TestAdminAuth(t *testing.T) {
+	s, d := testServer(t, AuthConfig{BearerTokenFile: tokenFile(t, "secret")})
+	router, err := s.router()
+	require.NoError(t, err)
+
+	drain := func(token string) int {
+		req := httptest.NewRequest(http.MethodPost, "/v1/admin/drain", nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	require.Equal(t, http.StatusUnauthorized, drain(""))
+	require.Equal(t, http.StatusUnauthorized, drain("wrong"))
+	require.False(t, d.draining)
+	require.Equal(t, http.StatusOK, drain("secret"))
+	require.True(t, d.draining)
+
+	// Clients with a verified certificate are authenticated without a token.
+	s, d = testServer(t, AuthConfig{ClientCAFile: "ca.pem"})
+	router, err = s.router()
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/admin/drain", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{}}}}
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response struct {
+		Draining bool `json:"draining"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	require.True(t, response.Draining)
+	require.True(t, d.draining)
+}
+
+func TestAdminRoutesRequireAuth(t *testing.T) {
+	s, _ := testServer(t, AuthConfig{})
+	_, err := s.router()
+	require.ErrorContains(t, err, "admin routes require authentication")
+
+	// Without the admin routes, no authentication is needed.
+	s.admin = nil
+	router, err := s.router()
+	require.NoError(t, err)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/admin/drain", nil))
+	require.Equal(t, http.StatusNotFound, rec.Code)
+}
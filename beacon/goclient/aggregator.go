@@ -1,6 +1,7 @@
 package goclient
 
 import (
+	"context"
 	"encoding/binary"
 	"fmt"
 	"time"
@@ -42,7 +43,7 @@ func (gc *GoClient) SubmitAggregateSelectionProof(slot phase0.Slot, committeeInd
 	}
 
 	aggDataReqStart := time.Now()
-	aggDataResp, err := gc.client.AggregateAttestation(gc.ctx, &api.AggregateAttestationOpts{
+	aggDataResp, err := gc.multiClient.AggregateAttestation(gc.ctx, &api.AggregateAttestationOpts{
 		Slot:                slot,
 		AttestationDataRoot: root,
 	})
@@ -70,7 +71,9 @@ func (gc *GoClient) SubmitAggregateSelectionProof(slot phase0.Slot, committeeInd
 
 // SubmitSignedAggregateSelectionProof broadcasts a signed aggregator msg
 func (gc *GoClient) SubmitSignedAggregateSelectionProof(msg *phase0.SignedAggregateAndProof) error {
-	return gc.client.SubmitAggregateAttestations(gc.ctx, []*phase0.SignedAggregateAndProof{msg})
+	return gc.multiClientSubmit(gc.ctx, "SubmitAggregateAttestations", func(ctx context.Context, client Client) error {
+		return client.SubmitAggregateAttestations(ctx, []*phase0.SignedAggregateAndProof{msg})
+	})
 }
 
 // IsAggregator returns true if the signature is from the input validator. The committee
@@ -13,7 +13,7 @@ import (
 
 // AttesterDuties returns attester duties for a given epoch.
 func (gc *GoClient) AttesterDuties(ctx context.Context, epoch phase0.Epoch, validatorIndices []phase0.ValidatorIndex) ([]*eth2apiv1.AttesterDuty, error) {
-	resp, err := gc.client.AttesterDuties(ctx, &api.AttesterDutiesOpts{
+	resp, err := gc.multiClient.AttesterDuties(ctx, &api.AttesterDutiesOpts{
 		Epoch:   epoch,
 		Indices: validatorIndices,
 	})
@@ -29,7 +29,7 @@ func (gc *GoClient) AttesterDuties(ctx context.Context, epoch phase0.Epoch, vali
 
 func (gc *GoClient) GetAttestationData(slot phase0.Slot, committeeIndex phase0.CommitteeIndex) (*phase0.AttestationData, spec.DataVersion, error) {
 	attDataReqStart := time.Now()
-	resp, err := gc.client.AttestationData(gc.ctx, &api.AttestationDataOpts{
+	resp, err := gc.multiClient.AttestationData(gc.ctx, &api.AttestationDataOpts{
 		Slot:           slot,
 		CommitteeIndex: committeeIndex,
 	})
@@ -47,5 +47,7 @@ func (gc *GoClient) GetAttestationData(slot phase0.Slot, committeeIndex phase0.C
 
 // SubmitAttestations implements Beacon interface
 func (gc *GoClient) SubmitAttestations(attestations []*phase0.Attestation) error {
-	return gc.client.SubmitAttestations(gc.ctx, attestations)
+	return gc.multiClientSubmit(gc.ctx, "SubmitAttestations", func(ctx context.Context, client Client) error {
+		return client.SubmitAttestations(ctx, attestations)
+	})
 }
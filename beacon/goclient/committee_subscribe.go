@@ -8,10 +8,14 @@ import (
 
 // SubmitBeaconCommitteeSubscriptions is implementation for subscribing committee to subnet (p2p topic)
 func (gc *GoClient) SubmitBeaconCommitteeSubscriptions(ctx context.Context, subscription []*eth2apiv1.BeaconCommitteeSubscription) error {
-	return gc.client.SubmitBeaconCommitteeSubscriptions(ctx, subscription)
+	return gc.multiClientSubmit(ctx, "SubmitBeaconCommitteeSubscriptions", func(ctx context.Context, client Client) error {
+		return client.SubmitBeaconCommitteeSubscriptions(ctx, subscription)
+	})
 }
 
 // SubmitSyncCommitteeSubscriptions is implementation for subscribing sync committee to subnet (p2p topic)
 func (gc *GoClient) SubmitSyncCommitteeSubscriptions(ctx context.Context, subscription []*eth2apiv1.SyncCommitteeSubscription) error {
-	return gc.client.SubmitSyncCommitteeSubscriptions(ctx, subscription)
+	return gc.multiClientSubmit(ctx, "SubmitSyncCommitteeSubscriptions", func(ctx context.Context, client Client) error {
+		return client.SubmitSyncCommitteeSubscriptions(ctx, subscription)
+	})
 }
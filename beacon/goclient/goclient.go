@@ -2,24 +2,30 @@ package goclient
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
+	"net/http"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	eth2client "github.com/attestantio/go-eth2-client"
 	"github.com/attestantio/go-eth2-client/api"
 	eth2clienthttp "github.com/attestantio/go-eth2-client/http"
+	eth2clientmulti "github.com/attestantio/go-eth2-client/multi"
 	"github.com/attestantio/go-eth2-client/spec"
 	"github.com/attestantio/go-eth2-client/spec/phase0"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/rs/zerolog"
+	"github.com/sourcegraph/conc/pool"
 	spectypes "github.com/ssvlabs/ssv-spec/types"
 	"go.uber.org/zap"
 
 	"github.com/ssvlabs/ssv/logging/fields"
+	"github.com/ssvlabs/ssv/nodeprobe"
 	operatordatastore "github.com/ssvlabs/ssv/operator/datastore"
 	"github.com/ssvlabs/ssv/operator/slotticker"
 	beaconprotocol "github.com/ssvlabs/ssv/protocol/v2/blockchain/beacon"
@@ -34,6 +40,9 @@ const (
 	// Client timeouts.
 	DefaultCommonTimeout = time.Second * 5  // For dialing and most requests.
 	DefaultLongTimeout   = time.Second * 60 // For long requests.
+
+	// beaconNodeAddrSeparator separates multiple beacon node addresses in Options.BeaconNodeAddr.
+	beaconNodeAddrSeparator = ";"
 )
 
 type beaconNodeStatus int32
@@ -41,12 +50,23 @@ type beaconNodeStatus int32
 var (
 	allMetrics = []prometheus.Collector{
 		metricsBeaconNodeStatus,
+		metricsBeaconClientStatus,
+		metricsBeaconClientRequest,
 		metricsBeaconDataRequest,
 	}
 	metricsBeaconNodeStatus = promauto.NewGauge(prometheus.GaugeOpts{
 		Name: "ssv_beacon_status",
 		Help: "Status of the connected beacon node",
 	})
+	metricsBeaconClientStatus = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ssv_beacon_client_status",
+		Help: "Status of each configured beacon node",
+	}, []string{"address"})
+	metricsBeaconClientRequest = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "ssv_beacon_client_request_duration_seconds",
+		Help:    "Beacon node request duration per configured beacon node (seconds)",
+		Buckets: []float64{0.02, 0.05, 0.1, 0.2, 0.5, 1, 5},
+	}, []string{"address", "api"})
 
 	// metricsBeaconDataRequest is located here to avoid including waiting for 1/3 or 2/3 of slot time into request duration.
 	metricsBeaconDataRequest = promauto.NewHistogramVec(prometheus.HistogramOpts{
@@ -107,6 +127,7 @@ type Client interface {
 	eth2client.NodeClientProvider
 	eth2client.SpecProvider
 	eth2client.GenesisProvider
+	eth2client.ForkScheduleProvider
 
 	eth2client.AttestationDataProvider
 	eth2client.AttestationsSubmitter
@@ -133,18 +154,50 @@ type Client interface {
 	eth2client.VoluntaryExitSubmitter
 }
 
+// MultiClient defines the subset of Client implemented by go-eth2-client's multi client,
+// which is used for reads with automatic failover between beacon nodes.
+type MultiClient interface {
+	eth2client.Service
+	eth2client.NodeVersionProvider
+	eth2client.SpecProvider
+	eth2client.GenesisProvider
+	eth2client.ForkScheduleProvider
+
+	eth2client.AttestationDataProvider
+	eth2client.AggregateAttestationProvider
+	eth2client.AttesterDutiesProvider
+	eth2client.ProposerDutiesProvider
+	eth2client.SyncCommitteeDutiesProvider
+	eth2client.NodeSyncingProvider
+	eth2client.ProposalProvider
+	eth2client.DomainProvider
+	eth2client.BeaconBlockRootProvider
+	eth2client.SyncCommitteeContributionProvider
+	eth2client.ValidatorsProvider
+	eth2client.EventsProvider
+}
+
 type NodeClientProvider interface {
 	NodeClient() NodeClient
 }
 
 var _ NodeClientProvider = (*GoClient)(nil)
+var _ nodeprobe.MultiNode = (*GoClient)(nil)
 
 // GoClient implementing Beacon struct
 type GoClient struct {
-	log                  *zap.Logger
-	ctx                  context.Context
-	network              beaconprotocol.Network
-	client               Client
+	log     *zap.Logger
+	ctx     context.Context
+	network beaconprotocol.Network
+
+	// clients holds a client per configured beacon node, in the configured order.
+	// Submissions are fanned out to all of them.
+	clients []Client
+	// addresses holds the configured (unredacted) address of each client in clients.
+	addresses []string
+	// multiClient wraps clients and fails over to the next healthy one on reads.
+	multiClient MultiClient
+
 	nodeVersion          string
 	nodeClient           NodeClient
 	gasLimit             uint64
@@ -154,16 +207,34 @@ type GoClient struct {
 	registrationCache    map[phase0.BLSPubKey]*api.VersionedSignedValidatorRegistration
 	commonTimeout        time.Duration
 	longTimeout          time.Duration
+	// httpClient is used for the requests go-eth2-client doesn't support.
+	httpClient *http.Client
+
+	statusesMu sync.RWMutex
+	statuses   map[string]nodeprobe.Status
+
+	forkScheduleMu sync.Mutex
+	forkSchedule   []*phase0.Fork
 }
 
-// New init new client and go-client instance
+// New init new client and go-client instance.
+// Options.BeaconNodeAddr may contain several semicolon-separated addresses,
+// in which case reads fail over between them and submissions are sent to all of them.
+// Only one of them must be reachable at startup.
 func New(
 	logger *zap.Logger,
 	opt beaconprotocol.Options,
 	operatorDataStore operatordatastore.OperatorDataStore,
 	slotTickerProvider slotticker.Provider,
 ) (*GoClient, error) {
-	logger.Info("consensus client: connecting", fields.Address(opt.BeaconNodeAddr), fields.Network(string(opt.Network.BeaconNetwork)))
+	addresses := ParseBeaconNodeAddresses(opt.BeaconNodeAddr)
+	if len(addresses) == 0 {
+		return nil, fmt.Errorf("no beacon node address provided")
+	}
+
+	logger.Info("consensus client: connecting",
+		zap.Strings("addresses", addresses),
+		fields.Network(string(opt.Network.BeaconNetwork)))
 
 	commonTimeout := opt.CommonTimeout
 	if commonTimeout == 0 {
@@ -174,50 +245,132 @@ func New(
 		longTimeout = DefaultLongTimeout
 	}
 
-	httpClient, err := eth2clienthttp.New(opt.Context,
-		// WithAddress supplies the address of the beacon node, in host:port format.
-		eth2clienthttp.WithAddress(opt.BeaconNodeAddr),
-		// LogLevel supplies the level of logging to carry out.
-		eth2clienthttp.WithLogLevel(zerolog.DebugLevel),
-		eth2clienthttp.WithTimeout(commonTimeout),
-		eth2clienthttp.WithReducedMemoryUsage(true),
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create http client: %w", err)
-	}
-
 	client := &GoClient{
 		log:               logger,
 		ctx:               opt.Context,
 		network:           opt.Network,
-		client:            httpClient.(*eth2clienthttp.Service),
 		gasLimit:          opt.GasLimit,
 		operatorDataStore: operatorDataStore,
 		registrationCache: map[phase0.BLSPubKey]*api.VersionedSignedValidatorRegistration{},
 		commonTimeout:     commonTimeout,
 		longTimeout:       longTimeout,
+		httpClient:        &http.Client{Timeout: commonTimeout},
+		statuses:          map[string]nodeprobe.Status{},
+	}
+
+	// Nodes which are down at startup are brought in by the multi client once they're up,
+	// so only one reachable node is required.
+	var reachable Client
+	var connectErr error
+	for _, address := range addresses {
+		httpClient, err := client.connect(opt.Context, address)
+		if err != nil {
+			logger.Warn("consensus client unreachable, it will be used once it's up",
+				fields.Address(address),
+				zap.Error(err))
+			connectErr = fmt.Errorf("failed to connect to beacon node %s: %w", address, err)
+
+			httpClient, err = client.newHTTPClient(opt.Context, address, true)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create client of beacon node %s: %w", address, err)
+			}
+		} else if reachable == nil {
+			reachable = httpClient
+		}
+		client.clients = append(client.clients, httpClient)
+		client.addresses = append(client.addresses, address)
+	}
+	if reachable == nil {
+		return nil, connectErr
 	}
 
-	nodeVersionResp, err := client.client.NodeVersion(opt.Context, &api.NodeVersionOpts{})
+	services := make([]eth2client.Service, 0, len(client.clients))
+	for _, c := range client.clients {
+		services = append(services, c)
+	}
+	multiClient, err := eth2clientmulti.New(
+		opt.Context,
+		eth2clientmulti.WithClients(services),
+		eth2clientmulti.WithLogLevel(zerolog.DebugLevel),
+		eth2clientmulti.WithTimeout(commonTimeout),
+		// Node readiness is awaited by nodeprobe.
+		eth2clientmulti.WithAllowDelayedStart(true),
+	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get node version: %w", err)
+		return nil, fmt.Errorf("failed to create multi client: %w", err)
 	}
-	if nodeVersionResp == nil {
-		return nil, fmt.Errorf("node version response is nil")
+	client.multiClient = multiClient.(*eth2clientmulti.Service)
+
+	// The first reachable node determines the node client, which is used to work around client-specific behaviour.
+	client.nodeVersion, client.nodeClient, err = client.fetchNodeVersion(opt.Context, reachable)
+	if err != nil {
+		return nil, err
 	}
-	client.nodeVersion = nodeVersionResp.Data
-	client.nodeClient = ParseNodeClient(nodeVersionResp.Data)
 
-	logger.Info("consensus client connected",
+	go client.registrationSubmitter(slotTickerProvider)
+
+	return client, nil
+}
+
+// ParseBeaconNodeAddresses splits a semicolon-separated list of beacon node addresses.
+func ParseBeaconNodeAddresses(addr string) []string {
+	var addresses []string
+	for _, a := range strings.Split(addr, beaconNodeAddrSeparator) {
+		if a = strings.TrimSpace(a); a != "" {
+			addresses = append(addresses, a)
+		}
+	}
+	return addresses
+}
+
+func (gc *GoClient) connect(ctx context.Context, address string) (Client, error) {
+	httpClient, err := gc.newHTTPClient(ctx, address, false)
+	if err != nil {
+		return nil, err
+	}
+
+	nodeVersion, nodeClient, err := gc.fetchNodeVersion(ctx, httpClient)
+	if err != nil {
+		return nil, err
+	}
+
+	gc.log.Info("consensus client connected",
 		fields.Name(httpClient.Name()),
 		fields.Address(httpClient.Address()),
-		zap.String("client", string(client.nodeClient)),
-		zap.String("version", client.nodeVersion),
+		zap.String("client", string(nodeClient)),
+		zap.String("version", nodeVersion),
 	)
 
-	go client.registrationSubmitter(slotTickerProvider)
+	return httpClient, nil
+}
 
-	return client, nil
+// newHTTPClient returns a client of the beacon node at the given address.
+// Unless allowDelayedStart is set, it fails if the node isn't active.
+func (gc *GoClient) newHTTPClient(ctx context.Context, address string, allowDelayedStart bool) (*eth2clienthttp.Service, error) {
+	httpClient, err := eth2clienthttp.New(ctx,
+		// WithAddress supplies the address of the beacon node, in host:port format.
+		eth2clienthttp.WithAddress(address),
+		// LogLevel supplies the level of logging to carry out.
+		eth2clienthttp.WithLogLevel(zerolog.DebugLevel),
+		eth2clienthttp.WithTimeout(gc.commonTimeout),
+		eth2clienthttp.WithReducedMemoryUsage(true),
+		eth2clienthttp.WithAllowDelayedStart(allowDelayedStart),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create http client: %w", err)
+	}
+	return httpClient.(*eth2clienthttp.Service), nil
+}
+
+func (gc *GoClient) fetchNodeVersion(ctx context.Context, client Client) (string, NodeClient, error) {
+	nodeVersionResp, err := client.NodeVersion(ctx, &api.NodeVersionOpts{})
+	if err != nil {
+		return "", NodeUnknown, fmt.Errorf("failed to get node version: %w", err)
+	}
+	if nodeVersionResp == nil {
+		return "", NodeUnknown, fmt.Errorf("node version response is nil")
+	}
+	return nodeVersionResp.Data, ParseNodeClient(nodeVersionResp.Data), nil
 }
 
 func (gc *GoClient) NodeClient() NodeClient {
@@ -226,34 +379,124 @@ func (gc *GoClient) NodeClient() NodeClient {
 
 // Healthy returns if beacon node is currently healthy: responds to requests, not in the syncing state, not optimistic
 // (for optimistic see https://github.com/ethereum/consensus-specs/blob/dev/sync/optimistic.md#block-production).
+// With multiple beacon nodes configured, it's enough for one of them to be healthy.
 func (gc *GoClient) Healthy(ctx context.Context) error {
-	nodeSyncingResp, err := gc.client.NodeSyncing(ctx, &api.NodeSyncingOpts{})
-	if err != nil {
-		// TODO: get rid of global variable, pass metrics to goClient
+	var (
+		wg      sync.WaitGroup
+		healthy atomic.Int32
+		mu      sync.Mutex
+		errs    []error
+	)
+	for _, client := range gc.clients {
+		wg.Add(1)
+		go func(client Client) {
+			defer wg.Done()
+
+			start := time.Now()
+			status, err := gc.clientHealthy(ctx, client)
+			metricsBeaconClientStatus.WithLabelValues(client.Address()).Set(float64(status))
+
+			gc.statusesMu.Lock()
+			gc.statuses[client.Address()] = nodeprobe.Status{
+				Err:       err,
+				Latency:   time.Since(start),
+				CheckedAt: start,
+			}
+			gc.statusesMu.Unlock()
+
+			if err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("%s: %w", client.Address(), err))
+				mu.Unlock()
+				return
+			}
+			healthy.Add(1)
+		}(client)
+	}
+	wg.Wait()
+
+	// TODO: get rid of global variable, pass metrics to goClient
+	if healthy.Load() == 0 {
 		metricsBeaconNodeStatus.Set(float64(statusUnknown))
-		return fmt.Errorf("failed to obtain node syncing status: %w", err)
+		if len(errs) == 1 {
+			return errs[0]
+		}
+		return fmt.Errorf("no healthy beacon nodes: %w", errors.Join(errs...))
+	}
+	for _, err := range errs {
+		gc.log.Warn("beacon node is not healthy", zap.Error(err))
+	}
+
+	metricsBeaconNodeStatus.Set(float64(statusOK))
+	return nil
+}
+
+func (gc *GoClient) clientHealthy(ctx context.Context, client Client) (beaconNodeStatus, error) {
+	nodeSyncingResp, err := client.NodeSyncing(ctx, &api.NodeSyncingOpts{})
+	if err != nil {
+		return statusUnknown, fmt.Errorf("failed to obtain node syncing status: %w", err)
 	}
 	if nodeSyncingResp == nil {
-		metricsBeaconNodeStatus.Set(float64(statusUnknown))
-		return fmt.Errorf("node syncing response is nil")
+		return statusUnknown, fmt.Errorf("node syncing response is nil")
 	}
 	if nodeSyncingResp.Data == nil {
-		metricsBeaconNodeStatus.Set(float64(statusUnknown))
-		return fmt.Errorf("node syncing data is nil")
+		return statusUnknown, fmt.Errorf("node syncing data is nil")
 	}
 	syncState := nodeSyncingResp.Data
 
 	// TODO: also check if syncState.ElOffline when github.com/attestantio/go-eth2-client supports it
-	metricsBeaconNodeStatus.Set(float64(statusSyncing))
 	if syncState.IsSyncing {
-		return fmt.Errorf("syncing")
+		return statusSyncing, fmt.Errorf("syncing")
 	}
 	if syncState.IsOptimistic {
-		return fmt.Errorf("optimistic")
+		return statusSyncing, fmt.Errorf("optimistic")
 	}
 
-	metricsBeaconNodeStatus.Set(float64(statusOK))
-	return nil
+	return statusOK, nil
+}
+
+// EndpointStatuses returns the result of the latest health check of each beacon node, keyed by address.
+func (gc *GoClient) EndpointStatuses() map[string]nodeprobe.Status {
+	gc.statusesMu.RLock()
+	defer gc.statusesMu.RUnlock()
+
+	statuses := make(map[string]nodeprobe.Status, len(gc.statuses))
+	for address, status := range gc.statuses {
+		statuses[address] = status
+	}
+	return statuses
+}
+
+// multiClientSubmit submits to all beacon nodes in parallel and succeeds if at least one of them accepted the submission.
+func (gc *GoClient) multiClientSubmit(ctx context.Context, operationName string, submitFunc func(ctx context.Context, client Client) error) error {
+	logger := gc.log.With(zap.String("api", operationName))
+
+	var submissions atomic.Int32
+	p := pool.New().WithErrors().WithContext(ctx).WithMaxGoroutines(len(gc.clients))
+	for _, client := range gc.clients {
+		client := client
+		p.Go(func(ctx context.Context) error {
+			ctx, cancel := context.WithTimeout(ctx, gc.commonTimeout)
+			defer cancel()
+
+			start := time.Now()
+			err := submitFunc(ctx, client)
+			metricsBeaconClientRequest.WithLabelValues(client.Address(), operationName).Observe(time.Since(start).Seconds())
+			if err != nil {
+				logger.Debug("beacon node failed to submit", fields.Address(client.Address()), zap.Error(err))
+				return fmt.Errorf("beacon node %s failed to submit %s: %w", client.Address(), operationName, err)
+			}
+
+			submissions.Add(1)
+			return nil
+		})
+	}
+	err := p.Wait()
+	if submissions.Load() > 0 {
+		// At least one beacon node accepted the submission, which is enough for it to propagate.
+		return nil
+	}
+	return err
 }
 
 // GetBeaconNetwork returns the beacon network the node is on
@@ -270,5 +513,5 @@ func (gc *GoClient) slotStartTime(slot phase0.Slot) time.Time {
 }
 
 func (gc *GoClient) Events(ctx context.Context, topics []string, handler eth2client.EventHandlerFunc) error {
-	return gc.client.Events(ctx, topics, handler)
+	return gc.multiClient.Events(ctx, topics, handler)
 }
@@ -7,6 +7,8 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -157,3 +159,159 @@ func mockServer(t *testing.T, delays delays) *httptest.Server {
 		}
 	}))
 }
+
+func TestMultipleBeaconNodes(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	acceptingNode := multiNodeServer(t, true)
+	rejectingNode := multiNodeServer(t, false)
+
+	client, err := mockClient(t, ctx, acceptingNode.server.URL+";"+rejectingNode.server.URL, DefaultCommonTimeout, DefaultLongTimeout)
+	require.NoError(t, err)
+	gc := client.(*GoClient)
+	require.Len(t, gc.clients, 2)
+
+	// Submissions are sent to every node and succeed if any of them accepts.
+	attestations := []*phase0.Attestation{}
+	require.NoError(t, gc.SubmitAttestations(attestations))
+	require.EqualValues(t, 1, acceptingNode.submissions.Load())
+	require.EqualValues(t, 1, rejectingNode.submissions.Load())
+
+	// Submissions fail if no node accepts.
+	acceptingNode.acceptSubmissions.Store(false)
+	require.Error(t, gc.SubmitAttestations(attestations))
+	require.EqualValues(t, 2, acceptingNode.submissions.Load())
+	require.EqualValues(t, 2, rejectingNode.submissions.Load())
+
+	// One healthy node is enough.
+	rejectingNode.syncing.Store(true)
+	require.NoError(t, gc.Healthy(ctx))
+
+	statuses := gc.EndpointStatuses()
+	require.Len(t, statuses, 2)
+	require.NoError(t, statuses[acceptingNode.server.URL].Err)
+	require.ErrorContains(t, statuses[rejectingNode.server.URL].Err, "syncing")
+
+	// No healthy node.
+	acceptingNode.syncing.Store(true)
+	require.ErrorContains(t, gc.Healthy(ctx), "no healthy beacon nodes")
+}
+
+func TestUnreachableBeaconNode(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	node := multiNodeServer(t, true)
+	downNode := multiNodeServer(t, true)
+	downNode.server.Close()
+
+	// Nodes which are down at startup don't prevent it.
+	client, err := mockClient(t, ctx, downNode.server.URL+";"+node.server.URL, DefaultCommonTimeout, DefaultLongTimeout)
+	require.NoError(t, err)
+	gc := client.(*GoClient)
+	require.Len(t, gc.clients, 2)
+	require.Equal(t, NodeLighthouse, gc.nodeClient)
+	require.NoError(t, gc.SubmitAttestations([]*phase0.Attestation{}))
+
+	// At least one node must be reachable.
+	_, err = mockClient(t, ctx, downNode.server.URL, DefaultCommonTimeout, DefaultLongTimeout)
+	require.ErrorContains(t, err, "failed to connect to beacon node")
+}
+
+func TestValidatorLiveness(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	rejectingNode := multiNodeServer(t, false)
+	acceptingNode := multiNodeServer(t, true)
+
+	client, err := mockClient(t, ctx, rejectingNode.server.URL+";"+acceptingNode.server.URL, DefaultCommonTimeout, DefaultLongTimeout)
+	require.NoError(t, err)
+	gc := client.(*GoClient)
+
+	// Falls back to the next node on failure.
+	liveness, err := gc.ValidatorLiveness(ctx, mockServerEpoch, []phase0.ValidatorIndex{1, 2})
+	require.NoError(t, err)
+	require.Equal(t, []ValidatorLiveness{{Index: 1, IsLive: true}, {Index: 2, IsLive: false}}, liveness)
+
+	acceptingNode.acceptSubmissions.Store(false)
+	_, err = gc.ValidatorLiveness(ctx, mockServerEpoch, []phase0.ValidatorIndex{1})
+	require.ErrorContains(t, err, "failed to obtain validator liveness")
+}
+
+func TestForkAtEpoch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client, err := mockClient(t, ctx, multiNodeServer(t, true).server.URL, DefaultCommonTimeout, DefaultLongTimeout)
+	require.NoError(t, err)
+	gc := client.(*GoClient)
+
+	fork, err := gc.ForkAtEpoch(ctx, 99)
+	require.NoError(t, err)
+	require.Equal(t, phase0.Version{0x01}, fork.CurrentVersion)
+
+	fork, err = gc.ForkAtEpoch(ctx, 100)
+	require.NoError(t, err)
+	require.Equal(t, phase0.Version{0x02}, fork.CurrentVersion)
+	require.Equal(t, phase0.Version{0x01}, fork.PreviousVersion)
+}
+
+func TestParseBeaconNodeAddresses(t *testing.T) {
+	require.Equal(t, []string{"http://a:5052"}, ParseBeaconNodeAddresses("http://a:5052"))
+	require.Equal(t, []string{"http://a:5052", "http://b:5052"}, ParseBeaconNodeAddresses("http://a:5052; http://b:5052;"))
+	require.Empty(t, ParseBeaconNodeAddresses(" ; "))
+}
+
+type multiNodeMock struct {
+	server            *httptest.Server
+	syncing           atomic.Bool
+	acceptSubmissions atomic.Bool
+	submissions       atomic.Int32
+}
+
+func multiNodeServer(t *testing.T, acceptSubmissions bool) *multiNodeMock {
+	m := &multiNodeMock{}
+	m.acceptSubmissions.Store(acceptSubmissions)
+
+	m.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/eth/v1/node/version":
+			_, _ = w.Write([]byte(`{"data":{"version":"Lighthouse/v5.1.3"}}`))
+		case "/eth/v1/node/syncing":
+			_, _ = fmt.Fprintf(w, `{"data":{"head_slot":"100","sync_distance":"0","is_syncing":%t,"is_optimistic":false,"el_offline":false}}`, m.syncing.Load())
+		case "/eth/v1/config/fork_schedule":
+			_, _ = w.Write([]byte(`{"data":[` +
+				`{"previous_version":"0x00000000","current_version":"0x00000000","epoch":"0"},` +
+				`{"previous_version":"0x00000000","current_version":"0x01000000","epoch":"10"},` +
+				`{"previous_version":"0x01000000","current_version":"0x02000000","epoch":"100"}]}`))
+		case "/eth/v1/beacon/pool/attestations":
+			m.submissions.Add(1)
+			if !m.acceptSubmissions.Load() {
+				w.WriteHeader(http.StatusInternalServerError)
+				_, _ = w.Write([]byte(`{"code":500,"message":"internal error"}`))
+			}
+		case "/eth/v1/validator/liveness/" + fmt.Sprint(mockServerEpoch):
+			if !m.acceptSubmissions.Load() {
+				w.WriteHeader(http.StatusInternalServerError)
+				_, _ = w.Write([]byte(`{"code":500,"message":"internal error"}`))
+				return
+			}
+			var indices []string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&indices))
+			var data []string
+			for _, index := range indices {
+				// Odd indices are live.
+				isLive := index[len(index)-1]%2 == 1
+				data = append(data, fmt.Sprintf(`{"index":"%s","is_live":%t}`, index, isLive))
+			}
+			_, _ = fmt.Fprintf(w, `{"data":[%s]}`, strings.Join(data, ","))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(m.server.Close)
+	return m
+}
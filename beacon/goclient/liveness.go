@@ -0,0 +1,114 @@
+package goclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+// ValidatorLiveness is the liveness of a validator in an epoch, as observed by the beacon node.
+type ValidatorLiveness struct {
+	Index  phase0.ValidatorIndex
+	IsLive bool
+}
+
+type validatorLivenessJSON struct {
+	Index  string `json:"index"`
+	IsLive bool   `json:"is_live"`
+}
+
+// ValidatorLiveness returns whether the given validators were seen live (attesting or proposing) in the given epoch.
+// go-eth2-client doesn't support the liveness endpoint yet, so it's requested directly,
+// trying the beacon nodes in the configured order until one of them responds.
+func (gc *GoClient) ValidatorLiveness(
+	ctx context.Context,
+	epoch phase0.Epoch,
+	indices []phase0.ValidatorIndex,
+) ([]ValidatorLiveness, error) {
+	if len(indices) == 0 {
+		return nil, nil
+	}
+
+	body := make([]string, 0, len(indices))
+	for _, index := range indices {
+		body = append(body, strconv.FormatUint(uint64(index), 10))
+	}
+	bodyJSON, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal liveness request: %w", err)
+	}
+
+	var errs []error
+	for i, address := range gc.addresses {
+		liveness, err := gc.validatorLiveness(ctx, address, epoch, bodyJSON)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", gc.clients[i].Address(), err))
+			continue
+		}
+		return liveness, nil
+	}
+	return nil, fmt.Errorf("failed to obtain validator liveness: %w", errors.Join(errs...))
+}
+
+func (gc *GoClient) validatorLiveness(
+	ctx context.Context,
+	address string,
+	epoch phase0.Epoch,
+	body []byte,
+) ([]ValidatorLiveness, error) {
+	// Addresses are in host:port format unless they have a scheme, like go-eth2-client accepts them.
+	if !strings.HasPrefix(address, "http://") && !strings.HasPrefix(address, "https://") {
+		address = "http://" + address
+	}
+	endpoint, err := url.JoinPath(address, "eth/v1/validator/liveness", strconv.FormatUint(uint64(epoch), 10))
+	if err != nil {
+		return nil, fmt.Errorf("invalid beacon node address: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := gc.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(msg))
+	}
+
+	var respJSON struct {
+		Data []validatorLivenessJSON `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&respJSON); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	liveness := make([]ValidatorLiveness, 0, len(respJSON.Data))
+	for _, entry := range respJSON.Data {
+		index, err := strconv.ParseUint(entry.Index, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid validator index %q: %w", entry.Index, err)
+		}
+		liveness = append(liveness, ValidatorLiveness{
+			Index:  phase0.ValidatorIndex(index),
+			IsLive: entry.IsLive,
+		})
+	}
+	return liveness, nil
+}
@@ -6,16 +6,22 @@ import (
 	"fmt"
 	"hash"
 	"sync"
+	"time"
 
+	eth2client "github.com/attestantio/go-eth2-client"
 	"github.com/attestantio/go-eth2-client/api"
+	eth2clienthttp "github.com/attestantio/go-eth2-client/http"
 	"github.com/attestantio/go-eth2-client/spec/phase0"
 	ssz "github.com/ferranbt/fastssz"
 	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
 	spectypes "github.com/ssvlabs/ssv-spec/types"
+
+	beaconprotocol "github.com/ssvlabs/ssv/protocol/v2/blockchain/beacon"
 )
 
 func (gc *GoClient) computeVoluntaryExitDomain(ctx context.Context) (phase0.Domain, error) {
-	specResponse, err := gc.client.Spec(gc.ctx, &api.SpecOpts{})
+	specResponse, err := gc.multiClient.Spec(gc.ctx, &api.SpecOpts{})
 	if err != nil {
 		return phase0.Domain{}, fmt.Errorf("failed to obtain spec response: %w", err)
 	}
@@ -42,7 +48,7 @@ func (gc *GoClient) computeVoluntaryExitDomain(ctx context.Context) (phase0.Doma
 		CurrentVersion: forkVersion,
 	}
 
-	genesisResponse, err := gc.client.Genesis(ctx, &api.GenesisOpts{})
+	genesisResponse, err := gc.multiClient.Genesis(ctx, &api.GenesisOpts{})
 	if err != nil {
 		return phase0.Domain{}, fmt.Errorf("failed to obtain genesis response: %w", err)
 	}
@@ -84,7 +90,7 @@ func (gc *GoClient) DomainData(epoch phase0.Epoch, domain phase0.DomainType) (ph
 		return gc.computeVoluntaryExitDomain(gc.ctx)
 	}
 
-	data, err := gc.client.Domain(gc.ctx, domain, epoch)
+	data, err := gc.multiClient.Domain(gc.ctx, domain, epoch)
 	if err != nil {
 		return phase0.Domain{}, err
 	}
@@ -211,3 +217,81 @@ func ToBytes4(x []byte) [4]byte {
 	copy(y[:], x)
 	return y
 }
+
+// GenesisValidatorsRoot returns the genesis validators root of the chain.
+func (gc *GoClient) GenesisValidatorsRoot(ctx context.Context) (phase0.Root, error) {
+	genesisResponse, err := gc.multiClient.Genesis(ctx, &api.GenesisOpts{})
+	if err != nil {
+		return phase0.Root{}, fmt.Errorf("failed to obtain genesis response: %w", err)
+	}
+	if genesisResponse == nil || genesisResponse.Data == nil {
+		return phase0.Root{}, fmt.Errorf("genesis response is nil")
+	}
+	return genesisResponse.Data.GenesisValidatorsRoot, nil
+}
+
+// FetchGenesisValidatorsRoot fetches the genesis validators root from the first of the configured
+// beacon nodes which responds, for tools which don't run a GoClient.
+func FetchGenesisValidatorsRoot(ctx context.Context, opt beaconprotocol.Options) (phase0.Root, error) {
+	timeout := opt.CommonTimeout
+	if timeout == 0 {
+		timeout = DefaultCommonTimeout
+	}
+
+	err := fmt.Errorf("no beacon node address provided")
+	for _, address := range ParseBeaconNodeAddresses(opt.BeaconNodeAddr) {
+		var root phase0.Root
+		if root, err = fetchGenesisValidatorsRoot(ctx, address, timeout); err == nil {
+			return root, nil
+		}
+	}
+	return phase0.Root{}, err
+}
+
+func fetchGenesisValidatorsRoot(ctx context.Context, address string, timeout time.Duration) (phase0.Root, error) {
+	client, err := eth2clienthttp.New(ctx,
+		eth2clienthttp.WithAddress(address),
+		eth2clienthttp.WithLogLevel(zerolog.Disabled),
+		eth2clienthttp.WithTimeout(timeout),
+	)
+	if err != nil {
+		return phase0.Root{}, fmt.Errorf("failed to create http client for %s: %w", address, err)
+	}
+	genesisResponse, err := client.(eth2client.GenesisProvider).Genesis(ctx, &api.GenesisOpts{})
+	if err != nil {
+		return phase0.Root{}, fmt.Errorf("failed to obtain genesis response from %s: %w", address, err)
+	}
+	if genesisResponse == nil || genesisResponse.Data == nil {
+		return phase0.Root{}, fmt.Errorf("genesis response from %s is nil", address)
+	}
+	return genesisResponse.Data.GenesisValidatorsRoot, nil
+}
+
+// ForkAtEpoch returns the fork which is active at the given epoch.
+// The fork schedule is fetched once and cached.
+func (gc *GoClient) ForkAtEpoch(ctx context.Context, epoch phase0.Epoch) (*phase0.Fork, error) {
+	gc.forkScheduleMu.Lock()
+	defer gc.forkScheduleMu.Unlock()
+
+	if gc.forkSchedule == nil {
+		resp, err := gc.multiClient.ForkSchedule(ctx, &api.ForkScheduleOpts{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to obtain fork schedule: %w", err)
+		}
+		if resp == nil || len(resp.Data) == 0 {
+			return nil, fmt.Errorf("fork schedule response is empty")
+		}
+		gc.forkSchedule = resp.Data
+	}
+
+	var fork *phase0.Fork
+	for _, f := range gc.forkSchedule {
+		if f.Epoch <= epoch && (fork == nil || f.Epoch >= fork.Epoch) {
+			fork = f
+		}
+	}
+	if fork == nil {
+		return nil, fmt.Errorf("no fork is scheduled at epoch %d", epoch)
+	}
+	return fork, nil
+}
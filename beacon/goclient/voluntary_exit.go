@@ -1,9 +1,13 @@
 package goclient
 
 import (
+	"context"
+
 	"github.com/attestantio/go-eth2-client/spec/phase0"
 )
 
 func (gc *GoClient) SubmitVoluntaryExit(voluntaryExit *phase0.SignedVoluntaryExit) error {
-	return gc.client.SubmitVoluntaryExit(gc.ctx, voluntaryExit)
+	return gc.multiClientSubmit(gc.ctx, "SubmitVoluntaryExit", func(ctx context.Context, client Client) error {
+		return client.SubmitVoluntaryExit(ctx, voluntaryExit)
+	})
 }
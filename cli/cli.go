@@ -1,7 +1,10 @@
 package cli
 
 import (
+	"context"
 	"log"
+	"os/signal"
+	"syscall"
 
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
@@ -24,7 +27,16 @@ func Execute(appName, version string) {
 	RootCmd.Short = appName
 	RootCmd.Version = version
 
-	if err := RootCmd.Execute(); err != nil {
+	// The context of the commands is cancelled by the first SIGINT or SIGTERM, so that they can shut down
+	// gracefully, and a second one terminates them right away.
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	if err := RootCmd.ExecuteContext(ctx); err != nil {
 		log.Fatal("failed to execute root command", zap.Error(err))
 	}
 }
@@ -33,4 +45,9 @@ func init() {
 	RootCmd.AddCommand(bootnode.StartBootNodeCmd)
 	RootCmd.AddCommand(operator.StartNodeCmd)
 	RootCmd.AddCommand(operator.GenerateDocCmd)
+	RootCmd.AddCommand(operator.SlashingProtectionCmd)
+	RootCmd.AddCommand(operator.CompactDBCmd)
+	RootCmd.AddCommand(operator.RegistrySnapshotCmd)
+	RootCmd.AddCommand(operator.AuditRegistryCmd)
+	RootCmd.AddCommand(operator.ReplayEventsCmd)
 }
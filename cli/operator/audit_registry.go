@@ -0,0 +1,178 @@
+package operator
+
+import (
+	"fmt"
+	"log"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	global_config "github.com/ssvlabs/ssv/cli/config"
+	"github.com/ssvlabs/ssv/ekm"
+	"github.com/ssvlabs/ssv/eth/eventhandler"
+	"github.com/ssvlabs/ssv/eth/eventparser"
+	"github.com/ssvlabs/ssv/eth/executionclient"
+	"github.com/ssvlabs/ssv/networkconfig"
+	operatordatastore "github.com/ssvlabs/ssv/operator/datastore"
+	"github.com/ssvlabs/ssv/operator/keys"
+	operatorstorage "github.com/ssvlabs/ssv/operator/storage"
+	"github.com/ssvlabs/ssv/registry/audit"
+	registrystorage "github.com/ssvlabs/ssv/registry/storage"
+	"github.com/ssvlabs/ssv/storage/basedb"
+	"github.com/ssvlabs/ssv/utils/cliflag"
+)
+
+const (
+	auditRegistryFromBlockFlag = "from-block"
+	auditRegistryRepairFlag    = "repair"
+)
+
+// AuditRegistryCmd replays the registry contract events into an in-memory database and reports how the
+// node's registry state differs from it. It operates directly on the node's database, so the node must not
+// be running; a running node can be audited with the admin API instead.
+var AuditRegistryCmd = &cobra.Command{
+	Use:   "audit-registry",
+	Short: "Compares the registry state synced from the contract events with a replay of the events, and optionally repairs it",
+	Run: func(cmd *cobra.Command, args []string) {
+		logger, err := setupGlobal()
+		if err != nil {
+			log.Fatal("could not create logger", err)
+		}
+
+		networkConfig, err := networkconfig.GetNetworkConfigByName(cfg.SSVOptions.NetworkName)
+		if err != nil {
+			logger.Fatal("could not get network config", zap.Error(err))
+		}
+
+		cfg.DBOptions.Ctx = cmd.Context()
+		db, err := setupDB(logger, networkConfig.Beacon.GetNetwork())
+		if err != nil {
+			logger.Fatal("could not setup db", zap.Error(err))
+		}
+		defer func() {
+			if err := db.Close(); err != nil {
+				logger.Error("could not close db", zap.Error(err))
+			}
+		}()
+
+		operatorPrivKey, operatorPrivKeyText := setupOperatorKey(logger)
+		nodeStorage, operatorData := setupOperatorStorage(logger, db, operatorPrivKey, operatorPrivKeyText)
+
+		executionClient, err := executionclient.New(
+			cmd.Context(),
+			cfg.ExecutionClient.Addr,
+			ethcommon.HexToAddress(networkConfig.RegistryContractAddr),
+			executionclient.WithLogger(logger),
+			executionclient.WithConnectionTimeout(cfg.ExecutionClient.ConnectionTimeout),
+		)
+		if err != nil {
+			logger.Fatal("could not connect to execution client", zap.Error(err))
+		}
+		defer func() {
+			if err := executionClient.Close(); err != nil {
+				logger.Error("could not close execution client", zap.Error(err))
+			}
+		}()
+
+		auditor := setupRegistryAuditor(logger, networkConfig, executionClient, nodeStorage, operatorPrivKey, func() uint64 {
+			return operatorData.ID
+		})
+
+		fromBlock, _ := cmd.Flags().GetUint64(auditRegistryFromBlockFlag)
+		repair, _ := cmd.Flags().GetBool(auditRegistryRepairFlag)
+		report, err := auditor.Audit(cmd.Context(), fromBlock, repair)
+		if err != nil {
+			logger.Fatal("could not audit registry", zap.Error(err))
+		}
+		printAuditReport(report)
+	},
+}
+
+// setupRegistryAuditor returns an Auditor whose replays have their own operator data and key manager,
+// so that the node's ones are left untouched.
+func setupRegistryAuditor(
+	logger *zap.Logger,
+	networkConfig networkconfig.NetworkConfig,
+	executionClient *executionclient.ExecutionClient,
+	nodeStorage operatorstorage.Storage,
+	operatorPrivKey keys.OperatorPrivateKey,
+	ownOperatorID func() uint64,
+) *audit.Auditor {
+	eventFilterer, err := executionClient.Filterer()
+	if err != nil {
+		logger.Fatal("failed to set up event filterer", zap.Error(err))
+	}
+	eventParser := eventparser.New(eventFilterer)
+
+	encodedPubKey, err := operatorPrivKey.Public().Base64()
+	if err != nil {
+		logger.Fatal("could not encode public key", zap.Error(err))
+	}
+	ekmHashedKey, err := operatorPrivKey.EKMHash()
+	if err != nil {
+		logger.Fatal("could not get operator private key hash", zap.Error(err))
+	}
+
+	newHandler := func(db basedb.Database, store operatorstorage.Storage) (audit.EventHandler, error) {
+		keyManager, err := ekm.NewETHKeyManagerSigner(logger, db, networkConfig, ekmHashedKey)
+		if err != nil {
+			return nil, fmt.Errorf("could not create key manager: %w", err)
+		}
+		return eventhandler.New(
+			store,
+			eventParser,
+			nil,
+			networkConfig,
+			operatordatastore.New(&registrystorage.OperatorData{PublicKey: encodedPubKey}),
+			operatorPrivKey,
+			keyManager,
+			nil,
+			eventhandler.WithLogger(logger.Named("RegistryAudit")),
+		)
+	}
+	return audit.New(logger, nodeStorage, executionClient, newHandler, networkConfig.RegistrySyncOffset.Uint64(), ownOperatorID)
+}
+
+func printAuditReport(report *audit.Report) {
+	fmt.Printf("Audited registry events from block %d to %d", report.FromBlock, report.ToBlock)
+	if report.Partial {
+		fmt.Print(" (partial: only entities created after the from block were compared)")
+	}
+	fmt.Printf("\nOperators: %d, shares: %d, recipients: %d\n", report.Operators, report.Shares, report.Recipients)
+
+	if len(report.Discrepancies) == 0 {
+		fmt.Println("No discrepancies found")
+		return
+	}
+	fmt.Printf("Found %d discrepancies:\n", len(report.Discrepancies))
+	unrepaired := 0
+	for _, d := range report.Discrepancies {
+		fmt.Printf("- %s %s is %s\n", d.Kind, d.Key, d.Issue)
+		if d.Expected != "" {
+			fmt.Printf("    expected: %s\n", d.Expected)
+		}
+		if d.Actual != "" {
+			fmt.Printf("    actual:   %s\n", d.Actual)
+		}
+		switch {
+		case d.Repaired:
+			fmt.Printf("    repaired: %s\n", d.Action)
+		case d.Repairable:
+			fmt.Printf("    action:   %s (repairable with --%s)\n", d.Action, auditRegistryRepairFlag)
+			unrepaired++
+		default:
+			fmt.Printf("    action:   %s\n", d.Action)
+			unrepaired++
+		}
+	}
+	if unrepaired > 0 {
+		fmt.Printf("%d discrepancies are left\n", unrepaired)
+	}
+}
+
+func init() {
+	global_config.ProcessArgs(&cfg, &globalArgs, AuditRegistryCmd)
+	cliflag.AddPersistentIntFlag(AuditRegistryCmd, auditRegistryFromBlockFlag, 0, "Block to replay the registry events from, defaulting to the registry sync offset of the network", false)
+	cliflag.AddPersistentBoolFlag(AuditRegistryCmd, auditRegistryRepairFlag, false, "Repair the discrepancies which don't require syncing registry events from scratch", false)
+}
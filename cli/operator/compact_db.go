@@ -0,0 +1,86 @@
+package operator
+
+import (
+	"log"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	global_config "github.com/ssvlabs/ssv/cli/config"
+	"github.com/ssvlabs/ssv/exporter/convert"
+	ibftstorage "github.com/ssvlabs/ssv/ibft/storage"
+	"github.com/ssvlabs/ssv/logging/fields"
+	"github.com/ssvlabs/ssv/networkconfig"
+	qbftstorage "github.com/ssvlabs/ssv/protocol/v2/qbft/storage"
+	"github.com/ssvlabs/ssv/storage/kv"
+	"github.com/ssvlabs/ssv/utils/cliflag"
+)
+
+const compactDBWindowFlag = "window"
+
+// CompactDBCmd removes the decided instances and participants which are out of the retention window
+// from the node's database, and reclaims their disk space. It operates directly on the database,
+// so the node must not be running.
+var CompactDBCmd = &cobra.Command{
+	Use:   "compact-db",
+	Short: "Removes decided instances and participants out of the retention window and reclaims their disk space",
+	Run: func(cmd *cobra.Command, args []string) {
+		logger, err := setupGlobal()
+		if err != nil {
+			log.Fatal("could not create logger", err)
+		}
+
+		networkConfig, err := networkconfig.GetNetworkConfigByName(cfg.SSVOptions.NetworkName)
+		if err != nil {
+			logger.Fatal("could not get network config", zap.Error(err))
+		}
+
+		retentionConfig := cfg.QBFTRetention
+		if window, _ := cmd.Flags().GetString(compactDBWindowFlag); window != "" {
+			retentionConfig.Window = window
+		}
+		retention, err := ibftstorage.ParseRetention(retentionConfig, networkConfig.Beacon.SlotDurationSec())
+		if err != nil {
+			logger.Fatal("could not parse QBFT retention", zap.Error(err))
+		}
+		if len(retention) == 0 {
+			logger.Fatal("no retention window is configured, please set QBFTRetention or --" + compactDBWindowFlag)
+		}
+
+		cfg.DBOptions.Ctx = cmd.Context()
+		db, err := kv.New(logger, cfg.DBOptions)
+		if err != nil {
+			logger.Fatal("could not open db", zap.Error(err))
+		}
+		defer func() {
+			if err := db.Close(); err != nil {
+				logger.Error("could not close db", zap.Error(err))
+			}
+		}()
+
+		start := time.Now()
+		stores := ibftstorage.NewStoresFromRoles(db, ibftstorage.Roles...)
+		// The database may not have been migrated by a node of this version yet, so what isn't indexed by slot is indexed first.
+		err = stores.Each(func(role convert.RunnerRole, store qbftstorage.QBFTStore) error {
+			_, err := store.IndexSlots()
+			return err
+		})
+		if err != nil {
+			logger.Fatal("could not index decided instances", zap.Error(err))
+		}
+		removed, err := ibftstorage.NewPruner(logger, stores, retention, networkConfig.Beacon, nil).Prune()
+		if err != nil {
+			logger.Fatal("could not prune decided instances", zap.Error(err))
+		}
+		if err := db.FullGC(cmd.Context()); err != nil {
+			logger.Fatal("could not collect garbage", zap.Error(err))
+		}
+		logger.Info("compacted db", zap.Int("removed", removed), fields.Duration(start))
+	},
+}
+
+func init() {
+	global_config.ProcessArgs(&cfg, &globalArgs, CompactDBCmd)
+	cliflag.AddPersistentStringFlag(CompactDBCmd, compactDBWindowFlag, "", "Retention window of every role, as a duration (e.g. 720h) or a number of slots, overriding the configured one", false)
+}
@@ -12,6 +12,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/attestantio/go-eth2-client/spec/phase0"
 	ethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/ilyakaznacheev/cleanenv"
 	"github.com/pkg/errors"
@@ -26,6 +27,8 @@ import (
 	"github.com/ssvlabs/ssv/beacon/goclient/genesisgoclient"
 	global_config "github.com/ssvlabs/ssv/cli/config"
 	"github.com/ssvlabs/ssv/ekm"
+	"github.com/ssvlabs/ssv/ekm/slashinghistory"
+	"github.com/ssvlabs/ssv/ekm/web3signer"
 	"github.com/ssvlabs/ssv/eth/eventhandler"
 	"github.com/ssvlabs/ssv/eth/eventparser"
 	"github.com/ssvlabs/ssv/eth/eventsyncer"
@@ -33,7 +36,7 @@ import (
 	"github.com/ssvlabs/ssv/eth/localevents"
 	exporterapi "github.com/ssvlabs/ssv/exporter/api"
 	"github.com/ssvlabs/ssv/exporter/api/decided"
-	"github.com/ssvlabs/ssv/exporter/convert"
+	"github.com/ssvlabs/ssv/exporter/backfill"
 	genesisibftstorage "github.com/ssvlabs/ssv/ibft/genesisstorage"
 	ibftstorage "github.com/ssvlabs/ssv/ibft/storage"
 	ssv_identity "github.com/ssvlabs/ssv/identity"
@@ -47,19 +50,29 @@ import (
 	"github.com/ssvlabs/ssv/monitoring/metricsreporter"
 	"github.com/ssvlabs/ssv/network"
 	p2pv1 "github.com/ssvlabs/ssv/network/p2p"
+	"github.com/ssvlabs/ssv/network/peers"
 	"github.com/ssvlabs/ssv/networkconfig"
 	"github.com/ssvlabs/ssv/nodeprobe"
 	"github.com/ssvlabs/ssv/operator"
+	"github.com/ssvlabs/ssv/operator/clusterhealth"
 	operatordatastore "github.com/ssvlabs/ssv/operator/datastore"
+	"github.com/ssvlabs/ssv/operator/doppelganger"
 	"github.com/ssvlabs/ssv/operator/duties/dutystore"
+	"github.com/ssvlabs/ssv/operator/dutyhistory"
+	"github.com/ssvlabs/ssv/operator/eventstream"
 	"github.com/ssvlabs/ssv/operator/keys"
 	"github.com/ssvlabs/ssv/operator/keystore"
+	"github.com/ssvlabs/ssv/operator/performance"
 	"github.com/ssvlabs/ssv/operator/slotticker"
 	operatorstorage "github.com/ssvlabs/ssv/operator/storage"
 	"github.com/ssvlabs/ssv/operator/validator"
 	"github.com/ssvlabs/ssv/operator/validators"
 	genesisssvtypes "github.com/ssvlabs/ssv/protocol/genesis/types"
 	beaconprotocol "github.com/ssvlabs/ssv/protocol/v2/blockchain/beacon"
+	"github.com/ssvlabs/ssv/protocol/v2/qbft/roundtimer"
+	qbftstorage "github.com/ssvlabs/ssv/protocol/v2/qbft/storage"
+	"github.com/ssvlabs/ssv/protocol/v2/ssv/queue"
+	"github.com/ssvlabs/ssv/protocol/v2/ssv/runner"
 	"github.com/ssvlabs/ssv/protocol/v2/types"
 	registrystorage "github.com/ssvlabs/ssv/registry/storage"
 	"github.com/ssvlabs/ssv/storage/basedb"
@@ -90,7 +103,20 @@ type config struct {
 	WsAPIPort                  int                              `yaml:"WebSocketAPIPort" env:"WS_API_PORT" env-description:"Port to listen on for the websocket API."`
 	WithPing                   bool                             `yaml:"WithPing" env:"WITH_PING" env-description:"Whether to send websocket ping messages'"`
 	SSVAPIPort                 int                              `yaml:"SSVAPIPort" env:"SSV_API_PORT" env-description:"Port to listen on for the SSV API."`
+	SSVAPIAdmin                bool                             `yaml:"SSVAPIAdmin" env:"SSV_API_ADMIN" env-description:"Serve the admin routes of the SSV API, which requires SSVAPIAuth to configure a bearer token or a client CA"`
+	SSVAPIAuth                 apiserver.AuthConfig             `yaml:"SSVAPIAuth"`
 	LocalEventsPath            string                           `yaml:"LocalEventsPath" env:"EVENTS_PATH" env-description:"path to local events"`
+	LocalEventsReloadInterval  time.Duration                    `yaml:"LocalEventsReloadInterval" env:"EVENTS_RELOAD_INTERVAL" env-default:"0" env-description:"Interval of checking the local events file for changes to process while running. Set to 0 to disable."`
+	Doppelganger               doppelganger.Config              `yaml:"Doppelganger"`
+	SlashingHistory            slashinghistory.Config           `yaml:"SlashingHistory"`
+	DutyHistory                dutyhistory.Config               `yaml:"DutyHistory"`
+	ClusterHealth              clusterhealth.Config             `yaml:"ClusterHealth"`
+	MessageValidation          validation.Config                `yaml:"MessageValidation"`
+	SignatureVerification      signatureverifier.Config         `yaml:"SignatureVerification"`
+	MessageQueues              queue.Config                     `yaml:"MessageQueues"`
+	RoundTimeouts              roundtimer.Config                `yaml:"RoundTimeouts"`
+	QBFTRetention              ibftstorage.RetentionConfig      `yaml:"QBFTRetention"`
+	RemoteSigner               web3signer.Config                `yaml:"RemoteSigner"`
 }
 
 var cfg config
@@ -129,38 +155,7 @@ var StartNodeCmd = &cobra.Command{
 			logger.Fatal("could not setup db", zap.Error(err))
 		}
 
-		var operatorPrivKey keys.OperatorPrivateKey
-		var operatorPrivKeyText string
-		if cfg.KeyStore.PrivateKeyFile != "" {
-			// nolint: gosec
-			encryptedJSON, err := os.ReadFile(cfg.KeyStore.PrivateKeyFile)
-			if err != nil {
-				logger.Fatal("could not read PEM file", zap.Error(err))
-			}
-
-			// nolint: gosec
-			keyStorePassword, err := os.ReadFile(cfg.KeyStore.PasswordFile)
-			if err != nil {
-				logger.Fatal("could not read password file", zap.Error(err))
-			}
-
-			decryptedKeystore, err := keystore.DecryptKeystore(encryptedJSON, string(keyStorePassword))
-			if err != nil {
-				logger.Fatal("could not decrypt operator private key keystore", zap.Error(err))
-			}
-			operatorPrivKey, err = keys.PrivateKeyFromBytes(decryptedKeystore)
-			if err != nil {
-				logger.Fatal("could not extract operator private key from file", zap.Error(err))
-			}
-
-			operatorPrivKeyText = base64.StdEncoding.EncodeToString(decryptedKeystore)
-		} else {
-			operatorPrivKey, err = keys.PrivateKeyFromString(cfg.OperatorPrivateKey)
-			if err != nil {
-				logger.Fatal("could not decode operator private key", zap.Error(err))
-			}
-			operatorPrivKeyText = cfg.OperatorPrivateKey
-		}
+		operatorPrivKey, operatorPrivKeyText := setupOperatorKey(logger)
 		cfg.P2pNetworkConfig.OperatorSigner = operatorPrivKey
 
 		nodeStorage, operatorData := setupOperatorStorage(logger, db, operatorPrivKey, operatorPrivKeyText)
@@ -177,11 +172,6 @@ var StartNodeCmd = &cobra.Command{
 			logger.Fatal("could not get operator private key hash", zap.Error(err))
 		}
 
-		keyManager, err := ekm.NewETHKeyManagerSigner(logger, db, networkConfig, ekmHashedKey)
-		if err != nil {
-			logger.Fatal("could not create new eth-key-manager signer", zap.Error(err))
-		}
-
 		cfg.P2pNetworkConfig.Ctx = cmd.Context()
 
 		slotTickerProvider := func() slotticker.SlotTicker {
@@ -197,6 +187,27 @@ var StartNodeCmd = &cobra.Command{
 
 		consensusClient := setupConsensusClient(logger, operatorDataStore, slotTickerProvider)
 
+		var ekmOptions []ekm.Option
+		var doppelgangerHandler doppelganger.Handler
+		if cfg.Doppelganger.Enabled {
+			doppelgangerHandler = doppelganger.NewHandler(&doppelganger.Options{
+				Logger:             logger,
+				Network:            networkConfig.Beacon.GetNetwork(),
+				BeaconNode:         consensusClient,
+				SlotTickerProvider: slotTickerProvider,
+				Epochs:             cfg.Doppelganger.Epochs,
+			})
+			go doppelgangerHandler.Start(cmd.Context())
+			ekmOptions = append(ekmOptions, ekm.WithDoppelgangerProtection(doppelgangerHandler))
+		}
+		var slashingHistory *slashinghistory.History
+		if cfg.SlashingHistory.Enabled {
+			slashingHistory = slashinghistory.New(db, networkConfig.Beacon, cfg.SlashingHistory.RetentionEpochs)
+			ekmOptions = append(ekmOptions, ekm.WithSlashingHistory(slashingHistory))
+		}
+
+		keyManager := setupKeyManager(cmd.Context(), logger, db, networkConfig, ekmHashedKey, consensusClient, ekmOptions)
+
 		executionClient, err := executionclient.New(
 			cmd.Context(),
 			cfg.ExecutionClient.Addr,
@@ -224,19 +235,63 @@ var StartNodeCmd = &cobra.Command{
 		cfg.SSVOptions.DutyStore = dutyStore
 
 		signatureVerifier := signatureverifier.NewSignatureVerifier(nodeStorage)
+		if cfg.SignatureVerification.Batched {
+			batchOpts := []signatureverifier.BatchOption{
+				signatureverifier.WithMetrics(metricsReporter),
+			}
+			if cfg.SignatureVerification.BatchWindow > 0 {
+				batchOpts = append(batchOpts, signatureverifier.WithBatchWindow(cfg.SignatureVerification.BatchWindow))
+			}
+			if cfg.SignatureVerification.MaxBatchSize > 0 {
+				batchOpts = append(batchOpts, signatureverifier.WithMaxBatchSize(cfg.SignatureVerification.MaxBatchSize))
+			}
+			if cfg.SignatureVerification.Workers > 0 {
+				batchOpts = append(batchOpts, signatureverifier.WithWorkers(cfg.SignatureVerification.Workers))
+			}
+			batchVerifier := signatureverifier.NewBatchVerifier(nodeStorage, batchOpts...)
+			go batchVerifier.Run(cmd.Context())
+			signatureVerifier = batchVerifier
+		}
 
 		validatorStore := nodeStorage.ValidatorStore()
 
 		var messageValidator validation.MessageValidator
 
+		ruleModes, err := validation.ParseRuleModes(cfg.MessageValidation.RuleModes)
+		if err != nil {
+			logger.Fatal("invalid message validation rule modes", zap.Error(err))
+		}
+
+		validationOpts := []validation.Option{
+			validation.WithLogger(logger),
+			validation.WithMetrics(metricsReporter),
+			validation.WithRuleModes(ruleModes),
+			validation.WithStateStore(db),
+		}
+		if cfg.P2pNetworkConfig.PrivateCluster {
+			// Peers of a private cluster are trusted, so their invalid messages are ignored without penalizing them.
+			validationOpts = append(validationOpts, validation.WithDefaultRuleMode(validation.RuleModeIgnore))
+		}
 		alanMsgValidator := validation.New(
 			networkConfig,
 			validatorStore,
 			dutyStore,
 			signatureVerifier,
-			validation.WithLogger(logger),
-			validation.WithMetrics(metricsReporter),
+			validationOpts...,
 		)
+		// stateSaved is closed once the message validation state is saved on shutdown.
+		stateSaved := make(chan struct{})
+		if persister, ok := alanMsgValidator.(validation.StatePersister); ok {
+			if err := persister.RestoreState(); err != nil {
+				logger.Warn("could not restore message validation state", zap.Error(err))
+			}
+			go func() {
+				defer close(stateSaved)
+				persister.PersistState(cmd.Context())
+			}()
+		} else {
+			close(stateSaved)
+		}
 
 		if networkConfig.PastAlanFork() {
 			messageValidator = alanMsgValidator
@@ -275,6 +330,21 @@ var StartNodeCmd = &cobra.Command{
 		cfg.SSVOptions.ValidatorOptions.GenesisBeacon = genesisgoclient.NewAdapter(consensusClient)
 		cfg.SSVOptions.ValidatorOptions.BeaconSigner = keyManager
 		cfg.SSVOptions.ValidatorOptions.ValidatorsMap = validatorsMap
+
+		queueDropPolicy, err := queue.ParseDropPolicy(cfg.MessageQueues.DropPolicy)
+		if err != nil {
+			logger.Fatal("invalid message queue drop policy", zap.Error(err))
+		}
+		cfg.SSVOptions.ValidatorOptions.QueueDropPolicy = queueDropPolicy
+
+		timeoutPolicy, err := roundtimer.NewPolicy(cfg.RoundTimeouts)
+		if err != nil {
+			logger.Fatal("invalid round timeouts", zap.Error(err))
+		}
+		cfg.SSVOptions.ValidatorOptions.TimeoutPolicy = timeoutPolicy
+
+		performanceTracker := performance.NewTracker()
+		cfg.SSVOptions.ValidatorOptions.PerformanceRecorder = performanceTracker
 		cfg.SSVOptions.ValidatorOptions.NetworkConfig = networkConfig
 
 		cfg.SSVOptions.ValidatorOptions.OperatorDataStore = operatorDataStore
@@ -293,23 +363,19 @@ var StartNodeCmd = &cobra.Command{
 
 		cfg.SSVOptions.ValidatorOptions.DutyRoles = []spectypes.BeaconRole{spectypes.BNRoleAttester} // TODO could be better to set in other place
 
-		storageRoles := []convert.RunnerRole{
-			convert.RoleCommittee,
-			convert.RoleAttester,
-			convert.RoleProposer,
-			convert.RoleSyncCommittee,
-			convert.RoleAggregator,
-			convert.RoleSyncCommitteeContribution,
-			convert.RoleValidatorRegistration,
-			convert.RoleVoluntaryExit,
-		}
-
 		storageMap := ibftstorage.NewStores()
 
-		for _, storageRole := range storageRoles {
+		for _, storageRole := range ibftstorage.Roles {
 			storageMap.Add(storageRole, ibftstorage.New(cfg.SSVOptions.ValidatorOptions.DB, storageRole.String()))
 		}
 
+		retention, err := ibftstorage.ParseRetention(cfg.QBFTRetention, networkConfig.Beacon.SlotDurationSec())
+		if err != nil {
+			logger.Fatal("failed to parse QBFT retention", zap.Error(err))
+		}
+		pruner := ibftstorage.NewPruner(logger, storageMap, retention, networkConfig.Beacon, db)
+		go pruner.Run(cmd.Context(), cfg.QBFTRetention.Interval)
+
 		genesisStorageRoles := []genesisspectypes.BeaconRole{
 			genesisspectypes.BNRoleAttester,
 			genesisspectypes.BNRoleAggregator,
@@ -331,6 +397,31 @@ var StartNodeCmd = &cobra.Command{
 		cfg.SSVOptions.ValidatorOptions.Graffiti = []byte(cfg.Graffiti)
 		cfg.SSVOptions.ValidatorOptions.ValidatorStore = nodeStorage.ValidatorStore()
 		cfg.SSVOptions.ValidatorOptions.OperatorSigner = types.NewSsvOperatorSigner(operatorPrivKey, operatorDataStore.GetOperatorID)
+		cfg.SSVOptions.ValidatorOptions.DoppelgangerHandler = doppelgangerHandler
+
+		eventStream := eventstream.New(logger, nodeStorage.ValidatorStore(), eventstream.DefaultHistorySize)
+		if wsDecidedHandler := cfg.SSVOptions.ValidatorOptions.NewDecidedHandler; wsDecidedHandler != nil {
+			cfg.SSVOptions.ValidatorOptions.NewDecidedHandler = func(msg qbftstorage.ParticipantsRangeEntry) {
+				wsDecidedHandler(msg)
+				eventStream.HandleDecided(msg)
+			}
+		} else {
+			cfg.SSVOptions.ValidatorOptions.NewDecidedHandler = eventStream.HandleDecided
+		}
+		dutyRecorders := runner.DutyRecorders{eventStream}
+		if cfg.ClusterHealth.Enabled {
+			participantsRecorder := clusterhealth.NewParticipantsRecorder(logger, networkConfig.DomainType(), storageMap)
+			go participantsRecorder.Run(cmd.Context())
+			dutyRecorders = append(dutyRecorders, participantsRecorder)
+		}
+
+		var dutyHistory *dutyhistory.Store
+		if cfg.DutyHistory.Enabled {
+			dutyHistory = dutyhistory.New(logger, db, networkConfig.Beacon, cfg.DutyHistory.RetentionEpochs)
+			dutyRecorders = append(dutyRecorders, dutyHistory)
+			go dutyHistory.Run(cmd.Context())
+		}
+		cfg.SSVOptions.ValidatorOptions.DutyRecorder = dutyRecorders
 		cfg.SSVOptions.Metrics = metricsReporter
 
 		cfg.SSVOptions.ValidatorOptions.GenesisControllerOptions.StorageMap = genesisStorageMap
@@ -378,6 +469,7 @@ var StartNodeCmd = &cobra.Command{
 			operatorDataStore,
 			operatorPrivKey,
 			keyManager,
+			eventStream,
 		)
 		if len(cfg.LocalEventsPath) == 0 {
 			nodeProber.AddNode("event syncer", eventSyncer)
@@ -393,7 +485,39 @@ var StartNodeCmd = &cobra.Command{
 			logger.Fatal("failed to start network", zap.Error(err))
 		}
 
+		host := p2pNetwork.(p2pv1.HostProvider).Host()
+		streamCtrl := p2pNetwork.(p2pv1.StreamControllerProvider).StreamController()
+		if cfg.SSVOptions.ValidatorOptions.FullNode {
+			host.SetStreamHandler(backfill.Protocol, backfill.NewServer(streamCtrl, storageMap).Handler(logger))
+		}
+		backfiller := backfill.NewBackfiller(
+			logger,
+			streamCtrl,
+			host.Network().Peers,
+			storageMap,
+			validatorStore,
+			consensusClient,
+			networkConfig.Beacon,
+			signatureVerifier,
+			networkConfig.DomainType(),
+		)
+
 		if cfg.SSVAPIPort > 0 {
+			var apiOptions []apiserver.Option
+			if cfg.SSVAPIAdmin {
+				apiOptions = append(apiOptions, apiserver.WithAdmin(
+					&handlers.Admin{
+						Shares:            nodeStorage.Shares(),
+						MetadataRefresher: validatorCtrl,
+						EventResyncer:     eventSyncer,
+						Drainer:           validatorCtrl,
+						Backfiller:        backfiller,
+						PeerBanner:        p2pNetwork.(p2pv1.PeerBanner),
+						RegistryAuditor:   setupRegistryAuditor(logger, networkConfig, executionClient, nodeStorage, operatorPrivKey, operatorDataStore.GetOperatorID),
+					},
+					cfg.SSVAPIAuth,
+				))
+			}
 			apiServer := apiserver.New(
 				logger,
 				fmt.Sprintf(":%d", cfg.SSVAPIPort),
@@ -408,6 +532,30 @@ var StartNodeCmd = &cobra.Command{
 				&handlers.Validators{
 					Shares: nodeStorage.Shares(),
 				},
+				&handlers.Doppelganger{
+					Handler: doppelgangerHandler,
+				},
+				&handlers.SlashingProtection{
+					Store:                 keyManager.(ekm.SlashingProtectionProvider).SlashingProtection(),
+					GenesisValidatorsRoot: genesisValidatorsRoot(cmd.Context(), logger, consensusClient),
+					SlashingHistory:       slashingHistory,
+					Shares:                nodeStorage.Shares(),
+				},
+				&handlers.DutyHistory{
+					Store: dutyHistory,
+				},
+				&handlers.Events{
+					Stream: eventStream,
+				},
+				&handlers.Clusters{
+					Validators:  nodeStorage.ValidatorStore(),
+					Checker:     clusterhealth.NewChecker(networkConfig.Beacon, networkConfig.DomainType(), storageMap),
+					QueueDumper: validatorsMap,
+				},
+				&handlers.Operators{
+					Tracker: performanceTracker,
+				},
+				apiOptions...,
 			)
 			go func() {
 				err := apiServer.Run()
@@ -419,6 +567,13 @@ var StartNodeCmd = &cobra.Command{
 		if err := operatorNode.Start(logger); err != nil {
 			logger.Fatal("failed to start SSV node", zap.Error(err))
 		}
+
+		// The node stops once the context is cancelled by SIGINT or SIGTERM.
+		logger.Info("shutting down")
+		<-stateSaved
+		if err := db.Close(); err != nil {
+			logger.Error("could not close db", zap.Error(err))
+		}
 	},
 }
 
@@ -531,6 +686,43 @@ func setupDB(logger *zap.Logger, eth2Network beaconprotocol.Network) (*kv.Badger
 	return db, nil
 }
 
+// setupOperatorKey loads the operator private key from the key store or the configuration,
+// returning it along with its base64 text.
+func setupOperatorKey(logger *zap.Logger) (operatorPrivKey keys.OperatorPrivateKey, operatorPrivKeyText string) {
+	var err error
+	if cfg.KeyStore.PrivateKeyFile != "" {
+		// nolint: gosec
+		encryptedJSON, err := os.ReadFile(cfg.KeyStore.PrivateKeyFile)
+		if err != nil {
+			logger.Fatal("could not read PEM file", zap.Error(err))
+		}
+
+		// nolint: gosec
+		keyStorePassword, err := os.ReadFile(cfg.KeyStore.PasswordFile)
+		if err != nil {
+			logger.Fatal("could not read password file", zap.Error(err))
+		}
+
+		decryptedKeystore, err := keystore.DecryptKeystore(encryptedJSON, string(keyStorePassword))
+		if err != nil {
+			logger.Fatal("could not decrypt operator private key keystore", zap.Error(err))
+		}
+		operatorPrivKey, err = keys.PrivateKeyFromBytes(decryptedKeystore)
+		if err != nil {
+			logger.Fatal("could not extract operator private key from file", zap.Error(err))
+		}
+
+		operatorPrivKeyText = base64.StdEncoding.EncodeToString(decryptedKeystore)
+	} else {
+		operatorPrivKey, err = keys.PrivateKeyFromString(cfg.OperatorPrivateKey)
+		if err != nil {
+			logger.Fatal("could not decode operator private key", zap.Error(err))
+		}
+		operatorPrivKeyText = cfg.OperatorPrivateKey
+	}
+	return operatorPrivKey, operatorPrivKeyText
+}
+
 func setupOperatorStorage(logger *zap.Logger, db basedb.Database, configPrivKey keys.OperatorPrivateKey, configPrivKeyText string) (operatorstorage.Storage, *registrystorage.OperatorData) {
 	nodeStorage, err := operatorstorage.NewNodeStorage(logger, db)
 	if err != nil {
@@ -643,6 +835,12 @@ func setupP2P(logger *zap.Logger, db basedb.Database, mr metricsreporter.Metrics
 	}
 	cfg.P2pNetworkConfig.NetworkPrivateKey = netPrivKey
 
+	reputation, err := peers.NewReputation(db, cfg.P2pNetworkConfig.PeerScoreHalfLife)
+	if err != nil {
+		logger.Fatal("failed to load peer reputation", zap.Error(err))
+	}
+	cfg.P2pNetworkConfig.Reputation = reputation
+
 	n, err := p2pv1.New(logger, &cfg.P2pNetworkConfig, mr)
 	if err != nil {
 		logger.Fatal("failed to setup p2p network", zap.Error(err))
@@ -650,6 +848,41 @@ func setupP2P(logger *zap.Logger, db basedb.Database, mr metricsreporter.Metrics
 	return n, p2pv1.GenesisP2P{Network: n}
 }
 
+// setupKeyManager creates the remote signer KeyManager if a remote signer is configured,
+// and otherwise the KeyManager which keeps share keys in the database.
+func setupKeyManager(
+	ctx context.Context,
+	logger *zap.Logger,
+	db basedb.Database,
+	networkConfig networkconfig.NetworkConfig,
+	ekmHashedKey string,
+	consensusClient *goclient.GoClient,
+	ekmOptions []ekm.Option,
+) ekm.KeyManager {
+	if cfg.RemoteSigner.URL == "" {
+		keyManager, err := ekm.NewETHKeyManagerSigner(logger, db, networkConfig, ekmHashedKey, ekmOptions...)
+		if err != nil {
+			logger.Fatal("could not create new eth-key-manager signer", zap.Error(err))
+		}
+		return keyManager
+	}
+
+	httpClient, err := cfg.RemoteSigner.HTTPClient()
+	if err != nil {
+		logger.Fatal("could not create remote signer client", zap.Error(err))
+	}
+	client := web3signer.New(cfg.RemoteSigner.URL, web3signer.WithHTTPClient(httpClient))
+	if err := client.UpCheck(ctx); err != nil {
+		logger.Fatal("remote signer is not up", zap.Error(err))
+	}
+	keyManager, err := ekm.NewRemoteKeyManager(logger, db, networkConfig, client, consensusClient, ekmOptions...)
+	if err != nil {
+		logger.Fatal("could not create remote signer key manager", zap.Error(err))
+	}
+	logger.Info("using remote signer", zap.String("url", cfg.RemoteSigner.URL))
+	return keyManager
+}
+
 func setupConsensusClient(
 	logger *zap.Logger,
 	operatorDataStore operatordatastore.OperatorDataStore,
@@ -664,6 +897,15 @@ func setupConsensusClient(
 	return cl
 }
 
+// genesisValidatorsRoot fetches the genesis validators root from the consensus client.
+func genesisValidatorsRoot(ctx context.Context, logger *zap.Logger, consensusClient *goclient.GoClient) phase0.Root {
+	root, err := consensusClient.GenesisValidatorsRoot(ctx)
+	if err != nil {
+		logger.Fatal("could not get genesis validators root", zap.Error(err))
+	}
+	return root
+}
+
 func setupEventHandling(
 	ctx context.Context,
 	logger *zap.Logger,
@@ -675,6 +917,7 @@ func setupEventHandling(
 	operatorDataStore operatordatastore.OperatorDataStore,
 	operatorDecrypter keys.OperatorDecrypter,
 	keyManager ekm.KeyManager,
+	eventStream *eventstream.Stream,
 ) *eventsyncer.EventSyncer {
 	eventFilterer, err := executionClient.Filterer()
 	if err != nil {
@@ -695,6 +938,7 @@ func setupEventHandling(
 		eventhandler.WithFullNode(),
 		eventhandler.WithLogger(logger),
 		eventhandler.WithMetrics(metricsReporter),
+		eventhandler.WithEventStream(eventStream, executionClient),
 	)
 	if err != nil {
 		logger.Fatal("failed to setup event data handler", zap.Error(err))
@@ -728,9 +972,18 @@ func setupEventHandling(
 			logger.Fatal("failed to load local events", zap.Error(err))
 		}
 
-		if err := eventHandler.HandleLocalEvents(localEvents); err != nil {
+		if err := eventHandler.HandleLocalEvents(localEvents, false); err != nil {
 			logger.Fatal("error occurred while running event data handler", zap.Error(err))
 		}
+
+		// Process the events added to the file while running in the background.
+		if cfg.LocalEventsReloadInterval > 0 {
+			go localevents.Watch(ctx, logger, cfg.LocalEventsPath, cfg.LocalEventsReloadInterval, func(localEvents []localevents.Event) {
+				if err := eventHandler.HandleLocalEvents(localEvents, true); err != nil {
+					logger.Error("failed to handle reloaded local events", zap.Error(err))
+				}
+			})
+		}
 	} else {
 		// Sync historical registry events.
 		logger.Debug("syncing historical registry events", zap.Uint64("fromBlock", fromBlock.Uint64()))
@@ -0,0 +1,145 @@
+package operator
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	global_config "github.com/ssvlabs/ssv/cli/config"
+	"github.com/ssvlabs/ssv/networkconfig"
+	"github.com/ssvlabs/ssv/registry/snapshot"
+	"github.com/ssvlabs/ssv/storage/kv"
+	"github.com/ssvlabs/ssv/utils/cliflag"
+)
+
+const (
+	registrySnapshotFileFlag = "file"
+	registrySnapshotHashFlag = "hash"
+)
+
+// RegistrySnapshotCmd groups the registry snapshot commands, which let a new node
+// continue syncing registry events from the block of a snapshot exported by another node.
+// They operate directly on the node's database, so the node must not be running.
+var RegistrySnapshotCmd = &cobra.Command{
+	Use:   "registry-snapshot",
+	Short: "Export or import a signed snapshot of the registry state synced from the contract events",
+}
+
+var registrySnapshotExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Exports the registry state to a snapshot file signed by the operator key",
+	Run: func(cmd *cobra.Command, args []string) {
+		logger, networkConfig, db := setupRegistrySnapshot(cmd)
+		defer func() {
+			if err := db.Close(); err != nil {
+				logger.Error("could not close db", zap.Error(err))
+			}
+		}()
+
+		operatorPrivKey, operatorPrivKeyText := setupOperatorKey(logger)
+		nodeStorage, _ := setupOperatorStorage(logger, db, operatorPrivKey, operatorPrivKeyText)
+
+		exported, err := snapshot.Export(nodeStorage, networkConfig.Name)
+		if err != nil {
+			logger.Fatal("could not export registry snapshot", zap.Error(err))
+		}
+		file, err := snapshot.Sign(exported, operatorPrivKey)
+		if err != nil {
+			logger.Fatal("could not sign registry snapshot", zap.Error(err))
+		}
+		data, err := json.Marshal(file)
+		if err != nil {
+			logger.Fatal("could not marshal registry snapshot", zap.Error(err))
+		}
+
+		path, _ := cmd.Flags().GetString(registrySnapshotFileFlag)
+		if err := os.WriteFile(path, data, 0600); err != nil {
+			logger.Fatal("could not write registry snapshot file", zap.Error(err))
+		}
+		logger.Info("exported registry snapshot",
+			zap.String("file", path),
+			zap.String("hash", file.Hash),
+			zap.Uint64("block", exported.BlockNumber),
+			zap.Int("operators", len(exported.Operators)),
+			zap.Int("shares", len(exported.Shares)),
+		)
+	},
+}
+
+var registrySnapshotImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Imports a registry snapshot file into a node which didn't sync registry events yet",
+	Run: func(cmd *cobra.Command, args []string) {
+		logger, networkConfig, db := setupRegistrySnapshot(cmd)
+		defer func() {
+			if err := db.Close(); err != nil {
+				logger.Error("could not close db", zap.Error(err))
+			}
+		}()
+
+		trustedHash, _ := cmd.Flags().GetString(registrySnapshotHashFlag)
+		if trustedHash == "" {
+			logger.Fatal("the trusted hash of the snapshot is required, please set --" + registrySnapshotHashFlag)
+		}
+
+		path, _ := cmd.Flags().GetString(registrySnapshotFileFlag)
+		// nolint: gosec
+		data, err := os.ReadFile(path)
+		if err != nil {
+			logger.Fatal("could not read registry snapshot file", zap.Error(err))
+		}
+		var file snapshot.File
+		if err := json.Unmarshal(data, &file); err != nil {
+			logger.Fatal("could not parse registry snapshot file", zap.Error(err))
+		}
+		imported, err := file.Verify(trustedHash)
+		if err != nil {
+			logger.Fatal("could not verify registry snapshot", zap.Error(err))
+		}
+
+		operatorPrivKey, operatorPrivKeyText := setupOperatorKey(logger)
+		nodeStorage, operatorData := setupOperatorStorage(logger, db, operatorPrivKey, operatorPrivKeyText)
+		if err := snapshot.Import(nodeStorage, imported, networkConfig.Name, operatorData.PublicKey); err != nil {
+			logger.Fatal("could not import registry snapshot", zap.Error(err))
+		}
+		logger.Info("imported registry snapshot",
+			zap.String("file", path),
+			zap.String("signer", file.PublicKey),
+			zap.Uint64("block", imported.BlockNumber),
+			zap.Int("operators", len(imported.Operators)),
+			zap.Int("shares", len(imported.Shares)),
+		)
+	},
+}
+
+// setupRegistrySnapshot opens the node's database from the configuration without running the node.
+func setupRegistrySnapshot(cmd *cobra.Command) (*zap.Logger, networkconfig.NetworkConfig, *kv.BadgerDB) {
+	logger, err := setupGlobal()
+	if err != nil {
+		log.Fatal("could not create logger", err)
+	}
+
+	networkConfig, err := networkconfig.GetNetworkConfigByName(cfg.SSVOptions.NetworkName)
+	if err != nil {
+		logger.Fatal("could not get network config", zap.Error(err))
+	}
+
+	cfg.DBOptions.Ctx = cmd.Context()
+	db, err := setupDB(logger, networkConfig.Beacon.GetNetwork())
+	if err != nil {
+		logger.Fatal("could not setup db", zap.Error(err))
+	}
+	return logger, networkConfig, db
+}
+
+func init() {
+	global_config.ProcessArgs(&cfg, &globalArgs, RegistrySnapshotCmd)
+	cliflag.AddPersistentStringFlag(RegistrySnapshotCmd, registrySnapshotFileFlag, "registry_snapshot.json", "Path to the registry snapshot file", false)
+	cliflag.AddPersistentStringFlag(registrySnapshotImportCmd, registrySnapshotHashFlag, "", "Trusted SHA-256 hash of the snapshot, as printed by the export command", false)
+
+	RegistrySnapshotCmd.AddCommand(registrySnapshotExportCmd)
+	RegistrySnapshotCmd.AddCommand(registrySnapshotImportCmd)
+}
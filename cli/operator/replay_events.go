@@ -0,0 +1,406 @@
+package operator
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/spf13/cobra"
+	spectypes "github.com/ssvlabs/ssv-spec/types"
+	"go.uber.org/zap"
+
+	global_config "github.com/ssvlabs/ssv/cli/config"
+	"github.com/ssvlabs/ssv/ekm"
+	"github.com/ssvlabs/ssv/eth/contract"
+	"github.com/ssvlabs/ssv/eth/eventhandler"
+	"github.com/ssvlabs/ssv/eth/eventparser"
+	"github.com/ssvlabs/ssv/eth/executionclient"
+	"github.com/ssvlabs/ssv/networkconfig"
+	operatordatastore "github.com/ssvlabs/ssv/operator/datastore"
+	operatorstorage "github.com/ssvlabs/ssv/operator/storage"
+	ssvtypes "github.com/ssvlabs/ssv/protocol/v2/types"
+	"github.com/ssvlabs/ssv/registry/snapshot"
+	registrystorage "github.com/ssvlabs/ssv/registry/storage"
+	"github.com/ssvlabs/ssv/storage/basedb"
+	"github.com/ssvlabs/ssv/storage/kv"
+	"github.com/ssvlabs/ssv/utils/cliflag"
+)
+
+const (
+	replayEventsFromBlockFlag    = "from-block"
+	replayEventsToBlockFlag      = "to-block"
+	replayEventsLogsFlag         = "logs"
+	replayEventsSaveLogsFlag     = "save-logs"
+	replayEventsSnapshotFlag     = "snapshot"
+	replayEventsSnapshotHashFlag = "snapshot-hash"
+)
+
+// ReplayEventsCmd processes registry contract events of a block range or of a log file against a scratch
+// database, and prints the changes and tasks which the node would make of them without executing any.
+// The scratch database starts empty, or from a registry snapshot to predict the effect of events on the
+// current registry state.
+var ReplayEventsCmd = &cobra.Command{
+	Use:   "replay-events",
+	Short: "Dry-runs registry contract events of a block range or a log file and prints their effects",
+	Run: func(cmd *cobra.Command, args []string) {
+		logger, err := setupGlobal()
+		if err != nil {
+			log.Fatal("could not create logger", err)
+		}
+
+		networkConfig, err := networkconfig.GetNetworkConfigByName(cfg.SSVOptions.NetworkName)
+		if err != nil {
+			logger.Fatal("could not get network config", zap.Error(err))
+		}
+
+		logsPath, _ := cmd.Flags().GetString(replayEventsLogsFlag)
+		fromBlock, _ := cmd.Flags().GetUint64(replayEventsFromBlockFlag)
+		toBlock, _ := cmd.Flags().GetUint64(replayEventsToBlockFlag)
+		if (logsPath == "") == (toBlock == 0) {
+			logger.Fatal("either --" + replayEventsLogsFlag + " or --" + replayEventsToBlockFlag + " must be set")
+		}
+
+		db, err := kv.NewInMemory(logger, basedb.Options{Ctx: cmd.Context()})
+		if err != nil {
+			logger.Fatal("could not create scratch db", zap.Error(err))
+		}
+		defer func() {
+			if err := db.Close(); err != nil {
+				logger.Error("could not close scratch db", zap.Error(err))
+			}
+		}()
+		nodeStorage, err := operatorstorage.NewNodeStorage(logger, db)
+		if err != nil {
+			logger.Fatal("could not create scratch storage", zap.Error(err))
+		}
+
+		operatorPrivKey, _ := setupOperatorKey(logger)
+		encodedPubKey, err := operatorPrivKey.Public().Base64()
+		if err != nil {
+			logger.Fatal("could not encode public key", zap.Error(err))
+		}
+
+		// By default, the range starts from where the registry state of the scratch database ends.
+		minFromBlock := networkConfig.RegistrySyncOffset.Uint64()
+		if path, _ := cmd.Flags().GetString(replayEventsSnapshotFlag); path != "" {
+			minFromBlock = seedReplayStorage(cmd, logger, nodeStorage, path, networkConfig.Name) + 1
+		}
+		if logsPath == "" {
+			if fromBlock == 0 {
+				fromBlock = minFromBlock
+			}
+			if fromBlock < minFromBlock {
+				logger.Fatal("--"+replayEventsFromBlockFlag+" must not be before the registry state of the scratch database", zap.Uint64("min_from_block", minFromBlock))
+			}
+			if fromBlock > toBlock {
+				logger.Fatal("--" + replayEventsFromBlockFlag + " must not be after --" + replayEventsToBlockFlag)
+			}
+		}
+		operatorData, found, err := nodeStorage.GetOperatorDataByPubKey(nil, encodedPubKey)
+		if err != nil {
+			logger.Fatal("could not get operator data", zap.Error(err))
+		}
+		if !found {
+			operatorData = &registrystorage.OperatorData{PublicKey: encodedPubKey}
+		}
+		operatorDataStore := operatordatastore.New(operatorData)
+
+		var blocks <-chan executionclient.BlockLogs
+		var fetchErrors <-chan error
+		var filterer *contract.ContractFilterer
+		if logsPath != "" {
+			blocks = readReplayLogs(logger, logsPath)
+			filterer, err = contract.NewContractFilterer(ethcommon.HexToAddress(networkConfig.RegistryContractAddr), nil)
+			if err != nil {
+				logger.Fatal("failed to set up event filterer", zap.Error(err))
+			}
+		} else {
+			executionClient, err := executionclient.New(
+				cmd.Context(),
+				cfg.ExecutionClient.Addr,
+				ethcommon.HexToAddress(networkConfig.RegistryContractAddr),
+				executionclient.WithLogger(logger),
+				executionclient.WithConnectionTimeout(cfg.ExecutionClient.ConnectionTimeout),
+			)
+			if err != nil {
+				logger.Fatal("could not connect to execution client", zap.Error(err))
+			}
+			defer func() {
+				if err := executionClient.Close(); err != nil {
+					logger.Error("could not close execution client", zap.Error(err))
+				}
+			}()
+			filterer, err = executionClient.Filterer()
+			if err != nil {
+				logger.Fatal("failed to set up event filterer", zap.Error(err))
+			}
+			blocks, fetchErrors = executionClient.FetchLogs(cmd.Context(), fromBlock, toBlock)
+		}
+
+		ekmHashedKey, err := operatorPrivKey.EKMHash()
+		if err != nil {
+			logger.Fatal("could not get operator private key hash", zap.Error(err))
+		}
+		keyManager, err := ekm.NewETHKeyManagerSigner(logger, db, networkConfig, ekmHashedKey)
+		if err != nil {
+			logger.Fatal("could not create scratch key manager", zap.Error(err))
+		}
+
+		executor := &dryRunExecutor{}
+		events := &dryRunMetrics{}
+		eventHandler, err := eventhandler.New(
+			nodeStorage,
+			eventparser.New(filterer),
+			executor,
+			networkConfig,
+			operatorDataStore,
+			operatorPrivKey,
+			keyManager,
+			nil,
+			eventhandler.WithLogger(logger),
+			eventhandler.WithMetrics(events),
+		)
+		if err != nil {
+			logger.Fatal("failed to setup event handler", zap.Error(err))
+		}
+
+		var savedLogs []ethtypes.Log
+		savePath, _ := cmd.Flags().GetString(replayEventsSaveLogsFlag)
+		for block := range blocks {
+			if savePath != "" {
+				savedLogs = append(savedLogs, block.Logs...)
+			}
+			if len(block.Logs) == 0 {
+				continue
+			}
+
+			*events = dryRunMetrics{}
+			executor.effects = nil
+			single := make(chan executionclient.BlockLogs, 1)
+			single <- block
+			close(single)
+			if _, err := eventHandler.HandleBlockEventsStream(single, true); err != nil {
+				logger.Fatal("could not process block events", zap.Uint64("block", block.BlockNumber), zap.Error(err))
+			}
+			changes, err := eventHandler.Changes(block.BlockNumber)
+			if err != nil {
+				logger.Fatal("could not get block changes", zap.Uint64("block", block.BlockNumber), zap.Error(err))
+			}
+			printBlockEffects(block, events, changes, executor.effects, operatorDataStore.GetOperatorID())
+		}
+		if fetchErrors != nil {
+			if err := <-fetchErrors; err != nil {
+				logger.Fatal("could not fetch logs", zap.Error(err))
+			}
+		}
+
+		if savePath != "" {
+			data, err := json.Marshal(savedLogs)
+			if err != nil {
+				logger.Fatal("could not marshal logs", zap.Error(err))
+			}
+			if err := os.WriteFile(savePath, data, 0600); err != nil {
+				logger.Fatal("could not write logs file", zap.Error(err))
+			}
+			logger.Info("saved logs", zap.String("file", savePath), zap.Int("logs", len(savedLogs)))
+		}
+	},
+}
+
+// seedReplayStorage imports the registry snapshot file into the scratch storage, and returns its block.
+func seedReplayStorage(cmd *cobra.Command, logger *zap.Logger, nodeStorage operatorstorage.Storage, path, network string) uint64 {
+	trustedHash, _ := cmd.Flags().GetString(replayEventsSnapshotHashFlag)
+	if trustedHash == "" {
+		logger.Fatal("the trusted hash of the snapshot is required, please set --" + replayEventsSnapshotHashFlag)
+	}
+	// nolint: gosec
+	data, err := os.ReadFile(path)
+	if err != nil {
+		logger.Fatal("could not read registry snapshot file", zap.Error(err))
+	}
+	var file snapshot.File
+	if err := json.Unmarshal(data, &file); err != nil {
+		logger.Fatal("could not parse registry snapshot file", zap.Error(err))
+	}
+	imported, err := file.Verify(trustedHash)
+	if err != nil {
+		logger.Fatal("could not verify registry snapshot", zap.Error(err))
+	}
+	// Shares of this operator are allowed, since their secrets aren't needed to dry-run events.
+	if err := snapshot.Import(nodeStorage, imported, network, nil); err != nil {
+		logger.Fatal("could not import registry snapshot", zap.Error(err))
+	}
+	return imported.BlockNumber
+}
+
+// readReplayLogs reads a JSON array of logs, as returned by eth_getLogs or saved with --save-logs,
+// and returns them grouped by block.
+func readReplayLogs(logger *zap.Logger, path string) <-chan executionclient.BlockLogs {
+	// nolint: gosec
+	data, err := os.ReadFile(path)
+	if err != nil {
+		logger.Fatal("could not read logs file", zap.Error(err))
+	}
+	var logs []ethtypes.Log
+	if err := json.Unmarshal(data, &logs); err != nil {
+		logger.Fatal("could not parse logs file", zap.Error(err))
+	}
+	blocks := executionclient.PackLogs(logs)
+	ch := make(chan executionclient.BlockLogs, len(blocks))
+	for _, block := range blocks {
+		ch <- block
+	}
+	close(ch)
+	return ch
+}
+
+// dryRunMetrics counts the processed and failed events of a block.
+type dryRunMetrics struct {
+	processed int
+	failed    []string
+}
+
+func (m *dryRunMetrics) OperatorPublicKey(spectypes.OperatorID, []byte) {}
+func (m *dryRunMetrics) ValidatorInactive([]byte)                       {}
+func (m *dryRunMetrics) ValidatorError([]byte)                          {}
+func (m *dryRunMetrics) ValidatorRemoved([]byte)                        {}
+func (m *dryRunMetrics) EventProcessed(string)                          { m.processed++ }
+func (m *dryRunMetrics) EventProcessingFailed(eventName string) {
+	m.failed = append(m.failed, eventName)
+}
+
+// dryRunExecutor describes the tasks of the events instead of executing them.
+type dryRunExecutor struct {
+	effects []string
+}
+
+func (e *dryRunExecutor) StopValidator(pubKey spectypes.ValidatorPK) error {
+	e.effects = append(e.effects, fmt.Sprintf("stop validator %x", pubKey[:]))
+	return nil
+}
+
+func (e *dryRunExecutor) LiquidateCluster(owner ethcommon.Address, operatorIDs []uint64, toLiquidate []*ssvtypes.SSVShare) error {
+	e.effects = append(e.effects, fmt.Sprintf("liquidate cluster %v of owner %s, stopping %d validators: %s",
+		operatorIDs, owner.Hex(), len(toLiquidate), formatValidators(toLiquidate)))
+	return nil
+}
+
+func (e *dryRunExecutor) ReactivateCluster(owner ethcommon.Address, operatorIDs []uint64, toReactivate []*ssvtypes.SSVShare) error {
+	e.effects = append(e.effects, fmt.Sprintf("reactivate cluster %v of owner %s, starting %d validators: %s",
+		operatorIDs, owner.Hex(), len(toReactivate), formatValidators(toReactivate)))
+	return nil
+}
+
+func (e *dryRunExecutor) UpdateFeeRecipient(owner, recipient ethcommon.Address) error {
+	e.effects = append(e.effects, fmt.Sprintf("update the fee recipient of owner %s's validators to %s", owner.Hex(), recipient.Hex()))
+	return nil
+}
+
+func (e *dryRunExecutor) ExitValidator(pubKey phase0.BLSPubKey, blockNumber uint64, validatorIndex phase0.ValidatorIndex, ownValidator bool) error {
+	effect := fmt.Sprintf("exit validator %x with index %d", pubKey[:], validatorIndex)
+	if ownValidator {
+		effect += " (own validator: a voluntary exit is submitted)"
+	}
+	e.effects = append(e.effects, effect)
+	return nil
+}
+
+func printBlockEffects(
+	block executionclient.BlockLogs,
+	events *dryRunMetrics,
+	changes *eventhandler.BlockChanges,
+	tasks []string,
+	ownOperatorID spectypes.OperatorID,
+) {
+	fmt.Printf("Block %d: %d events processed", block.BlockNumber, events.processed)
+	if len(events.failed) > 0 {
+		fmt.Printf(", %d failed (%s), see the logs", len(events.failed), strings.Join(events.failed, ", "))
+	}
+	fmt.Println()
+
+	for _, change := range changes.Operators {
+		switch {
+		case change.Previous == nil:
+			fmt.Printf("  operator %d added: owner %s\n", change.ID, change.Current.OwnerAddress.Hex())
+		case change.Current == nil:
+			fmt.Printf("  operator %d removed\n", change.ID)
+		default:
+			fmt.Printf("  operator %d changed: owner %s\n", change.ID, change.Current.OwnerAddress.Hex())
+		}
+	}
+	for _, change := range changes.Shares {
+		pubKey := hex.EncodeToString(change.ValidatorPubKey[:])
+		share := change.Current
+		if share == nil {
+			share = change.Previous
+		}
+		own := share.BelongsToOperator(ownOperatorID)
+		switch {
+		case change.Previous == nil:
+			fmt.Printf("  validator %s added: %s\n", pubKey, formatReplayShare(share))
+			if own && !share.Liquidated {
+				fmt.Printf("    own validator: started\n")
+			}
+		case change.Current == nil:
+			fmt.Printf("  validator %s removed\n", pubKey)
+		case change.Previous.Liquidated != change.Current.Liquidated:
+			if change.Current.Liquidated {
+				fmt.Printf("  validator %s liquidated\n", pubKey)
+			} else {
+				fmt.Printf("  validator %s reactivated\n", pubKey)
+			}
+		default:
+			fmt.Printf("  validator %s changed: %s\n", pubKey, formatReplayShare(share))
+		}
+	}
+	for _, change := range changes.Recipients {
+		if change.Current == nil {
+			fmt.Printf("  recipient of owner %s removed\n", change.Owner.Hex())
+			continue
+		}
+		fmt.Printf("  recipient of owner %s: fee recipient %s%s\n", change.Owner.Hex(),
+			ethcommon.Address(change.Current.FeeRecipient).Hex(), formatNonce(change.Current.Nonce))
+	}
+	for _, task := range tasks {
+		fmt.Printf("  task: %s\n", task)
+	}
+}
+
+func formatReplayShare(share *ssvtypes.SSVShare) string {
+	signers := make([]spectypes.OperatorID, len(share.Committee))
+	for i, member := range share.Committee {
+		signers[i] = member.Signer
+	}
+	return fmt.Sprintf("owner %s, operators %v, liquidated %t", share.OwnerAddress.Hex(), signers, share.Liquidated)
+}
+
+func formatValidators(shares []*ssvtypes.SSVShare) string {
+	pubKeys := make([]string, len(shares))
+	for i, share := range shares {
+		pubKeys[i] = hex.EncodeToString(share.ValidatorPubKey[:])
+	}
+	return strings.Join(pubKeys, ", ")
+}
+
+func formatNonce(nonce *registrystorage.Nonce) string {
+	if nonce == nil {
+		return ""
+	}
+	return fmt.Sprintf(", nonce %d", *nonce)
+}
+
+func init() {
+	global_config.ProcessArgs(&cfg, &globalArgs, ReplayEventsCmd)
+	cliflag.AddPersistentIntFlag(ReplayEventsCmd, replayEventsFromBlockFlag, 0, "First block of the range to fetch events from, defaulting to the registry sync offset or the block after the snapshot", false)
+	cliflag.AddPersistentIntFlag(ReplayEventsCmd, replayEventsToBlockFlag, 0, "Last block of the range to fetch events from", false)
+	cliflag.AddPersistentStringFlag(ReplayEventsCmd, replayEventsLogsFlag, "", "Path to a JSON file of logs to replay instead of a block range, as returned by eth_getLogs", false)
+	cliflag.AddPersistentStringFlag(ReplayEventsCmd, replayEventsSaveLogsFlag, "", "Path to save the fetched logs to, for replaying them later with --"+replayEventsLogsFlag, false)
+	cliflag.AddPersistentStringFlag(ReplayEventsCmd, replayEventsSnapshotFlag, "", "Path to a registry snapshot file to start the scratch database from", false)
+	cliflag.AddPersistentStringFlag(ReplayEventsCmd, replayEventsSnapshotHashFlag, "", "Trusted SHA-256 hash of the registry snapshot", false)
+}
@@ -0,0 +1,167 @@
+package operator
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/ssvlabs/ssv/beacon/goclient"
+	global_config "github.com/ssvlabs/ssv/cli/config"
+	"github.com/ssvlabs/ssv/ekm"
+	"github.com/ssvlabs/ssv/ekm/interchange"
+	"github.com/ssvlabs/ssv/networkconfig"
+	operatorstorage "github.com/ssvlabs/ssv/operator/storage"
+	"github.com/ssvlabs/ssv/protocol/v2/types"
+	"github.com/ssvlabs/ssv/storage/kv"
+	"github.com/ssvlabs/ssv/utils/cliflag"
+)
+
+const (
+	slashingProtectionFileFlag                  = "file"
+	slashingProtectionGenesisValidatorsRootFlag = "genesis-validators-root"
+)
+
+// SlashingProtectionCmd groups the slashing protection interchange commands.
+// They operate directly on the node's database, so the node must not be running.
+var SlashingProtectionCmd = &cobra.Command{
+	Use:   "slashing-protection",
+	Short: "Import or export slashing protection data in the EIP-3076 interchange format",
+}
+
+var slashingProtectionExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Exports the slashing protection data of the operator's shares to an EIP-3076 interchange file of their validators",
+	Run: func(cmd *cobra.Command, args []string) {
+		logger, store, keys, genesisValidatorsRoot, closeDB := setupSlashingProtection(cmd)
+		defer closeDB()
+
+		exported, err := interchange.Export(store, keys, genesisValidatorsRoot)
+		if err != nil {
+			logger.Fatal("could not export slashing protection data", zap.Error(err))
+		}
+		data, err := json.MarshalIndent(exported, "", "  ")
+		if err != nil {
+			logger.Fatal("could not marshal interchange", zap.Error(err))
+		}
+
+		path, _ := cmd.Flags().GetString(slashingProtectionFileFlag)
+		if err := os.WriteFile(path, data, 0600); err != nil {
+			logger.Fatal("could not write interchange file", zap.Error(err))
+		}
+		logger.Info("exported slashing protection data",
+			zap.String("file", path),
+			zap.Int("validators", len(exported.Data)),
+		)
+	},
+}
+
+var slashingProtectionImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Imports an EIP-3076 interchange file, keeping the higher watermark of the operator's share of each validator",
+	Run: func(cmd *cobra.Command, args []string) {
+		logger, store, keys, genesisValidatorsRoot, closeDB := setupSlashingProtection(cmd)
+		defer closeDB()
+
+		path, _ := cmd.Flags().GetString(slashingProtectionFileFlag)
+		// nolint: gosec
+		data, err := os.ReadFile(path)
+		if err != nil {
+			logger.Fatal("could not read interchange file", zap.Error(err))
+		}
+		var imported interchange.Interchange
+		if err := json.Unmarshal(data, &imported); err != nil {
+			logger.Fatal("could not parse interchange file", zap.Error(err))
+		}
+
+		result, err := interchange.Import(store, keys, &imported, genesisValidatorsRoot)
+		if err != nil {
+			logger.Fatal("could not import slashing protection data", zap.Error(err))
+		}
+		logger.Info("imported slashing protection data",
+			zap.String("file", path),
+			zap.Int("attestations", result.Attestations),
+			zap.Int("proposals", result.Proposals),
+			zap.Int("unchanged", result.Unchanged),
+			zap.Int("unknown", result.Unknown),
+		)
+	},
+}
+
+// setupSlashingProtection opens the node's database from the configuration without running the node.
+func setupSlashingProtection(cmd *cobra.Command) (*zap.Logger, interchange.Store, *interchange.Keys, phase0.Root, func()) {
+	logger, err := setupGlobal()
+	if err != nil {
+		log.Fatal("could not create logger", err)
+	}
+
+	networkConfig, err := networkconfig.GetNetworkConfigByName(cfg.SSVOptions.NetworkName)
+	if err != nil {
+		logger.Fatal("could not get network config", zap.Error(err))
+	}
+
+	genesisValidatorsRoot, err := slashingProtectionGenesisValidatorsRoot(cmd)
+	if err != nil {
+		logger.Fatal("could not get genesis validators root", zap.Error(err))
+	}
+
+	cfg.DBOptions.Ctx = cmd.Context()
+	db, err := kv.New(logger, cfg.DBOptions)
+	if err != nil {
+		logger.Fatal("could not open db", zap.Error(err))
+	}
+	closeDB := func() {
+		if err := db.Close(); err != nil {
+			logger.Error("could not close db", zap.Error(err))
+		}
+	}
+
+	nodeStorage, err := operatorstorage.NewNodeStorage(logger, db)
+	if err != nil {
+		logger.Fatal("could not create node storage", zap.Error(err))
+	}
+	keys := interchange.NewKeys()
+	nodeStorage.Shares().Range(nil, func(share *types.SSVShare) bool {
+		if len(share.SharePubKey) != 0 {
+			keys.Add(share.ValidatorPubKey[:], share.SharePubKey)
+		}
+		return true
+	})
+
+	return logger, ekm.NewSignerStorage(db, networkConfig.Beacon, logger), keys, genesisValidatorsRoot, closeDB
+}
+
+// slashingProtectionGenesisValidatorsRoot returns the genesis validators root from the flag,
+// or otherwise fetches it from the configured consensus client.
+func slashingProtectionGenesisValidatorsRoot(cmd *cobra.Command) (phase0.Root, error) {
+	flagValue, _ := cmd.Flags().GetString(slashingProtectionGenesisValidatorsRootFlag)
+	if flagValue == "" {
+		root, err := goclient.FetchGenesisValidatorsRoot(cmd.Context(), cfg.ConsensusClient)
+		if err != nil {
+			return phase0.Root{}, fmt.Errorf("could not fetch it from the consensus client, please specify it with --%s: %w",
+				slashingProtectionGenesisValidatorsRootFlag, err)
+		}
+		return root, nil
+	}
+
+	b, err := hex.DecodeString(strings.TrimPrefix(flagValue, "0x"))
+	if err != nil || len(b) != len(phase0.Root{}) {
+		return phase0.Root{}, fmt.Errorf("invalid genesis validators root %q", flagValue)
+	}
+	return phase0.Root(b), nil
+}
+
+func init() {
+	global_config.ProcessArgs(&cfg, &globalArgs, SlashingProtectionCmd)
+	cliflag.AddPersistentStringFlag(SlashingProtectionCmd, slashingProtectionFileFlag, "slashing_protection.json", "Path to the interchange file", false)
+	cliflag.AddPersistentStringFlag(SlashingProtectionCmd, slashingProtectionGenesisValidatorsRootFlag, "", "Genesis validators root of the network, fetched from the consensus client by default", false)
+
+	SlashingProtectionCmd.AddCommand(slashingProtectionExportCmd)
+	SlashingProtectionCmd.AddCommand(slashingProtectionImportCmd)
+}
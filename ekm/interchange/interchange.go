@@ -0,0 +1,306 @@
+// Package interchange implements the EIP-3076 slashing protection interchange format
+// (https://eips.ethereum.org/EIPS/eip-3076) for the signer storage.
+//
+// The signer storage tracks slashing protection per share rather than per validator,
+// so the data is mapped between the validator public keys of the interchange document
+// and the operator's share public keys by Keys. Only the highest attestation
+// and proposal of each share are exported (the minimal interchange format).
+package interchange
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+// FormatVersion is the supported interchange format version.
+const FormatVersion = "5"
+
+// Store is the slashing protection storage, implemented by ekm.Storage.
+type Store interface {
+	ListHighestAttestations() (map[string]*phase0.AttestationData, error)
+	ListHighestProposals() (map[string]phase0.Slot, error)
+	RetrieveHighestAttestation(pubKey []byte) (*phase0.AttestationData, bool, error)
+	SaveHighestAttestation(pubKey []byte, attestation *phase0.AttestationData) error
+	RetrieveHighestProposal(pubKey []byte) (phase0.Slot, bool, error)
+	SaveHighestProposal(pubKey []byte, slot phase0.Slot) error
+}
+
+// Keys maps between the validator public keys of interchange documents
+// and the operator's share public keys, by which the store is keyed.
+type Keys struct {
+	shareByValidator map[string][]byte
+	validatorByShare map[string][]byte
+}
+
+func NewKeys() *Keys {
+	return &Keys{
+		shareByValidator: map[string][]byte{},
+		validatorByShare: map[string][]byte{},
+	}
+}
+
+// Add maps a validator public key to the operator's share public key of it.
+func (k *Keys) Add(validatorPubKey, sharePubKey []byte) {
+	k.shareByValidator[hex.EncodeToString(validatorPubKey)] = sharePubKey
+	k.validatorByShare[hex.EncodeToString(sharePubKey)] = validatorPubKey
+}
+
+// Interchange is an EIP-3076 interchange document.
+type Interchange struct {
+	Metadata Metadata `json:"metadata"`
+	Data     []*Data  `json:"data"`
+}
+
+type Metadata struct {
+	InterchangeFormatVersion string `json:"interchange_format_version"`
+	GenesisValidatorsRoot    Hex    `json:"genesis_validators_root"`
+}
+
+type Data struct {
+	PubKey             Hex                  `json:"pubkey"`
+	SignedBlocks       []*SignedBlock       `json:"signed_blocks"`
+	SignedAttestations []*SignedAttestation `json:"signed_attestations"`
+}
+
+type SignedBlock struct {
+	Slot        Uint64 `json:"slot"`
+	SigningRoot Hex    `json:"signing_root,omitempty"`
+}
+
+type SignedAttestation struct {
+	SourceEpoch Uint64 `json:"source_epoch"`
+	TargetEpoch Uint64 `json:"target_epoch"`
+	SigningRoot Hex    `json:"signing_root,omitempty"`
+}
+
+// Validate checks that the interchange is supported and belongs to the given chain.
+func (i *Interchange) Validate(genesisValidatorsRoot phase0.Root) error {
+	if i.Metadata.InterchangeFormatVersion != FormatVersion {
+		return fmt.Errorf("unsupported interchange format version %q", i.Metadata.InterchangeFormatVersion)
+	}
+	if len(i.Metadata.GenesisValidatorsRoot) != len(genesisValidatorsRoot) ||
+		phase0.Root(i.Metadata.GenesisValidatorsRoot) != genesisValidatorsRoot {
+		return fmt.Errorf("genesis validators root mismatch: got %s, expected %s",
+			i.Metadata.GenesisValidatorsRoot, Hex(genesisValidatorsRoot[:]))
+	}
+	for _, data := range i.Data {
+		if len(data.PubKey) != phase0.PublicKeyLength {
+			return fmt.Errorf("invalid public key %s", data.PubKey)
+		}
+		for _, att := range data.SignedAttestations {
+			if att.SourceEpoch > att.TargetEpoch {
+				return fmt.Errorf("attestation of %s has source epoch %d after target epoch %d",
+					data.PubKey, att.SourceEpoch, att.TargetEpoch)
+			}
+		}
+	}
+	return nil
+}
+
+// Export exports the slashing protection data of every share in the store by its validator public key.
+// Shares which aren't mapped to a validator, such as leftovers of removed validators, are skipped.
+func Export(store Store, keys *Keys, genesisValidatorsRoot phase0.Root) (*Interchange, error) {
+	attestations, err := store.ListHighestAttestations()
+	if err != nil {
+		return nil, fmt.Errorf("could not list highest attestations: %w", err)
+	}
+	proposals, err := store.ListHighestProposals()
+	if err != nil {
+		return nil, fmt.Errorf("could not list highest proposals: %w", err)
+	}
+
+	byPubKey := map[string]*Data{}
+	data := func(sharePubKey string) *Data {
+		if d, ok := byPubKey[sharePubKey]; ok {
+			return d
+		}
+		validatorPubKey, ok := keys.validatorByShare[sharePubKey]
+		if !ok {
+			return nil
+		}
+		d := &Data{
+			PubKey:             validatorPubKey,
+			SignedBlocks:       []*SignedBlock{},
+			SignedAttestations: []*SignedAttestation{},
+		}
+		byPubKey[sharePubKey] = d
+		return d
+	}
+	for pubKey, att := range attestations {
+		d := data(pubKey)
+		if d == nil {
+			continue
+		}
+		d.SignedAttestations = append(d.SignedAttestations, &SignedAttestation{
+			SourceEpoch: Uint64(att.Source.Epoch),
+			TargetEpoch: Uint64(att.Target.Epoch),
+		})
+	}
+	for pubKey, slot := range proposals {
+		d := data(pubKey)
+		if d == nil {
+			continue
+		}
+		d.SignedBlocks = append(d.SignedBlocks, &SignedBlock{Slot: Uint64(slot)})
+	}
+
+	interchange := &Interchange{
+		Metadata: Metadata{
+			InterchangeFormatVersion: FormatVersion,
+			GenesisValidatorsRoot:    genesisValidatorsRoot[:],
+		},
+		Data: make([]*Data, 0, len(byPubKey)),
+	}
+	for _, d := range byPubKey {
+		interchange.Data = append(interchange.Data, d)
+	}
+	sort.Slice(interchange.Data, func(i, j int) bool {
+		return interchange.Data[i].PubKey.String() < interchange.Data[j].PubKey.String()
+	})
+	return interchange, nil
+}
+
+// ImportResult summarizes an import.
+type ImportResult struct {
+	// Attestations is the number of shares whose highest attestation was raised.
+	Attestations int `json:"attestations"`
+	// Proposals is the number of shares whose highest proposal was raised.
+	Proposals int `json:"proposals"`
+	// Unchanged is the number of shares whose data was already at least as high.
+	Unchanged int `json:"unchanged"`
+	// Unknown is the number of validators which the operator has no share of.
+	Unknown int `json:"unknown"`
+}
+
+// Import merges the interchange into the store, never lowering an existing watermark:
+// the highest source and target epochs and the highest slot are kept for every share.
+// Validators which the operator has no share of are skipped.
+func Import(store Store, keys *Keys, interchange *Interchange, genesisValidatorsRoot phase0.Root) (*ImportResult, error) {
+	if err := interchange.Validate(genesisValidatorsRoot); err != nil {
+		return nil, err
+	}
+
+	result := &ImportResult{}
+	for _, data := range interchange.Data {
+		sharePubKey, ok := keys.shareByValidator[hex.EncodeToString(data.PubKey)]
+		if !ok {
+			result.Unknown++
+			continue
+		}
+		attChanged, err := importAttestations(store, sharePubKey, data)
+		if err != nil {
+			return result, fmt.Errorf("could not import attestations of %s: %w", data.PubKey, err)
+		}
+		propChanged, err := importBlocks(store, sharePubKey, data)
+		if err != nil {
+			return result, fmt.Errorf("could not import blocks of %s: %w", data.PubKey, err)
+		}
+		if attChanged {
+			result.Attestations++
+		}
+		if propChanged {
+			result.Proposals++
+		}
+		if !attChanged && !propChanged {
+			result.Unchanged++
+		}
+	}
+	return result, nil
+}
+
+func importAttestations(store Store, sharePubKey []byte, data *Data) (bool, error) {
+	if len(data.SignedAttestations) == 0 {
+		return false, nil
+	}
+	var source, target phase0.Epoch
+	for _, att := range data.SignedAttestations {
+		source = max(source, phase0.Epoch(att.SourceEpoch))
+		target = max(target, phase0.Epoch(att.TargetEpoch))
+	}
+
+	highest, found, err := store.RetrieveHighestAttestation(sharePubKey)
+	if err != nil {
+		return false, err
+	}
+	if found && highest != nil {
+		if highest.Source.Epoch >= source && highest.Target.Epoch >= target {
+			return false, nil
+		}
+		source = max(source, highest.Source.Epoch)
+		target = max(target, highest.Target.Epoch)
+	}
+
+	return true, store.SaveHighestAttestation(sharePubKey, &phase0.AttestationData{
+		Source: &phase0.Checkpoint{Epoch: source},
+		Target: &phase0.Checkpoint{Epoch: target},
+	})
+}
+
+func importBlocks(store Store, sharePubKey []byte, data *Data) (bool, error) {
+	var slot phase0.Slot
+	for _, block := range data.SignedBlocks {
+		slot = max(slot, phase0.Slot(block.Slot))
+	}
+	if slot == 0 {
+		return false, nil
+	}
+
+	highest, found, err := store.RetrieveHighestProposal(sharePubKey)
+	if err != nil {
+		return false, err
+	}
+	if found && highest >= slot {
+		return false, nil
+	}
+	return true, store.SaveHighestProposal(sharePubKey, slot)
+}
+
+// Hex is a byte slice encoded as a 0x-prefixed hex string.
+type Hex []byte
+
+func (h Hex) String() string {
+	return "0x" + hex.EncodeToString(h)
+}
+
+func (h Hex) MarshalJSON() ([]byte, error) {
+	return json.Marshal(h.String())
+}
+
+func (h *Hex) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return fmt.Errorf("invalid hex string %q: %w", s, err)
+	}
+	*h = b
+	return nil
+}
+
+// Uint64 is an uint64 encoded as a decimal string.
+type Uint64 uint64
+
+func (u Uint64) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strconv.FormatUint(uint64(u), 10))
+}
+
+func (u *Uint64) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	v, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid integer %q: %w", s, err)
+	}
+	*u = Uint64(v)
+	return nil
+}
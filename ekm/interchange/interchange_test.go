@@ -0,0 +1,166 @@
+package interchange
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryStore struct {
+	attestations map[string]*phase0.AttestationData
+	proposals    map[string]phase0.Slot
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		attestations: map[string]*phase0.AttestationData{},
+		proposals:    map[string]phase0.Slot{},
+	}
+}
+
+func (s *memoryStore) ListHighestAttestations() (map[string]*phase0.AttestationData, error) {
+	return s.attestations, nil
+}
+
+func (s *memoryStore) ListHighestProposals() (map[string]phase0.Slot, error) {
+	return s.proposals, nil
+}
+
+func (s *memoryStore) RetrieveHighestAttestation(pubKey []byte) (*phase0.AttestationData, bool, error) {
+	att, ok := s.attestations[hex.EncodeToString(pubKey)]
+	return att, ok, nil
+}
+
+func (s *memoryStore) SaveHighestAttestation(pubKey []byte, attestation *phase0.AttestationData) error {
+	s.attestations[hex.EncodeToString(pubKey)] = attestation
+	return nil
+}
+
+func (s *memoryStore) RetrieveHighestProposal(pubKey []byte) (phase0.Slot, bool, error) {
+	slot, ok := s.proposals[hex.EncodeToString(pubKey)]
+	return slot, ok, nil
+}
+
+func (s *memoryStore) SaveHighestProposal(pubKey []byte, slot phase0.Slot) error {
+	s.proposals[hex.EncodeToString(pubKey)] = slot
+	return nil
+}
+
+func attestation(source, target phase0.Epoch) *phase0.AttestationData {
+	return &phase0.AttestationData{
+		Source: &phase0.Checkpoint{Epoch: source},
+		Target: &phase0.Checkpoint{Epoch: target},
+	}
+}
+
+const (
+	// Validator public keys.
+	pk1 = "a8cb269bd7741740cfe90de2f8db6ea35a9da443385155da0fa2f621ba80e5ac14b5c8f65d23fd9ccc170cc85f29e27d"
+	pk2 = "8796fafa576051372030a75c41caafea149e4368aebaca21c9f90d9974b3973d5cee7d7874e4ec9ec59fb2c8945b3e01"
+	pk3 = "b3a22e4a673ac7a153ab5b3c17a4dbef55f7e47210b20c0cbb0e66df5b36bb49ef808577610b034172e955d2312a61b9"
+
+	// Share public keys of pk1 and pk2.
+	share1 = "8e80066551a81b318258709edaf7dd1f63cd686a0e4db8b29bbb7acfe65608677af5a527d9448ee47835485e02b50bc0"
+	share2 = "a3f8ad0a3b1ff2fd5e1f6ed1b7a4c3f9cd82a7cbd63d3b7f1e0d67b2fba8a8a4e1f1f7b42e69dbd56c5bd8f9e1f2e6f0"
+
+	genesisValidatorsRoot = "4b363db94e286120d76eb905340fdd4e54bfe9f06bf33ff6cf5ad27f511bfe95"
+)
+
+func testKeys(t *testing.T) *Keys {
+	keys := NewKeys()
+	for validator, share := range map[string]string{pk1: share1, pk2: share2} {
+		validatorPubKey, err := hex.DecodeString(validator)
+		require.NoError(t, err)
+		sharePubKey, err := hex.DecodeString(share)
+		require.NoError(t, err)
+		keys.Add(validatorPubKey, sharePubKey)
+	}
+	return keys
+}
+
+func testRoot(t *testing.T, s string) phase0.Root {
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+	return phase0.Root(b)
+}
+
+func TestExportImport(t *testing.T) {
+	root := testRoot(t, genesisValidatorsRoot)
+	keys := testKeys(t)
+
+	source := newMemoryStore()
+	source.attestations[share1] = attestation(10, 11)
+	source.proposals[share1] = 300
+	source.proposals[share2] = 500
+	// Data of a share which isn't mapped to a validator isn't exported.
+	source.proposals[pk3] = 700
+
+	exported, err := Export(source, keys, root)
+	require.NoError(t, err)
+	require.Len(t, exported.Data, 2)
+
+	// Round-trip through JSON.
+	b, err := json.Marshal(exported)
+	require.NoError(t, err)
+	require.Contains(t, string(b), `"interchange_format_version":"5"`)
+	require.Contains(t, string(b), `"slot":"300"`)
+	require.Contains(t, string(b), `"pubkey":"0x`+pk1+`"`)
+	require.NotContains(t, string(b), share1)
+	var interchange Interchange
+	require.NoError(t, json.Unmarshal(b, &interchange))
+
+	// Existing higher watermarks are kept, lower ones are raised.
+	target := newMemoryStore()
+	target.attestations[share1] = attestation(12, 9)
+	target.proposals[share2] = 600
+
+	result, err := Import(target, keys, &interchange, root)
+	require.NoError(t, err)
+	require.Equal(t, &ImportResult{Attestations: 1, Proposals: 1, Unchanged: 1}, result)
+	require.Equal(t, attestation(12, 11), target.attestations[share1])
+	require.EqualValues(t, 300, target.proposals[share1])
+	require.EqualValues(t, 600, target.proposals[share2])
+
+	// Importing again changes nothing.
+	result, err = Import(target, keys, &interchange, root)
+	require.NoError(t, err)
+	require.Equal(t, &ImportResult{Unchanged: 2}, result)
+
+	// Validators which the operator has no share of are skipped.
+	result, err = Import(target, NewKeys(), &interchange, root)
+	require.NoError(t, err)
+	require.Equal(t, &ImportResult{Unknown: 2}, result)
+}
+
+func TestImportValidation(t *testing.T) {
+	root := testRoot(t, genesisValidatorsRoot)
+	otherRoot := testRoot(t, "9143aa7c615a7f7115e2b6aac319c03529df8242ae705fba9df39b79c59fa8b1")
+	keys := testKeys(t)
+
+	const doc = `{
+		"metadata": {"interchange_format_version": "5", "genesis_validators_root": "0x` + genesisValidatorsRoot + `"},
+		"data": [{
+			"pubkey": "0x` + pk1 + `",
+			"signed_blocks": [{"slot": "81952", "signing_root": "0x4ff6f743a43f3b4f95350831aeaf0a122a1a392922c45d804280284a69eb850b"}, {"slot": "81951"}],
+			"signed_attestations": [{"source_epoch": "2290", "target_epoch": "3007"}, {"source_epoch": "2291", "target_epoch": "3000"}]
+		}]
+	}`
+	var interchange Interchange
+	require.NoError(t, json.Unmarshal([]byte(doc), &interchange))
+
+	_, err := Import(newMemoryStore(), keys, &interchange, otherRoot)
+	require.ErrorContains(t, err, "genesis validators root mismatch")
+
+	store := newMemoryStore()
+	_, err = Import(store, keys, &interchange, root)
+	require.NoError(t, err)
+	require.Equal(t, attestation(2291, 3007), store.attestations[share1])
+	require.EqualValues(t, 81952, store.proposals[share1])
+
+	interchange.Metadata.InterchangeFormatVersion = "4"
+	_, err = Import(newMemoryStore(), keys, &interchange, root)
+	require.ErrorContains(t, err, "unsupported interchange format version")
+}
@@ -0,0 +1,380 @@
+package ekm
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+
+	eth2apiv1 "github.com/attestantio/go-eth2-client/api/v1"
+	apiv1capella "github.com/attestantio/go-eth2-client/api/v1/capella"
+	apiv1deneb "github.com/attestantio/go-eth2-client/api/v1/deneb"
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/altair"
+	"github.com/attestantio/go-eth2-client/spec/capella"
+	"github.com/attestantio/go-eth2-client/spec/deneb"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	slashingprotection "github.com/bloxapp/eth2-key-manager/slashing_protection"
+	ssz "github.com/ferranbt/fastssz"
+	"github.com/herumi/bls-eth-go-binary/bls"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	spectypes "github.com/ssvlabs/ssv-spec/types"
+
+	"github.com/ssvlabs/ssv/ekm/web3signer"
+	"github.com/ssvlabs/ssv/networkconfig"
+	"github.com/ssvlabs/ssv/protocol/v2/blockchain/beacon"
+	ssvtypes "github.com/ssvlabs/ssv/protocol/v2/types"
+	"github.com/ssvlabs/ssv/storage/basedb"
+)
+
+// ForkInfoProvider provides the fork information remote signers compute signing domains from.
+type ForkInfoProvider interface {
+	ForkAtEpoch(ctx context.Context, epoch phase0.Epoch) (*phase0.Fork, error)
+	GenesisValidatorsRoot(ctx context.Context) (phase0.Root, error)
+}
+
+// remoteKeyManager is a KeyManager which keeps share keys in a Web3Signer-compatible remote signer.
+// The local slashing protection is still checked and updated before every request,
+// as a second line of defence besides the remote signer's own.
+type remoteKeyManager struct {
+	signingProtection
+	client   *web3signer.Client
+	forkInfo ForkInfoProvider
+	network  beacon.BeaconNetwork
+	domain   spectypes.DomainType
+
+	// slashingLock serializes slashing checks with their respective updates.
+	slashingLock sync.Mutex
+
+	genesisValidatorsRootMu sync.Mutex
+	genesisValidatorsRoot   *phase0.Root
+}
+
+// NewRemoteKeyManager returns a KeyManager which signs with the given remote signer.
+//
+// Remote signers can't sign the SSV messages of the genesis protocol, which are signed with share keys,
+// so it fails before the Alan fork.
+func NewRemoteKeyManager(logger *zap.Logger, db basedb.Database, network networkconfig.NetworkConfig, client *web3signer.Client, forkInfo ForkInfoProvider, opts ...Option) (KeyManager, error) {
+	if !network.PastAlanFork() {
+		return nil, fmt.Errorf("remote signers are only supported after the Alan fork at epoch %d", network.AlanForkEpoch)
+	}
+
+	signerStore := NewSignerStorage(db, network.Beacon, logger)
+
+	km := &remoteKeyManager{
+		signingProtection: signingProtection{
+			storage:           signerStore,
+			slashingProtector: slashingprotection.NewNormalProtection(signerStore),
+		},
+		client:   client,
+		forkInfo: forkInfo,
+		network:  network.Beacon,
+		domain:   network.DomainType(),
+	}
+	km.signingLock = &km.slashingLock
+	for _, opt := range opts {
+		opt(&km.signingProtection)
+	}
+
+	return km, nil
+}
+
+func (km *remoteKeyManager) SignBeaconObject(obj ssz.HashRoot, domain phase0.Domain, pk []byte, domainType phase0.DomainType) (spectypes.Signature, [32]byte, error) {
+	if err := km.checkDoppelganger(pk, domainType); err != nil {
+		return nil, [32]byte{}, err
+	}
+
+	request, epoch, err := km.signRequest(obj, domainType)
+	if err != nil {
+		return nil, [32]byte{}, err
+	}
+	request.SigningRoot, err = spectypes.ComputeETHSigningRoot(obj, domain)
+	if err != nil {
+		return nil, [32]byte{}, errors.Wrap(err, "could not compute signing root")
+	}
+
+	// The history is checked first, so that objects of the same share wait for it without holding slashingLock.
+	recordSlashingHistory, err := km.checkSlashingHistory(obj, domain, pk, domainType)
+	if err != nil {
+		return nil, [32]byte{}, err
+	}
+
+	sig, err := km.sign(obj, domain, pk, domainType, request, epoch)
+	if recordErr := recordSlashingHistory(err == nil); recordErr != nil {
+		return nil, [32]byte{}, errors.Wrap(recordErr, "could not record slashing history")
+	}
+	if err != nil {
+		return nil, [32]byte{}, err
+	}
+	return sig[:], request.SigningRoot, nil
+}
+
+func (km *remoteKeyManager) sign(obj ssz.HashRoot, domain phase0.Domain, pk []byte, domainType phase0.DomainType, request *web3signer.SignRequest, epoch phase0.Epoch) (phase0.BLSSignature, error) {
+	if err := km.checkAndUpdateSlashingProtection(obj, domain, pk, domainType); err != nil {
+		return phase0.BLSSignature{}, err
+	}
+
+	ctx := context.Background()
+	// Builder registrations are signed with the genesis fork version and no genesis validators root.
+	if request.Type != web3signer.TypeValidatorRegistration {
+		var err error
+		request.ForkInfo, err = km.forkInfoAt(ctx, epoch)
+		if err != nil {
+			return phase0.BLSSignature{}, err
+		}
+	}
+
+	sig, err := km.client.Sign(ctx, pk, request)
+	if err != nil {
+		return phase0.BLSSignature{}, errors.Wrap(err, "remote signer failed to sign")
+	}
+	return sig, nil
+}
+
+// signRequest returns a signing request of the object, without the signing root and fork info,
+// and the epoch whose fork the object is signed with.
+func (km *remoteKeyManager) signRequest(obj ssz.HashRoot, domainType phase0.DomainType) (*web3signer.SignRequest, phase0.Epoch, error) {
+	switch domainType {
+	case spectypes.DomainAttester:
+		data, ok := obj.(*phase0.AttestationData)
+		if !ok {
+			return nil, 0, errors.New("could not cast obj to AttestationData")
+		}
+		return &web3signer.SignRequest{Type: web3signer.TypeAttestation, Attestation: data}, data.Target.Epoch, nil
+	case spectypes.DomainProposer:
+		block, err := blockHeader(obj)
+		if err != nil {
+			return nil, 0, err
+		}
+		return &web3signer.SignRequest{Type: web3signer.TypeBlockV2, BeaconBlock: block}, km.network.EstimatedEpochAtSlot(block.BlockHeader.Slot), nil
+	case spectypes.DomainVoluntaryExit:
+		data, ok := obj.(*phase0.VoluntaryExit)
+		if !ok {
+			return nil, 0, errors.New("could not cast obj to VoluntaryExit")
+		}
+		return &web3signer.SignRequest{Type: web3signer.TypeVoluntaryExit, VoluntaryExit: data}, data.Epoch, nil
+	case spectypes.DomainAggregateAndProof:
+		data, ok := obj.(*phase0.AggregateAndProof)
+		if !ok {
+			return nil, 0, errors.New("could not cast obj to AggregateAndProof")
+		}
+		return &web3signer.SignRequest{Type: web3signer.TypeAggregateAndProof, AggregateAndProof: data}, km.network.EstimatedEpochAtSlot(data.Aggregate.Data.Slot), nil
+	case spectypes.DomainSelectionProof:
+		data, ok := obj.(spectypes.SSZUint64)
+		if !ok {
+			return nil, 0, errors.New("could not cast obj to SSZUint64")
+		}
+		slot := phase0.Slot(data)
+		return &web3signer.SignRequest{Type: web3signer.TypeAggregationSlot, AggregationSlot: &web3signer.AggregationSlot{Slot: slot}}, km.network.EstimatedEpochAtSlot(slot), nil
+	case spectypes.DomainRandao:
+		data, ok := obj.(spectypes.SSZUint64)
+		if !ok {
+			return nil, 0, errors.New("could not cast obj to SSZUint64")
+		}
+		epoch := phase0.Epoch(data)
+		return &web3signer.SignRequest{Type: web3signer.TypeRandaoReveal, RandaoReveal: &web3signer.RandaoReveal{Epoch: epoch}}, epoch, nil
+	case spectypes.DomainSyncCommittee:
+		// Only the block root is signed, but remote signers require the slot of the message as well.
+		data, ok := obj.(ssvtypes.SyncCommitteeBlockRoot)
+		if !ok {
+			return nil, 0, errors.New("could not cast obj to SyncCommitteeBlockRoot")
+		}
+		return &web3signer.SignRequest{
+			Type: web3signer.TypeSyncCommitteeMessage,
+			SyncCommitteeMessage: &web3signer.SyncCommitteeMessage{
+				BeaconBlockRoot: phase0.Root(data.SSZBytes),
+				Slot:            data.Slot,
+			},
+		}, km.network.EstimatedEpochAtSlot(data.Slot), nil
+	case spectypes.DomainSyncCommitteeSelectionProof:
+		data, ok := obj.(*altair.SyncAggregatorSelectionData)
+		if !ok {
+			return nil, 0, errors.New("could not cast obj to SyncAggregatorSelectionData")
+		}
+		return &web3signer.SignRequest{Type: web3signer.TypeSyncCommitteeSelectionProof, SyncAggregatorSelectionData: data}, km.network.EstimatedEpochAtSlot(data.Slot), nil
+	case spectypes.DomainContributionAndProof:
+		data, ok := obj.(*altair.ContributionAndProof)
+		if !ok {
+			return nil, 0, errors.New("could not cast obj to ContributionAndProof")
+		}
+		return &web3signer.SignRequest{Type: web3signer.TypeSyncCommitteeContributionAndProof, ContributionAndProof: data}, km.network.EstimatedEpochAtSlot(data.Contribution.Slot), nil
+	case spectypes.DomainApplicationBuilder:
+		data, ok := obj.(*eth2apiv1.ValidatorRegistration)
+		if !ok {
+			return nil, 0, fmt.Errorf("obj type is unknown: %T", obj)
+		}
+		return &web3signer.SignRequest{Type: web3signer.TypeValidatorRegistration, ValidatorRegistration: data}, 0, nil
+	default:
+		return nil, 0, errors.New("domain unknown")
+	}
+}
+
+// blockHeader returns the header of a block, which remote signers sign blocks by.
+func blockHeader(obj ssz.HashRoot) (*web3signer.BeaconBlock, error) {
+	var version spec.DataVersion
+	var header *phase0.BeaconBlockHeader
+	var bodyRoot phase0.Root
+	var err error
+	switch v := obj.(type) {
+	case *capella.BeaconBlock:
+		version = spec.DataVersionCapella
+		header = &phase0.BeaconBlockHeader{Slot: v.Slot, ProposerIndex: v.ProposerIndex, ParentRoot: v.ParentRoot, StateRoot: v.StateRoot}
+		bodyRoot, err = v.Body.HashTreeRoot()
+	case *deneb.BeaconBlock:
+		version = spec.DataVersionDeneb
+		header = &phase0.BeaconBlockHeader{Slot: v.Slot, ProposerIndex: v.ProposerIndex, ParentRoot: v.ParentRoot, StateRoot: v.StateRoot}
+		bodyRoot, err = v.Body.HashTreeRoot()
+	case *apiv1capella.BlindedBeaconBlock:
+		version = spec.DataVersionCapella
+		header = &phase0.BeaconBlockHeader{Slot: v.Slot, ProposerIndex: v.ProposerIndex, ParentRoot: v.ParentRoot, StateRoot: v.StateRoot}
+		bodyRoot, err = v.Body.HashTreeRoot()
+	case *apiv1deneb.BlindedBeaconBlock:
+		version = spec.DataVersionDeneb
+		header = &phase0.BeaconBlockHeader{Slot: v.Slot, ProposerIndex: v.ProposerIndex, ParentRoot: v.ParentRoot, StateRoot: v.StateRoot}
+		bodyRoot, err = v.Body.HashTreeRoot()
+	default:
+		return nil, fmt.Errorf("obj type is unknown: %T", obj)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "could not compute block body root")
+	}
+	header.BodyRoot = bodyRoot
+
+	return &web3signer.BeaconBlock{
+		Version:     strings.ToUpper(version.String()),
+		BlockHeader: header,
+	}, nil
+}
+
+// checkAndUpdateSlashingProtection checks attestations and blocks against the local slashing protection
+// and raises the highest attestation and proposal, which the remote signer doesn't do for us.
+func (km *remoteKeyManager) checkAndUpdateSlashingProtection(obj ssz.HashRoot, domain phase0.Domain, pk []byte, domainType phase0.DomainType) error {
+	km.slashingLock.Lock()
+	defer km.slashingLock.Unlock()
+
+	switch domainType {
+	case spectypes.DomainAttester:
+		data, ok := obj.(*phase0.AttestationData)
+		if !ok {
+			return errors.New("could not cast obj to AttestationData")
+		}
+		if err := km.IsAttestationSlashable(pk, data); err != nil {
+			return err
+		}
+		highest, found, err := km.storage.RetrieveHighestAttestation(pk)
+		if err != nil {
+			return errors.Wrap(err, "could not retrieve highest attestation")
+		}
+		if found && highest != nil && highest.Source.Epoch >= data.Source.Epoch && highest.Target.Epoch >= data.Target.Epoch {
+			return nil
+		}
+		source, target := data.Source.Epoch, data.Target.Epoch
+		if found && highest != nil {
+			source, target = max(source, highest.Source.Epoch), max(target, highest.Target.Epoch)
+		}
+		if err := km.storage.SaveHighestAttestation(pk, &phase0.AttestationData{
+			Source: &phase0.Checkpoint{Epoch: source},
+			Target: &phase0.Checkpoint{Epoch: target},
+		}); err != nil {
+			return errors.Wrap(err, "could not save highest attestation")
+		}
+	case spectypes.DomainProposer:
+		block, err := blockHeader(obj)
+		if err != nil {
+			return err
+		}
+		slot := block.BlockHeader.Slot
+		if err := km.IsBeaconBlockSlashable(pk, slot); err != nil {
+			return err
+		}
+		if err := km.storage.SaveHighestProposal(pk, slot); err != nil {
+			return errors.Wrap(err, "could not save highest proposal")
+		}
+	}
+	return nil
+}
+
+func (km *remoteKeyManager) forkInfoAt(ctx context.Context, epoch phase0.Epoch) (*web3signer.ForkInfo, error) {
+	fork, err := km.forkInfo.ForkAtEpoch(ctx, epoch)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get fork")
+	}
+
+	km.genesisValidatorsRootMu.Lock()
+	defer km.genesisValidatorsRootMu.Unlock()
+	if km.genesisValidatorsRoot == nil {
+		root, err := km.forkInfo.GenesisValidatorsRoot(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get genesis validators root")
+		}
+		km.genesisValidatorsRoot = &root
+	}
+
+	return &web3signer.ForkInfo{
+		Fork:                  fork,
+		GenesisValidatorsRoot: *km.genesisValidatorsRoot,
+	}, nil
+}
+
+// SignRoot fails, since remote signers can only sign beacon objects. Share keys sign SSV messages
+// only in the genesis protocol, which NewRemoteKeyManager doesn't support, and SSV messages of the
+// Alan protocol are signed with the operator key instead.
+func (km *remoteKeyManager) SignRoot(spectypes.Root, spectypes.SignatureType, []byte) (spectypes.Signature, error) {
+	return nil, errors.New("remote signers can't sign SSV messages with share keys")
+}
+
+// AddShare imports the share key into the remote signer, encrypted with a random password.
+func (km *remoteKeyManager) AddShare(shareKey *bls.SecretKey) error {
+	pubKey := shareKey.GetPublicKey().Serialize()
+
+	passwordBytes := make([]byte, 32)
+	if _, err := rand.Read(passwordBytes); err != nil {
+		return errors.Wrap(err, "could not generate keystore password")
+	}
+	password := hex.EncodeToString(passwordBytes)
+
+	keystore, err := web3signer.NewKeystore(shareKey.Serialize(), pubKey, password)
+	if err != nil {
+		return errors.Wrap(err, "could not create keystore")
+	}
+
+	statuses, err := km.client.ImportKeystores(context.Background(), []string{keystore}, []string{password})
+	if err != nil {
+		return errors.Wrap(err, "could not import share")
+	}
+	switch statuses[0].Status {
+	case web3signer.StatusImported:
+		if err := km.BumpSlashingProtection(pubKey); err != nil {
+			return errors.Wrap(err, "could not bump slashing protection")
+		}
+	case web3signer.StatusDuplicate:
+	default:
+		return fmt.Errorf("could not import share: %s %s", statuses[0].Status, statuses[0].Message)
+	}
+	return nil
+}
+
+// RemoveShare deletes the share key from the remote signer.
+func (km *remoteKeyManager) RemoveShare(pubKey string) error {
+	pkDecoded, err := hex.DecodeString(pubKey)
+	if err != nil {
+		return errors.Wrap(err, "could not hex decode share public key")
+	}
+
+	statuses, err := km.client.DeleteKeystores(context.Background(), [][]byte{pkDecoded})
+	if err != nil {
+		return errors.Wrap(err, "could not delete share")
+	}
+	switch statuses[0].Status {
+	case web3signer.StatusDeleted, web3signer.StatusNotActive:
+		return km.removeSlashingProtection(pkDecoded)
+	case web3signer.StatusNotFound:
+		return nil
+	default:
+		return fmt.Errorf("could not delete share: %s %s", statuses[0].Status, statuses[0].Message)
+	}
+}
@@ -0,0 +1,122 @@
+package ekm
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/herumi/bls-eth-go-binary/bls"
+	genesisspecqbft "github.com/ssvlabs/ssv-spec-pre-cc/qbft"
+	spectypes "github.com/ssvlabs/ssv-spec/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ssvlabs/ssv/ekm/web3signer"
+	"github.com/ssvlabs/ssv/ekm/web3signer/web3signertest"
+	"github.com/ssvlabs/ssv/logging"
+	"github.com/ssvlabs/ssv/networkconfig"
+	ssvtypes "github.com/ssvlabs/ssv/protocol/v2/types"
+	"github.com/ssvlabs/ssv/utils"
+	"github.com/ssvlabs/ssv/utils/threshold"
+)
+
+type staticForkInfo struct {
+	fork                  *phase0.Fork
+	genesisValidatorsRoot phase0.Root
+}
+
+func (f staticForkInfo) ForkAtEpoch(context.Context, phase0.Epoch) (*phase0.Fork, error) {
+	return f.fork, nil
+}
+
+func (f staticForkInfo) GenesisValidatorsRoot(context.Context) (phase0.Root, error) {
+	return f.genesisValidatorsRoot, nil
+}
+
+func TestRemoteKeyManager(t *testing.T) {
+	threshold.Init()
+	logger := logging.TestLogger(t)
+
+	stub := web3signertest.New()
+	defer stub.Close()
+
+	db, err := getBaseStorage(logger)
+	require.NoError(t, err)
+	network := networkconfig.NetworkConfig{
+		Beacon:            utils.SetupMockBeaconNetwork(t, nil),
+		GenesisDomainType: networkconfig.TestNetwork.DomainType(),
+		AlanDomainType:    networkconfig.TestNetwork.DomainType(),
+	}
+	forkInfo := staticForkInfo{
+		fork:                  &phase0.Fork{CurrentVersion: phase0.Version{1}},
+		genesisValidatorsRoot: phase0.Root{2},
+	}
+	km, err := NewRemoteKeyManager(logger, db, network, web3signer.New(stub.URL), forkInfo)
+	require.NoError(t, err)
+
+	sk := &bls.SecretKey{}
+	require.NoError(t, sk.SetHexString(sk1Str))
+	pk := sk.GetPublicKey().Serialize()
+
+	require.NoError(t, km.AddShare(sk))
+	require.True(t, stub.HasKey(pk))
+	require.NoError(t, km.AddShare(sk))
+
+	attestation := &phase0.AttestationData{
+		Slot:   100,
+		Source: &phase0.Checkpoint{Epoch: 2},
+		Target: &phase0.Checkpoint{Epoch: 3},
+	}
+	sig, root, err := km.SignBeaconObject(attestation, phase0.Domain{}, pk, spectypes.DomainAttester)
+	require.NoError(t, err)
+	expectedRoot, err := spectypes.ComputeETHSigningRoot(attestation, phase0.Domain{})
+	require.NoError(t, err)
+	require.EqualValues(t, expectedRoot, root)
+	require.Equal(t, sk.SignByte(expectedRoot[:]).Serialize(), []byte(sig))
+
+	requests := stub.Requests()
+	require.Len(t, requests, 1)
+	require.Equal(t, web3signer.TypeAttestation, requests[0].Type)
+	require.Equal(t, forkInfo.fork, requests[0].ForkInfo.Fork)
+	require.Equal(t, forkInfo.genesisValidatorsRoot, requests[0].ForkInfo.GenesisValidatorsRoot)
+
+	// Slashable attestations are rejected locally, before reaching the remote signer.
+	_, _, err = km.SignBeaconObject(attestation, phase0.Domain{}, pk, spectypes.DomainAttester)
+	require.ErrorContains(t, err, "slashable attestation")
+	require.Len(t, stub.Requests(), 1)
+
+	highest, found, err := km.(*remoteKeyManager).RetrieveHighestAttestation(pk)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, attestation.Target.Epoch, highest.Target.Epoch)
+
+	// Sync committee messages are signed for the slot of the message.
+	blockRoot := phase0.Root{4}
+	_, _, err = km.SignBeaconObject(ssvtypes.SyncCommitteeBlockRoot{SSZBytes: blockRoot[:], Slot: 200}, phase0.Domain{}, pk, spectypes.DomainSyncCommittee)
+	require.NoError(t, err)
+	require.Equal(t, web3signer.TypeSyncCommitteeMessage, stub.Requests()[1].Type)
+	require.Equal(t, phase0.Slot(200), stub.Requests()[1].SyncCommitteeMessage.Slot)
+	require.Equal(t, blockRoot, stub.Requests()[1].SyncCommitteeMessage.BeaconBlockRoot)
+
+	// SSV messages can't be signed with share keys.
+	msg := &genesisspecqbft.Message{
+		MsgType:    genesisspecqbft.CommitMsgType,
+		Height:     genesisspecqbft.Height(3),
+		Identifier: []byte("identifier1"),
+		Root:       [32]byte{1, 2, 3},
+	}
+	_, err = km.SignRoot(msg, spectypes.QBFTSignatureType, pk)
+	require.Error(t, err)
+	require.Len(t, stub.Requests(), 2)
+
+	require.NoError(t, km.RemoveShare(hex.EncodeToString(pk)))
+	require.False(t, stub.HasKey(pk))
+	_, found, err = km.(*remoteKeyManager).RetrieveHighestAttestation(pk)
+	require.NoError(t, err)
+	require.False(t, found)
+
+	// The genesis protocol isn't supported.
+	network.AlanForkEpoch = network.Beacon.EstimatedCurrentEpoch() + 1
+	_, err = NewRemoteKeyManager(logger, db, network, web3signer.New(stub.URL), forkInfo)
+	require.ErrorContains(t, err, "only supported after the Alan fork")
+}
@@ -23,9 +23,11 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 
+	"github.com/ssvlabs/ssv/ekm/slashinghistory"
 	"github.com/ssvlabs/ssv/logging"
 	"github.com/ssvlabs/ssv/networkconfig"
 	"github.com/ssvlabs/ssv/operator/keys"
+	"github.com/ssvlabs/ssv/protocol/v2/blockchain/beacon"
 	"github.com/ssvlabs/ssv/storage/basedb"
 	"github.com/ssvlabs/ssv/utils"
 	"github.com/ssvlabs/ssv/utils/threshold"
@@ -788,3 +790,84 @@ func TestEkmListAccounts(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, 2, len(accounts))
 }
+
+type doppelgangerProtection map[string]bool
+
+func (d doppelgangerProtection) CanSign(sharePubKey []byte) bool {
+	return d[hex.EncodeToString(sharePubKey)]
+}
+
+func TestDoppelgangerProtection(t *testing.T) {
+	require.NoError(t, bls.Init(bls.BLS12_381))
+
+	km := testKeyManager(t, nil)
+	sk1 := &bls.SecretKey{}
+	require.NoError(t, sk1.SetHexString(sk1Str))
+	sk2 := &bls.SecretKey{}
+	require.NoError(t, sk2.SetHexString(sk2Str))
+
+	km.(*ethKeyManagerSigner).doppelganger = doppelgangerProtection{
+		hex.EncodeToString(sk1.GetPublicKey().Serialize()): true,
+	}
+
+	_, _, err := km.SignBeaconObject(spectypes.SSZUint64(1), phase0.Domain{}, sk1.GetPublicKey().Serialize(), spectypes.DomainRandao)
+	require.NoError(t, err)
+
+	_, _, err = km.SignBeaconObject(spectypes.SSZUint64(1), phase0.Domain{}, sk2.GetPublicKey().Serialize(), spectypes.DomainRandao)
+	require.ErrorContains(t, err, "doppelganger protection")
+
+	// Voluntary exits aren't blocked.
+	_, _, err = km.SignBeaconObject(&phase0.VoluntaryExit{Epoch: 1, ValidatorIndex: 1}, phase0.Domain{}, sk2.GetPublicKey().Serialize(), spectypes.DomainVoluntaryExit)
+	require.NoError(t, err)
+}
+
+func TestSlashingHistory(t *testing.T) {
+	require.NoError(t, bls.Init(bls.BLS12_381))
+
+	km := testKeyManager(t, nil)
+	sk1 := &bls.SecretKey{}
+	require.NoError(t, sk1.SetHexString(sk1Str))
+	pk := sk1.GetPublicKey().Serialize()
+
+	db, err := getBaseStorage(logging.TestLogger(t))
+	require.NoError(t, err)
+	network := beacon.NewNetwork(spectypes.MainNetwork)
+	history := slashinghistory.New(db, network, 0)
+	km.(*ethKeyManagerSigner).slashingHistory = history
+
+	epoch := network.EstimatedCurrentEpoch()
+	attestationData := func(root phase0.Root) *phase0.AttestationData {
+		return &phase0.AttestationData{
+			Slot:            network.GetEpochFirstSlot(epoch),
+			BeaconBlockRoot: root,
+			Source:          &phase0.Checkpoint{Epoch: epoch - 1},
+			Target:          &phase0.Checkpoint{Epoch: epoch},
+		}
+	}
+
+	_, _, err = km.SignBeaconObject(attestationData(phase0.Root{1}), phase0.Domain{}, pk, spectypes.DomainAttester)
+	require.NoError(t, err)
+
+	_, _, err = km.SignBeaconObject(attestationData(phase0.Root{2}), phase0.Domain{}, pk, spectypes.DomainAttester)
+	require.ErrorIs(t, err, slashinghistory.ErrDoubleVote)
+
+	attestations, err := history.Attestations(pk)
+	require.NoError(t, err)
+	require.Len(t, attestations, 1)
+
+	// Attestations which fail to be signed, here for a share the key manager doesn't have, aren't recorded.
+	unknownSK := &bls.SecretKey{}
+	unknownSK.SetByCSPRNG()
+	unknownPK := unknownSK.GetPublicKey().Serialize()
+	_, _, err = km.SignBeaconObject(attestationData(phase0.Root{1}), phase0.Domain{}, unknownPK, spectypes.DomainAttester)
+	require.Error(t, err)
+	attestations, err = history.Attestations(unknownPK)
+	require.NoError(t, err)
+	require.Empty(t, attestations)
+
+	// The history is removed along with the share.
+	require.NoError(t, km.RemoveShare(hex.EncodeToString(pk)))
+	attestations, err = history.Attestations(pk)
+	require.NoError(t, err)
+	require.Empty(t, attestations)
+}
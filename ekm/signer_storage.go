@@ -20,6 +20,7 @@ import (
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
+	"github.com/ssvlabs/ssv/ekm/interchange"
 	"github.com/ssvlabs/ssv/logging"
 	"github.com/ssvlabs/ssv/protocol/v2/blockchain/beacon"
 	registry "github.com/ssvlabs/ssv/protocol/v2/blockchain/eth1"
@@ -44,6 +45,8 @@ type Storage interface {
 
 	RemoveHighestAttestation(pubKey []byte) error
 	RemoveHighestProposal(pubKey []byte) error
+	ListHighestAttestations() (map[string]*phase0.AttestationData, error)
+	ListHighestProposals() (map[string]phase0.Slot, error)
 	SetEncryptionKey(newKey string) error
 	ListAccountsTxn(r basedb.Reader) ([]core.ValidatorAccount, error)
 	SaveAccountTxn(rw basedb.ReadWriter, account core.ValidatorAccount) error
@@ -51,6 +54,8 @@ type Storage interface {
 	BeaconNetwork() beacon.BeaconNetwork
 }
 
+var _ interchange.Store = (*storage)(nil)
+
 type storage struct {
 	db            basedb.Database
 	network       beacon.BeaconNetwork
@@ -299,6 +304,23 @@ func (s *storage) RemoveHighestAttestation(pubKey []byte) error {
 	return s.db.Delete(s.objPrefix(highestAttPrefix), pubKey)
 }
 
+// ListHighestAttestations returns the highest attestation of every share, keyed by hex-encoded share public key.
+func (s *storage) ListHighestAttestations() (map[string]*phase0.AttestationData, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	ret := make(map[string]*phase0.AttestationData)
+	err := s.db.GetAll(s.objPrefix(highestAttPrefix), func(i int, obj basedb.Obj) error {
+		att := &phase0.AttestationData{}
+		if err := att.UnmarshalSSZ(obj.Value); err != nil {
+			return errors.Wrap(err, "could not unmarshal attestation data")
+		}
+		ret[hex.EncodeToString(obj.Key)] = att
+		return nil
+	})
+	return ret, err
+}
+
 func (s *storage) SaveHighestProposal(pubKey []byte, slot phase0.Slot) error {
 	s.lock.Lock()
 	defer s.lock.Unlock()
@@ -342,6 +364,22 @@ func (s *storage) RetrieveHighestProposal(pubKey []byte) (phase0.Slot, bool, err
 	return slot, found, nil
 }
 
+// ListHighestProposals returns the highest proposal slot of every share, keyed by hex-encoded share public key.
+func (s *storage) ListHighestProposals() (map[string]phase0.Slot, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	ret := make(map[string]phase0.Slot)
+	err := s.db.GetAll(s.objPrefix(highestProposalPrefix), func(i int, obj basedb.Obj) error {
+		if len(obj.Value) == 0 {
+			return errors.New("highest proposal value is empty")
+		}
+		ret[hex.EncodeToString(obj.Key)] = phase0.Slot(ssz.UnmarshallUint64(obj.Value))
+		return nil
+	})
+	return ret, err
+}
+
 func (s *storage) RemoveHighestProposal(pubKey []byte) error {
 	s.lock.Lock()
 	defer s.lock.Unlock()
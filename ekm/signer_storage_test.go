@@ -546,3 +546,28 @@ func TestDropRegistryData(t *testing.T) {
 	err := signerStorage.DropRegistryData()
 	require.NoError(t, err)
 }
+
+func TestListHighestAttestationsAndProposals(t *testing.T) {
+	signerStorage, done := newStorageForTest(t)
+	defer done()
+
+	pk1 := _byteArray(pk1Str)
+	pk2 := _byteArray(pk2Str)
+
+	att := &phase0.AttestationData{
+		BeaconBlockRoot: [32]byte{},
+		Source:          &phase0.Checkpoint{Epoch: 8877, Root: [32]byte{}},
+		Target:          &phase0.Checkpoint{Epoch: 8878, Root: [32]byte{}},
+	}
+	require.NoError(t, signerStorage.SaveHighestAttestation(pk1, att))
+	require.NoError(t, signerStorage.SaveHighestProposal(pk2, 100))
+
+	attestations, err := signerStorage.ListHighestAttestations()
+	require.NoError(t, err)
+	require.Len(t, attestations, 1)
+	require.Equal(t, att.Target.Epoch, attestations[pk1Str].Target.Epoch)
+
+	proposals, err := signerStorage.ListHighestProposals()
+	require.NoError(t, err)
+	require.Equal(t, map[string]phase0.Slot{pk2Str: 100}, proposals)
+}
@@ -0,0 +1,325 @@
+package ekm
+
+import (
+	"fmt"
+	"sync"
+
+	apiv1capella "github.com/attestantio/go-eth2-client/api/v1/capella"
+	apiv1deneb "github.com/attestantio/go-eth2-client/api/v1/deneb"
+	"github.com/attestantio/go-eth2-client/spec/capella"
+	"github.com/attestantio/go-eth2-client/spec/deneb"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/bloxapp/eth2-key-manager/core"
+	ssz "github.com/ferranbt/fastssz"
+	"github.com/pkg/errors"
+
+	spectypes "github.com/ssvlabs/ssv-spec/types"
+
+	"github.com/ssvlabs/ssv/ekm/interchange"
+	"github.com/ssvlabs/ssv/ekm/slashinghistory"
+)
+
+// signingProtection holds the slashing and doppelganger protection shared by the KeyManager implementations.
+type signingProtection struct {
+	storage           Storage
+	slashingProtector core.SlashingProtector
+	doppelganger      DoppelgangerProtection
+	slashingHistory   *slashinghistory.History
+
+	// signingLock is held by the KeyManager while it checks and updates the slashing protection of a signature.
+	signingLock sync.Locker
+}
+
+// SlashingProtectionProvider provides the KeyManager's slashing protection storage for the interchange format.
+type SlashingProtectionProvider interface {
+	SlashingProtection() interchange.Store
+}
+
+// SlashingProtection returns the slashing protection storage of the KeyManager, whose writes are
+// serialized with signing and never lower a watermark, so that importing can't race signatures.
+func (p *signingProtection) SlashingProtection() interchange.Store {
+	return &lockedSlashingProtection{Store: p.storage, lock: p.signingLock}
+}
+
+type lockedSlashingProtection struct {
+	interchange.Store
+	lock sync.Locker
+}
+
+func (s *lockedSlashingProtection) SaveHighestAttestation(pubKey []byte, attestation *phase0.AttestationData) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	highest, found, err := s.Store.RetrieveHighestAttestation(pubKey)
+	if err != nil {
+		return err
+	}
+	if found && highest != nil {
+		attestation = &phase0.AttestationData{
+			Source: &phase0.Checkpoint{Epoch: max(attestation.Source.Epoch, highest.Source.Epoch)},
+			Target: &phase0.Checkpoint{Epoch: max(attestation.Target.Epoch, highest.Target.Epoch)},
+		}
+	}
+	return s.Store.SaveHighestAttestation(pubKey, attestation)
+}
+
+func (s *lockedSlashingProtection) SaveHighestProposal(pubKey []byte, slot phase0.Slot) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	highest, found, err := s.Store.RetrieveHighestProposal(pubKey)
+	if err != nil {
+		return err
+	}
+	if found {
+		slot = max(slot, highest)
+	}
+	return s.Store.SaveHighestProposal(pubKey, slot)
+}
+
+// DoppelgangerProtection tells whether it's safe to sign with a share,
+// i.e. its validator isn't suspected to be run elsewhere.
+type DoppelgangerProtection interface {
+	CanSign(sharePubKey []byte) bool
+}
+
+// Option defines a KeyManager configuration option.
+type Option func(*signingProtection)
+
+// WithDoppelgangerProtection blocks signing duties for shares which aren't safe to sign with yet.
+func WithDoppelgangerProtection(doppelganger DoppelgangerProtection) Option {
+	return func(p *signingProtection) {
+		p.doppelganger = doppelganger
+	}
+}
+
+// WithSlashingHistory checks attestations and blocks against the full slashing protection history
+// in addition to the highest attestation and proposal, and records them once they're signed.
+func WithSlashingHistory(history *slashinghistory.History) Option {
+	return func(p *signingProtection) {
+		p.slashingHistory = history
+	}
+}
+
+// checkDoppelganger returns an error if the share isn't safe to sign with yet.
+// Exits and builder registrations can't get the validator slashed, so they're not blocked.
+func (p *signingProtection) checkDoppelganger(pk []byte, domainType phase0.DomainType) error {
+	if p.doppelganger != nil &&
+		domainType != spectypes.DomainVoluntaryExit &&
+		domainType != spectypes.DomainApplicationBuilder &&
+		!p.doppelganger.CanSign(pk) {
+		return errors.New("doppelganger protection: validator isn't safe to sign for yet")
+	}
+	return nil
+}
+
+// removeSlashingProtection removes the slashing protection data of a share.
+func (p *signingProtection) removeSlashingProtection(pk []byte) error {
+	if err := p.storage.RemoveHighestAttestation(pk); err != nil {
+		return errors.Wrap(err, "could not remove highest attestation")
+	}
+	if err := p.storage.RemoveHighestProposal(pk); err != nil {
+		return errors.Wrap(err, "could not remove highest proposal")
+	}
+	if p.slashingHistory != nil {
+		if err := p.slashingHistory.Remove(pk); err != nil {
+			return errors.Wrap(err, "could not remove slashing history")
+		}
+	}
+	return nil
+}
+
+func (p *signingProtection) ListAccounts() ([]core.ValidatorAccount, error) {
+	return p.storage.ListAccounts()
+}
+
+func (p *signingProtection) RetrieveHighestAttestation(pubKey []byte) (*phase0.AttestationData, bool, error) {
+	return p.storage.RetrieveHighestAttestation(pubKey)
+}
+
+func (p *signingProtection) RetrieveHighestProposal(pubKey []byte) (phase0.Slot, bool, error) {
+	return p.storage.RetrieveHighestProposal(pubKey)
+}
+
+// checkSlashingHistory checks attestations and blocks against the slashing history. Unless it returns an error,
+// the returned function must be called once signing is done, and records the object if it was signed.
+// Other objects of the share wait for the record, so that they're checked against it.
+func (p *signingProtection) checkSlashingHistory(obj ssz.HashRoot, domain phase0.Domain, pk []byte, domainType phase0.DomainType) (record func(signed bool) error, err error) {
+	record = func(bool) error { return nil }
+	if p.slashingHistory == nil {
+		return record, nil
+	}
+
+	switch domainType {
+	case spectypes.DomainAttester:
+		data, ok := obj.(*phase0.AttestationData)
+		if !ok {
+			return nil, errors.New("could not cast obj to AttestationData")
+		}
+		signingRoot, err := spectypes.ComputeETHSigningRoot(obj, domain)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not compute signing root")
+		}
+		unlock := p.slashingHistory.Lock(pk)
+		if err := p.slashingHistory.CheckAttestation(pk, data, signingRoot); err != nil {
+			unlock()
+			return nil, errors.Wrap(err, "slashable attestation, not signing")
+		}
+		return func(signed bool) error {
+			defer unlock()
+			if !signed {
+				return nil
+			}
+			return p.slashingHistory.SaveAttestation(pk, data, signingRoot)
+		}, nil
+	case spectypes.DomainProposer:
+		var slot phase0.Slot
+		switch v := obj.(type) {
+		case *capella.BeaconBlock:
+			slot = v.Slot
+		case *deneb.BeaconBlock:
+			slot = v.Slot
+		case *apiv1capella.BlindedBeaconBlock:
+			slot = v.Slot
+		case *apiv1deneb.BlindedBeaconBlock:
+			slot = v.Slot
+		default:
+			return nil, fmt.Errorf("obj type is unknown: %T", obj)
+		}
+		signingRoot, err := spectypes.ComputeETHSigningRoot(obj, domain)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not compute signing root")
+		}
+		unlock := p.slashingHistory.Lock(pk)
+		if err := p.slashingHistory.CheckProposal(pk, slot, signingRoot); err != nil {
+			unlock()
+			return nil, errors.Wrap(err, "slashable proposal, not signing")
+		}
+		return func(signed bool) error {
+			defer unlock()
+			if !signed {
+				return nil
+			}
+			return p.slashingHistory.SaveProposal(pk, slot, signingRoot)
+		}, nil
+	}
+	return record, nil
+}
+
+func (p *signingProtection) IsAttestationSlashable(pk spectypes.ShareValidatorPK, data *phase0.AttestationData) error {
+	if val, err := p.slashingProtector.IsSlashableAttestation(pk, data); err != nil || val != nil {
+		if err != nil {
+			return err
+		}
+		return errors.Errorf("slashable attestation (%s), not signing", val.Status)
+	}
+	return nil
+}
+
+func (p *signingProtection) IsBeaconBlockSlashable(pk []byte, slot phase0.Slot) error {
+	status, err := p.slashingProtector.IsSlashableProposal(pk, slot)
+	if err != nil {
+		return err
+	}
+	if status.Status != core.ValidProposal {
+		return errors.Errorf("slashable proposal (%s), not signing", status.Status)
+	}
+
+	return nil
+}
+
+// BumpSlashingProtection updates the slashing protection data for a given public key.
+func (p *signingProtection) BumpSlashingProtection(pubKey []byte) error {
+	currentSlot := p.storage.BeaconNetwork().EstimatedCurrentSlot()
+
+	// Update highest attestation data for slashing protection.
+	if err := p.updateHighestAttestation(pubKey, currentSlot); err != nil {
+		return err
+	}
+
+	// Update highest proposal data for slashing protection.
+	if err := p.updateHighestProposal(pubKey, currentSlot); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// updateHighestAttestation updates the highest attestation data for slashing protection.
+func (p *signingProtection) updateHighestAttestation(pubKey []byte, slot phase0.Slot) error {
+	// Retrieve the highest attestation data stored for the given public key.
+	retrievedHighAtt, found, err := p.RetrieveHighestAttestation(pubKey)
+	if err != nil {
+		return fmt.Errorf("could not retrieve highest attestation: %w", err)
+	}
+
+	currentEpoch := p.storage.BeaconNetwork().EstimatedEpochAtSlot(slot)
+	minimalSP := p.computeMinimalAttestationSP(currentEpoch)
+
+	// Check if the retrieved highest attestation data is valid and not outdated.
+	if found && retrievedHighAtt != nil {
+		if retrievedHighAtt.Source.Epoch >= minimalSP.Source.Epoch || retrievedHighAtt.Target.Epoch >= minimalSP.Target.Epoch {
+			return nil
+		}
+	}
+
+	// At this point, either the retrieved attestation data was not found, or it was outdated.
+	// In either case, we update it to the minimal slashing protection data.
+	if err := p.storage.SaveHighestAttestation(pubKey, minimalSP); err != nil {
+		return fmt.Errorf("could not save highest attestation: %w", err)
+	}
+
+	return nil
+}
+
+// updateHighestProposal updates the highest proposal slot for slashing protection.
+func (p *signingProtection) updateHighestProposal(pubKey []byte, slot phase0.Slot) error {
+	// Retrieve the highest proposal slot stored for the given public key.
+	retrievedHighProp, found, err := p.RetrieveHighestProposal(pubKey)
+	if err != nil {
+		return fmt.Errorf("could not retrieve highest proposal: %w", err)
+	}
+
+	minimalSPSlot := p.computeMinimalProposerSP(slot)
+
+	// Check if the retrieved highest proposal slot is valid and not outdated.
+	if found && retrievedHighProp != 0 {
+		if retrievedHighProp >= minimalSPSlot {
+			return nil
+		}
+	}
+
+	// At this point, either the retrieved proposal slot was not found, or it was outdated.
+	// In either case, we update it to the minimal slashing protection slot.
+	if err := p.storage.SaveHighestProposal(pubKey, minimalSPSlot); err != nil {
+		return fmt.Errorf("could not save highest proposal: %w", err)
+	}
+
+	return nil
+}
+
+// computeMinimalAttestationSP calculates the minimal safe attestation data for slashing protection.
+// It takes the current epoch as an argument and returns an AttestationData object with the minimal safe source and target epochs.
+func (p *signingProtection) computeMinimalAttestationSP(epoch phase0.Epoch) *phase0.AttestationData {
+	// Calculate the highest safe target epoch based on the current epoch and a predefined minimum distance.
+	highestTarget := epoch + minSPAttestationEpochGap
+	// The highest safe source epoch is one less than the highest target epoch.
+	highestSource := highestTarget - 1
+
+	// Return a new AttestationData object with the calculated source and target epochs.
+	return &phase0.AttestationData{
+		Source: &phase0.Checkpoint{
+			Epoch: highestSource,
+		},
+		Target: &phase0.Checkpoint{
+			Epoch: highestTarget,
+		},
+	}
+}
+
+// computeMinimalProposerSP calculates the minimal safe slot for a block proposal to avoid slashing.
+// It takes the current slot as an argument and returns the minimal safe slot.
+func (p *signingProtection) computeMinimalProposerSP(slot phase0.Slot) phase0.Slot {
+	// Calculate the highest safe proposal slot based on the current slot and a predefined minimum distance.
+	return slot + minSPProposalSlotGap
+}
@@ -0,0 +1,280 @@
+// Package slashinghistory keeps the full history of attestations and blocks signed by each share,
+// within a window of recent epochs, to detect double and surround votes which
+// the highest-watermark slashing protection can't detect against older history.
+package slashinghistory
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/pkg/errors"
+
+	"github.com/ssvlabs/ssv/protocol/v2/blockchain/beacon"
+	"github.com/ssvlabs/ssv/storage/basedb"
+)
+
+const (
+	attestationsPrefix = "signer_data-att_history-"
+	proposalsPrefix    = "signer_data-prop_history-"
+
+	// DefaultRetentionEpochs is the default number of epochs history is kept for (about 30 days).
+	DefaultRetentionEpochs = 6750
+
+	// shareLockStripes is the number of locks the shares are spread over.
+	shareLockStripes = 256
+)
+
+var (
+	// ErrDoubleVote is returned for an attestation with the same target epoch as a different signed attestation.
+	ErrDoubleVote = errors.New("double vote")
+	// ErrSurroundVote is returned for an attestation which surrounds or is surrounded by a signed attestation.
+	ErrSurroundVote = errors.New("surround vote")
+	// ErrDoubleProposal is returned for a block with the same slot as a different signed block.
+	ErrDoubleProposal = errors.New("double proposal")
+	// ErrOutsideWindow is returned when signing for an epoch older than the retained history.
+	ErrOutsideWindow = errors.New("older than the slashing history window")
+)
+
+// Config holds the slashing history configuration.
+type Config struct {
+	Enabled         bool   `yaml:"Enabled" env:"SLASHING_HISTORY" env-default:"false" env-description:"Keep the full history of signed attestations and blocks for slashing protection, instead of just the highest"`
+	RetentionEpochs uint64 `yaml:"RetentionEpochs" env:"SLASHING_HISTORY_RETENTION_EPOCHS" env-default:"6750" env-description:"Number of epochs to keep the slashing protection history for"`
+}
+
+// SignedAttestation is an attestation signed by a share.
+type SignedAttestation struct {
+	SourceEpoch phase0.Epoch
+	TargetEpoch phase0.Epoch
+	SigningRoot phase0.Root
+}
+
+// SignedBlock is a block signed by a share.
+type SignedBlock struct {
+	Slot        phase0.Slot
+	SigningRoot phase0.Root
+}
+
+// History is the slashing protection history of all shares.
+type History struct {
+	db              basedb.Database
+	network         beacon.BeaconNetwork
+	retentionEpochs phase0.Epoch
+
+	// shareLocks serialize the checks of each share with their respective saves.
+	// Shares are spread over a fixed set of locks, so that removed shares don't leave locks behind.
+	shareLocks [shareLockStripes]sync.Mutex
+}
+
+// New returns a History keeping records of the given number of recent epochs.
+func New(db basedb.Database, network beacon.BeaconNetwork, retentionEpochs uint64) *History {
+	if retentionEpochs == 0 {
+		retentionEpochs = DefaultRetentionEpochs
+	}
+	return &History{
+		db:              db,
+		network:         network,
+		retentionEpochs: phase0.Epoch(retentionEpochs),
+	}
+}
+
+// Lock locks the history of the given share until the returned function is called,
+// so that checking an object, signing it and saving it can't interleave with another object of the share.
+// Shares may share a lock, so only one share may be locked at a time.
+func (h *History) Lock(pubKey []byte) (unlock func()) {
+	stripe := fnv.New32a()
+	_, _ = stripe.Write(pubKey)
+	lock := &h.shareLocks[stripe.Sum32()%shareLockStripes]
+	lock.Lock()
+	return lock.Unlock
+}
+
+// CheckAttestation returns an error if the attestation is slashable against the history.
+// Signing the same attestation again is allowed.
+func (h *History) CheckAttestation(pubKey []byte, data *phase0.AttestationData, signingRoot phase0.Root) error {
+	if data.Target.Epoch < h.oldestEpoch() {
+		return ErrOutsideWindow
+	}
+	prefix := h.prefix(attestationsPrefix, pubKey)
+
+	obj, found, err := h.db.Get(prefix, uint64Key(uint64(data.Target.Epoch)))
+	if err != nil {
+		return errors.Wrap(err, "could not get attestation")
+	}
+	if found {
+		att, err := decodeAttestation(obj)
+		if err != nil {
+			return err
+		}
+		if att.SourceEpoch == data.Source.Epoch && att.SigningRoot == signingRoot {
+			return nil
+		}
+		return fmt.Errorf("%w: target epoch %d was already signed with a different root", ErrDoubleVote, att.TargetEpoch)
+	}
+
+	// Only attestations with targets between the source and the target can be surrounded by this one.
+	err = h.db.GetRange(prefix, uint64Key(uint64(data.Source.Epoch)+1), uint64Key(uint64(data.Target.Epoch)), func(obj basedb.Obj) error {
+		att, err := decodeAttestation(obj)
+		if err != nil {
+			return err
+		}
+		if data.Source.Epoch < att.SourceEpoch {
+			return fmt.Errorf("%w: surrounds source %d, target %d", ErrSurroundVote, att.SourceEpoch, att.TargetEpoch)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Only attestations with later targets can surround this one.
+	return h.db.GetRange(prefix, uint64Key(uint64(data.Target.Epoch)+1), nil, func(obj basedb.Obj) error {
+		att, err := decodeAttestation(obj)
+		if err != nil {
+			return err
+		}
+		if att.SourceEpoch < data.Source.Epoch {
+			return fmt.Errorf("%w: surrounded by source %d, target %d", ErrSurroundVote, att.SourceEpoch, att.TargetEpoch)
+		}
+		return nil
+	})
+}
+
+// SaveAttestation records a signed attestation, and prunes the attestations of the share
+// which are older than the retention window.
+func (h *History) SaveAttestation(pubKey []byte, data *phase0.AttestationData, signingRoot phase0.Root) error {
+	value := make([]byte, 8+len(signingRoot))
+	binary.BigEndian.PutUint64(value, uint64(data.Source.Epoch))
+	copy(value[8:], signingRoot[:])
+	if err := h.db.Set(h.prefix(attestationsPrefix, pubKey), uint64Key(uint64(data.Target.Epoch)), value); err != nil {
+		return errors.Wrap(err, "could not save attestation")
+	}
+	if err := h.prune(h.prefix(attestationsPrefix, pubKey), uint64(h.oldestEpoch())); err != nil {
+		return errors.Wrap(err, "could not prune attestations")
+	}
+	return nil
+}
+
+// CheckProposal returns an error if the block is slashable against the history.
+// Signing the same block again is allowed.
+func (h *History) CheckProposal(pubKey []byte, slot phase0.Slot, signingRoot phase0.Root) error {
+	if h.network.EstimatedEpochAtSlot(slot) < h.oldestEpoch() {
+		return ErrOutsideWindow
+	}
+
+	obj, found, err := h.db.Get(h.prefix(proposalsPrefix, pubKey), uint64Key(uint64(slot)))
+	if err != nil {
+		return errors.Wrap(err, "could not get proposal")
+	}
+	if found && !bytes.Equal(obj.Value, signingRoot[:]) {
+		return fmt.Errorf("%w: slot %d was already signed with a different root", ErrDoubleProposal, slot)
+	}
+	return nil
+}
+
+// SaveProposal records a signed block, and prunes the blocks of the share
+// which are older than the retention window.
+func (h *History) SaveProposal(pubKey []byte, slot phase0.Slot, signingRoot phase0.Root) error {
+	if err := h.db.Set(h.prefix(proposalsPrefix, pubKey), uint64Key(uint64(slot)), signingRoot[:]); err != nil {
+		return errors.Wrap(err, "could not save proposal")
+	}
+	if err := h.prune(h.prefix(proposalsPrefix, pubKey), uint64(h.network.GetEpochFirstSlot(h.oldestEpoch()))); err != nil {
+		return errors.Wrap(err, "could not prune proposals")
+	}
+	return nil
+}
+
+// Attestations returns the attestations signed by the given share, ordered by target epoch.
+func (h *History) Attestations(pubKey []byte) ([]SignedAttestation, error) {
+	return h.attestations(pubKey)
+}
+
+// Proposals returns the blocks signed by the given share, ordered by slot.
+func (h *History) Proposals(pubKey []byte) ([]SignedBlock, error) {
+	var blocks []SignedBlock
+	err := h.db.GetAll(h.prefix(proposalsPrefix, pubKey), func(i int, obj basedb.Obj) error {
+		if len(obj.Key) != 8 || len(obj.Value) != len(phase0.Root{}) {
+			return fmt.Errorf("invalid proposal record")
+		}
+		blocks = append(blocks, SignedBlock{
+			Slot:        phase0.Slot(binary.BigEndian.Uint64(obj.Key)),
+			SigningRoot: phase0.Root(obj.Value),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list proposals")
+	}
+	sort.Slice(blocks, func(i, j int) bool { return blocks[i].Slot < blocks[j].Slot })
+	return blocks, nil
+}
+
+// Remove removes the history of the given share.
+func (h *History) Remove(pubKey []byte) error {
+	defer h.Lock(pubKey)()
+
+	if err := h.db.DropPrefix(h.prefix(attestationsPrefix, pubKey)); err != nil {
+		return errors.Wrap(err, "could not remove attestations")
+	}
+	if err := h.db.DropPrefix(h.prefix(proposalsPrefix, pubKey)); err != nil {
+		return errors.Wrap(err, "could not remove proposals")
+	}
+	return nil
+}
+
+func (h *History) attestations(pubKey []byte) ([]SignedAttestation, error) {
+	var attestations []SignedAttestation
+	err := h.db.GetAll(h.prefix(attestationsPrefix, pubKey), func(i int, obj basedb.Obj) error {
+		att, err := decodeAttestation(obj)
+		if err != nil {
+			return err
+		}
+		attestations = append(attestations, att)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list attestations")
+	}
+	sort.Slice(attestations, func(i, j int) bool { return attestations[i].TargetEpoch < attestations[j].TargetEpoch })
+	return attestations, nil
+}
+
+func decodeAttestation(obj basedb.Obj) (SignedAttestation, error) {
+	if len(obj.Key) != 8 || len(obj.Value) != 8+len(phase0.Root{}) {
+		return SignedAttestation{}, fmt.Errorf("invalid attestation record")
+	}
+	return SignedAttestation{
+		SourceEpoch: phase0.Epoch(binary.BigEndian.Uint64(obj.Value)),
+		TargetEpoch: phase0.Epoch(binary.BigEndian.Uint64(obj.Key)),
+		SigningRoot: phase0.Root(obj.Value[8:]),
+	}, nil
+}
+
+// prune removes the records under the prefix whose keys are below the given epoch or slot.
+func (h *History) prune(prefix []byte, oldest uint64) error {
+	return h.db.GetKeys(prefix, nil, uint64Key(oldest), func(key []byte) error {
+		return h.db.Delete(prefix, key)
+	})
+}
+
+func (h *History) oldestEpoch() phase0.Epoch {
+	currentEpoch := h.network.EstimatedCurrentEpoch()
+	if currentEpoch < h.retentionEpochs {
+		return 0
+	}
+	return currentEpoch - h.retentionEpochs
+}
+
+func (h *History) prefix(prefix string, pubKey []byte) []byte {
+	return append([]byte(string(h.network.GetBeaconNetwork())+prefix), pubKey...)
+}
+
+func uint64Key(v uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, v)
+	return b
+}
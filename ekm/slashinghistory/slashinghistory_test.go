@@ -0,0 +1,134 @@
+package slashinghistory
+
+import (
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	spectypes "github.com/ssvlabs/ssv-spec/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ssvlabs/ssv/logging"
+	"github.com/ssvlabs/ssv/protocol/v2/blockchain/beacon"
+	"github.com/ssvlabs/ssv/storage/basedb"
+	"github.com/ssvlabs/ssv/storage/kv"
+)
+
+func newHistory(t *testing.T, retentionEpochs uint64) (*History, beacon.BeaconNetwork) {
+	db, err := kv.NewInMemory(logging.TestLogger(t), basedb.Options{})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	network := beacon.NewNetwork(spectypes.MainNetwork)
+	return New(db, network, retentionEpochs), network
+}
+
+func attestation(source, target phase0.Epoch) *phase0.AttestationData {
+	return &phase0.AttestationData{
+		Source: &phase0.Checkpoint{Epoch: source},
+		Target: &phase0.Checkpoint{Epoch: target},
+	}
+}
+
+// checkAndSaveAttestation checks the attestation and saves it unless it's slashable, like signing does.
+func checkAndSaveAttestation(h *History, pubKey []byte, data *phase0.AttestationData, signingRoot phase0.Root) error {
+	if err := h.CheckAttestation(pubKey, data, signingRoot); err != nil {
+		return err
+	}
+	return h.SaveAttestation(pubKey, data, signingRoot)
+}
+
+func checkAndSaveProposal(h *History, pubKey []byte, slot phase0.Slot, signingRoot phase0.Root) error {
+	if err := h.CheckProposal(pubKey, slot, signingRoot); err != nil {
+		return err
+	}
+	return h.SaveProposal(pubKey, slot, signingRoot)
+}
+
+func TestAttestations(t *testing.T) {
+	h, network := newHistory(t, 100)
+	pk1, pk2 := []byte{1}, []byte{2}
+	epoch := network.EstimatedCurrentEpoch()
+
+	require.NoError(t, checkAndSaveAttestation(h, pk1, attestation(epoch-10, epoch-5), phase0.Root{1}))
+	require.NoError(t, checkAndSaveAttestation(h, pk1, attestation(epoch-4, epoch-3), phase0.Root{2}))
+
+	// Signing the same attestation again is allowed.
+	require.NoError(t, checkAndSaveAttestation(h, pk1, attestation(epoch-10, epoch-5), phase0.Root{1}))
+
+	// Same target with a different root.
+	err := checkAndSaveAttestation(h, pk1, attestation(epoch-10, epoch-5), phase0.Root{3})
+	require.ErrorIs(t, err, ErrDoubleVote)
+
+	// Surrounded by (epoch-10, epoch-5).
+	err = checkAndSaveAttestation(h, pk1, attestation(epoch-8, epoch-6), phase0.Root{4})
+	require.ErrorIs(t, err, ErrSurroundVote)
+
+	// Surrounds (epoch-4, epoch-3), which the highest attestation alone can't detect.
+	err = checkAndSaveAttestation(h, pk1, attestation(epoch-5, epoch-2), phase0.Root{5})
+	require.ErrorIs(t, err, ErrSurroundVote)
+
+	// Surrounds (epoch-10, epoch-5) from the other side.
+	err = checkAndSaveAttestation(h, pk1, attestation(epoch-11, epoch-4), phase0.Root{5})
+	require.ErrorIs(t, err, ErrSurroundVote)
+
+	// A check alone doesn't record the attestation.
+	require.NoError(t, h.CheckAttestation(pk1, attestation(epoch-3, epoch-1), phase0.Root{7}))
+	require.NoError(t, h.CheckAttestation(pk1, attestation(epoch-3, epoch-1), phase0.Root{8}))
+
+	// Other shares are unaffected.
+	require.NoError(t, checkAndSaveAttestation(h, pk2, attestation(epoch-8, epoch-6), phase0.Root{4}))
+
+	// Older than the window.
+	err = checkAndSaveAttestation(h, pk1, attestation(epoch-200, epoch-150), phase0.Root{6})
+	require.ErrorIs(t, err, ErrOutsideWindow)
+
+	attestations, err := h.Attestations(pk1)
+	require.NoError(t, err)
+	require.Equal(t, []SignedAttestation{
+		{SourceEpoch: epoch - 10, TargetEpoch: epoch - 5, SigningRoot: phase0.Root{1}},
+		{SourceEpoch: epoch - 4, TargetEpoch: epoch - 3, SigningRoot: phase0.Root{2}},
+	}, attestations)
+
+	require.NoError(t, h.Remove(pk1))
+	attestations, err = h.Attestations(pk1)
+	require.NoError(t, err)
+	require.Empty(t, attestations)
+}
+
+func TestProposals(t *testing.T) {
+	h, network := newHistory(t, 100)
+	pk := []byte{1}
+	slot := network.EstimatedCurrentSlot()
+
+	require.NoError(t, checkAndSaveProposal(h, pk, slot, phase0.Root{1}))
+	require.NoError(t, checkAndSaveProposal(h, pk, slot, phase0.Root{1}))
+	require.ErrorIs(t, checkAndSaveProposal(h, pk, slot, phase0.Root{2}), ErrDoubleProposal)
+	require.NoError(t, checkAndSaveProposal(h, pk, slot+1, phase0.Root{2}))
+
+	blocks, err := h.Proposals(pk)
+	require.NoError(t, err)
+	require.Equal(t, []SignedBlock{{Slot: slot, SigningRoot: phase0.Root{1}}, {Slot: slot + 1, SigningRoot: phase0.Root{2}}}, blocks)
+}
+
+func TestPruning(t *testing.T) {
+	h, network := newHistory(t, 10)
+	pk := []byte{1}
+	epoch := network.EstimatedCurrentEpoch()
+
+	// Records which fall out of the window are pruned on the next save.
+	h.retentionEpochs = 100
+	require.NoError(t, checkAndSaveAttestation(h, pk, attestation(epoch-51, epoch-50), phase0.Root{1}))
+	require.NoError(t, checkAndSaveProposal(h, pk, network.GetEpochFirstSlot(epoch-50), phase0.Root{1}))
+	h.retentionEpochs = 10
+	require.NoError(t, checkAndSaveAttestation(h, pk, attestation(epoch-1, epoch), phase0.Root{2}))
+	require.NoError(t, checkAndSaveProposal(h, pk, network.GetEpochFirstSlot(epoch), phase0.Root{2}))
+
+	attestations, err := h.Attestations(pk)
+	require.NoError(t, err)
+	require.Len(t, attestations, 1)
+	require.Equal(t, epoch, attestations[0].TargetEpoch)
+
+	blocks, err := h.Proposals(pk)
+	require.NoError(t, err)
+	require.Len(t, blocks, 1)
+}
@@ -0,0 +1,210 @@
+// Package web3signer is a client of Web3Signer-compatible remote signers:
+// it signs through the eth2 signing API and manages keys through the key manager API.
+package web3signer
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+const (
+	signPath      = "/api/v1/eth2/sign/"
+	keystoresPath = "/eth/v1/keystores"
+	upCheckPath   = "/upcheck"
+
+	defaultTimeout = 10 * time.Second
+)
+
+// Client is a client of a Web3Signer-compatible remote signer.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// Option defines a Client configuration option.
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client, e.g. for TLS client authentication or a custom timeout.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// New returns a client of the signer at the given base URL.
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{Timeout: defaultTimeout},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Sign signs the request with the key of the given public key.
+func (c *Client) Sign(ctx context.Context, pubKey []byte, request *SignRequest) (phase0.BLSSignature, error) {
+	respBody, contentType, err := c.do(ctx, http.MethodPost, signPath+"0x"+hex.EncodeToString(pubKey), request)
+	if err != nil {
+		return phase0.BLSSignature{}, err
+	}
+
+	// Web3Signer responds with JSON when it's accepted, and with the plain hex signature otherwise.
+	signature := strings.TrimSpace(string(respBody))
+	if strings.HasPrefix(contentType, "application/json") {
+		var resp struct {
+			Signature string `json:"signature"`
+		}
+		if err := json.Unmarshal(respBody, &resp); err != nil {
+			return phase0.BLSSignature{}, fmt.Errorf("could not decode signature response: %w", err)
+		}
+		signature = resp.Signature
+	}
+
+	b, err := hex.DecodeString(strings.TrimPrefix(signature, "0x"))
+	if err != nil || len(b) != phase0.SignatureLength {
+		return phase0.BLSSignature{}, fmt.Errorf("invalid signature %q", signature)
+	}
+	return phase0.BLSSignature(b), nil
+}
+
+// ImportKeystores imports EIP-2335 keystores, decrypted by the respective passwords.
+func (c *Client) ImportKeystores(ctx context.Context, keystores, passwords []string) ([]KeystoreStatus, error) {
+	request := struct {
+		Keystores []string `json:"keystores"`
+		Passwords []string `json:"passwords"`
+	}{
+		Keystores: keystores,
+		Passwords: passwords,
+	}
+	respBody, _, err := c.do(ctx, http.MethodPost, keystoresPath, request)
+	if err != nil {
+		return nil, err
+	}
+	return decodeStatuses(respBody, len(keystores))
+}
+
+// DeleteKeystores deletes the keys of the given public keys.
+func (c *Client) DeleteKeystores(ctx context.Context, pubKeys [][]byte) ([]KeystoreStatus, error) {
+	request := struct {
+		PubKeys []string `json:"pubkeys"`
+	}{
+		PubKeys: make([]string, len(pubKeys)),
+	}
+	for i, pk := range pubKeys {
+		request.PubKeys[i] = "0x" + hex.EncodeToString(pk)
+	}
+	respBody, _, err := c.do(ctx, http.MethodDelete, keystoresPath, request)
+	if err != nil {
+		return nil, err
+	}
+	return decodeStatuses(respBody, len(pubKeys))
+}
+
+// UpCheck returns an error if the signer isn't up.
+func (c *Client) UpCheck(ctx context.Context) error {
+	_, _, err := c.do(ctx, http.MethodGet, upCheckPath, nil)
+	return err
+}
+
+func (c *Client) do(ctx context.Context, method, path string, request any) ([]byte, string, error) {
+	var body io.Reader
+	if request != nil {
+		b, err := json.Marshal(request)
+		if err != nil {
+			return nil, "", fmt.Errorf("could not encode request: %w", err)
+		}
+		body = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return nil, "", fmt.Errorf("could not create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("%s %s failed: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("could not read response of %s %s: %w", method, path, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("%s %s failed with status %d: %s", method, path, resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	return respBody, resp.Header.Get("Content-Type"), nil
+}
+
+func decodeStatuses(respBody []byte, expected int) ([]KeystoreStatus, error) {
+	var resp struct {
+		Data []KeystoreStatus `json:"data"`
+	}
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("could not decode response: %w", err)
+	}
+	if len(resp.Data) != expected {
+		return nil, fmt.Errorf("expected %d statuses, got %d", expected, len(resp.Data))
+	}
+	return resp.Data, nil
+}
+
+// Config holds the remote signer configuration.
+type Config struct {
+	URL            string        `yaml:"URL" env:"REMOTE_SIGNER_URL" env-description:"URL of a Web3Signer-compatible remote signer to keep share keys in instead of the node's database"`
+	RequestTimeout time.Duration `yaml:"RequestTimeout" env:"REMOTE_SIGNER_REQUEST_TIMEOUT" env-default:"10s" env-description:"Timeout of remote signer requests"`
+	CACertFile     string        `yaml:"CACertFile" env:"REMOTE_SIGNER_CA_CERT_FILE" env-description:"Path to the CA certificate to verify the remote signer with"`
+	ClientCertFile string        `yaml:"ClientCertFile" env:"REMOTE_SIGNER_CLIENT_CERT_FILE" env-description:"Path to the client certificate to authenticate to the remote signer with"`
+	ClientKeyFile  string        `yaml:"ClientKeyFile" env:"REMOTE_SIGNER_CLIENT_KEY_FILE" env-description:"Path to the private key of the client certificate"`
+}
+
+// HTTPClient returns an HTTP client with the configured timeout and TLS settings.
+func (c Config) HTTPClient() (*http.Client, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if c.CACertFile != "" {
+		// nolint: gosec
+		caCert, err := os.ReadFile(c.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not read CA certificate: %w", err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("invalid CA certificate")
+		}
+	}
+	if c.ClientCertFile != "" || c.ClientKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.ClientCertFile, c.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	timeout := c.RequestTimeout
+	if timeout == 0 {
+		timeout = defaultTimeout
+	}
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: &http.Transport{TLSClientConfig: tlsConfig, Proxy: http.ProxyFromEnvironment},
+	}, nil
+}
@@ -0,0 +1,72 @@
+package web3signer_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/herumi/bls-eth-go-binary/bls"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ssvlabs/ssv/ekm/web3signer"
+	"github.com/ssvlabs/ssv/ekm/web3signer/web3signertest"
+)
+
+func TestClient(t *testing.T) {
+	require.NoError(t, bls.Init(bls.BLS12_381))
+	ctx := context.Background()
+
+	stub := web3signertest.New()
+	defer stub.Close()
+	client := web3signer.New(stub.URL + "/")
+	require.NoError(t, client.UpCheck(ctx))
+
+	sk := &bls.SecretKey{}
+	sk.SetByCSPRNG()
+	pk := sk.GetPublicKey().Serialize()
+
+	keystore, err := web3signer.NewKeystore(sk.Serialize(), pk, "password")
+	require.NoError(t, err)
+
+	statuses, err := client.ImportKeystores(ctx, []string{keystore}, []string{"password"})
+	require.NoError(t, err)
+	require.Equal(t, web3signer.StatusImported, statuses[0].Status)
+	statuses, err = client.ImportKeystores(ctx, []string{keystore}, []string{"password"})
+	require.NoError(t, err)
+	require.Equal(t, web3signer.StatusDuplicate, statuses[0].Status)
+
+	request := &web3signer.SignRequest{
+		Type:        web3signer.TypeRandaoReveal,
+		SigningRoot: phase0.Root{1, 2, 3},
+		RandaoReveal: &web3signer.RandaoReveal{
+			Epoch: 10,
+		},
+	}
+	sig, err := client.Sign(ctx, pk, request)
+	require.NoError(t, err)
+	root := request.SigningRoot
+	require.Equal(t, sk.SignByte(root[:]).Serialize(), sig[:])
+	require.Equal(t, []*web3signer.SignRequest{request}, stub.Requests())
+
+	statuses, err = client.DeleteKeystores(ctx, [][]byte{pk})
+	require.NoError(t, err)
+	require.Equal(t, web3signer.StatusDeleted, statuses[0].Status)
+
+	_, err = client.Sign(ctx, pk, request)
+	require.ErrorContains(t, err, "status 404")
+}
+
+func TestClientPlainSignature(t *testing.T) {
+	const signature = "0xb3baa751d0a9132cfe93e4e3d5ff9075111100e3789dca219ade5a24d27e19d16b3353149da1833e9b691bb38634e8dc04469be7032132906c927d7e1a49b414730612877bc6b2810c8f202daf793d1ab0d6b5cb21d52f9e52e883859887a5d9"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte(signature))
+	}))
+	defer server.Close()
+
+	sig, err := web3signer.New(server.URL).Sign(context.Background(), []byte{1}, &web3signer.SignRequest{Type: web3signer.TypeRandaoReveal})
+	require.NoError(t, err)
+	require.Equal(t, signature, sig.String())
+}
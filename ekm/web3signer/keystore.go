@@ -0,0 +1,31 @@
+package web3signer
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	keystorev4 "github.com/wealdtech/go-eth2-wallet-encryptor-keystorev4"
+)
+
+// NewKeystore encrypts a BLS secret key into an EIP-2335 keystore.
+func NewKeystore(secretKey, pubKey []byte, password string) (string, error) {
+	crypto, err := keystorev4.New().Encrypt(secretKey, password)
+	if err != nil {
+		return "", fmt.Errorf("could not encrypt secret key: %w", err)
+	}
+
+	keystore := map[string]any{
+		"crypto":  crypto,
+		"pubkey":  hex.EncodeToString(pubKey),
+		"path":    "",
+		"uuid":    uuid.New().String(),
+		"version": 4,
+	}
+	b, err := json.Marshal(keystore)
+	if err != nil {
+		return "", fmt.Errorf("could not encode keystore: %w", err)
+	}
+	return string(b), nil
+}
@@ -0,0 +1,83 @@
+package web3signer
+
+import (
+	eth2apiv1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/altair"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+// SignedObjectType is the type of the object to sign.
+type SignedObjectType string
+
+const (
+	TypeAggregationSlot                   SignedObjectType = "AGGREGATION_SLOT"
+	TypeAggregateAndProof                 SignedObjectType = "AGGREGATE_AND_PROOF"
+	TypeAttestation                       SignedObjectType = "ATTESTATION"
+	TypeBlockV2                           SignedObjectType = "BLOCK_V2"
+	TypeRandaoReveal                      SignedObjectType = "RANDAO_REVEAL"
+	TypeVoluntaryExit                     SignedObjectType = "VOLUNTARY_EXIT"
+	TypeSyncCommitteeMessage              SignedObjectType = "SYNC_COMMITTEE_MESSAGE"
+	TypeSyncCommitteeSelectionProof       SignedObjectType = "SYNC_COMMITTEE_SELECTION_PROOF"
+	TypeSyncCommitteeContributionAndProof SignedObjectType = "SYNC_COMMITTEE_CONTRIBUTION_AND_PROOF"
+	TypeValidatorRegistration             SignedObjectType = "VALIDATOR_REGISTRATION"
+)
+
+// ForkInfo is the fork information the signer computes the signing domain from.
+type ForkInfo struct {
+	Fork                  *phase0.Fork `json:"fork"`
+	GenesisValidatorsRoot phase0.Root  `json:"genesis_validators_root"`
+}
+
+// SignRequest is the body of a signing request. Besides the type and the signing root,
+// only the field of the respective type is set.
+type SignRequest struct {
+	Type        SignedObjectType `json:"type"`
+	ForkInfo    *ForkInfo        `json:"fork_info,omitempty"`
+	SigningRoot phase0.Root      `json:"signingRoot"`
+
+	AggregationSlot             *AggregationSlot                    `json:"aggregation_slot,omitempty"`
+	AggregateAndProof           *phase0.AggregateAndProof           `json:"aggregate_and_proof,omitempty"`
+	Attestation                 *phase0.AttestationData             `json:"attestation,omitempty"`
+	BeaconBlock                 *BeaconBlock                        `json:"beacon_block,omitempty"`
+	RandaoReveal                *RandaoReveal                       `json:"randao_reveal,omitempty"`
+	VoluntaryExit               *phase0.VoluntaryExit               `json:"voluntary_exit,omitempty"`
+	SyncCommitteeMessage        *SyncCommitteeMessage               `json:"sync_committee_message,omitempty"`
+	SyncAggregatorSelectionData *altair.SyncAggregatorSelectionData `json:"sync_aggregator_selection_data,omitempty"`
+	ContributionAndProof        *altair.ContributionAndProof        `json:"contribution_and_proof,omitempty"`
+	ValidatorRegistration       *eth2apiv1.ValidatorRegistration    `json:"validator_registration,omitempty"`
+}
+
+type AggregationSlot struct {
+	Slot phase0.Slot `json:"slot,string"`
+}
+
+// BeaconBlock is a block of a post-Bellatrix version, which is signed by its header.
+type BeaconBlock struct {
+	Version     string                    `json:"version"`
+	BlockHeader *phase0.BeaconBlockHeader `json:"block_header"`
+}
+
+type RandaoReveal struct {
+	Epoch phase0.Epoch `json:"epoch,string"`
+}
+
+type SyncCommitteeMessage struct {
+	BeaconBlockRoot phase0.Root `json:"beacon_block_root"`
+	Slot            phase0.Slot `json:"slot,string"`
+}
+
+// KeystoreStatus is the result of importing or deleting a single keystore.
+type KeystoreStatus struct {
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// Keystore statuses of the key manager API.
+const (
+	StatusImported  = "imported"
+	StatusDuplicate = "duplicate"
+	StatusDeleted   = "deleted"
+	StatusNotActive = "not_active"
+	StatusNotFound  = "not_found"
+	StatusError     = "error"
+)
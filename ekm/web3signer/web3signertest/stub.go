@@ -0,0 +1,155 @@
+// Package web3signertest provides an in-process stub of a Web3Signer-compatible remote signer for tests.
+package web3signertest
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/herumi/bls-eth-go-binary/bls"
+	keystorev4 "github.com/wealdtech/go-eth2-wallet-encryptor-keystorev4"
+
+	"github.com/ssvlabs/ssv/ekm/web3signer"
+)
+
+// Signer is a stub signer which keeps imported keys in memory and signs the signing root of every request.
+type Signer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	keys     map[string]*bls.SecretKey
+	requests []*web3signer.SignRequest
+}
+
+// New starts a stub signer. BLS must be initialized by the caller.
+func New() *Signer {
+	s := &Signer{keys: map[string]*bls.SecretKey{}}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// Requests returns the signing requests received so far.
+func (s *Signer) Requests() []*web3signer.SignRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*web3signer.SignRequest(nil), s.requests...)
+}
+
+// HasKey tells whether the key of the given public key was imported.
+func (s *Signer) HasKey(pubKey []byte) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.keys[hex.EncodeToString(pubKey)]
+	return ok
+}
+
+func (s *Signer) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/upcheck":
+		_, _ = w.Write([]byte("OK"))
+	case r.Method == http.MethodPost && r.URL.Path == "/eth/v1/keystores":
+		s.importKeystores(w, r)
+	case r.Method == http.MethodDelete && r.URL.Path == "/eth/v1/keystores":
+		s.deleteKeystores(w, r)
+	case r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, "/api/v1/eth2/sign/"):
+		s.sign(w, r, strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/api/v1/eth2/sign/"), "0x"))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Signer) importKeystores(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Keystores []string `json:"keystores"`
+		Passwords []string `json:"passwords"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || len(request.Keystores) != len(request.Passwords) {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+
+	var response struct {
+		Data []web3signer.KeystoreStatus `json:"data"`
+	}
+	for i, keystore := range request.Keystores {
+		var decoded struct {
+			Crypto map[string]any `json:"crypto"`
+			PubKey string         `json:"pubkey"`
+		}
+		if err := json.Unmarshal([]byte(keystore), &decoded); err != nil {
+			response.Data = append(response.Data, web3signer.KeystoreStatus{Status: web3signer.StatusError, Message: err.Error()})
+			continue
+		}
+		secret, err := keystorev4.New().Decrypt(decoded.Crypto, request.Passwords[i])
+		if err != nil {
+			response.Data = append(response.Data, web3signer.KeystoreStatus{Status: web3signer.StatusError, Message: err.Error()})
+			continue
+		}
+		sk := &bls.SecretKey{}
+		if err := sk.Deserialize(secret); err != nil {
+			response.Data = append(response.Data, web3signer.KeystoreStatus{Status: web3signer.StatusError, Message: err.Error()})
+			continue
+		}
+		pk := hex.EncodeToString(sk.GetPublicKey().Serialize())
+		if _, ok := s.keys[pk]; ok {
+			response.Data = append(response.Data, web3signer.KeystoreStatus{Status: web3signer.StatusDuplicate})
+			continue
+		}
+		s.keys[pk] = sk
+		response.Data = append(response.Data, web3signer.KeystoreStatus{Status: web3signer.StatusImported})
+	}
+	writeJSON(w, response)
+}
+
+func (s *Signer) deleteKeystores(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		PubKeys []string `json:"pubkeys"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+
+	var response struct {
+		Data []web3signer.KeystoreStatus `json:"data"`
+	}
+	for _, pubKey := range request.PubKeys {
+		pk := strings.TrimPrefix(pubKey, "0x")
+		if _, ok := s.keys[pk]; !ok {
+			response.Data = append(response.Data, web3signer.KeystoreStatus{Status: web3signer.StatusNotFound})
+			continue
+		}
+		delete(s.keys, pk)
+		response.Data = append(response.Data, web3signer.KeystoreStatus{Status: web3signer.StatusDeleted})
+	}
+	writeJSON(w, response)
+}
+
+func (s *Signer) sign(w http.ResponseWriter, r *http.Request, pubKey string) {
+	var request web3signer.SignRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	sk, ok := s.keys[pubKey]
+	if !ok {
+		http.Error(w, "key not found", http.StatusNotFound)
+		return
+	}
+	s.requests = append(s.requests, &request)
+
+	root := request.SigningRoot
+	signature := sk.SignByte(root[:]).Serialize()
+	writeJSON(w, map[string]string{"signature": "0x" + hex.EncodeToString(signature)})
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
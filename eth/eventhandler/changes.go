@@ -0,0 +1,187 @@
+package eventhandler
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	spectypes "github.com/ssvlabs/ssv-spec/types"
+
+	ssvtypes "github.com/ssvlabs/ssv/protocol/v2/types"
+	registrystorage "github.com/ssvlabs/ssv/registry/storage"
+	"github.com/ssvlabs/ssv/storage/basedb"
+)
+
+// OperatorChange is a change of an operator by the events of a block.
+// Previous or Current is nil if the operator didn't exist before or after the block.
+type OperatorChange struct {
+	ID       spectypes.OperatorID
+	Previous *registrystorage.OperatorData
+	Current  *registrystorage.OperatorData
+}
+
+// ShareChange is a change of a share by the events of a block.
+// Previous or Current is nil if the share didn't exist before or after the block.
+type ShareChange struct {
+	ValidatorPubKey spectypes.ValidatorPK
+	Previous        *ssvtypes.SSVShare
+	Current         *ssvtypes.SSVShare
+}
+
+// RecipientChange is a change of an owner's recipient data by the events of a block.
+// Previous or Current is nil if the recipient data didn't exist before or after the block.
+type RecipientChange struct {
+	Owner    ethcommon.Address
+	Previous *registrystorage.RecipientData
+	Current  *registrystorage.RecipientData
+}
+
+// BlockChanges are the changes which the events of a block made to the registry state.
+type BlockChanges struct {
+	Operators  []OperatorChange
+	Shares     []ShareChange
+	Recipients []RecipientChange
+}
+
+// Changes returns the changes which the events of the last processed block made, read from its undo record.
+func (eh *EventHandler) Changes(blockNumber uint64) (*BlockChanges, error) {
+	txn := eh.nodeStorage.BeginRead()
+	defer txn.Discard()
+
+	lastProcessedBlock, found, err := eh.nodeStorage.GetLastProcessedBlock(txn)
+	if err != nil {
+		return nil, fmt.Errorf("get last processed block: %w", err)
+	}
+	// The current state is only the state after the block if no later block was processed.
+	if !found || lastProcessedBlock == nil || lastProcessedBlock.Uint64() != blockNumber {
+		return nil, fmt.Errorf("block %d is not the last processed block", blockNumber)
+	}
+
+	obj, found, err := txn.Get(undoPrefix, undoKey(blockNumber))
+	if err != nil {
+		return nil, fmt.Errorf("could not get undo record: %w", err)
+	}
+	if !found {
+		return nil, fmt.Errorf("no undo record of block %d", blockNumber)
+	}
+	var record undoRecord
+	if err := json.Unmarshal(obj.Value, &record); err != nil {
+		return nil, fmt.Errorf("could not unmarshal undo record: %w", err)
+	}
+
+	changes := &BlockChanges{}
+	seen := make(map[string]bool, len(record.Changes))
+	for _, change := range record.Changes {
+		// The first change of an entity holds its state before the block.
+		id := string(change.Kind) + string(change.Key)
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		switch change.Kind {
+		case undoOperator:
+			err = eh.operatorChange(txn, change, changes)
+		case undoShare:
+			err = eh.shareChange(txn, change, changes)
+		case undoRecipient:
+			err = eh.recipientChange(txn, change, changes)
+		default:
+			err = fmt.Errorf("unknown undo kind %q", change.Kind)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return changes, nil
+}
+
+func (eh *EventHandler) operatorChange(r basedb.Reader, change undoChange, changes *BlockChanges) error {
+	id := binary.BigEndian.Uint64(change.Key)
+	current, found, err := eh.nodeStorage.GetOperatorData(r, id)
+	if err != nil {
+		return fmt.Errorf("could not get operator data: %w", err)
+	}
+	if !found {
+		current = nil
+	}
+	var encodedCurrent []byte
+	if current != nil {
+		if encodedCurrent, err = json.Marshal(current); err != nil {
+			return fmt.Errorf("could not marshal operator data: %w", err)
+		}
+	}
+	if bytes.Equal(change.Previous, encodedCurrent) {
+		return nil
+	}
+
+	operatorChange := OperatorChange{ID: id, Current: current}
+	if change.Previous != nil {
+		operatorChange.Previous = &registrystorage.OperatorData{}
+		if err := json.Unmarshal(change.Previous, operatorChange.Previous); err != nil {
+			return fmt.Errorf("could not unmarshal operator data: %w", err)
+		}
+	}
+	changes.Operators = append(changes.Operators, operatorChange)
+	return nil
+}
+
+func (eh *EventHandler) shareChange(r basedb.Reader, change undoChange, changes *BlockChanges) error {
+	current, exists := eh.nodeStorage.Shares().Get(r, change.Key)
+	if !exists {
+		current = nil
+	}
+	var encodedCurrent []byte
+	if current != nil {
+		var err error
+		if encodedCurrent, err = registrystorage.EncodeShare(current); err != nil {
+			return fmt.Errorf("could not encode share: %w", err)
+		}
+	}
+	if bytes.Equal(change.Previous, encodedCurrent) {
+		return nil
+	}
+
+	shareChange := ShareChange{ValidatorPubKey: spectypes.ValidatorPK(change.Key), Current: current}
+	if change.Previous != nil {
+		previous, err := registrystorage.DecodeShare(change.Previous)
+		if err != nil {
+			return fmt.Errorf("could not decode share: %w", err)
+		}
+		shareChange.Previous = previous
+	}
+	changes.Shares = append(changes.Shares, shareChange)
+	return nil
+}
+
+func (eh *EventHandler) recipientChange(r basedb.Reader, change undoChange, changes *BlockChanges) error {
+	owner := ethcommon.BytesToAddress(change.Key)
+	current, found, err := eh.nodeStorage.GetRecipientData(r, owner)
+	if err != nil {
+		return fmt.Errorf("could not get recipient data: %w", err)
+	}
+	if !found {
+		current = nil
+	}
+	var encodedCurrent []byte
+	if current != nil {
+		if encodedCurrent, err = json.Marshal(current); err != nil {
+			return fmt.Errorf("could not marshal recipient data: %w", err)
+		}
+	}
+	if bytes.Equal(change.Previous, encodedCurrent) {
+		return nil
+	}
+
+	recipientChange := RecipientChange{Owner: owner, Current: current}
+	if change.Previous != nil {
+		recipientChange.Previous = &registrystorage.RecipientData{}
+		if err := json.Unmarshal(change.Previous, recipientChange.Previous); err != nil {
+			return fmt.Errorf("could not unmarshal recipient data: %w", err)
+		}
+	}
+	changes.Recipients = append(changes.Recipients, recipientChange)
+	return nil
+}
@@ -0,0 +1,55 @@
+package eventhandler
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	spectypes "github.com/ssvlabs/ssv-spec/types"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	registrystorage "github.com/ssvlabs/ssv/registry/storage"
+)
+
+func TestChanges(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ops, err := createOperators(1, 0)
+	require.NoError(t, err)
+	eh, _, err := setupEventHandler(t, ctx, zaptest.NewLogger(t), nil, ops[0], false)
+	require.NoError(t, err)
+
+	owner := ethcommon.HexToAddress("0x97a6C1f3aaB5427B901fb135ED492749191C0f1F")
+	txn := eh.nodeStorage.Begin()
+	eh.undo = &undoRecord{BlockNumber: 1}
+	require.NoError(t, eh.recordOperator(txn, 100))
+	_, err = eh.nodeStorage.SaveOperatorData(txn, &registrystorage.OperatorData{ID: 100, PublicKey: []byte("pk"), OwnerAddress: owner})
+	require.NoError(t, err)
+	require.NoError(t, eh.recordRecipient(txn, owner))
+	require.NoError(t, eh.nodeStorage.BumpNonce(txn, owner))
+	require.NoError(t, eh.recordRecipient(txn, owner))
+	require.NoError(t, eh.nodeStorage.BumpNonce(txn, owner))
+	// Unchanged entities aren't reported.
+	pubKey := spectypes.ValidatorPK{1, 2, 3}
+	require.NoError(t, eh.recordShare(txn, pubKey[:]))
+	require.NoError(t, eh.saveUndoRecord(txn, eh.undo))
+	require.NoError(t, eh.nodeStorage.SaveLastProcessedBlock(txn, big.NewInt(1)))
+	require.NoError(t, txn.Commit())
+	eh.undo = nil
+
+	changes, err := eh.Changes(1)
+	require.NoError(t, err)
+	require.Len(t, changes.Operators, 1)
+	require.Nil(t, changes.Operators[0].Previous)
+	require.Equal(t, owner, changes.Operators[0].Current.OwnerAddress)
+	require.Empty(t, changes.Shares)
+	require.Len(t, changes.Recipients, 1)
+	require.Nil(t, changes.Recipients[0].Previous)
+	require.EqualValues(t, 1, *changes.Recipients[0].Current.Nonce)
+
+	_, err = eh.Changes(0)
+	require.Error(t, err)
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"sync"
 	"time"
 
 	"github.com/ssvlabs/ssv/ekm"
@@ -23,6 +24,7 @@ import (
 	"github.com/ssvlabs/ssv/logging/fields"
 	"github.com/ssvlabs/ssv/networkconfig"
 	operatordatastore "github.com/ssvlabs/ssv/operator/datastore"
+	"github.com/ssvlabs/ssv/operator/eventstream"
 	"github.com/ssvlabs/ssv/operator/keys"
 	nodestorage "github.com/ssvlabs/ssv/operator/storage"
 	beaconprotocol "github.com/ssvlabs/ssv/protocol/v2/blockchain/beacon"
@@ -69,6 +71,24 @@ type EventHandler struct {
 	fullNode bool
 	logger   *zap.Logger
 	metrics  metrics
+
+	// eventStream is nil unless set with WithEventStream, along with blockHeaders.
+	eventStream  *eventstream.Stream
+	blockHeaders BlockHeaders
+
+	undoRetention uint64
+
+	// processMu serializes the processing of blocks, local events and rollbacks, which are called
+	// from the goroutines of the event syncer and the local events watcher, and guards the state
+	// of the block being processed below.
+	processMu sync.Mutex
+	// lifecycleEvents are queued until the changes of the events being processed are committed.
+	lifecycleEvents []lifecycleEvent
+	// undo records the changes of the block being processed, and is nil when processing local events.
+	undo *undoRecord
+	// localBlockEvents are the numbers of local events seen in each block at or below the last processed one,
+	// to warn about the events added to them in reloaded files, which are never processed.
+	localBlockEvents map[uint64]int
 }
 
 func New(
@@ -93,6 +113,7 @@ func New(
 		beacon:            beacon,
 		logger:            zap.NewNop(),
 		metrics:           nopMetrics{},
+		undoRetention:     DefaultUndoRetention,
 	}
 
 	for _, opt := range opts {
@@ -140,6 +161,9 @@ func (eh *EventHandler) HandleBlockEventsStream(logs <-chan executionclient.Bloc
 }
 
 func (eh *EventHandler) processBlockEvents(block executionclient.BlockLogs) ([]Task, error) {
+	eh.processMu.Lock()
+	defer eh.processMu.Unlock()
+
 	txn := eh.nodeStorage.Begin()
 	defer txn.Discard()
 
@@ -155,13 +179,14 @@ func (eh *EventHandler) processBlockEvents(block executionclient.BlockLogs) ([]T
 	if lastProcessedBlock.Uint64() >= block.BlockNumber {
 		// Same or higher block has already been processed, this should never happen!
 		// Returning an error to signal that we should stop processing and
-		// investigate the issue.
-		//
-		// TODO: this may happen during reorgs, and we should either
-		// implement reorg support or only process finalized blocks.
+		// investigate the issue. Reorgs are handled by rolling back first.
 		return nil, ErrInferiorBlock
 	}
 
+	eh.lifecycleEvents = nil
+	eh.undo = &undoRecord{BlockNumber: block.BlockNumber, BlockHash: block.BlockHash}
+	defer func() { eh.undo = nil }()
+
 	var tasks []Task
 	for _, log := range block.Logs {
 		task, err := eh.processEvent(txn, log)
@@ -173,6 +198,10 @@ func (eh *EventHandler) processBlockEvents(block executionclient.BlockLogs) ([]T
 		}
 	}
 
+	if err := eh.saveUndoRecord(txn, eh.undo); err != nil {
+		return nil, err
+	}
+
 	if err := eh.nodeStorage.SaveLastProcessedBlock(txn, new(big.Int).SetUint64(block.BlockNumber)); err != nil {
 		return nil, fmt.Errorf("set last processed block: %w", err)
 	}
@@ -180,6 +209,7 @@ func (eh *EventHandler) processBlockEvents(block executionclient.BlockLogs) ([]T
 	if err := txn.Commit(); err != nil {
 		return nil, fmt.Errorf("commit transaction: %w", err)
 	}
+	eh.publishLifecycleEvents(eh.blockSlot(block.BlockNumber))
 
 	return tasks, nil
 }
@@ -430,79 +460,185 @@ func (eh *EventHandler) processEvent(txn basedb.Txn, event ethtypes.Log) (Task,
 	}
 }
 
-func (eh *EventHandler) HandleLocalEvents(localEvents []localevents.Event) error {
+// HandleLocalEvents processes the local events block by block, skipping the blocks which were already processed,
+// so that the events file can be reloaded while the node runs. The events must be ordered by their block numbers.
+// If executeTasks is true, the tasks of the processed events are executed.
+func (eh *EventHandler) HandleLocalEvents(localEvents []localevents.Event, executeTasks bool) error {
+	for len(localEvents) > 0 {
+		blockNumber := localEvents[0].BlockNumber
+		blockEvents := localEvents
+		for i, event := range localEvents {
+			if event.BlockNumber != blockNumber {
+				blockEvents = localEvents[:i]
+				break
+			}
+		}
+		localEvents = localEvents[len(blockEvents):]
+
+		logger := eh.logger.With(fields.BlockNumber(blockNumber))
+		tasks, err := eh.processLocalBlockEvents(blockNumber, blockEvents)
+		if err != nil {
+			return fmt.Errorf("failed to process local events of block %d: %w", blockNumber, err)
+		}
+		if !executeTasks || len(tasks) == 0 {
+			continue
+		}
+
+		logger.Debug("executing tasks", fields.Count(len(tasks)))
+
+		for _, task := range tasks {
+			logger := logger.With(fields.Type(task))
+			logger.Debug("executing task")
+			if err := task.Execute(); err != nil {
+				logger.Error("failed to execute task", zap.Error(err))
+			} else {
+				logger.Debug("executed task")
+			}
+		}
+	}
+
+	return nil
+}
+
+func (eh *EventHandler) processLocalBlockEvents(blockNumber uint64, localEvents []localevents.Event) ([]Task, error) {
+	eh.processMu.Lock()
+	defer eh.processMu.Unlock()
+
 	txn := eh.nodeStorage.Begin()
 	defer txn.Discard()
 
+	lastProcessedBlock, found, err := eh.nodeStorage.GetLastProcessedBlock(txn)
+	if err != nil {
+		return nil, fmt.Errorf("get last processed block: %w", err)
+	}
+	if found && lastProcessedBlock == nil {
+		return nil, fmt.Errorf("last processed block is nil")
+	}
+	if found && lastProcessedBlock.Uint64() >= blockNumber {
+		// Already processed before the events file was reloaded or the node was restarted.
+		if seen, ok := eh.localBlockEvents[blockNumber]; ok && len(localEvents) > seen {
+			eh.logger.Warn("ignoring local events added to an already processed block, add them to a later block instead",
+				fields.BlockNumber(blockNumber),
+				zap.Uint64("last_processed_block", lastProcessedBlock.Uint64()),
+				fields.Count(len(localEvents)-seen))
+		}
+		eh.seeLocalBlockEvents(blockNumber, len(localEvents))
+		return nil, nil
+	}
+
+	eh.lifecycleEvents = nil
+
+	var tasks []Task
 	for _, event := range localEvents {
-		if err := eh.processLocalEvent(txn, event); err != nil {
-			return fmt.Errorf("process local event: %w", err)
+		task, err := eh.processLocalEvent(txn, event)
+		if err != nil {
+			return nil, fmt.Errorf("process local event: %w", err)
+		}
+		if task != nil {
+			tasks = append(tasks, task)
 		}
 	}
 
+	if err := eh.nodeStorage.SaveLastProcessedBlock(txn, new(big.Int).SetUint64(blockNumber)); err != nil {
+		return nil, fmt.Errorf("set last processed block: %w", err)
+	}
+
 	if err := txn.Commit(); err != nil {
-		return fmt.Errorf("commit transaction: %w", err)
+		return nil, fmt.Errorf("commit transaction: %w", err)
 	}
+	eh.seeLocalBlockEvents(blockNumber, len(localEvents))
+	// Local blocks aren't on chain, so their events happen now.
+	eh.publishLifecycleEvents(eh.networkConfig.Beacon.EstimatedCurrentSlot())
 
-	return nil
+	return tasks, nil
 }
 
-func (eh *EventHandler) processLocalEvent(txn basedb.Txn, event localevents.Event) error {
+func (eh *EventHandler) seeLocalBlockEvents(blockNumber uint64, count int) {
+	if eh.localBlockEvents == nil {
+		eh.localBlockEvents = make(map[uint64]int)
+	}
+	eh.localBlockEvents[blockNumber] = count
+}
+
+func (eh *EventHandler) processLocalEvent(txn basedb.Txn, event localevents.Event) (Task, error) {
 	switch event.Name {
 	case OperatorAdded:
 		data := event.Data.(contract.ContractOperatorAdded)
 		if err := eh.handleOperatorAdded(txn, &data); err != nil {
-			return fmt.Errorf("handle OperatorAdded: %w", err)
+			return nil, fmt.Errorf("handle OperatorAdded: %w", err)
 		}
-		return nil
+		return nil, nil
 	case OperatorRemoved:
 		data := event.Data.(contract.ContractOperatorRemoved)
 		if err := eh.handleOperatorRemoved(txn, &data); err != nil {
-			return fmt.Errorf("handle OperatorRemoved: %w", err)
+			return nil, fmt.Errorf("handle OperatorRemoved: %w", err)
 		}
-		return nil
+		return nil, nil
 	case ValidatorAdded:
 		data := event.Data.(contract.ContractValidatorAdded)
 		if _, err := eh.handleValidatorAdded(txn, &data); err != nil {
-			return fmt.Errorf("handle ValidatorAdded: %w", err)
+			return nil, fmt.Errorf("handle ValidatorAdded: %w", err)
 		}
-		return nil
+		return nil, nil
 	case ValidatorRemoved:
 		data := event.Data.(contract.ContractValidatorRemoved)
-		if _, err := eh.handleValidatorRemoved(txn, &data); err != nil {
-			return fmt.Errorf("handle ValidatorRemoved: %w", err)
+		validatorPubKey, err := eh.handleValidatorRemoved(txn, &data)
+		if err != nil {
+			return nil, fmt.Errorf("handle ValidatorRemoved: %w", err)
 		}
-		return nil
+		if validatorPubKey != emptyPK {
+			return NewStopValidatorTask(eh.taskExecutor, validatorPubKey), nil
+		}
+		return nil, nil
 	case ClusterLiquidated:
 		data := event.Data.(contract.ContractClusterLiquidated)
-		_, err := eh.handleClusterLiquidated(txn, &data)
+		sharesToLiquidate, err := eh.handleClusterLiquidated(txn, &data)
 		if err != nil {
-			return fmt.Errorf("handle ClusterLiquidated: %w", err)
+			return nil, fmt.Errorf("handle ClusterLiquidated: %w", err)
+		}
+		if len(sharesToLiquidate) == 0 {
+			return nil, nil
 		}
-		return nil
+		return NewLiquidateClusterTask(eh.taskExecutor, data.Owner, data.OperatorIds, sharesToLiquidate), nil
 	case ClusterReactivated:
 		data := event.Data.(contract.ContractClusterReactivated)
-		_, err := eh.handleClusterReactivated(txn, &data)
+		sharesToReactivate, err := eh.handleClusterReactivated(txn, &data)
 		if err != nil {
-			return fmt.Errorf("handle ClusterReactivated: %w", err)
+			return nil, fmt.Errorf("handle ClusterReactivated: %w", err)
 		}
-		return nil
+		if len(sharesToReactivate) == 0 {
+			return nil, nil
+		}
+		return NewReactivateClusterTask(eh.taskExecutor, data.Owner, data.OperatorIds, sharesToReactivate), nil
 	case FeeRecipientAddressUpdated:
 		data := event.Data.(contract.ContractFeeRecipientAddressUpdated)
-		_, err := eh.handleFeeRecipientAddressUpdated(txn, &data)
+		updated, err := eh.handleFeeRecipientAddressUpdated(txn, &data)
 		if err != nil {
-			return fmt.Errorf("handle FeeRecipientAddressUpdated: %w", err)
+			return nil, fmt.Errorf("handle FeeRecipientAddressUpdated: %w", err)
+		}
+		if !updated {
+			return nil, nil
 		}
-		return nil
+		return NewUpdateFeeRecipientTask(eh.taskExecutor, data.Owner, data.RecipientAddress), nil
 	case ValidatorExited:
 		data := event.Data.(contract.ContractValidatorExited)
-		_, err := eh.handleValidatorExited(txn, &data)
+		exitDescriptor, err := eh.handleValidatorExited(txn, &data)
 		if err != nil {
-			return fmt.Errorf("handle ValidatorExited: %w", err)
+			return nil, fmt.Errorf("handle ValidatorExited: %w", err)
+		}
+		if exitDescriptor == nil {
+			return nil, nil
 		}
-		return nil
+		return NewExitValidatorTask(
+			eh.taskExecutor,
+			exitDescriptor.PubKey,
+			exitDescriptor.BlockNumber,
+			exitDescriptor.ValidatorIndex,
+			exitDescriptor.OwnValidator,
+		), nil
 	default:
-		eh.logger.Warn("unknown local event name", fields.Name(event.Name))
-		return nil
+		// The other contract events don't affect the registry state of the node.
+		eh.logger.Debug("local event has no effect", fields.Name(event.Name))
+		return nil, nil
 	}
 }
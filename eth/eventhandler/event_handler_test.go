@@ -913,6 +913,14 @@ func TestHandleBlockEventsStream(t *testing.T) {
 			valShare, exists = eh.nodeStorage.Shares().Get(nil, valPubKey)
 			require.False(t, exists)
 			require.Nil(t, valShare)
+
+			// The share key is kept until the undo record of the removal is pruned.
+			requireKeyManagerDataToExist(t, eh, 4, validatorData1)
+			pruningBlock := blockNum + eh.undoRetention + 1
+			txn := eh.nodeStorage.Begin()
+			require.NoError(t, eh.saveUndoRecord(txn, &undoRecord{BlockNumber: pruningBlock}))
+			require.NoError(t, txn.Delete(undoPrefix, undoKey(pruningBlock)))
+			require.NoError(t, txn.Commit())
 			requireKeyManagerDataToNotExist(t, eh, 3, validatorData1)
 		})
 	})
@@ -0,0 +1,108 @@
+package eventhandler
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	spectypes "github.com/ssvlabs/ssv-spec/types"
+	"go.uber.org/zap"
+
+	"github.com/ssvlabs/ssv/logging/fields"
+	"github.com/ssvlabs/ssv/operator/eventstream"
+	ssvtypes "github.com/ssvlabs/ssv/protocol/v2/types"
+	registrystorage "github.com/ssvlabs/ssv/registry/storage"
+	"github.com/ssvlabs/ssv/storage/basedb"
+)
+
+// blockHeaderTimeout is the timeout of fetching the header of a block, whose time lifecycle events are stamped with.
+const blockHeaderTimeout = 10 * time.Second
+
+// BlockHeaders returns the headers of execution blocks, and is implemented by the execution client.
+type BlockHeaders interface {
+	HeaderByNumber(ctx context.Context, blockNumber *big.Int) (*ethtypes.Header, error)
+}
+
+type lifecycleEvent struct {
+	eventType   eventstream.Type
+	owner       ethcommon.Address
+	operatorIDs []uint64
+	validators  []spectypes.ValidatorPK
+	data        eventstream.LifecycleData
+}
+
+// queueLifecycleEvent queues a validator lifecycle event to be published
+// once the changes of the event are committed.
+func (eh *EventHandler) queueLifecycleEvent(
+	eventType eventstream.Type,
+	owner ethcommon.Address,
+	operatorIDs []uint64,
+	validators []spectypes.ValidatorPK,
+	raw ethtypes.Log,
+) {
+	if eh.eventStream == nil {
+		return
+	}
+	eh.lifecycleEvents = append(eh.lifecycleEvents, lifecycleEvent{
+		eventType:   eventType,
+		owner:       owner,
+		operatorIDs: operatorIDs,
+		validators:  validators,
+		data: eventstream.LifecycleData{
+			BlockNumber: raw.BlockNumber,
+			TxHash:      raw.TxHash,
+		},
+	})
+}
+
+// queueClusterLifecycleEvent queues a lifecycle event of all the validators of a cluster.
+func (eh *EventHandler) queueClusterLifecycleEvent(
+	txn basedb.Txn,
+	eventType eventstream.Type,
+	owner ethcommon.Address,
+	operatorIDs []uint64,
+	raw ethtypes.Log,
+) {
+	if eh.eventStream == nil {
+		return
+	}
+	shares := eh.nodeStorage.Shares().List(txn, registrystorage.ByClusterIDHash(ssvtypes.ComputeClusterIDHash(owner, operatorIDs)))
+	if len(shares) == 0 {
+		return
+	}
+	validators := make([]spectypes.ValidatorPK, len(shares))
+	for i, share := range shares {
+		validators[i] = share.ValidatorPubKey
+	}
+	eh.queueLifecycleEvent(eventType, owner, operatorIDs, validators, raw)
+}
+
+// publishLifecycleEvents publishes the queued lifecycle events at the given slot.
+func (eh *EventHandler) publishLifecycleEvents(slot phase0.Slot) {
+	for _, event := range eh.lifecycleEvents {
+		eh.eventStream.PublishLifecycle(event.eventType, slot, event.owner, event.operatorIDs, event.validators, event.data)
+	}
+	eh.lifecycleEvents = nil
+}
+
+// blockSlot returns the slot of the given block, by the time in its header. If the header can't be fetched,
+// it returns the current slot, which the slot of a newly processed block is close to anyway.
+func (eh *EventHandler) blockSlot(blockNumber uint64) phase0.Slot {
+	if len(eh.lifecycleEvents) == 0 {
+		return 0
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), blockHeaderTimeout)
+	defer cancel()
+	header, err := eh.blockHeaders.HeaderByNumber(ctx, new(big.Int).SetUint64(blockNumber))
+	if err != nil {
+		eh.logger.Warn("could not get block header, stamping lifecycle events with the current slot",
+			fields.BlockNumber(blockNumber),
+			zap.Error(err))
+		return eh.networkConfig.Beacon.EstimatedCurrentSlot()
+	}
+	return eh.networkConfig.Beacon.EstimatedSlotAtTime(int64(header.Time)) // #nosec G115 -- block times fit in int64
+}
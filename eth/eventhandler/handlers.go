@@ -16,6 +16,7 @@ import (
 	"github.com/ssvlabs/ssv/eth/contract"
 	"github.com/ssvlabs/ssv/logging/fields"
 	"github.com/ssvlabs/ssv/operator/duties"
+	"github.com/ssvlabs/ssv/operator/eventstream"
 	ssvtypes "github.com/ssvlabs/ssv/protocol/v2/types"
 	registrystorage "github.com/ssvlabs/ssv/registry/storage"
 	"github.com/ssvlabs/ssv/storage/basedb"
@@ -75,6 +76,10 @@ func (eh *EventHandler) handleOperatorAdded(txn basedb.Txn, event *contract.Cont
 		return &MalformedEventError{Err: ErrOperatorPubkeyAlreadyExists}
 	}
 
+	if err := eh.recordOperator(txn, od.ID); err != nil {
+		return err
+	}
+
 	exists, err := eh.nodeStorage.SaveOperatorData(txn, od)
 	if err != nil {
 		return fmt.Errorf("save operator data: %w", err)
@@ -140,6 +145,9 @@ func (eh *EventHandler) handleValidatorAdded(txn basedb.Txn, event *contract.Con
 
 	// Bump nonce. This transaction would be reverted later if the handling fails,
 	// unless the failure is due to a malformed event.
+	if err := eh.recordRecipient(txn, event.Owner); err != nil {
+		return nil, err
+	}
 	if err := eh.nodeStorage.BumpNonce(txn, event.Owner); err != nil {
 		return nil, err
 	}
@@ -213,6 +221,8 @@ func (eh *EventHandler) handleValidatorAdded(txn basedb.Txn, event *contract.Con
 		logger = logger.With(zap.Bool("own_validator", true))
 	}
 
+	eh.queueLifecycleEvent(eventstream.TypeValidatorAdded, event.Owner, event.OperatorIds, []spectypes.ValidatorPK{validatorShare.ValidatorPubKey}, event.Raw)
+
 	logger.Debug("processed event")
 	return
 }
@@ -246,6 +256,9 @@ func (eh *EventHandler) handleShareCreation(
 	}
 
 	// Save share.
+	if err := eh.recordShare(txn, share.ValidatorPubKey[:]); err != nil {
+		return nil, err
+	}
 	if err := eh.nodeStorage.Shares().Save(txn, share); err != nil {
 		return nil, fmt.Errorf("could not save validator share: %w", err)
 	}
@@ -360,18 +373,21 @@ func (eh *EventHandler) handleValidatorRemoved(txn basedb.Txn, event *contract.C
 		return emptyPK, &MalformedEventError{Err: ErrShareBelongsToDifferentOwner}
 	}
 
+	if err := eh.recordShare(txn, share.ValidatorPubKey[:]); err != nil {
+		return emptyPK, err
+	}
 	if err := eh.nodeStorage.Shares().Delete(txn, share.ValidatorPubKey[:]); err != nil {
 		return emptyPK, fmt.Errorf("could not remove validator share: %w", err)
 	}
+	eh.queueLifecycleEvent(eventstream.TypeValidatorRemoved, event.Owner, event.OperatorIds, []spectypes.ValidatorPK{share.ValidatorPubKey}, event.Raw)
 
 	isOperatorShare := share.BelongsToOperator(eh.operatorDataStore.GetOperatorID())
 	if isOperatorShare || eh.fullNode {
 		logger = logger.With(zap.String("validator_pubkey", hex.EncodeToString(share.ValidatorPubKey[:])))
 	}
 	if isOperatorShare {
-		err := eh.keyManager.RemoveShare(hex.EncodeToString(share.SharePubKey))
-		if err != nil {
-			return emptyPK, fmt.Errorf("could not remove share from ekm storage: %w", err)
+		if err := eh.removeShareKey(share); err != nil {
+			return emptyPK, err
 		}
 
 		eh.metrics.ValidatorRemoved(event.PublicKey)
@@ -396,6 +412,7 @@ func (eh *EventHandler) handleClusterLiquidated(txn basedb.Txn, event *contract.
 	if err != nil {
 		return nil, fmt.Errorf("could not process cluster event: %w", err)
 	}
+	eh.queueClusterLifecycleEvent(txn, eventstream.TypeValidatorLiquidated, event.Owner, event.OperatorIds, event.Raw)
 
 	if len(liquidatedPubKeys) > 0 {
 		logger = logger.With(zap.Strings("liquidated_validators", liquidatedPubKeys))
@@ -418,6 +435,7 @@ func (eh *EventHandler) handleClusterReactivated(txn basedb.Txn, event *contract
 	if err != nil {
 		return nil, fmt.Errorf("could not process cluster event: %w", err)
 	}
+	eh.queueClusterLifecycleEvent(txn, eventstream.TypeValidatorReactivated, event.Owner, event.OperatorIds, event.Raw)
 
 	// bump slashing protection for operator reactivated validators
 	for _, share := range toReactivate {
@@ -454,6 +472,9 @@ func (eh *EventHandler) handleFeeRecipientAddressUpdated(txn basedb.Txn, event *
 		}
 	}
 
+	if err := eh.recordRecipient(txn, event.Owner); err != nil {
+		return false, err
+	}
 	copy(recipientData.FeeRecipient[:], event.RecipientAddress.Bytes())
 
 	r, err := eh.nodeStorage.SaveRecipientData(txn, recipientData)
@@ -540,6 +561,10 @@ func (eh *EventHandler) processClusterEvent(
 			updatedPubKeys = append(updatedPubKeys, hex.EncodeToString(share.ValidatorPubKey[:]))
 		}
 		if isOperatorShare {
+			// The share is changed in place, so its previous state is recorded first.
+			if err := eh.recordShare(txn, share.ValidatorPubKey[:]); err != nil {
+				return nil, nil, err
+			}
 			share.Liquidated = toLiquidate
 			toUpdate = append(toUpdate, share)
 		}
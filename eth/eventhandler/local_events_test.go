@@ -8,7 +8,9 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	spectypes "github.com/ssvlabs/ssv-spec/types"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest"
+	"go.uber.org/zap/zaptest/observer"
 	"gopkg.in/yaml.v3"
 
 	"github.com/ssvlabs/ssv/eth/contract"
@@ -51,7 +53,37 @@ func TestHandleLocalEvent(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		require.NoError(t, eh.HandleLocalEvents(parsedData))
+		require.NoError(t, eh.HandleLocalEvents(parsedData, false))
+
+		// Reloading the events skips the processed block and processes the following ones.
+		parsedData = append(parsedData, localevents.Event{
+			Name:        OperatorRemoved,
+			BlockNumber: 1,
+			Data:        contract.ContractOperatorRemoved{OperatorId: 1},
+		})
+		require.NoError(t, eh.HandleLocalEvents(parsedData, false))
+
+		_, found, err := eh.nodeStorage.GetOperatorData(nil, 1)
+		require.NoError(t, err)
+		require.False(t, found)
+		lastProcessedBlock, found, err := eh.nodeStorage.GetLastProcessedBlock(nil)
+		require.NoError(t, err)
+		require.True(t, found)
+		require.EqualValues(t, 1, lastProcessedBlock.Uint64())
+
+		// Events added to processed blocks are ignored with a warning.
+		core, logs := observer.New(zap.WarnLevel)
+		eh.logger = zap.New(core)
+		parsedData = append(parsedData, localevents.Event{
+			Name:        OperatorRemoved,
+			BlockNumber: 1,
+			LogIndex:    1,
+			Data:        contract.ContractOperatorRemoved{OperatorId: 2},
+		})
+		require.NoError(t, eh.HandleLocalEvents(parsedData, false))
+		require.Equal(t, 1, logs.FilterMessageSnippet("already processed block").Len())
+		require.NoError(t, eh.HandleLocalEvents(parsedData, false))
+		require.Equal(t, 1, logs.FilterMessageSnippet("already processed block").Len())
 	})
 
 	// TODO: test correct signature
@@ -90,6 +122,6 @@ func TestHandleLocalEvent(t *testing.T) {
 			require.False(t, found)
 		}
 
-		require.ErrorIs(t, eh.HandleLocalEvents(parsedData), ErrSignatureVerification)
+		require.ErrorIs(t, eh.HandleLocalEvents(parsedData, false), ErrSignatureVerification)
 	})
 }
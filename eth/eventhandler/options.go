@@ -1,8 +1,10 @@
 package eventhandler
 
 import (
-	"github.com/ssvlabs/ssv/logging"
 	"go.uber.org/zap"
+
+	"github.com/ssvlabs/ssv/logging"
+	"github.com/ssvlabs/ssv/operator/eventstream"
 )
 
 // Option defines EventHandler configuration option.
@@ -28,3 +30,20 @@ func WithFullNode() Option {
 		eh.fullNode = true
 	}
 }
+
+// WithEventStream publishes validator lifecycle changes to the given stream,
+// at the slots of their blocks, by the block headers.
+func WithEventStream(stream *eventstream.Stream, blockHeaders BlockHeaders) Option {
+	return func(eh *EventHandler) {
+		eh.eventStream = stream
+		eh.blockHeaders = blockHeaders
+	}
+}
+
+// WithUndoRetention sets the number of blocks for which undo records are kept,
+// which limits how deep reorgs can be rolled back.
+func WithUndoRetention(blocks uint64) Option {
+	return func(eh *EventHandler) {
+		eh.undoRetention = blocks
+	}
+}
@@ -0,0 +1,452 @@
+package eventhandler
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	spectypes "github.com/ssvlabs/ssv-spec/types"
+	"go.uber.org/zap"
+
+	"github.com/ssvlabs/ssv/logging/fields"
+	"github.com/ssvlabs/ssv/operator/eventstream"
+	ssvtypes "github.com/ssvlabs/ssv/protocol/v2/types"
+	registrystorage "github.com/ssvlabs/ssv/registry/storage"
+	"github.com/ssvlabs/ssv/storage/basedb"
+)
+
+// DefaultUndoRetention is the default number of blocks for which undo records are kept.
+// Reorgs deeper than that can't be rolled back.
+const DefaultUndoRetention = 256
+
+var undoPrefix = []byte("operator/undo/")
+
+// ErrRollbackTooDeep is returned when rolling back to a block whose undo records were pruned or never written.
+var ErrRollbackTooDeep = errors.New("rollback is deeper than the retained undo records")
+
+type undoKind string
+
+const (
+	undoOperator  undoKind = "operator"
+	undoShare     undoKind = "share"
+	undoRecipient undoKind = "recipient"
+)
+
+// undoRecord holds the state which the events of a block changed,
+// which is written in the same transaction as the changes.
+type undoRecord struct {
+	BlockNumber uint64         `json:"blockNumber"`
+	BlockHash   ethcommon.Hash `json:"blockHash"`
+	Changes     []undoChange   `json:"changes,omitempty"`
+	// RemovedKeys are the own share keys which are removed from the key manager once the record is pruned,
+	// so that a rolled back removal can restore the validator with its key and slashing protection.
+	RemovedKeys []removedKey `json:"removedKeys,omitempty"`
+}
+
+// removedKey is an own share key whose removal from the key manager is deferred.
+type removedKey struct {
+	ValidatorPubKey []byte `json:"validatorPubKey"`
+	SharePubKey     []byte `json:"sharePubKey"`
+}
+
+// undoChange holds the state of an operator, share or recipient before it was changed.
+// Previous is nil if it didn't exist.
+type undoChange struct {
+	Kind     undoKind `json:"kind"`
+	Key      []byte   `json:"key"`
+	Previous []byte   `json:"previous,omitempty"`
+}
+
+func undoKey(blockNumber uint64) []byte {
+	return binary.BigEndian.AppendUint64(nil, blockNumber)
+}
+
+// recordOperator records the current state of the given operator, before it's changed.
+func (eh *EventHandler) recordOperator(txn basedb.Txn, id spectypes.OperatorID) error {
+	if eh.undo == nil {
+		return nil
+	}
+	od, found, err := eh.nodeStorage.GetOperatorData(txn, id)
+	if err != nil {
+		return fmt.Errorf("could not get operator data: %w", err)
+	}
+	change := undoChange{Kind: undoOperator, Key: binary.BigEndian.AppendUint64(nil, id)}
+	if found && od != nil {
+		if change.Previous, err = json.Marshal(od); err != nil {
+			return fmt.Errorf("could not marshal operator data: %w", err)
+		}
+	}
+	eh.undo.Changes = append(eh.undo.Changes, change)
+	return nil
+}
+
+// recordShare records the current state of the given share, before it's changed.
+func (eh *EventHandler) recordShare(txn basedb.Txn, pubKey []byte) error {
+	if eh.undo == nil {
+		return nil
+	}
+	change := undoChange{Kind: undoShare, Key: pubKey}
+	if share, exists := eh.nodeStorage.Shares().Get(txn, pubKey); exists {
+		var err error
+		if change.Previous, err = registrystorage.EncodeShare(share); err != nil {
+			return fmt.Errorf("could not encode share: %w", err)
+		}
+	}
+	eh.undo.Changes = append(eh.undo.Changes, change)
+	return nil
+}
+
+// recordRecipient records the current state of the given owner's recipient data, before it's changed.
+func (eh *EventHandler) recordRecipient(txn basedb.Txn, owner ethcommon.Address) error {
+	if eh.undo == nil {
+		return nil
+	}
+	recipientData, found, err := eh.nodeStorage.GetRecipientData(txn, owner)
+	if err != nil {
+		return fmt.Errorf("could not get recipient data: %w", err)
+	}
+	change := undoChange{Kind: undoRecipient, Key: owner.Bytes()}
+	if found && recipientData != nil {
+		if change.Previous, err = json.Marshal(recipientData); err != nil {
+			return fmt.Errorf("could not marshal recipient data: %w", err)
+		}
+	}
+	eh.undo.Changes = append(eh.undo.Changes, change)
+	return nil
+}
+
+// removeShareKey removes the own share key from the key manager once the undo record
+// of the block being processed is pruned, or right away when processing local events.
+func (eh *EventHandler) removeShareKey(share *ssvtypes.SSVShare) error {
+	if eh.undo == nil {
+		if err := eh.keyManager.RemoveShare(hex.EncodeToString(share.SharePubKey)); err != nil {
+			return fmt.Errorf("could not remove share from ekm storage: %w", err)
+		}
+		return nil
+	}
+	eh.undo.RemovedKeys = append(eh.undo.RemovedKeys, removedKey{
+		ValidatorPubKey: share.ValidatorPubKey[:],
+		SharePubKey:     share.SharePubKey,
+	})
+	return nil
+}
+
+func putUndoRecord(txn basedb.Txn, record *undoRecord) error {
+	raw, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("could not marshal undo record: %w", err)
+	}
+	if err := txn.Set(undoPrefix, undoKey(record.BlockNumber), raw); err != nil {
+		return fmt.Errorf("could not save undo record: %w", err)
+	}
+	return nil
+}
+
+// saveUndoRecord saves the undo record of the processed block and prunes
+// the records which are older than the retention, removing their share keys.
+func (eh *EventHandler) saveUndoRecord(txn basedb.Txn, record *undoRecord) error {
+	if err := putUndoRecord(txn, record); err != nil {
+		return err
+	}
+	if record.BlockNumber <= eh.undoRetention {
+		return nil
+	}
+	pruneBefore := record.BlockNumber - eh.undoRetention
+	var expired []undoRecord
+	err := txn.GetRange(undoPrefix, nil, undoKey(pruneBefore), func(obj basedb.Obj) error {
+		var record undoRecord
+		if err := json.Unmarshal(obj.Value, &record); err != nil {
+			return fmt.Errorf("could not unmarshal undo record: %w", err)
+		}
+		expired = append(expired, record)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("could not get undo records: %w", err)
+	}
+	for _, record := range expired {
+		for _, key := range record.RemovedKeys {
+			// The share may have been added again since, in which case its key is still in use.
+			if share, exists := eh.nodeStorage.Shares().Get(txn, key.ValidatorPubKey); exists && bytes.Equal(share.SharePubKey, key.SharePubKey) {
+				continue
+			}
+			if err := eh.keyManager.RemoveShare(hex.EncodeToString(key.SharePubKey)); err != nil {
+				return fmt.Errorf("could not remove share from ekm storage: %w", err)
+			}
+		}
+		if err := txn.Delete(undoPrefix, undoKey(record.BlockNumber)); err != nil {
+			return fmt.Errorf("could not delete undo record: %w", err)
+		}
+	}
+	return nil
+}
+
+// undoRecords returns the retained undo records, ordered by block number.
+func (eh *EventHandler) undoRecords(r basedb.Reader) ([]undoRecord, error) {
+	var records []undoRecord
+	err := r.GetAll(undoPrefix, func(_ int, obj basedb.Obj) error {
+		var record undoRecord
+		if err := json.Unmarshal(obj.Value, &record); err != nil {
+			return fmt.Errorf("could not unmarshal undo record: %w", err)
+		}
+		records = append(records, record)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].BlockNumber < records[j].BlockNumber
+	})
+	return records, nil
+}
+
+// BlockHashes returns the hashes of the processed blocks which can be rolled back.
+func (eh *EventHandler) BlockHashes() (map[uint64]ethcommon.Hash, error) {
+	txn := eh.nodeStorage.BeginRead()
+	defer txn.Discard()
+
+	records, err := eh.undoRecords(txn)
+	if err != nil {
+		return nil, err
+	}
+	hashes := make(map[uint64]ethcommon.Hash, len(records))
+	for _, record := range records {
+		hashes[record.BlockNumber] = record.BlockHash
+	}
+	return hashes, nil
+}
+
+// Rollback reverts the changes of the blocks after toBlock using their undo records,
+// so that they can be processed again from the canonical chain.
+// If executeTasks is true, the tasks which revert the effects of the rolled back events are executed.
+func (eh *EventHandler) Rollback(toBlock uint64, executeTasks bool) error {
+	eh.processMu.Lock()
+	defer eh.processMu.Unlock()
+
+	logger := eh.logger.With(zap.Uint64("to_block", toBlock))
+
+	txn := eh.nodeStorage.Begin()
+	defer txn.Discard()
+
+	lastProcessedBlock, found, err := eh.nodeStorage.GetLastProcessedBlock(txn)
+	if err != nil {
+		return fmt.Errorf("get last processed block: %w", err)
+	}
+	if !found || lastProcessedBlock == nil || lastProcessedBlock.Uint64() <= toBlock {
+		return nil
+	}
+
+	records, err := eh.undoRecords(txn)
+	if err != nil {
+		return err
+	}
+	// A record at or before toBlock guarantees that the changes since are all recorded.
+	if len(records) == 0 || records[0].BlockNumber > toBlock {
+		return ErrRollbackTooDeep
+	}
+
+	// The keys of own shares whose addition is rolled back are removed once the last retained record is pruned,
+	// unless the shares are added back by then.
+	last := len(records) - 1
+	for records[last].BlockNumber > toBlock {
+		last--
+	}
+	retained := &records[last]
+	retainedKeys := len(retained.RemovedKeys)
+
+	eh.lifecycleEvents = nil
+	var tasks []Task
+	for i := len(records) - 1; i > last; i-- {
+		blockTasks, err := eh.undoBlock(txn, records[i], retained)
+		if err != nil {
+			return fmt.Errorf("undo block %d: %w", records[i].BlockNumber, err)
+		}
+		tasks = append(tasks, blockTasks...)
+		if err := txn.Delete(undoPrefix, undoKey(records[i].BlockNumber)); err != nil {
+			return fmt.Errorf("could not delete undo record: %w", err)
+		}
+	}
+	if len(retained.RemovedKeys) != retainedKeys {
+		if err := putUndoRecord(txn, retained); err != nil {
+			return err
+		}
+	}
+
+	if err := eh.nodeStorage.SaveLastProcessedBlock(txn, new(big.Int).SetUint64(toBlock)); err != nil {
+		return fmt.Errorf("set last processed block: %w", err)
+	}
+	if err := txn.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	// The changes are reverted now, rather than at the time of the rolled back blocks.
+	eh.publishLifecycleEvents(eh.networkConfig.Beacon.EstimatedCurrentSlot())
+
+	logger.Info("rolled back registry events",
+		zap.Uint64("from_block", lastProcessedBlock.Uint64()),
+		fields.Count(len(tasks)))
+
+	if !executeTasks {
+		return nil
+	}
+	for _, task := range tasks {
+		if err := task.Execute(); err != nil {
+			logger.Error("failed to execute rollback task", fields.Type(task), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// undoBlock restores the state which the events of a block changed, in reverse order,
+// and returns the tasks which revert their effects. The removal of the keys of own shares
+// whose addition is undone is deferred to the retained record.
+func (eh *EventHandler) undoBlock(txn basedb.Txn, record undoRecord, retained *undoRecord) ([]Task, error) {
+	var tasks []Task
+	for i := len(record.Changes) - 1; i >= 0; i-- {
+		var task Task
+		var err error
+		change := record.Changes[i]
+		switch change.Kind {
+		case undoOperator:
+			err = eh.undoOperatorChange(txn, change)
+		case undoShare:
+			task, err = eh.undoShareChange(txn, record.BlockNumber, change, retained)
+		case undoRecipient:
+			task, err = eh.undoRecipientChange(txn, change)
+		default:
+			err = fmt.Errorf("unknown undo kind %q", change.Kind)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if task != nil {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+func (eh *EventHandler) undoOperatorChange(txn basedb.Txn, change undoChange) error {
+	id := binary.BigEndian.Uint64(change.Key)
+	current, found, err := eh.nodeStorage.GetOperatorData(txn, id)
+	if err != nil {
+		return fmt.Errorf("could not get operator data: %w", err)
+	}
+	if err := eh.nodeStorage.DeleteOperatorData(txn, id); err != nil {
+		return fmt.Errorf("could not delete operator data: %w", err)
+	}
+	if change.Previous == nil {
+		if found && current != nil && current.ID == eh.operatorDataStore.GetOperatorID() {
+			eh.logger.Warn("rolled back the registration of own operator", fields.OperatorID(id))
+		}
+		return nil
+	}
+	previous := &registrystorage.OperatorData{}
+	if err := json.Unmarshal(change.Previous, previous); err != nil {
+		return fmt.Errorf("could not unmarshal operator data: %w", err)
+	}
+	if _, err := eh.nodeStorage.SaveOperatorData(txn, previous); err != nil {
+		return fmt.Errorf("could not save operator data: %w", err)
+	}
+	return nil
+}
+
+func (eh *EventHandler) undoShareChange(txn basedb.Txn, blockNumber uint64, change undoChange, retained *undoRecord) (Task, error) {
+	operatorID := eh.operatorDataStore.GetOperatorID()
+	current, exists := eh.nodeStorage.Shares().Get(txn, change.Key)
+
+	if change.Previous == nil {
+		if !exists {
+			return nil, nil
+		}
+		if err := eh.nodeStorage.Shares().Delete(txn, change.Key); err != nil {
+			return nil, fmt.Errorf("could not remove validator share: %w", err)
+		}
+		eh.queueLifecycleEvent(eventstream.TypeValidatorRemoved, current.OwnerAddress, committeeIDs(current), []spectypes.ValidatorPK{current.ValidatorPubKey}, ethtypes.Log{BlockNumber: blockNumber})
+		if !current.BelongsToOperator(operatorID) {
+			return nil, nil
+		}
+		retained.RemovedKeys = append(retained.RemovedKeys, removedKey{
+			ValidatorPubKey: current.ValidatorPubKey[:],
+			SharePubKey:     current.SharePubKey,
+		})
+		return NewStopValidatorTask(eh.taskExecutor, current.ValidatorPubKey), nil
+	}
+
+	previous, err := registrystorage.DecodeShare(change.Previous)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode share: %w", err)
+	}
+	if err := eh.nodeStorage.Shares().Save(txn, previous); err != nil {
+		return nil, fmt.Errorf("could not save validator share: %w", err)
+	}
+	if !previous.BelongsToOperator(operatorID) {
+		if !exists {
+			eh.queueLifecycleEvent(eventstream.TypeValidatorAdded, previous.OwnerAddress, committeeIDs(previous), []spectypes.ValidatorPK{previous.ValidatorPubKey}, ethtypes.Log{BlockNumber: blockNumber})
+		}
+		return nil, nil
+	}
+
+	switch {
+	case !exists:
+		// The removal of the share key is deferred to the deleted undo record, so the key is still
+		// in the key manager and the validator is started again like a newly added one.
+		eh.queueLifecycleEvent(eventstream.TypeValidatorAdded, previous.OwnerAddress, committeeIDs(previous), []spectypes.ValidatorPK{previous.ValidatorPubKey}, ethtypes.Log{BlockNumber: blockNumber})
+		return nil, nil
+	case current.Liquidated && !previous.Liquidated:
+		return NewReactivateClusterTask(eh.taskExecutor, previous.OwnerAddress, committeeIDs(previous), []*ssvtypes.SSVShare{previous}), nil
+	case !current.Liquidated && previous.Liquidated:
+		return NewLiquidateClusterTask(eh.taskExecutor, previous.OwnerAddress, committeeIDs(previous), []*ssvtypes.SSVShare{previous}), nil
+	default:
+		return nil, nil
+	}
+}
+
+func (eh *EventHandler) undoRecipientChange(txn basedb.Txn, change undoChange) (Task, error) {
+	owner := ethcommon.BytesToAddress(change.Key)
+	current, found, err := eh.nodeStorage.GetRecipientData(txn, owner)
+	if err != nil {
+		return nil, fmt.Errorf("could not get recipient data: %w", err)
+	}
+	// Deleting first makes sure the previous nonce is saved even if the fee recipient is the same.
+	if err := eh.nodeStorage.DeleteRecipientData(txn, owner); err != nil {
+		return nil, fmt.Errorf("could not delete recipient data: %w", err)
+	}
+
+	// Without recipient data, the owner address is the fee recipient.
+	feeRecipient := owner
+	if change.Previous != nil {
+		previous := &registrystorage.RecipientData{}
+		if err := json.Unmarshal(change.Previous, previous); err != nil {
+			return nil, fmt.Errorf("could not unmarshal recipient data: %w", err)
+		}
+		if _, err := eh.nodeStorage.SaveRecipientData(txn, previous); err != nil {
+			return nil, fmt.Errorf("could not save recipient data: %w", err)
+		}
+		feeRecipient = ethcommon.Address(previous.FeeRecipient)
+	}
+
+	currentFeeRecipient := owner
+	if found && current != nil {
+		currentFeeRecipient = ethcommon.Address(current.FeeRecipient)
+	}
+	if currentFeeRecipient == feeRecipient {
+		return nil, nil
+	}
+	return NewUpdateFeeRecipientTask(eh.taskExecutor, owner, feeRecipient), nil
+}
+
+func committeeIDs(share *ssvtypes.SSVShare) []uint64 {
+	ids := make([]uint64, len(share.Committee))
+	for i, member := range share.Committee {
+		ids[i] = member.Signer
+	}
+	return ids
+}
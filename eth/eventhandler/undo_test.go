@@ -0,0 +1,163 @@
+package eventhandler
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/herumi/bls-eth-go-binary/bls"
+	spectypes "github.com/ssvlabs/ssv-spec/types"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/ssvlabs/ssv/ekm"
+	"github.com/ssvlabs/ssv/eth/executionclient"
+	ssvtypes "github.com/ssvlabs/ssv/protocol/v2/types"
+	registrystorage "github.com/ssvlabs/ssv/registry/storage"
+	"github.com/ssvlabs/ssv/storage/basedb"
+	"github.com/ssvlabs/ssv/utils/threshold"
+)
+
+func TestRollback(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ops, err := createOperators(1, 0)
+	require.NoError(t, err)
+	eh, _, err := setupEventHandler(t, ctx, zaptest.NewLogger(t), nil, ops[0], false)
+	require.NoError(t, err)
+
+	_, err = eh.processBlockEvents(executionclient.BlockLogs{BlockNumber: 1, BlockHash: ethcommon.HexToHash("0x1")})
+	require.NoError(t, err)
+
+	// Apply the changes of block 2 the way the event handlers do.
+	owner := ethcommon.HexToAddress("0x97a6C1f3aaB5427B901fb135ED492749191C0f1F")
+	share := &ssvtypes.SSVShare{
+		Share: spectypes.Share{
+			ValidatorPubKey: spectypes.ValidatorPK{1, 2, 3},
+			Committee:       []*spectypes.ShareMember{{Signer: 100}, {Signer: 101}, {Signer: 102}, {Signer: 103}},
+		},
+		Metadata: ssvtypes.Metadata{OwnerAddress: owner},
+	}
+	txn := eh.nodeStorage.Begin()
+	eh.undo = &undoRecord{BlockNumber: 2, BlockHash: ethcommon.HexToHash("0x2")}
+	require.NoError(t, eh.recordOperator(txn, 100))
+	_, err = eh.nodeStorage.SaveOperatorData(txn, &registrystorage.OperatorData{ID: 100, PublicKey: []byte("pk"), OwnerAddress: owner})
+	require.NoError(t, err)
+	require.NoError(t, eh.recordRecipient(txn, owner))
+	require.NoError(t, eh.nodeStorage.BumpNonce(txn, owner))
+	require.NoError(t, eh.recordShare(txn, share.ValidatorPubKey[:]))
+	require.NoError(t, eh.nodeStorage.Shares().Save(txn, share))
+	require.NoError(t, eh.saveUndoRecord(txn, eh.undo))
+	require.NoError(t, eh.nodeStorage.SaveLastProcessedBlock(txn, big.NewInt(2)))
+	require.NoError(t, txn.Commit())
+	eh.undo = nil
+
+	hashes, err := eh.BlockHashes()
+	require.NoError(t, err)
+	require.Equal(t, map[uint64]ethcommon.Hash{
+		1: ethcommon.HexToHash("0x1"),
+		2: ethcommon.HexToHash("0x2"),
+	}, hashes)
+
+	require.NoError(t, eh.Rollback(1, false))
+
+	_, found, err := eh.nodeStorage.GetOperatorData(nil, 100)
+	require.NoError(t, err)
+	require.False(t, found)
+	_, found, err = eh.nodeStorage.GetRecipientData(nil, owner)
+	require.NoError(t, err)
+	require.False(t, found)
+	_, found = eh.nodeStorage.Shares().Get(nil, share.ValidatorPubKey[:])
+	require.False(t, found)
+	lastProcessedBlock, _, err := eh.nodeStorage.GetLastProcessedBlock(nil)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, lastProcessedBlock.Uint64())
+
+	// The changes before the oldest undo record aren't known.
+	require.ErrorIs(t, eh.Rollback(0, false), ErrRollbackTooDeep)
+
+	// Only the undo records within the retention are kept.
+	eh.undoRetention = 2
+	for block := uint64(2); block <= 5; block++ {
+		_, err = eh.processBlockEvents(executionclient.BlockLogs{BlockNumber: block})
+		require.NoError(t, err)
+	}
+	hashes, err = eh.BlockHashes()
+	require.NoError(t, err)
+	require.Len(t, hashes, 3)
+	require.Contains(t, hashes, uint64(3))
+}
+
+func TestRollbackOwnShare(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	threshold.Init()
+	ops, err := createOperators(1, 0)
+	require.NoError(t, err)
+	eh, _, err := setupEventHandler(t, ctx, zaptest.NewLogger(t), nil, ops[0], false)
+	require.NoError(t, err)
+
+	shareSecret := &bls.SecretKey{}
+	shareSecret.SetByCSPRNG()
+	sharePubKey := shareSecret.GetPublicKey().Serialize()
+	share := &ssvtypes.SSVShare{
+		Share: spectypes.Share{
+			ValidatorPubKey: spectypes.ValidatorPK{4, 5, 6},
+			SharePubKey:     sharePubKey,
+			Committee:       []*spectypes.ShareMember{{Signer: eh.operatorDataStore.GetOperatorID()}},
+		},
+	}
+	requireShareKey := func(exists bool) {
+		accounts, err := eh.keyManager.(ekm.StorageProvider).ListAccounts()
+		require.NoError(t, err)
+		require.Equal(t, exists, shareExist(accounts, sharePubKey))
+	}
+
+	processBlock := func(blockNumber uint64, change func(txn basedb.Txn)) {
+		txn := eh.nodeStorage.Begin()
+		defer txn.Discard()
+		eh.undo = &undoRecord{BlockNumber: blockNumber}
+		defer func() { eh.undo = nil }()
+		if change != nil {
+			change(txn)
+		}
+		require.NoError(t, eh.saveUndoRecord(txn, eh.undo))
+		require.NoError(t, eh.nodeStorage.SaveLastProcessedBlock(txn, new(big.Int).SetUint64(blockNumber)))
+		require.NoError(t, txn.Commit())
+	}
+
+	// Block 2 adds the share and block 3 removes it.
+	processBlock(1, nil)
+	processBlock(2, func(txn basedb.Txn) {
+		require.NoError(t, eh.recordShare(txn, share.ValidatorPubKey[:]))
+		require.NoError(t, eh.nodeStorage.Shares().Save(txn, share))
+		require.NoError(t, eh.keyManager.AddShare(shareSecret))
+	})
+	processBlock(3, func(txn basedb.Txn) {
+		require.NoError(t, eh.recordShare(txn, share.ValidatorPubKey[:]))
+		require.NoError(t, eh.nodeStorage.Shares().Delete(txn, share.ValidatorPubKey[:]))
+		require.NoError(t, eh.removeShareKey(share))
+	})
+	requireShareKey(true)
+
+	// Rolling back the removal restores the share with its key.
+	require.NoError(t, eh.Rollback(2, false))
+	_, found := eh.nodeStorage.Shares().Get(nil, share.ValidatorPubKey[:])
+	require.True(t, found)
+	requireShareKey(true)
+
+	// Rolling back the addition keeps the key until the retained record is pruned.
+	require.NoError(t, eh.Rollback(1, false))
+	_, found = eh.nodeStorage.Shares().Get(nil, share.ValidatorPubKey[:])
+	require.False(t, found)
+	requireShareKey(true)
+
+	eh.undoRetention = 1
+	processBlock(2, nil)
+	requireShareKey(true)
+	processBlock(3, nil)
+	requireShareKey(false)
+}
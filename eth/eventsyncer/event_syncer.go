@@ -6,8 +6,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/big"
+	"sort"
+	"sync/atomic"
 	"time"
 
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"go.uber.org/zap"
 
 	"github.com/ssvlabs/ssv/eth/executionclient"
@@ -21,17 +26,35 @@ import (
 var (
 	// ErrNodeNotReady is returned when node is not ready.
 	ErrNodeNotReady = fmt.Errorf("node not ready")
+
+	// ErrNotSyncingOngoing is returned by Resync when ongoing events aren't being synced.
+	ErrNotSyncingOngoing = fmt.Errorf("not syncing ongoing events")
+
+	// ErrResyncAhead is returned by Resync when the given block is after the next block to process.
+	ErrResyncAhead = fmt.Errorf("can't re-sync from a block after the next block to process")
+
+	// ErrReorgTooDeep is returned when none of the processed blocks which can be rolled back are canonical.
+	ErrReorgTooDeep = fmt.Errorf("reorg is deeper than the blocks which can be rolled back")
 )
 
 type ExecutionClient interface {
 	FetchHistoricalLogs(ctx context.Context, fromBlock uint64) (logs <-chan executionclient.BlockLogs, errors <-chan error, err error)
 	StreamLogs(ctx context.Context, fromBlock uint64) <-chan executionclient.BlockLogs
+	HeaderByNumber(ctx context.Context, blockNumber *big.Int) (*ethtypes.Header, error)
 }
 
 type EventHandler interface {
 	HandleBlockEventsStream(logs <-chan executionclient.BlockLogs, executeTasks bool) (uint64, error)
 }
 
+// Rollbacker is implemented by EventHandlers which can roll back processed blocks after a reorg.
+type Rollbacker interface {
+	// BlockHashes returns the hashes of the processed blocks which can be rolled back.
+	BlockHashes() (map[uint64]ethcommon.Hash, error)
+	// Rollback reverts the changes of the blocks after toBlock.
+	Rollback(toBlock uint64, executeTasks bool) error
+}
+
 // EventSyncer syncs registry contract events from the given ExecutionClient
 // and passes them to the given EventHandler for processing.
 type EventSyncer struct {
@@ -42,9 +65,13 @@ type EventSyncer struct {
 	logger             *zap.Logger
 	metrics            metrics
 	stalenessThreshold time.Duration
+	reorgCheckInterval time.Duration
 
 	lastProcessedBlock       uint64
 	lastProcessedBlockChange time.Time
+
+	syncingOngoing atomic.Bool
+	resync         chan uint64
 }
 
 func New(nodeStorage nodestorage.Storage, executionClient ExecutionClient, eventHandler EventHandler, opts ...Option) *EventSyncer {
@@ -56,6 +83,8 @@ func New(nodeStorage nodestorage.Storage, executionClient ExecutionClient, event
 		logger:             zap.NewNop(),
 		metrics:            nopMetrics{},
 		stalenessThreshold: 150 * time.Second,
+		reorgCheckInterval: 12 * time.Second,
+		resync:             make(chan uint64),
 	}
 
 	for _, opt := range opts {
@@ -120,10 +149,132 @@ func (es *EventSyncer) SyncHistory(ctx context.Context, fromBlock uint64) (lastP
 }
 
 // SyncOngoing streams and processes ongoing events as they come since the given fromBlock.
+// It restarts from an earlier block when requested by Resync, or when the processed blocks were reorged,
+// in which case their changes are rolled back first. It fails with ErrReorgTooDeep when none of the blocks
+// which can be rolled back are canonical anymore.
 func (es *EventSyncer) SyncOngoing(ctx context.Context, fromBlock uint64) error {
-	es.logger.Info("subscribing to ongoing registry events", fields.FromBlock(fromBlock))
+	es.syncingOngoing.Store(true)
+	defer es.syncingOngoing.Store(false)
+
+	rollbacker, _ := es.eventHandler.(Rollbacker)
+	var reorgCheck <-chan time.Time
+	if rollbacker != nil && es.reorgCheckInterval > 0 {
+		ticker := time.NewTicker(es.reorgCheckInterval)
+		defer ticker.Stop()
+		reorgCheck = ticker.C
+	}
 
-	logs := es.executionClient.StreamLogs(ctx, fromBlock)
-	_, err := es.eventHandler.HandleBlockEventsStream(logs, true)
-	return err
+	for {
+		es.logger.Info("subscribing to ongoing registry events", fields.FromBlock(fromBlock))
+
+		streamCtx, cancel := context.WithCancel(ctx)
+		logs := es.executionClient.StreamLogs(streamCtx, fromBlock)
+		handled := make(chan error, 1)
+		go func() {
+			_, err := es.eventHandler.HandleBlockEventsStream(logs, true)
+			handled <- err
+		}()
+
+	stream:
+		for {
+			select {
+			case err := <-handled:
+				cancel()
+				return err
+			case fromBlock = <-es.resync:
+				// Stop streaming and wait for the events which were already streamed to be handled.
+				cancel()
+				if err := <-handled; err != nil {
+					return err
+				}
+				es.logger.Info("re-syncing registry events", fields.FromBlock(fromBlock))
+				break stream
+			case <-reorgCheck:
+				toBlock, reorged, err := es.checkReorg(ctx, rollbacker)
+				if errors.Is(err, ErrReorgTooDeep) {
+					// The registry state can't be rolled back to a canonical block, so it can't be relied on anymore.
+					cancel()
+					<-handled
+					return err
+				}
+				if err != nil {
+					es.logger.Error("failed to check for reorgs of processed blocks", zap.Error(err))
+					continue
+				}
+				if !reorged {
+					continue
+				}
+				es.metrics.RegistryReorged()
+				cancel()
+				if err := <-handled; err != nil {
+					return err
+				}
+				if err := rollbacker.Rollback(toBlock, true); err != nil {
+					return fmt.Errorf("roll back reorged blocks: %w", err)
+				}
+				fromBlock = toBlock + 1
+				es.logger.Warn("rolled back reorged registry events", fields.FromBlock(fromBlock))
+				break stream
+			}
+		}
+	}
+}
+
+// checkReorg compares the hashes of the processed blocks with the canonical chain,
+// and if they were reorged, returns the last processed block which is still canonical.
+func (es *EventSyncer) checkReorg(ctx context.Context, rollbacker Rollbacker) (toBlock uint64, reorged bool, err error) {
+	hashes, err := rollbacker.BlockHashes()
+	if err != nil {
+		return 0, false, fmt.Errorf("get processed block hashes: %w", err)
+	}
+	blocks := make([]uint64, 0, len(hashes))
+	for block, hash := range hashes {
+		if hash != (ethcommon.Hash{}) {
+			blocks = append(blocks, block)
+		}
+	}
+	if len(blocks) == 0 {
+		return 0, false, nil
+	}
+	sort.Slice(blocks, func(i, j int) bool { return blocks[i] > blocks[j] })
+
+	// The ancestors of a canonical block are canonical too, so the search stops at the first one.
+	for i, block := range blocks {
+		header, err := es.executionClient.HeaderByNumber(ctx, new(big.Int).SetUint64(block))
+		if err != nil {
+			return 0, false, fmt.Errorf("get header of block %d: %w", block, err)
+		}
+		if header.Hash() != hashes[block] {
+			es.logger.Warn("processed block was reorged",
+				fields.BlockNumber(block),
+				zap.String("processed_hash", hashes[block].Hex()),
+				zap.String("canonical_hash", header.Hash().Hex()))
+			continue
+		}
+		return block, i > 0, nil
+	}
+	return 0, true, ErrReorgTooDeep
+}
+
+// Resync makes the ongoing sync restart from the given block, processing the events since it again.
+// It's meant for recovering from events which were missed or processed incorrectly.
+func (es *EventSyncer) Resync(ctx context.Context, fromBlock uint64) error {
+	if !es.syncingOngoing.Load() {
+		return ErrNotSyncingOngoing
+	}
+
+	lastProcessedBlock, found, err := es.nodeStorage.GetLastProcessedBlock(nil)
+	if err != nil {
+		return fmt.Errorf("failed to read last processed block: %w", err)
+	}
+	if found && lastProcessedBlock != nil && fromBlock > lastProcessedBlock.Uint64()+1 {
+		return fmt.Errorf("%w: last processed block is %d", ErrResyncAhead, lastProcessedBlock.Uint64())
+	}
+
+	select {
+	case es.resync <- fromBlock:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
@@ -3,9 +3,12 @@ package eventsyncer
 import (
 	"context"
 	"encoding/base64"
+	"fmt"
 	"math/big"
 	"net/http/httptest"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -143,6 +146,208 @@ func TestEventSyncer(t *testing.T) {
 	require.NoError(t, eventSyncer.SyncOngoing(ctx, lastProcessedBlock+1))
 }
 
+type streamingClient struct {
+	head uint64
+	// reorgedFrom is the first block which was reorged, or zero if none were.
+	reorgedFrom *atomic.Uint64
+}
+
+func (c streamingClient) FetchHistoricalLogs(context.Context, uint64) (<-chan executionclient.BlockLogs, <-chan error, error) {
+	return nil, nil, executionclient.ErrNothingToSync
+}
+
+func (c streamingClient) StreamLogs(ctx context.Context, fromBlock uint64) <-chan executionclient.BlockLogs {
+	logs := make(chan executionclient.BlockLogs)
+	go func() {
+		defer close(logs)
+		for block := fromBlock; block <= c.head; block++ {
+			select {
+			case logs <- executionclient.BlockLogs{BlockNumber: block, BlockHash: c.header(block).Hash()}:
+			case <-ctx.Done():
+				return
+			}
+		}
+		<-ctx.Done()
+	}()
+	return logs
+}
+
+func (c streamingClient) HeaderByNumber(_ context.Context, blockNumber *big.Int) (*ethtypes.Header, error) {
+	return c.header(blockNumber.Uint64()), nil
+}
+
+func (c streamingClient) header(block uint64) *ethtypes.Header {
+	header := &ethtypes.Header{Number: new(big.Int).SetUint64(block)}
+	if c.reorgedFrom != nil && c.reorgedFrom.Load() != 0 && block >= c.reorgedFrom.Load() {
+		header.Extra = []byte(fmt.Sprintf("reorged from %d", c.reorgedFrom.Load()))
+	}
+	return header
+}
+
+type recordingHandler struct {
+	nodeStorage operatorstorage.Storage
+	handled     chan uint64
+}
+
+func (h recordingHandler) HandleBlockEventsStream(logs <-chan executionclient.BlockLogs, executeTasks bool) (lastProcessedBlock uint64, err error) {
+	for blockLogs := range logs {
+		if err := h.nodeStorage.SaveLastProcessedBlock(nil, new(big.Int).SetUint64(blockLogs.BlockNumber)); err != nil {
+			return 0, err
+		}
+		lastProcessedBlock = blockLogs.BlockNumber
+		h.handled <- blockLogs.BlockNumber
+	}
+	return lastProcessedBlock, nil
+}
+
+// rollbackHandler is a recordingHandler which keeps the hashes of the handled blocks to roll them back.
+type rollbackHandler struct {
+	recordingHandler
+	mu         sync.Mutex
+	hashes     map[uint64]ethcommon.Hash
+	rolledBack chan uint64
+}
+
+func (h *rollbackHandler) HandleBlockEventsStream(logs <-chan executionclient.BlockLogs, executeTasks bool) (lastProcessedBlock uint64, err error) {
+	recorded := make(chan executionclient.BlockLogs)
+	go func() {
+		defer close(recorded)
+		for blockLogs := range logs {
+			h.mu.Lock()
+			h.hashes[blockLogs.BlockNumber] = blockLogs.BlockHash
+			h.mu.Unlock()
+			recorded <- blockLogs
+		}
+	}()
+	return h.recordingHandler.HandleBlockEventsStream(recorded, executeTasks)
+}
+
+func (h *rollbackHandler) BlockHashes() (map[uint64]ethcommon.Hash, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	hashes := make(map[uint64]ethcommon.Hash, len(h.hashes))
+	for block, hash := range h.hashes {
+		hashes[block] = hash
+	}
+	return hashes, nil
+}
+
+func (h *rollbackHandler) Rollback(toBlock uint64, executeTasks bool) error {
+	h.mu.Lock()
+	for block := range h.hashes {
+		if block > toBlock {
+			delete(h.hashes, block)
+		}
+	}
+	h.mu.Unlock()
+	if err := h.nodeStorage.SaveLastProcessedBlock(nil, new(big.Int).SetUint64(toBlock)); err != nil {
+		return err
+	}
+	h.rolledBack <- toBlock
+	return nil
+}
+
+func TestEventSyncerReorg(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	db, err := kv.NewInMemory(logger, basedb.Options{Ctx: ctx})
+	require.NoError(t, err)
+	nodeStorage, err := operatorstorage.NewNodeStorage(logger, db)
+	require.NoError(t, err)
+
+	handler := &rollbackHandler{
+		recordingHandler: recordingHandler{nodeStorage: nodeStorage, handled: make(chan uint64)},
+		hashes:           make(map[uint64]ethcommon.Hash),
+		rolledBack:       make(chan uint64, 1),
+	}
+	client := streamingClient{head: 10, reorgedFrom: &atomic.Uint64{}}
+	eventSyncer := New(nodeStorage, client, handler, WithLogger(logger), WithReorgCheckInterval(10*time.Millisecond))
+
+	syncCtx, stopSync := context.WithCancel(ctx)
+	synced := make(chan error, 1)
+	go func() {
+		synced <- eventSyncer.SyncOngoing(syncCtx, 1)
+	}()
+
+	expectBlocks := func(from, to uint64) {
+		for block := from; block <= to; block++ {
+			select {
+			case handled := <-handler.handled:
+				require.Equal(t, block, handled)
+			case <-ctx.Done():
+				t.Fatalf("timed out waiting for block %d", block)
+			}
+		}
+	}
+	expectBlocks(1, 10)
+
+	// Blocks 8 to 10 are replaced, so they're rolled back and handled again.
+	client.reorgedFrom.Store(8)
+	select {
+	case toBlock := <-handler.rolledBack:
+		require.EqualValues(t, 7, toBlock)
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for rollback")
+	}
+	expectBlocks(8, 10)
+
+	hashes, err := handler.BlockHashes()
+	require.NoError(t, err)
+	require.Equal(t, client.header(9).Hash(), hashes[9])
+
+	// None of the processed blocks are canonical anymore, so they can't be rolled back and the sync stops.
+	client.reorgedFrom.Store(1)
+	select {
+	case err := <-synced:
+		require.ErrorIs(t, err, ErrReorgTooDeep)
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for the sync to stop")
+	}
+	stopSync()
+}
+
+func TestEventSyncerResync(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	db, err := kv.NewInMemory(logger, basedb.Options{Ctx: ctx})
+	require.NoError(t, err)
+	nodeStorage, err := operatorstorage.NewNodeStorage(logger, db)
+	require.NoError(t, err)
+
+	handler := recordingHandler{nodeStorage: nodeStorage, handled: make(chan uint64)}
+	eventSyncer := New(nodeStorage, streamingClient{head: 10}, handler, WithLogger(logger))
+	require.ErrorIs(t, eventSyncer.Resync(ctx, 5), ErrNotSyncingOngoing)
+
+	syncCtx, stopSync := context.WithCancel(ctx)
+	synced := make(chan error, 1)
+	go func() {
+		synced <- eventSyncer.SyncOngoing(syncCtx, 1)
+	}()
+
+	expectBlocks := func(from, to uint64) {
+		for block := from; block <= to; block++ {
+			select {
+			case handled := <-handler.handled:
+				require.Equal(t, block, handled)
+			case <-ctx.Done():
+				t.Fatalf("timed out waiting for block %d", block)
+			}
+		}
+	}
+	expectBlocks(1, 10)
+
+	require.ErrorIs(t, eventSyncer.Resync(ctx, 12), ErrResyncAhead)
+	require.NoError(t, eventSyncer.Resync(ctx, 5))
+	expectBlocks(5, 10)
+
+	stopSync()
+	require.NoError(t, <-synced)
+}
+
 func setupEventHandler(
 	t *testing.T,
 	ctx context.Context,
@@ -3,6 +3,7 @@ package eventsyncer
 type metrics interface {
 	LastBlockProcessed(uint64)
 	LogsProcessingError(error)
+	RegistryReorged()
 }
 
 // nopMetrics is no-op metrics.
@@ -10,3 +11,4 @@ type nopMetrics struct{}
 
 func (n nopMetrics) LastBlockProcessed(uint64) {}
 func (n nopMetrics) LogsProcessingError(error) {}
+func (n nopMetrics) RegistryReorged()          {}
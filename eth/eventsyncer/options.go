@@ -28,3 +28,11 @@ func WithStalenessThreshold(threshold time.Duration) Option {
 		es.stalenessThreshold = threshold
 	}
 }
+
+// WithReorgCheckInterval sets how often the processed blocks are checked for reorgs.
+// Zero disables the checks.
+func WithReorgCheckInterval(interval time.Duration) Option {
+	return func(es *EventSyncer) {
+		es.reorgCheckInterval = interval
+	}
+}
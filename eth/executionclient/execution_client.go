@@ -6,6 +6,8 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum"
@@ -16,6 +18,7 @@ import (
 
 	"github.com/ssvlabs/ssv/eth/contract"
 	"github.com/ssvlabs/ssv/logging/fields"
+	"github.com/ssvlabs/ssv/nodeprobe"
 	"github.com/ssvlabs/ssv/utils/tasks"
 )
 
@@ -24,12 +27,27 @@ var (
 	ErrNotConnected  = fmt.Errorf("not connected")
 	ErrBadInput      = fmt.Errorf("bad input")
 	ErrNothingToSync = errors.New("nothing to sync")
+	ErrInconsistent  = errors.New("execution client is on a different fork")
 )
 
+// nodeAddrSeparator separates multiple execution client addresses.
+const nodeAddrSeparator = ";"
+
+var _ nodeprobe.MultiNode = (*ExecutionClient)(nil)
+
+// blockCheckpoint is a block seen by the connected execution client,
+// used to verify that an endpoint we fail over to is on the same chain.
+type blockCheckpoint struct {
+	number uint64
+	hash   ethcommon.Hash
+}
+
 // ExecutionClient represents a client for interacting with Ethereum execution client.
+// It may be given several endpoints, in which case it uses the first one it can connect to
+// and fails over to the next healthy one in order when it stops responding.
 type ExecutionClient struct {
 	// mandatory
-	nodeAddr        string
+	nodeAddrs       []string
 	contractAddress ethcommon.Address
 
 	// optional
@@ -42,14 +60,27 @@ type ExecutionClient struct {
 	logBatchSize                uint64
 
 	// variables
-	client *ethclient.Client
-	closed chan struct{}
+	clientMu     sync.RWMutex
+	client       *ethclient.Client
+	nodeIdx      int // index of the connected endpoint in nodeAddrs
+	failoverMu   sync.Mutex
+	checkpointMu sync.Mutex
+	checkpoint   blockCheckpoint
+	statusesMu   sync.RWMutex
+	statuses     map[string]nodeprobe.Status
+	closed       chan struct{}
 }
 
 // New creates a new instance of ExecutionClient.
+// nodeAddr may contain several semicolon-separated addresses, ordered by preference.
 func New(ctx context.Context, nodeAddr string, contractAddr ethcommon.Address, opts ...Option) (*ExecutionClient, error) {
+	nodeAddrs := ParseNodeAddresses(nodeAddr)
+	if len(nodeAddrs) == 0 {
+		return nil, fmt.Errorf("no execution client address provided")
+	}
+
 	client := &ExecutionClient{
-		nodeAddr:                    nodeAddr,
+		nodeAddrs:                   nodeAddrs,
 		contractAddress:             contractAddr,
 		logger:                      zap.NewNop(),
 		metrics:                     nopMetrics{},
@@ -58,6 +89,7 @@ func New(ctx context.Context, nodeAddr string, contractAddr ethcommon.Address, o
 		reconnectionInitialInterval: DefaultReconnectionInitialInterval,
 		reconnectionMaxInterval:     DefaultReconnectionMaxInterval,
 		logBatchSize:                DefaultHistoricalLogsBatchSize, // TODO Make batch of logs adaptive depending on "websocket: read limit"
+		statuses:                    make(map[string]nodeprobe.Status),
 		closed:                      make(chan struct{}),
 	}
 	for _, opt := range opts {
@@ -70,16 +102,47 @@ func New(ctx context.Context, nodeAddr string, contractAddr ethcommon.Address, o
 	return client, nil
 }
 
+// ParseNodeAddresses splits a semicolon-separated list of execution client addresses.
+func ParseNodeAddresses(addr string) []string {
+	var addrs []string
+	for _, a := range strings.Split(addr, nodeAddrSeparator) {
+		if a = strings.TrimSpace(a); a != "" {
+			addrs = append(addrs, a)
+		}
+	}
+	return addrs
+}
+
 // Close shuts down ExecutionClient.
 func (ec *ExecutionClient) Close() error {
 	close(ec.closed)
-	ec.client.Close()
+	ec.currentClient().Close()
 	return nil
 }
 
+// currentClient returns the client of the connected endpoint.
+func (ec *ExecutionClient) currentClient() *ethclient.Client {
+	ec.clientMu.RLock()
+	defer ec.clientMu.RUnlock()
+
+	return ec.client
+}
+
+// currentNodeAddr returns the address of the connected endpoint.
+func (ec *ExecutionClient) currentNodeAddr() string {
+	ec.clientMu.RLock()
+	defer ec.clientMu.RUnlock()
+
+	return ec.nodeAddrs[ec.nodeIdx]
+}
+
 // FetchHistoricalLogs retrieves historical logs emitted by the contract starting from fromBlock.
 func (ec *ExecutionClient) FetchHistoricalLogs(ctx context.Context, fromBlock uint64) (logs <-chan BlockLogs, errors <-chan error, err error) {
-	currentBlock, err := ec.client.BlockNumber(ctx)
+	var currentBlock uint64
+	err = ec.withFailover(ctx, "BlockNumber", func(client *ethclient.Client) (err error) {
+		currentBlock, err = client.BlockNumber(ctx)
+		return err
+	})
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to get current block: %w", err)
 	}
@@ -95,6 +158,12 @@ func (ec *ExecutionClient) FetchHistoricalLogs(ctx context.Context, fromBlock ui
 	return
 }
 
+// FetchLogs retrieves the logs emitted by the contract from fromBlock to toBlock, inclusive.
+// Unlike FetchHistoricalLogs, toBlock isn't limited by the follow distance.
+func (ec *ExecutionClient) FetchLogs(ctx context.Context, fromBlock, toBlock uint64) (logs <-chan BlockLogs, errors <-chan error) {
+	return ec.fetchLogsInBatches(ctx, fromBlock, toBlock)
+}
+
 // Calls FilterLogs multiple times and batches results to avoid fetching enormous amount of events
 func (ec *ExecutionClient) fetchLogsInBatches(ctx context.Context, startBlock, endBlock uint64) (<-chan BlockLogs, <-chan error) {
 	logs := make(chan BlockLogs, defaultLogBuf)
@@ -116,11 +185,7 @@ func (ec *ExecutionClient) fetchLogsInBatches(ctx context.Context, startBlock, e
 			}
 
 			start := time.Now()
-			results, err := ec.client.FilterLogs(ctx, ethereum.FilterQuery{
-				Addresses: []ethcommon.Address{ec.contractAddress},
-				FromBlock: new(big.Int).SetUint64(fromBlock),
-				ToBlock:   new(big.Int).SetUint64(toBlock),
-			})
+			results, err := ec.filterLogs(ctx, fromBlock, toBlock)
 			if err != nil {
 				errors <- err
 				return
@@ -157,9 +222,19 @@ func (ec *ExecutionClient) fetchLogsInBatches(ctx context.Context, startBlock, e
 					}
 					validLogs = append(validLogs, log)
 				}
+				if len(validLogs) != 0 {
+					last := validLogs[len(validLogs)-1]
+					ec.setCheckpoint(blockCheckpoint{number: last.BlockNumber, hash: last.BlockHash})
+				}
 				if len(validLogs) == 0 {
 					// Emit empty block logs to indicate that we have advanced to this block.
-					logs <- BlockLogs{BlockNumber: toBlock}
+					// The block hash allows the consumer to detect a reorg of this block later.
+					header, err := ec.HeaderByNumber(ctx, new(big.Int).SetUint64(toBlock))
+					if err != nil {
+						errors <- err
+						return
+					}
+					logs <- BlockLogs{BlockNumber: toBlock, BlockHash: header.Hash()}
 				} else {
 					for _, blockLogs := range PackLogs(validLogs) {
 						logs <- blockLogs
@@ -174,6 +249,42 @@ func (ec *ExecutionClient) fetchLogsInBatches(ctx context.Context, startBlock, e
 	return logs, errors
 }
 
+// filterLogs fetches the contract logs in the given range. On failover, the range is re-fetched as a whole,
+// so no logs are skipped or duplicated.
+func (ec *ExecutionClient) filterLogs(ctx context.Context, fromBlock, toBlock uint64) ([]ethtypes.Log, error) {
+	query := ethereum.FilterQuery{
+		Addresses: []ethcommon.Address{ec.contractAddress},
+		FromBlock: new(big.Int).SetUint64(fromBlock),
+		ToBlock:   new(big.Int).SetUint64(toBlock),
+	}
+
+	var results []ethtypes.Log
+	err := ec.withFailover(ctx, "FilterLogs", func(client *ethclient.Client) (err error) {
+		results, err = client.FilterLogs(ctx, query)
+		return err
+	})
+	return results, err
+}
+
+// withFailover calls f with the connected endpoint's client and, if it fails,
+// fails over to the next healthy endpoint and retries, at most once per endpoint.
+func (ec *ExecutionClient) withFailover(ctx context.Context, operation string, f func(client *ethclient.Client) error) error {
+	for attempt := 0; ; attempt++ {
+		err := f(ec.currentClient())
+		if err == nil || ctx.Err() != nil || attempt >= len(ec.nodeAddrs)-1 {
+			return err
+		}
+
+		ec.logger.Warn("execution client request failed, failing over",
+			zap.String("operation", operation),
+			fields.Address(ec.currentNodeAddr()),
+			zap.Error(err))
+		if failoverErr := ec.failover(ctx); failoverErr != nil {
+			return fmt.Errorf("%w (failover: %w)", err, failoverErr)
+		}
+	}
+}
+
 // StreamLogs subscribes to events emitted by the contract.
 func (ec *ExecutionClient) StreamLogs(ctx context.Context, fromBlock uint64) <-chan BlockLogs {
 	logs := make(chan BlockLogs)
@@ -188,7 +299,7 @@ func (ec *ExecutionClient) StreamLogs(ctx context.Context, fromBlock uint64) <-c
 			case <-ec.closed:
 				return
 			default:
-				lastBlock, err := ec.streamLogsToChan(ctx, logs, fromBlock)
+				nextBlock, err := ec.streamLogsToChan(ctx, logs, fromBlock)
 				if errors.Is(err, ErrClosed) || errors.Is(err, context.Canceled) {
 					// Closed gracefully.
 					return
@@ -201,17 +312,18 @@ func (ec *ExecutionClient) StreamLogs(ctx context.Context, fromBlock uint64) <-c
 				}
 
 				tries++
-				if tries > 2 {
+				if tries > 2*len(ec.nodeAddrs) {
 					ec.logger.Fatal("failed to stream registry events", zap.Error(err))
 				}
-				if lastBlock > fromBlock {
+				if nextBlock > fromBlock {
 					// Successfully streamed some logs, reset tries.
 					tries = 0
 				}
 
 				ec.logger.Error("failed to stream registry events, reconnecting", zap.Error(err))
 				ec.reconnect(ctx)
-				fromBlock = lastBlock + 1
+				// Resume right after the last block streamed, on whichever endpoint reconnect switched to.
+				fromBlock = nextBlock
 			}
 		}
 	}()
@@ -219,16 +331,34 @@ func (ec *ExecutionClient) StreamLogs(ctx context.Context, fromBlock uint64) <-c
 	return logs
 }
 
-// Healthy returns if execution client is currently healthy: responds to requests and not in the syncing state.
+// Healthy returns if execution client is currently healthy: responds to requests, is not in the syncing state
+// and agrees with the last seen checkpoint block. It only reports the health of the connected endpoint,
+// failing over is left to the requests and the log stream which fail on it.
 func (ec *ExecutionClient) Healthy(ctx context.Context) error {
 	if ec.isClosed() {
 		return ErrClosed
 	}
 
+	nodeAddr, client := ec.currentNodeAddr(), ec.currentClient()
+	if err := ec.checkHealth(ctx, nodeAddr, client); err != nil {
+		return err
+	}
+	return ec.checkConsistency(ctx, nodeAddr, client)
+}
+
+// checkHealth checks that the given endpoint responds and isn't syncing, and records its status.
+func (ec *ExecutionClient) checkHealth(ctx context.Context, nodeAddr string, client *ethclient.Client) (err error) {
 	ctx, cancel := context.WithTimeout(ctx, ec.connectionTimeout)
 	defer cancel()
 
-	sp, err := ec.client.SyncProgress(ctx)
+	start := time.Now()
+	defer func() {
+		ec.statusesMu.Lock()
+		ec.statuses[nodeAddr] = nodeprobe.Status{Err: err, Latency: time.Since(start), CheckedAt: start}
+		ec.statusesMu.Unlock()
+	}()
+
+	sp, err := client.SyncProgress(ctx)
 	if err != nil {
 		ec.metrics.ExecutionClientFailure()
 		return err
@@ -244,8 +374,72 @@ func (ec *ExecutionClient) Healthy(ctx context.Context) error {
 	return nil
 }
 
-func (ec *ExecutionClient) BlockByNumber(ctx context.Context, blockNumber *big.Int) (*ethtypes.Block, error) {
-	return ec.client.BlockByNumber(ctx, blockNumber)
+// EndpointStatuses returns the result of the latest health check of each endpoint, keyed by address.
+func (ec *ExecutionClient) EndpointStatuses() map[string]nodeprobe.Status {
+	ec.statusesMu.RLock()
+	defer ec.statusesMu.RUnlock()
+
+	statuses := make(map[string]nodeprobe.Status, len(ec.statuses))
+	for addr, status := range ec.statuses {
+		statuses[addr] = status
+	}
+	return statuses
+}
+
+func (ec *ExecutionClient) BlockByNumber(ctx context.Context, blockNumber *big.Int) (block *ethtypes.Block, err error) {
+	err = ec.withFailover(ctx, "BlockByNumber", func(client *ethclient.Client) (err error) {
+		block, err = client.BlockByNumber(ctx, blockNumber)
+		return err
+	})
+	return block, err
+}
+
+// HeaderByNumber returns the header of the given block, or the latest header if blockNumber is nil.
+func (ec *ExecutionClient) HeaderByNumber(ctx context.Context, blockNumber *big.Int) (header *ethtypes.Header, err error) {
+	err = ec.withFailover(ctx, "HeaderByNumber", func(client *ethclient.Client) (err error) {
+		header, err = client.HeaderByNumber(ctx, blockNumber)
+		return err
+	})
+	return header, err
+}
+
+func (ec *ExecutionClient) setCheckpoint(checkpoint blockCheckpoint) {
+	ec.checkpointMu.Lock()
+	defer ec.checkpointMu.Unlock()
+
+	if checkpoint.number >= ec.checkpoint.number {
+		ec.checkpoint = checkpoint
+	}
+}
+
+// checkConsistency verifies that the given endpoint has the same block at the height of the last checkpoint,
+// which guards against failing over to an endpoint that follows a different fork.
+func (ec *ExecutionClient) checkConsistency(ctx context.Context, nodeAddr string, client *ethclient.Client) error {
+	ec.checkpointMu.Lock()
+	checkpoint := ec.checkpoint
+	ec.checkpointMu.Unlock()
+
+	if checkpoint.hash == (ethcommon.Hash{}) {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, ec.connectionTimeout)
+	defer cancel()
+
+	header, err := client.HeaderByNumber(ctx, new(big.Int).SetUint64(checkpoint.number))
+	if err != nil {
+		return fmt.Errorf("get header %d: %w", checkpoint.number, err)
+	}
+	if header.Hash() != checkpoint.hash {
+		ec.metrics.ExecutionClientInconsistent()
+		ec.logger.Error("execution client block hash doesn't match previously seen block",
+			fields.Address(nodeAddr),
+			fields.BlockNumber(checkpoint.number),
+			zap.String("expected_hash", checkpoint.hash.Hex()),
+			zap.String("actual_hash", header.Hash().Hex()))
+		return fmt.Errorf("%w: block %d has hash %s, expected %s", ErrInconsistent, checkpoint.number, header.Hash().Hex(), checkpoint.hash.Hex())
+	}
+	return nil
 }
 
 func (ec *ExecutionClient) isClosed() bool {
@@ -258,12 +452,13 @@ func (ec *ExecutionClient) isClosed() bool {
 }
 
 // streamLogsToChan streams ongoing logs from the given block to the given channel.
-// streamLogsToChan *always* returns the last block it fetched, even if it errored.
+// streamLogsToChan *always* returns the block to resume streaming from, even if it errored:
+// the block after the last one whose logs were sent to the channel, or after the last fully fetched range.
 // TODO: consider handling "websocket: read limit exceeded" error and reducing batch size (syncSmartContractsEvents has code for this)
-func (ec *ExecutionClient) streamLogsToChan(ctx context.Context, logs chan<- BlockLogs, fromBlock uint64) (lastBlock uint64, err error) {
+func (ec *ExecutionClient) streamLogsToChan(ctx context.Context, logs chan<- BlockLogs, fromBlock uint64) (nextBlock uint64, err error) {
 	heads := make(chan *ethtypes.Header)
 
-	sub, err := ec.client.SubscribeNewHead(ctx, heads)
+	sub, err := ec.currentClient().SubscribeNewHead(ctx, heads)
 	if err != nil {
 		return fromBlock, fmt.Errorf("subscribe heads: %w", err)
 	}
@@ -294,11 +489,11 @@ func (ec *ExecutionClient) streamLogsToChan(ctx context.Context, logs chan<- Blo
 			logStream, fetchErrors := ec.fetchLogsInBatches(ctx, fromBlock, toBlock)
 			for block := range logStream {
 				logs <- block
-				lastBlock = block.BlockNumber
+				fromBlock = block.BlockNumber + 1
 			}
 			if err := <-fetchErrors; err != nil {
-				// If we get an error while fetching, we return the last block we fetched.
-				return lastBlock, fmt.Errorf("fetch logs: %w", err)
+				// The logs of each sent block are complete, so the stream resumes after the last one sent.
+				return fromBlock, fmt.Errorf("fetch logs: %w", err)
 			}
 			fromBlock = toBlock + 1
 			ec.metrics.ExecutionClientLastFetchedBlock(fromBlock)
@@ -306,35 +501,102 @@ func (ec *ExecutionClient) streamLogsToChan(ctx context.Context, logs chan<- Blo
 	}
 }
 
-// connect connects to Ethereum execution client.
+// connect connects to the first reachable execution client endpoint, in order of preference.
 // It must not be called twice in parallel.
 func (ec *ExecutionClient) connect(ctx context.Context) error {
-	logger := ec.logger.With(fields.Address(ec.nodeAddr))
+	var errs []error
+	for i, nodeAddr := range ec.nodeAddrs {
+		client, err := ec.dial(ctx, nodeAddr)
+		if err != nil {
+			ec.logger.Warn("could not connect to execution client", fields.Address(nodeAddr), zap.Error(err))
+			errs = append(errs, fmt.Errorf("%s: %w", nodeAddr, err))
+			continue
+		}
+		ec.setClient(i, client)
+		return nil
+	}
+	return errors.Join(errs...)
+}
+
+func (ec *ExecutionClient) dial(ctx context.Context, nodeAddr string) (*ethclient.Client, error) {
+	logger := ec.logger.With(fields.Address(nodeAddr))
 
 	ctx, cancel := context.WithTimeout(ctx, ec.connectionTimeout)
 	defer cancel()
 
 	start := time.Now()
-	var err error
-	ec.client, err = ethclient.DialContext(ctx, ec.nodeAddr)
+	client, err := ethclient.DialContext(ctx, nodeAddr)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	logger.Info("connected to execution client", zap.Duration("took", time.Since(start)))
-	return nil
+	return client, nil
+}
+
+// setClient switches to the given endpoint, closing the previous connection if it's a different one.
+func (ec *ExecutionClient) setClient(nodeIdx int, client *ethclient.Client) {
+	ec.clientMu.Lock()
+	defer ec.clientMu.Unlock()
+
+	if ec.client != nil && ec.client != client {
+		ec.client.Close()
+	}
+	ec.client = client
+	ec.nodeIdx = nodeIdx
+}
+
+// failover switches to the next endpoint (in order, wrapping around to the current one) which is
+// healthy and agrees with the last seen checkpoint block.
+func (ec *ExecutionClient) failover(ctx context.Context) error {
+	ec.failoverMu.Lock()
+	defer ec.failoverMu.Unlock()
+
+	ec.clientMu.RLock()
+	current := ec.nodeIdx
+	ec.clientMu.RUnlock()
+
+	var errs []error
+	for i := 1; i <= len(ec.nodeAddrs); i++ {
+		idx := (current + i) % len(ec.nodeAddrs)
+		nodeAddr := ec.nodeAddrs[idx]
+
+		client, err := ec.dial(ctx, nodeAddr)
+		if err == nil {
+			err = ec.checkHealth(ctx, nodeAddr, client)
+		}
+		if err == nil {
+			err = ec.checkConsistency(ctx, nodeAddr, client)
+		}
+		if err != nil {
+			if client != nil {
+				client.Close()
+			}
+			errs = append(errs, fmt.Errorf("%s: %w", nodeAddr, err))
+			continue
+		}
+
+		if idx != current {
+			ec.logger.Info("switched execution client",
+				zap.String("from", ec.nodeAddrs[current]),
+				zap.String("to", nodeAddr))
+		}
+		ec.setClient(idx, client)
+		return nil
+	}
+	return errors.Join(errs...)
 }
 
-// reconnect tries to reconnect multiple times with an exponent interval.
+// reconnect tries to reconnect multiple times with an exponent interval, failing over between endpoints.
 // It panics when reconnecting limit is reached.
 // It must not be called twice in parallel.
 func (ec *ExecutionClient) reconnect(ctx context.Context) {
-	logger := ec.logger.With(fields.Address(ec.nodeAddr))
+	logger := ec.logger.With(fields.Address(ec.currentNodeAddr()))
 
 	start := time.Now()
 	tasks.ExecWithInterval(func(lastTick time.Duration) (stop bool, cont bool) {
 		logger.Info("reconnecting")
-		if err := ec.connect(ctx); err != nil {
+		if err := ec.failover(ctx); err != nil {
 			if ec.isClosed() {
 				return true, false
 			}
@@ -349,9 +611,9 @@ func (ec *ExecutionClient) reconnect(ctx context.Context) {
 		return true, false
 	}, ec.reconnectionInitialInterval, ec.reconnectionMaxInterval+(ec.reconnectionInitialInterval))
 
-	logger.Info("reconnected to execution client", zap.Duration("took", time.Since(start)))
+	logger.Info("reconnected to execution client", fields.Address(ec.currentNodeAddr()), zap.Duration("took", time.Since(start)))
 }
 
 func (ec *ExecutionClient) Filterer() (*contract.ContractFilterer, error) {
-	return contract.NewContractFilterer(ec.contractAddress, ec.client)
+	return contract.NewContractFilterer(ec.contractAddress, ec.currentClient())
 }
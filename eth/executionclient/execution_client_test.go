@@ -3,6 +3,8 @@ package executionclient
 import (
 	"context"
 	"math/big"
+	"net"
+	"net/http"
 	"net/http/httptest"
 	"strings"
 	"sync"
@@ -313,6 +315,244 @@ func TestFetchLogsInBatches(t *testing.T) {
 	require.NoError(t, sim.Close())
 }
 
+func TestFailover(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	const testTimeout = 5 * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	sim := simTestBackend(testAddr)
+
+	// Expose the same chain through two endpoints.
+	rpcServer, _ := sim.Node().RPCHandler()
+	defer rpcServer.Stop()
+	primary := newKillableServer(t, rpcServer.WebsocketHandler([]string{"*"}))
+	secondary := httptest.NewServer(rpcServer.WebsocketHandler([]string{"*"}))
+	defer secondary.Close()
+
+	parsed, _ := abi.JSON(strings.NewReader(callableAbi))
+	auth, _ := bind.NewKeyedTransactorWithChainID(testKey, big.NewInt(1337))
+	contractAddr, _, contract, err := bind.DeployContract(auth, parsed, ethcommon.FromHex(callableBin), sim.Client())
+	require.NoError(t, err)
+	sim.Commit()
+
+	for i := 0; i < blocksWithLogsLength; i++ {
+		_, err := contract.Transact(auth, "Call")
+		require.NoError(t, err)
+		sim.Commit()
+	}
+
+	client, err := New(
+		ctx,
+		httpToWebSocketURL(primary.URL)+";"+httpToWebSocketURL(secondary.URL),
+		contractAddr,
+		WithLogger(logger),
+		WithFollowDistance(0),
+		WithLogBatchSize(5),
+	)
+	require.NoError(t, err)
+	require.Equal(t, httpToWebSocketURL(primary.URL), client.currentNodeAddr())
+
+	// Fetch the first half of the logs from the primary endpoint.
+	var fetchedLogs []ethtypes.Log
+	logs, errs := client.fetchLogsInBatches(ctx, 0, blocksWithLogsLength/2)
+	for block := range logs {
+		fetchedLogs = append(fetchedLogs, block.Logs...)
+	}
+	require.NoError(t, <-errs)
+
+	// Take the primary endpoint down and fetch the rest from the secondary one.
+	primary.kill()
+
+	logs, errs = client.fetchLogsInBatches(ctx, blocksWithLogsLength/2+1, blocksWithLogsLength+1)
+	for block := range logs {
+		fetchedLogs = append(fetchedLogs, block.Logs...)
+	}
+	require.NoError(t, <-errs)
+	require.Equal(t, httpToWebSocketURL(secondary.URL), client.currentNodeAddr())
+
+	// Every log is received exactly once.
+	require.Len(t, fetchedLogs, blocksWithLogsLength)
+	for i := 1; i < len(fetchedLogs); i++ {
+		require.Less(t, fetchedLogs[i-1].BlockNumber, fetchedLogs[i].BlockNumber)
+	}
+
+	require.NoError(t, client.Healthy(ctx))
+	statuses := client.EndpointStatuses()
+	require.NoError(t, statuses[httpToWebSocketURL(secondary.URL)].Err)
+
+	require.NoError(t, client.Close())
+	require.NoError(t, sim.Close())
+}
+
+func TestStreamLogsFailover(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	const testTimeout = 10 * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	sim := simTestBackend(testAddr)
+
+	rpcServer, _ := sim.Node().RPCHandler()
+	defer rpcServer.Stop()
+	primary := newKillableServer(t, rpcServer.WebsocketHandler([]string{"*"}))
+	secondary := httptest.NewServer(rpcServer.WebsocketHandler([]string{"*"}))
+	defer secondary.Close()
+
+	parsed, _ := abi.JSON(strings.NewReader(callableAbi))
+	auth, _ := bind.NewKeyedTransactorWithChainID(testKey, big.NewInt(1337))
+	contractAddr, _, contract, err := bind.DeployContract(auth, parsed, ethcommon.FromHex(callableBin), sim.Client())
+	require.NoError(t, err)
+	sim.Commit()
+
+	client, err := New(
+		ctx,
+		httpToWebSocketURL(primary.URL)+";"+httpToWebSocketURL(secondary.URL),
+		contractAddr,
+		WithLogger(logger),
+		WithFollowDistance(0),
+		WithReconnectionInitialInterval(10*time.Millisecond),
+	)
+	require.NoError(t, err)
+
+	var streamedBlocks []uint64
+	var streamedLogsCount atomic.Int64
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for block := range client.StreamLogs(ctx, 0) {
+			streamedBlocks = append(streamedBlocks, block.BlockNumber)
+			streamedLogsCount.Add(int64(len(block.Logs)))
+		}
+	}()
+
+	for i := 0; i < blocksWithLogsLength; i++ {
+		if i == blocksWithLogsLength/2 {
+			require.Eventually(t, func() bool {
+				return streamedLogsCount.Load() == int64(i)
+			}, testTimeout, 5*time.Millisecond)
+			primary.kill()
+		}
+		_, err := contract.Transact(auth, "Call")
+		require.NoError(t, err)
+		sim.Commit()
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Every block is streamed exactly once, across the failover.
+	require.Eventually(t, func() bool {
+		return streamedLogsCount.Load() == blocksWithLogsLength
+	}, testTimeout, 5*time.Millisecond)
+	require.Equal(t, httpToWebSocketURL(secondary.URL), client.currentNodeAddr())
+
+	require.NoError(t, client.Close())
+	<-done
+	for i := 1; i < len(streamedBlocks); i++ {
+		require.Less(t, streamedBlocks[i-1], streamedBlocks[i])
+	}
+	require.NoError(t, sim.Close())
+}
+
+func TestFailoverInconsistentEndpoint(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	const testTimeout = 5 * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	// Two independent chains with the same genesis.
+	sim := simTestBackend(testAddr)
+	otherSim := simTestBackend(testAddr)
+
+	rpcServer, _ := sim.Node().RPCHandler()
+	defer rpcServer.Stop()
+	primary := newKillableServer(t, rpcServer.WebsocketHandler([]string{"*"}))
+
+	otherRPCServer, _ := otherSim.Node().RPCHandler()
+	defer otherRPCServer.Stop()
+	forked := httptest.NewServer(otherRPCServer.WebsocketHandler([]string{"*"}))
+	defer forked.Close()
+
+	parsed, _ := abi.JSON(strings.NewReader(callableAbi))
+	auth, _ := bind.NewKeyedTransactorWithChainID(testKey, big.NewInt(1337))
+	contractAddr, _, contract, err := bind.DeployContract(auth, parsed, ethcommon.FromHex(callableBin), sim.Client())
+	require.NoError(t, err)
+	sim.Commit()
+	otherSim.Commit()
+
+	for i := 0; i < 5; i++ {
+		_, err := contract.Transact(auth, "Call")
+		require.NoError(t, err)
+		sim.Commit()
+		otherSim.Commit()
+	}
+
+	metrics := &inconsistencyMetrics{}
+	client, err := New(
+		ctx,
+		httpToWebSocketURL(primary.URL)+";"+httpToWebSocketURL(forked.URL),
+		contractAddr,
+		WithLogger(logger),
+		WithMetrics(metrics),
+		WithFollowDistance(0),
+	)
+	require.NoError(t, err)
+
+	logs, errs := client.fetchLogsInBatches(ctx, 0, 6)
+	for range logs {
+	}
+	require.NoError(t, <-errs)
+
+	primary.kill()
+
+	_, err = client.BlockByNumber(ctx, big.NewInt(1))
+	require.ErrorIs(t, err, ErrInconsistent)
+	require.EqualValues(t, 1, metrics.inconsistencies.Load())
+
+	require.NoError(t, sim.Close())
+	require.NoError(t, otherSim.Close())
+}
+
+// killableServer is a test server whose kill drops all connections, including hijacked websocket ones.
+type killableServer struct {
+	*httptest.Server
+	mu    sync.Mutex
+	conns []net.Conn
+}
+
+func newKillableServer(t *testing.T, handler http.Handler) *killableServer {
+	s := &killableServer{Server: httptest.NewUnstartedServer(handler)}
+	s.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			s.mu.Lock()
+			s.conns = append(s.conns, conn)
+			s.mu.Unlock()
+		}
+	}
+	s.Start()
+	t.Cleanup(s.kill)
+	return s
+}
+
+func (s *killableServer) kill() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.conns = nil
+	_ = s.Listener.Close()
+}
+
+type inconsistencyMetrics struct {
+	nopMetrics
+	inconsistencies atomic.Int32
+}
+
+func (m *inconsistencyMetrics) ExecutionClientInconsistent() {
+	m.inconsistencies.Add(1)
+}
+
 // TestChainReorganizationLogs check that the client receives removed logs correctly.
 // Steps:
 //  1. Deploy the Callable contract.
@@ -0,0 +1,146 @@
+package localevents
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"gopkg.in/yaml.v3"
+
+	"github.com/ssvlabs/ssv/eth/contract"
+)
+
+// contractEvents are the types of the contract events by their names. The events which have no YAML type
+// of their own are decoded field by field: addresses and byte slices are hex strings, big integers are
+// numbers or numeric strings, and the cluster is a mapping of its fields.
+var contractEvents = eventTypes(
+	contract.ContractAdminChanged{},
+	contract.ContractBeaconUpgraded{},
+	contract.ContractClusterDeposited{},
+	contract.ContractClusterLiquidated{},
+	contract.ContractClusterReactivated{},
+	contract.ContractClusterWithdrawn{},
+	contract.ContractDeclareOperatorFeePeriodUpdated{},
+	contract.ContractExecuteOperatorFeePeriodUpdated{},
+	contract.ContractFeeRecipientAddressUpdated{},
+	contract.ContractInitialized{},
+	contract.ContractLiquidationThresholdPeriodUpdated{},
+	contract.ContractMinimumLiquidationCollateralUpdated{},
+	contract.ContractNetworkEarningsWithdrawn{},
+	contract.ContractNetworkFeeUpdated{},
+	contract.ContractOperatorAdded{},
+	contract.ContractOperatorFeeDeclarationCancelled{},
+	contract.ContractOperatorFeeDeclared{},
+	contract.ContractOperatorFeeExecuted{},
+	contract.ContractOperatorFeeIncreaseLimitUpdated{},
+	contract.ContractOperatorMaximumFeeUpdated{},
+	contract.ContractOperatorRemoved{},
+	contract.ContractOperatorWhitelistUpdated{},
+	contract.ContractOperatorWithdrawn{},
+	contract.ContractOwnershipTransferStarted{},
+	contract.ContractOwnershipTransferred{},
+	contract.ContractUpgraded{},
+	contract.ContractValidatorAdded{},
+	contract.ContractValidatorExited{},
+	contract.ContractValidatorRemoved{},
+)
+
+func eventTypes(events ...interface{}) map[string]reflect.Type {
+	types := make(map[string]reflect.Type, len(events))
+	for _, event := range events {
+		typ := reflect.TypeOf(event)
+		types[strings.TrimPrefix(typ.Name(), "Contract")] = typ
+	}
+	return types
+}
+
+var (
+	addressType = reflect.TypeOf(ethcommon.Address{})
+	bigIntType  = reflect.TypeOf((*big.Int)(nil))
+	bytesType   = reflect.TypeOf([]byte(nil))
+)
+
+type contractEventYAML struct {
+	value reflect.Value
+}
+
+func (e *contractEventYAML) toEventData() (interface{}, error) {
+	return e.value.Interface(), nil
+}
+
+// decodeValue decodes the node into the settable value.
+func decodeValue(node *yaml.Node, v reflect.Value) error {
+	switch v.Type() {
+	case addressType:
+		var s string
+		if err := node.Decode(&s); err != nil {
+			return err
+		}
+		if !ethcommon.IsHexAddress(s) {
+			return fmt.Errorf("invalid address %q", s)
+		}
+		v.Set(reflect.ValueOf(ethcommon.HexToAddress(s)))
+		return nil
+
+	case bigIntType:
+		var s string
+		if err := node.Decode(&s); err != nil {
+			return err
+		}
+		n, ok := new(big.Int).SetString(s, 0)
+		if !ok {
+			return fmt.Errorf("invalid integer %q", s)
+		}
+		v.Set(reflect.ValueOf(n))
+		return nil
+
+	case bytesType:
+		var s string
+		if err := node.Decode(&s); err != nil {
+			return err
+		}
+		b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+		if err != nil {
+			return err
+		}
+		v.SetBytes(b)
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			return fmt.Errorf("line %d: expected a mapping", node.Line)
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			name := node.Content[i].Value
+			field := v.FieldByName(name)
+			if name == "Raw" || !field.IsValid() || !field.CanSet() {
+				return fmt.Errorf("line %d: unknown field %q", node.Content[i].Line, name)
+			}
+			if err := decodeValue(node.Content[i+1], field); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+		}
+		return nil
+
+	case reflect.Slice:
+		if node.Kind != yaml.SequenceNode {
+			return fmt.Errorf("line %d: expected a sequence", node.Line)
+		}
+		slice := reflect.MakeSlice(v.Type(), len(node.Content), len(node.Content))
+		for i, item := range node.Content {
+			if err := decodeValue(item, slice.Index(i)); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+		return nil
+
+	default:
+		return node.Decode(v.Addr().Interface())
+	}
+}
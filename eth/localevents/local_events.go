@@ -1,41 +1,124 @@
 package localevents
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
 	"encoding/hex"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
 	"strings"
+	"time"
 
 	ethcommon "github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
 	"gopkg.in/yaml.v3"
 
 	"github.com/ssvlabs/ssv/eth/contract"
 )
 
 // Event represents an eth1 event log in the system
-// TODO: It has no Log field because it's not used and seems unnecessary. However, we need to make sure we don't need it.
 type Event struct {
 	// Name is the event name used for internal representation.
 	Name string
-	// Data is the parsed event
+	// BlockNumber is the block the event is processed in. Events without a block number are in block 0.
+	BlockNumber uint64
+	// LogIndex is the index of the event in its block. Load assigns the events without a log index
+	// the index following the previous event of their block in the file.
+	LogIndex uint
+	// Data is the parsed event, whose Raw log has the block number and log index of the event.
 	Data interface{}
+
+	hasLogIndex bool
 }
 
+// Load reads the events of a file, ordered by their block numbers and log indexes.
 func Load(path string) ([]Event, error) {
 	yamlFile, err := os.ReadFile(filepath.Clean(path))
 	if err != nil {
 		return nil, err
 	}
+	return parse(yamlFile)
+}
 
+func parse(yamlFile []byte) ([]Event, error) {
 	var events []Event
 	if err := yaml.Unmarshal(yamlFile, &events); err != nil {
 		return nil, err
 	}
 
+	nextLogIndex := make(map[uint64]uint)
+	for i := range events {
+		if !events[i].hasLogIndex {
+			events[i].LogIndex = nextLogIndex[events[i].BlockNumber]
+		}
+		nextLogIndex[events[i].BlockNumber] = events[i].LogIndex + 1
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].BlockNumber != events[j].BlockNumber {
+			return events[i].BlockNumber < events[j].BlockNumber
+		}
+		return events[i].LogIndex < events[j].LogIndex
+	})
+
+	for i := range events {
+		if i > 0 && events[i].BlockNumber == events[i-1].BlockNumber && events[i].LogIndex == events[i-1].LogIndex {
+			return nil, fmt.Errorf("duplicate log index %d in block %d", events[i].LogIndex, events[i].BlockNumber)
+		}
+		events[i].Data = withRawLog(events[i].Data, events[i].BlockNumber, events[i].LogIndex)
+	}
+
 	return events, nil
 }
 
+// withRawLog returns a copy of the event data whose Raw log has the given block number and log index.
+func withRawLog(data interface{}, blockNumber uint64, logIndex uint) interface{} {
+	v := reflect.New(reflect.TypeOf(data)).Elem()
+	v.Set(reflect.ValueOf(data))
+	raw := v.FieldByName("Raw")
+	if !raw.IsValid() {
+		return data
+	}
+	raw.FieldByName("BlockNumber").SetUint(blockNumber)
+	raw.FieldByName("Index").SetUint(uint64(logIndex))
+	return v.Interface()
+}
+
+// Watch checks the events file for changes every interval until the context is done, and calls onChange
+// with its events whenever its content changes, starting with its current content.
+// Files which fail to load are logged and skipped until they change again.
+func Watch(ctx context.Context, logger *zap.Logger, path string, interval time.Duration, onChange func([]Event)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var lastHash []byte
+	for {
+		yamlFile, err := os.ReadFile(filepath.Clean(path))
+		if err != nil {
+			logger.Warn("could not read local events file", zap.String("path", path), zap.Error(err))
+		} else if hash := sha256.Sum256(yamlFile); !bytes.Equal(hash[:], lastHash) {
+			lastHash = hash[:]
+			events, err := parse(yamlFile)
+			if err != nil {
+				logger.Error("could not load local events", zap.String("path", path), zap.Error(err))
+			} else {
+				onChange(events)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
 type eventData interface {
 	toEventData() (interface{}, error)
 }
@@ -193,7 +276,13 @@ func (u *eventDataUnmarshaler) UnmarshalYAML(value *yaml.Node) error {
 		err = value.Decode(&v)
 		u.data = &v
 	default:
-		return errors.New("event unknown")
+		typ, ok := contractEvents[u.name]
+		if !ok {
+			return errors.New("event unknown")
+		}
+		v := &contractEventYAML{value: reflect.New(typ).Elem()}
+		err = decodeValue(value, v.value)
+		u.data = v
 	}
 
 	return err
@@ -201,7 +290,9 @@ func (u *eventDataUnmarshaler) UnmarshalYAML(value *yaml.Node) error {
 
 func (e *Event) UnmarshalYAML(value *yaml.Node) error {
 	var evName struct {
-		Name string `yaml:"Name"`
+		Name        string `yaml:"Name"`
+		BlockNumber uint64 `yaml:"BlockNumber"`
+		LogIndex    *uint  `yaml:"LogIndex"`
 	}
 	err := value.Decode(&evName)
 	if err != nil {
@@ -222,6 +313,11 @@ func (e *Event) UnmarshalYAML(value *yaml.Node) error {
 		return errors.New("event data is nil")
 	}
 	e.Name = ev.Data.name
+	e.BlockNumber = evName.BlockNumber
+	if evName.LogIndex != nil {
+		e.LogIndex = *evName.LogIndex
+		e.hasLogIndex = true
+	}
 	data, err := ev.Data.data.toEventData()
 	if err != nil {
 		return err
@@ -1,10 +1,16 @@
 package localevents_test
 
 import (
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/ssvlabs/ssv/eth/contract"
 	"github.com/ssvlabs/ssv/eth/localevents"
+	"github.com/ssvlabs/ssv/logging"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
 )
@@ -83,3 +89,185 @@ func TestUnmarshalYAMLOperatorAddedEvent(t *testing.T) {
 		require.EqualError(t, err, "yaml: unmarshal errors:\n  line 5: cannot unmarshal !!str `id` into uint64")
 	})
 }
+
+func TestUnmarshalYAMLContractEvents(t *testing.T) {
+	t.Run("Successfully unmarshal every contract event", func(t *testing.T) {
+		contractABI, err := contract.ContractMetaData.GetAbi()
+		require.NoError(t, err)
+		for name := range contractABI.Events {
+			input := []byte("- Name: " + name + "\n  Data: {}\n")
+			var parsedData []*localevents.Event
+			require.NoError(t, yaml.Unmarshal(input, &parsedData), name)
+			require.Equal(t, name, parsedData[0].Name)
+		}
+	})
+
+	t.Run("Successfully unmarshal OperatorFeeDeclared event", func(t *testing.T) {
+		input := []byte(`
+- Name: OperatorFeeDeclared
+  Data:
+    Owner: 0x97a6C1f3aaB5427B901fb135ED492749191C0f1F
+    OperatorId: 1
+    BlockNumber: 100
+    Fee: "1000000000000000000000"
+`)
+		var parsedData []*localevents.Event
+		require.NoError(t, yaml.Unmarshal(input, &parsedData))
+		eventData, ok := parsedData[0].Data.(contract.ContractOperatorFeeDeclared)
+		require.True(t, ok)
+		require.Equal(t, "0x97a6C1f3aaB5427B901fb135ED492749191C0f1F", eventData.Owner.String())
+		require.Equal(t, uint64(1), eventData.OperatorId)
+		require.Equal(t, big.NewInt(100), eventData.BlockNumber)
+		require.Equal(t, "1000000000000000000000", eventData.Fee.String())
+	})
+
+	t.Run("Successfully unmarshal ClusterDeposited event", func(t *testing.T) {
+		input := []byte(`
+- Name: ClusterDeposited
+  Data:
+    Owner: 0x97a6C1f3aaB5427B901fb135ED492749191C0f1F
+    OperatorIds: [1, 2, 3, 4]
+    Value: 0x10
+    Cluster:
+      ValidatorCount: 2
+      Active: true
+      Balance: 5
+`)
+		var parsedData []*localevents.Event
+		require.NoError(t, yaml.Unmarshal(input, &parsedData))
+		eventData, ok := parsedData[0].Data.(contract.ContractClusterDeposited)
+		require.True(t, ok)
+		require.Equal(t, []uint64{1, 2, 3, 4}, eventData.OperatorIds)
+		require.Equal(t, big.NewInt(16), eventData.Value)
+		require.Equal(t, uint32(2), eventData.Cluster.ValidatorCount)
+		require.True(t, eventData.Cluster.Active)
+		require.Equal(t, big.NewInt(5), eventData.Cluster.Balance)
+	})
+
+	t.Run("Fail to unmarshal contract event with unknown field", func(t *testing.T) {
+		input := []byte(`
+- Name: OperatorWhitelistUpdated
+  Data:
+    OperatorId: 1
+    Whitelist: 0x97a6C1f3aaB5427B901fb135ED492749191C0f1F
+`)
+		var parsedData []*localevents.Event
+		require.EqualError(t, yaml.Unmarshal(input, &parsedData), `line 5: unknown field "Whitelist"`)
+	})
+
+	t.Run("Fail to unmarshal contract event with invalid address", func(t *testing.T) {
+		input := []byte(`
+- Name: NetworkEarningsWithdrawn
+  Data:
+    Recipient: 0x1234
+`)
+		var parsedData []*localevents.Event
+		require.EqualError(t, yaml.Unmarshal(input, &parsedData), `Recipient: invalid address "0x1234"`)
+	})
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.yaml")
+
+	t.Run("Successfully load events in block order", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(path, []byte(`
+- Name: NetworkFeeUpdated
+  BlockNumber: 20
+  Data:
+    OldFee: 1
+    NewFee: 2
+- Name: OperatorRemoved
+  BlockNumber: 10
+  LogIndex: 3
+  Data:
+    ID: 1
+- Name: OperatorRemoved
+  BlockNumber: 10
+  Data:
+    ID: 2
+- Name: OperatorRemoved
+  BlockNumber: 10
+  LogIndex: 1
+  Data:
+    ID: 3
+`), 0600))
+
+		events, err := localevents.Load(path)
+		require.NoError(t, err)
+		require.Len(t, events, 4)
+
+		type position struct {
+			name        string
+			blockNumber uint64
+			logIndex    uint
+		}
+		var positions []position
+		for _, event := range events {
+			positions = append(positions, position{event.Name, event.BlockNumber, event.LogIndex})
+		}
+		require.Equal(t, []position{
+			{"OperatorRemoved", 10, 1},
+			{"OperatorRemoved", 10, 3},
+			{"OperatorRemoved", 10, 4},
+			{"NetworkFeeUpdated", 20, 0},
+		}, positions)
+
+		eventData, ok := events[2].Data.(contract.ContractOperatorRemoved)
+		require.True(t, ok)
+		require.Equal(t, uint64(2), eventData.OperatorId)
+		require.Equal(t, uint64(10), eventData.Raw.BlockNumber)
+		require.Equal(t, uint(4), eventData.Raw.Index)
+	})
+
+	t.Run("Fail to load events with duplicate log index", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(path, []byte(`
+- Name: OperatorRemoved
+  BlockNumber: 10
+  LogIndex: 1
+  Data:
+    ID: 1
+- Name: OperatorRemoved
+  BlockNumber: 10
+  Data:
+    ID: 2
+- Name: OperatorRemoved
+  BlockNumber: 10
+  LogIndex: 2
+  Data:
+    ID: 3
+`), 0600))
+
+		_, err := localevents.Load(path)
+		require.EqualError(t, err, "duplicate log index 2 in block 10")
+	})
+}
+
+func TestWatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.yaml")
+	writeEvents := func(ids ...string) {
+		var content string
+		for _, id := range ids {
+			content += "- Name: OperatorRemoved\n  Data:\n    ID: " + id + "\n"
+		}
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	}
+	writeEvents("1")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes := make(chan []localevents.Event, 10)
+	go localevents.Watch(ctx, logging.TestLogger(t), path, 10*time.Millisecond, func(events []localevents.Event) {
+		changes <- events
+	})
+
+	require.Len(t, <-changes, 1)
+
+	// Files which fail to load are skipped.
+	writeEvents("id")
+	writeEvents("1", "2")
+	require.Len(t, <-changes, 2)
+
+	time.Sleep(50 * time.Millisecond)
+	require.Empty(t, changes)
+}
@@ -0,0 +1,501 @@
+package backfill
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+
+	eth2apiv1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/libp2p/go-libp2p/core/peer"
+	specqbft "github.com/ssvlabs/ssv-spec/qbft"
+	spectypes "github.com/ssvlabs/ssv-spec/types"
+	"go.uber.org/zap"
+
+	"github.com/ssvlabs/ssv/exporter/convert"
+	"github.com/ssvlabs/ssv/ibft/storage"
+	"github.com/ssvlabs/ssv/logging/fields"
+	"github.com/ssvlabs/ssv/message/signatureverifier"
+	"github.com/ssvlabs/ssv/network/streams"
+	beaconprotocol "github.com/ssvlabs/ssv/protocol/v2/blockchain/beacon"
+	qbftstorage "github.com/ssvlabs/ssv/protocol/v2/qbft/storage"
+	ssvtypes "github.com/ssvlabs/ssv/protocol/v2/types"
+	registrystorage "github.com/ssvlabs/ssv/registry/storage"
+)
+
+// DefaultMaxPeers is the default number of peers each range of missing slots is requested from.
+const DefaultMaxPeers = 8
+
+// MaxRange is the maximum number of slots a single backfill may cover, which is about a day.
+const MaxRange = 7200
+
+// ValidatorStore returns the shares and committees whose operators decided instances are verified against.
+type ValidatorStore interface {
+	Validator(pubKey []byte) (*ssvtypes.SSVShare, bool)
+	Committee(id spectypes.CommitteeID) (*registrystorage.Committee, bool)
+}
+
+// Duties returns the attester and sync committee duties of validators, which committee
+// decided messages don't tell, and is implemented by the beacon node client.
+type Duties interface {
+	AttesterDuties(ctx context.Context, epoch phase0.Epoch, indices []phase0.ValidatorIndex) ([]*eth2apiv1.AttesterDuty, error)
+	SyncCommitteeDuties(ctx context.Context, epoch phase0.Epoch, indices []phase0.ValidatorIndex) ([]*eth2apiv1.SyncCommitteeDuty, error)
+}
+
+// Result is what a backfill filled in.
+type Result struct {
+	// Instances is the number of decided instances saved.
+	Instances int
+	// Participants is the number of slots whose participants were saved.
+	Participants int
+}
+
+// Backfiller fills the gaps in the decided history of identifiers with the decided messages of their peers.
+//
+// The participants of validator duties are saved for all roles. For the committee role, they're saved
+// for each of the committee's validators with attester or sync committee duties, which are fetched
+// from the beacon node, like the committee observer saves them.
+type Backfiller struct {
+	logger     *zap.Logger
+	streams    streams.StreamController
+	peers      func() []peer.ID
+	stores     *storage.QBFTStores
+	validators ValidatorStore
+	duties     Duties
+	network    beaconprotocol.BeaconNetwork
+	verifier   signatureverifier.SignatureVerifier
+	domain     spectypes.DomainType
+	maxPeers   int
+}
+
+// Option configures a Backfiller.
+type Option func(*Backfiller)
+
+// WithMaxPeers sets the number of peers each range of missing slots is requested from.
+func WithMaxPeers(maxPeers int) Option {
+	return func(b *Backfiller) {
+		b.maxPeers = maxPeers
+	}
+}
+
+// NewBackfiller returns a Backfiller which requests the decided messages from the peers returned by peers.
+func NewBackfiller(
+	logger *zap.Logger,
+	streams streams.StreamController,
+	peers func() []peer.ID,
+	stores *storage.QBFTStores,
+	validators ValidatorStore,
+	duties Duties,
+	network beaconprotocol.BeaconNetwork,
+	verifier signatureverifier.SignatureVerifier,
+	domain spectypes.DomainType,
+	opts ...Option,
+) *Backfiller {
+	b := &Backfiller{
+		logger:     logger,
+		streams:    streams,
+		peers:      peers,
+		stores:     stores,
+		validators: validators,
+		duties:     duties,
+		network:    network,
+		verifier:   verifier,
+		domain:     domain,
+		maxPeers:   DefaultMaxPeers,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// BackfillValidator backfills the duties of the given role of a validator. Attester and sync committee duties
+// are decided by the validator's committee, so the committee's instances are backfilled for them,
+// along with the participants of all of the committee's validators.
+func (b *Backfiller) BackfillValidator(ctx context.Context, pubKey []byte, role spectypes.BeaconRole, from, to phase0.Slot) (Result, error) {
+	share, found := b.validators.Validator(pubKey)
+	if !found {
+		return Result{}, fmt.Errorf("validator %x not found", pubKey)
+	}
+	runnerRole := spectypes.MapDutyToRunnerRole(role)
+	if runnerRole == spectypes.RoleUnknown {
+		return Result{}, fmt.Errorf("unknown role %d", role)
+	}
+	dutyExecutorID := share.ValidatorPubKey[:]
+	if runnerRole == spectypes.RoleCommittee {
+		committeeID := share.CommitteeID()
+		dutyExecutorID = committeeID[:]
+	}
+	return b.Backfill(ctx, spectypes.NewMsgID(b.domain, dutyExecutorID, runnerRole), from, to)
+}
+
+// Backfill requests the decided messages of the slots of the given range which are missing
+// for the identifier, and saves the ones which are verified. Slots are missing if their
+// participants weren't saved, or for the committee role, if their instance or the participants
+// of any validator with a duty in the slot weren't saved.
+func (b *Backfiller) Backfill(ctx context.Context, identifier spectypes.MessageID, from, to phase0.Slot) (Result, error) {
+	if from > to {
+		return Result{}, fmt.Errorf("from slot %d is after to slot %d", from, to)
+	}
+	if to-from >= MaxRange {
+		return Result{}, fmt.Errorf("range of %d slots exceeds the maximum of %d", to-from+1, MaxRange)
+	}
+	role := identifier.GetRoleType()
+	store := b.stores.Get(convert.RunnerRole(role))
+	if store == nil {
+		return Result{}, fmt.Errorf("unknown role %d", role)
+	}
+	operators, validators, err := b.committee(identifier)
+	if err != nil {
+		return Result{}, err
+	}
+	isCommittee := role == spectypes.RoleCommittee
+	participantsID := convert.NewMsgID(spectypes.DomainType(identifier.GetDomain()), identifier.GetDutyExecutorID(), convert.RunnerRole(role))
+
+	logger := b.logger.With(fields.MessageID(identifier))
+	var result Result
+	for chunkFrom := from; ; chunkFrom += MaxSlots {
+		chunkTo := to
+		if to-chunkFrom >= MaxSlots {
+			chunkTo = chunkFrom + MaxSlots - 1
+		}
+
+		var duties map[phase0.Slot][]validatorDuty
+		if isCommittee {
+			if duties, err = b.committeeDuties(ctx, validators, chunkFrom, chunkTo); err != nil {
+				return result, err
+			}
+		}
+
+		missing := make(map[phase0.Slot]struct{})
+		for slot := chunkFrom; slot <= chunkTo; slot++ {
+			var filled bool
+			if isCommittee {
+				filled, err = b.committeeSlotFilled(store, identifier, slot, duties[slot])
+			} else {
+				filled, err = participantsSaved(store, participantsID, slot)
+			}
+			if err != nil {
+				return result, err
+			}
+			if !filled {
+				missing[slot] = struct{}{}
+			}
+		}
+
+		for _, peerID := range b.selectPeers() {
+			if len(missing) == 0 {
+				break
+			}
+			if err := ctx.Err(); err != nil {
+				return result, err
+			}
+			decided, err := b.request(logger, peerID, identifier, chunkFrom, chunkTo)
+			if err != nil {
+				logger.Debug("could not request decided history", fields.PeerID(peerID), zap.Error(err))
+				continue
+			}
+			for _, signed := range decided {
+				msg, err := verifyDecided(b.verifier, signed, identifier, operators, chunkFrom, chunkTo)
+				if err != nil {
+					metricsInvalidMessages.Inc()
+					logger.Debug("peer sent an invalid decided message", fields.PeerID(peerID), zap.Error(err))
+					continue
+				}
+				slot := phase0.Slot(msg.Height)
+				if _, ok := missing[slot]; !ok {
+					continue
+				}
+				instanceSaved, err := saveInstance(store, identifier, msg, signed)
+				if err != nil {
+					return result, err
+				}
+				if instanceSaved {
+					result.Instances++
+					metricsInstances.WithLabelValues(role.String()).Inc()
+				}
+				if isCommittee {
+					saved, err := b.saveCommitteeParticipants(duties[slot], slot, sortedSigners(signed))
+					if err != nil {
+						return result, err
+					}
+					if saved {
+						result.Participants++
+					}
+				} else {
+					if err := store.SaveParticipants(participantsID, slot, sortedSigners(signed)); err != nil {
+						return result, fmt.Errorf("could not save participants: %w", err)
+					}
+					result.Participants++
+					metricsParticipants.WithLabelValues(role.String()).Inc()
+				}
+				delete(missing, slot)
+			}
+		}
+
+		if chunkTo == to {
+			break
+		}
+	}
+	return result, nil
+}
+
+// committee returns the operators of the committee which decides the instances of the identifier,
+// and for the committee role, its validators.
+func (b *Backfiller) committee(identifier spectypes.MessageID) ([]spectypes.OperatorID, []*ssvtypes.SSVShare, error) {
+	executorID := identifier.GetDutyExecutorID()
+	if identifier.GetRoleType() == spectypes.RoleCommittee {
+		committeeID := spectypes.CommitteeID(executorID[16:])
+		committee, found := b.validators.Committee(committeeID)
+		if !found {
+			return nil, nil, fmt.Errorf("committee %x not found", committeeID[:])
+		}
+		return committee.Operators, committee.Validators, nil
+	}
+
+	share, found := b.validators.Validator(executorID)
+	if !found {
+		return nil, nil, fmt.Errorf("validator %x not found", executorID)
+	}
+	operators := make([]spectypes.OperatorID, 0, len(share.Committee))
+	for _, member := range share.Committee {
+		operators = append(operators, member.Signer)
+	}
+	return operators, nil, nil
+}
+
+// validatorDuty is an attester or sync committee duty of a committee's validator.
+type validatorDuty struct {
+	pubKey spectypes.ValidatorPK
+	role   convert.RunnerRole
+}
+
+// committeeDuties returns the attester and sync committee duties of the validators in the given range, by slot.
+func (b *Backfiller) committeeDuties(ctx context.Context, validators []*ssvtypes.SSVShare, from, to phase0.Slot) (map[phase0.Slot][]validatorDuty, error) {
+	pubKeys := make(map[phase0.ValidatorIndex]spectypes.ValidatorPK, len(validators))
+	indices := make([]phase0.ValidatorIndex, 0, len(validators))
+	for _, share := range validators {
+		if !share.HasBeaconMetadata() {
+			continue
+		}
+		pubKeys[share.ValidatorIndex] = share.ValidatorPubKey
+		indices = append(indices, share.ValidatorIndex)
+	}
+
+	duties := make(map[phase0.Slot][]validatorDuty)
+	if len(indices) == 0 {
+		return duties, nil
+	}
+	for epoch := b.network.EstimatedEpochAtSlot(from); epoch <= b.network.EstimatedEpochAtSlot(to); epoch++ {
+		attesterDuties, err := b.duties.AttesterDuties(ctx, epoch, indices)
+		if err != nil {
+			return nil, fmt.Errorf("could not get attester duties of epoch %d: %w", epoch, err)
+		}
+		for _, duty := range attesterDuties {
+			pubKey, ok := pubKeys[duty.ValidatorIndex]
+			if !ok || duty.Slot < from || duty.Slot > to {
+				continue
+			}
+			duties[duty.Slot] = append(duties[duty.Slot], validatorDuty{pubKey: pubKey, role: convert.RoleAttester})
+		}
+
+		syncCommitteeDuties, err := b.duties.SyncCommitteeDuties(ctx, epoch, indices)
+		if err != nil {
+			return nil, fmt.Errorf("could not get sync committee duties of epoch %d: %w", epoch, err)
+		}
+		// Sync committee members sign in every slot of the epoch.
+		firstSlot := max(from, b.network.GetEpochFirstSlot(epoch))
+		lastSlot := min(to, b.network.GetEpochFirstSlot(epoch+1)-1)
+		for _, duty := range syncCommitteeDuties {
+			pubKey, ok := pubKeys[duty.ValidatorIndex]
+			if !ok {
+				continue
+			}
+			for slot := firstSlot; slot <= lastSlot; slot++ {
+				duties[slot] = append(duties[slot], validatorDuty{pubKey: pubKey, role: convert.RoleSyncCommittee})
+			}
+		}
+	}
+	return duties, nil
+}
+
+// committeeSlotFilled returns whether the committee's instance of the slot
+// and the participants of its validators with duties in the slot are saved.
+func (b *Backfiller) committeeSlotFilled(store qbftstorage.QBFTStore, identifier spectypes.MessageID, slot phase0.Slot, duties []validatorDuty) (bool, error) {
+	instance, err := store.GetInstance(identifier[:], specqbft.Height(slot))
+	if err != nil {
+		return false, fmt.Errorf("could not get instance: %w", err)
+	}
+	if instance == nil {
+		return false, nil
+	}
+	for _, duty := range duties {
+		dutyStore := b.stores.Get(duty.role)
+		if dutyStore == nil {
+			return false, fmt.Errorf("unknown role %s", duty.role)
+		}
+		saved, err := participantsSaved(dutyStore, convert.NewMsgID(b.domain, duty.pubKey[:], duty.role), slot)
+		if err != nil || !saved {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// saveCommitteeParticipants saves the signers of the committee's decided message as the participants
+// of its validators with duties in the slot, and returns whether there were any.
+func (b *Backfiller) saveCommitteeParticipants(duties []validatorDuty, slot phase0.Slot, signers []spectypes.OperatorID) (bool, error) {
+	for _, duty := range duties {
+		dutyStore := b.stores.Get(duty.role)
+		if dutyStore == nil {
+			return false, fmt.Errorf("unknown role %s", duty.role)
+		}
+		if err := dutyStore.SaveParticipants(convert.NewMsgID(b.domain, duty.pubKey[:], duty.role), slot, signers); err != nil {
+			return false, fmt.Errorf("could not save participants: %w", err)
+		}
+		metricsParticipants.WithLabelValues(duty.role.String()).Inc()
+	}
+	return len(duties) > 0, nil
+}
+
+func participantsSaved(store qbftstorage.QBFTStore, participantsID convert.MessageID, slot phase0.Slot) (bool, error) {
+	participants, err := store.GetParticipants(participantsID, slot)
+	if err != nil {
+		return false, fmt.Errorf("could not get participants: %w", err)
+	}
+	return len(participants) > 0, nil
+}
+
+// selectPeers returns up to maxPeers of the peers, in random order.
+func (b *Backfiller) selectPeers() []peer.ID {
+	peers := slices.Clone(b.peers())
+	rand.Shuffle(len(peers), func(i, j int) {
+		peers[i], peers[j] = peers[j], peers[i]
+	})
+	if len(peers) > b.maxPeers {
+		peers = peers[:b.maxPeers]
+	}
+	return peers
+}
+
+// request returns the decided messages the peer has for the identifier in the given range.
+func (b *Backfiller) request(logger *zap.Logger, peerID peer.ID, identifier spectypes.MessageID, from, to phase0.Slot) ([]*spectypes.SignedSSVMessage, error) {
+	request := &Request{
+		Identifier: identifier[:],
+		From:       from,
+		To:         to,
+	}
+	data, err := request.Encode()
+	if err != nil {
+		return nil, fmt.Errorf("could not encode request: %w", err)
+	}
+	data, err = b.streams.Request(logger, peerID, Protocol, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var response Response
+	if err := response.Decode(data); err != nil {
+		return nil, fmt.Errorf("could not decode response: %w", err)
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	decided := make([]*spectypes.SignedSSVMessage, 0, len(response.Decided))
+	for _, encoded := range response.Decided {
+		signed := &spectypes.SignedSSVMessage{}
+		if err := signed.Decode(encoded); err != nil {
+			return nil, fmt.Errorf("could not decode decided message: %w", err)
+		}
+		decided = append(decided, signed)
+	}
+	return decided, nil
+}
+
+// verifyDecided returns the QBFT message of the given decided message, or an error unless it's a commit
+// of the identifier in the given range, signed by a quorum of the given committee.
+func verifyDecided(
+	verifier signatureverifier.SignatureVerifier,
+	signed *spectypes.SignedSSVMessage,
+	identifier spectypes.MessageID,
+	operators []spectypes.OperatorID,
+	from, to phase0.Slot,
+) (*specqbft.Message, error) {
+	if signed.SSVMessage == nil {
+		return nil, errors.New("nil SSVMessage")
+	}
+	msg, err := specqbft.NewProcessingMessage(signed)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode message: %w", err)
+	}
+	if err := msg.Validate(); err != nil {
+		return nil, err
+	}
+	if signed.SSVMessage.MsgID != identifier || !slices.Equal(msg.QBFTMessage.Identifier, identifier[:]) {
+		return nil, errors.New("wrong identifier")
+	}
+	if msg.QBFTMessage.MsgType != specqbft.CommitMsgType {
+		return nil, errors.New("not a commit message")
+	}
+	if slot := phase0.Slot(msg.QBFTMessage.Height); slot < from || slot > to {
+		return nil, fmt.Errorf("slot %d is out of the requested range", slot)
+	}
+
+	for _, signer := range signed.OperatorIDs {
+		if !slices.Contains(operators, signer) {
+			return nil, fmt.Errorf("signer %d isn't in the committee", signer)
+		}
+	}
+	quorum, _ := ssvtypes.ComputeQuorumAndPartialQuorum(uint64(len(operators)))
+	if uint64(len(signed.OperatorIDs)) < quorum {
+		return nil, fmt.Errorf("%d signers don't reach the quorum of %d", len(signed.OperatorIDs), quorum)
+	}
+
+	root, err := specqbft.HashDataRoot(signed.FullData)
+	if err != nil {
+		return nil, fmt.Errorf("could not hash full data: %w", err)
+	}
+	if root != msg.QBFTMessage.Root {
+		return nil, errors.New("full data doesn't match the root")
+	}
+
+	for i, signer := range signed.OperatorIDs {
+		if err := verifier.VerifySignature(signer, signed.SSVMessage, signed.Signatures[i]); err != nil {
+			return nil, fmt.Errorf("invalid signature of operator %d: %w", signer, err)
+		}
+	}
+	return msg.QBFTMessage, nil
+}
+
+// saveInstance saves the decided instance to the history of the identifier, unless it's already there.
+func saveInstance(store qbftstorage.QBFTStore, identifier spectypes.MessageID, msg *specqbft.Message, signed *spectypes.SignedSSVMessage) (bool, error) {
+	existing, err := store.GetInstance(identifier[:], msg.Height)
+	if err != nil {
+		return false, fmt.Errorf("could not get instance: %w", err)
+	}
+	if existing != nil {
+		return false, nil
+	}
+	err = store.SaveInstance(&qbftstorage.StoredInstance{
+		State: &specqbft.State{
+			ID:           identifier[:],
+			Height:       msg.Height,
+			Round:        msg.Round,
+			Decided:      true,
+			DecidedValue: signed.FullData,
+		},
+		DecidedMessage: signed,
+	})
+	if err != nil {
+		return false, fmt.Errorf("could not save instance: %w", err)
+	}
+	return true, nil
+}
+
+func sortedSigners(signed *spectypes.SignedSSVMessage) []spectypes.OperatorID {
+	signers := slices.Clone(signed.OperatorIDs)
+	slices.Sort(signers)
+	return signers
+}
@@ -0,0 +1,337 @@
+package backfill
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"testing"
+
+	eth2apiv1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/libp2p/go-libp2p/core"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+	specqbft "github.com/ssvlabs/ssv-spec/qbft"
+	spectypes "github.com/ssvlabs/ssv-spec/types"
+	"github.com/ssvlabs/ssv-spec/types/testingutils"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/ssvlabs/ssv/exporter/convert"
+	"github.com/ssvlabs/ssv/ibft/storage"
+	"github.com/ssvlabs/ssv/logging"
+	"github.com/ssvlabs/ssv/network/streams"
+	"github.com/ssvlabs/ssv/networkconfig"
+	beaconprotocol "github.com/ssvlabs/ssv/protocol/v2/blockchain/beacon"
+	qbftstorage "github.com/ssvlabs/ssv/protocol/v2/qbft/storage"
+	ssvtypes "github.com/ssvlabs/ssv/protocol/v2/types"
+	registrystorage "github.com/ssvlabs/ssv/registry/storage"
+	"github.com/ssvlabs/ssv/storage/basedb"
+	"github.com/ssvlabs/ssv/storage/kv"
+)
+
+// testStreams routes requests to the handlers of the peers.
+type testStreams map[peer.ID]func([]byte) ([]byte, error)
+
+func (s testStreams) Request(_ *zap.Logger, peerID peer.ID, _ protocol.ID, msg []byte) ([]byte, error) {
+	return s[peerID](msg)
+}
+
+func (s testStreams) HandleStream(*zap.Logger, core.Stream) ([]byte, streams.StreamResponder, func(), error) {
+	return nil, nil, func() {}, errors.New("not implemented")
+}
+
+type testVerifier map[spectypes.OperatorID]*rsa.PrivateKey
+
+func (v testVerifier) VerifySignature(operatorID spectypes.OperatorID, message *spectypes.SSVMessage, signature []byte) error {
+	encoded, err := message.Encode()
+	if err != nil {
+		return err
+	}
+	hash := sha256.Sum256(encoded)
+	return rsa.VerifyPKCS1v15(&v[operatorID].PublicKey, crypto.SHA256, hash[:], signature)
+}
+
+type testValidators struct {
+	share     *ssvtypes.SSVShare
+	committee *registrystorage.Committee
+}
+
+func (v testValidators) Validator(pubKey []byte) (*ssvtypes.SSVShare, bool) {
+	return v.share, string(pubKey) == string(v.share.ValidatorPubKey[:])
+}
+
+func (v testValidators) Committee(committeeID spectypes.CommitteeID) (*registrystorage.Committee, bool) {
+	return v.committee, v.committee != nil && v.committee.ID == committeeID
+}
+
+type testDuties struct {
+	attester      []*eth2apiv1.AttesterDuty
+	syncCommittee []*eth2apiv1.SyncCommitteeDuty
+}
+
+func (d testDuties) AttesterDuties(context.Context, phase0.Epoch, []phase0.ValidatorIndex) ([]*eth2apiv1.AttesterDuty, error) {
+	return d.attester, nil
+}
+
+func (d testDuties) SyncCommitteeDuties(context.Context, phase0.Epoch, []phase0.ValidatorIndex) ([]*eth2apiv1.SyncCommitteeDuty, error) {
+	return d.syncCommittee, nil
+}
+
+func decided(ks *testingutils.TestKeySet, identifier spectypes.MessageID, slot phase0.Slot, ids ...spectypes.OperatorID) *spectypes.SignedSSVMessage {
+	sks := make([]*rsa.PrivateKey, 0, len(ids))
+	for _, id := range ids {
+		sks = append(sks, ks.OperatorKeys[id])
+	}
+	return testingutils.TestingCommitMultiSignerMessageWithHeightIdentifierAndFullData(sks, ids, specqbft.Height(slot), identifier[:], []byte("data"))
+}
+
+func newStores(t *testing.T, logger *zap.Logger) *storage.QBFTStores {
+	db, err := kv.NewInMemory(logger, basedb.Options{})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	return storage.NewStoresFromRoles(db, convert.RoleProposer, convert.RoleAttester, convert.RoleSyncCommittee)
+}
+
+func TestBackfill(t *testing.T) {
+	logger := logging.TestLogger(t)
+	ks := testingutils.Testing4SharesSet()
+	share := &ssvtypes.SSVShare{Share: *testingutils.TestingShare(ks, testingutils.TestingValidatorIndex)}
+	identifier := spectypes.NewMsgID(testingutils.TestingSSVDomainType, share.ValidatorPubKey[:], spectypes.RoleProposer)
+	participantsID := convert.NewMsgID(testingutils.TestingSSVDomainType, share.ValidatorPubKey[:], convert.RoleProposer)
+
+	// A full node which decided slots 10, 11 and 13.
+	fullNodeStores := newStores(t, logger)
+	for _, slot := range []phase0.Slot{10, 11, 13} {
+		require.NoError(t, fullNodeStores.Get(convert.RoleProposer).SaveInstance(&qbftstorage.StoredInstance{
+			State:          &specqbft.State{ID: identifier[:], Height: specqbft.Height(slot)},
+			DecidedMessage: decided(ks, identifier, slot, 1, 2, 3),
+		}))
+	}
+	server := NewServer(nil, fullNodeStores)
+
+	// A peer which sends decided messages without a quorum.
+	badResponse := &Response{}
+	for _, slot := range []phase0.Slot{10, 12} {
+		encoded, err := decided(ks, identifier, slot, 1, 2).Encode()
+		require.NoError(t, err)
+		badResponse.Decided = append(badResponse.Decided, encoded)
+	}
+
+	peers := testStreams{
+		"full-node": func(data []byte) ([]byte, error) {
+			response, err := server.handle(data)
+			if err != nil {
+				return nil, err
+			}
+			return response.Encode()
+		},
+		"bad":         func([]byte) ([]byte, error) { return badResponse.Encode() },
+		"unreachable": func([]byte) ([]byte, error) { return nil, errors.New("dial failed") },
+	}
+
+	// The exporter only missed slots 10 and 13.
+	stores := newStores(t, logger)
+	require.NoError(t, stores.Get(convert.RoleProposer).SaveParticipants(participantsID, 11, []spectypes.OperatorID{1, 2, 3, 4}))
+
+	backfiller := NewBackfiller(
+		logger,
+		peers,
+		func() []peer.ID { return []peer.ID{"full-node", "bad", "unreachable"} },
+		stores,
+		testValidators{share: share},
+		testDuties{},
+		networkconfig.TestNetwork.Beacon,
+		testVerifier(ks.OperatorKeys),
+		testingutils.TestingSSVDomainType,
+	)
+	result, err := backfiller.BackfillValidator(context.Background(), share.ValidatorPubKey[:], spectypes.BNRoleProposer, 10, 13)
+	require.NoError(t, err)
+	require.Equal(t, Result{Instances: 2, Participants: 2}, result)
+
+	participants, err := stores.Get(convert.RoleProposer).GetParticipantsInRange(participantsID, 10, 13)
+	require.NoError(t, err)
+	require.Len(t, participants, 3)
+	require.Equal(t, phase0.Slot(10), participants[0].Slot)
+	require.Equal(t, []spectypes.OperatorID{1, 2, 3}, participants[0].Signers)
+	require.Empty(t, participants[0].Late)
+	require.Equal(t, []spectypes.OperatorID{1, 2, 3, 4}, participants[1].Signers)
+	require.Equal(t, phase0.Slot(13), participants[2].Slot)
+
+	instance, err := stores.Get(convert.RoleProposer).GetInstance(identifier[:], 13)
+	require.NoError(t, err)
+	require.NotNil(t, instance)
+	require.Equal(t, []spectypes.OperatorID{1, 2, 3}, instance.DecidedMessage.OperatorIDs)
+
+	// Nothing is missing anymore.
+	result, err = backfiller.Backfill(context.Background(), identifier, 10, 13)
+	require.NoError(t, err)
+	require.Equal(t, Result{}, result)
+
+	_, err = backfiller.Backfill(context.Background(), spectypes.NewMsgID(testingutils.TestingSSVDomainType, []byte("unknown"), spectypes.RoleProposer), 10, 13)
+	require.ErrorContains(t, err, "not found")
+	_, err = backfiller.Backfill(context.Background(), identifier, 10, 10+MaxRange)
+	require.ErrorContains(t, err, "exceeds the maximum")
+}
+
+func TestBackfillCommittee(t *testing.T) {
+	logger := logging.TestLogger(t)
+	ks := testingutils.Testing4SharesSet()
+	attester := &ssvtypes.SSVShare{
+		Share:    *testingutils.TestingShare(ks, 1),
+		Metadata: ssvtypes.Metadata{BeaconMetadata: &beaconprotocol.ValidatorMetadata{Index: 1}},
+	}
+	syncCommittee := &ssvtypes.SSVShare{
+		Share:    *testingutils.TestingShare(ks, 2),
+		Metadata: ssvtypes.Metadata{BeaconMetadata: &beaconprotocol.ValidatorMetadata{Index: 2}},
+	}
+	syncCommittee.ValidatorPubKey[0] ^= 0xff
+	committee := &registrystorage.Committee{
+		ID:         attester.CommitteeID(),
+		Operators:  []spectypes.OperatorID{1, 2, 3, 4},
+		Validators: []*ssvtypes.SSVShare{attester, syncCommittee},
+	}
+	identifier := spectypes.NewMsgID(testingutils.TestingSSVDomainType, committee.ID[:], spectypes.RoleCommittee)
+
+	// A full node which decided slots 10, 11 and 13.
+	fullNodeStores := newStores(t, logger)
+	for _, slot := range []phase0.Slot{10, 11, 13} {
+		require.NoError(t, fullNodeStores.Get(convert.RunnerRole(spectypes.RoleCommittee)).SaveInstance(&qbftstorage.StoredInstance{
+			State:          &specqbft.State{ID: identifier[:], Height: specqbft.Height(slot)},
+			DecidedMessage: decided(ks, identifier, slot, 1, 2, 3),
+		}))
+	}
+	server := NewServer(nil, fullNodeStores)
+	peers := testStreams{
+		"full-node": func(data []byte) ([]byte, error) {
+			response, err := server.handle(data)
+			if err != nil {
+				return nil, err
+			}
+			return response.Encode()
+		},
+	}
+
+	// The first validator attests in slot 10, and the second is in the sync committee of the epoch.
+	duties := testDuties{
+		attester:      []*eth2apiv1.AttesterDuty{{ValidatorIndex: 1, Slot: 10}, {ValidatorIndex: 1, Slot: 20}},
+		syncCommittee: []*eth2apiv1.SyncCommitteeDuty{{ValidatorIndex: 2}},
+	}
+	stores := newStores(t, logger)
+	backfiller := NewBackfiller(
+		logger,
+		peers,
+		func() []peer.ID { return []peer.ID{"full-node"} },
+		stores,
+		testValidators{committee: committee},
+		duties,
+		networkconfig.TestNetwork.Beacon,
+		testVerifier(ks.OperatorKeys),
+		testingutils.TestingSSVDomainType,
+	)
+	result, err := backfiller.Backfill(context.Background(), identifier, 10, 13)
+	require.NoError(t, err)
+	require.Equal(t, Result{Instances: 3, Participants: 3}, result)
+
+	attesterID := convert.NewMsgID(testingutils.TestingSSVDomainType, attester.ValidatorPubKey[:], convert.RoleAttester)
+	participants, err := stores.Get(convert.RoleAttester).GetParticipantsInRange(attesterID, 10, 13)
+	require.NoError(t, err)
+	require.Len(t, participants, 1)
+	require.Equal(t, phase0.Slot(10), participants[0].Slot)
+	require.Equal(t, []spectypes.OperatorID{1, 2, 3}, participants[0].Signers)
+
+	syncCommitteeID := convert.NewMsgID(testingutils.TestingSSVDomainType, syncCommittee.ValidatorPubKey[:], convert.RoleSyncCommittee)
+	participants, err = stores.Get(convert.RoleSyncCommittee).GetParticipantsInRange(syncCommitteeID, 10, 13)
+	require.NoError(t, err)
+	require.Len(t, participants, 3)
+	require.Equal(t, phase0.Slot(13), participants[2].Slot)
+
+	// Only slot 12, which no peer decided, is still missing.
+	result, err = backfiller.Backfill(context.Background(), identifier, 10, 13)
+	require.NoError(t, err)
+	require.Equal(t, Result{}, result)
+}
+
+func TestVerifyDecided(t *testing.T) {
+	ks := testingutils.Testing4SharesSet()
+	identifier := spectypes.NewMsgID(testingutils.TestingSSVDomainType, []byte("pk"), spectypes.RoleProposer)
+	operators := []spectypes.OperatorID{1, 2, 3, 4}
+	verifier := testVerifier(ks.OperatorKeys)
+
+	tests := []struct {
+		name    string
+		msg     func() *spectypes.SignedSSVMessage
+		wantErr string
+	}{
+		{
+			name: "valid",
+			msg:  func() *spectypes.SignedSSVMessage { return decided(ks, identifier, 10, 1, 2, 3) },
+		},
+		{
+			name:    "out of range",
+			msg:     func() *spectypes.SignedSSVMessage { return decided(ks, identifier, 20, 1, 2, 3) },
+			wantErr: "out of the requested range",
+		},
+		{
+			name: "wrong identifier",
+			msg: func() *spectypes.SignedSSVMessage {
+				other := spectypes.NewMsgID(testingutils.TestingSSVDomainType, []byte("other"), spectypes.RoleProposer)
+				return decided(ks, other, 10, 1, 2, 3)
+			},
+			wantErr: "wrong identifier",
+		},
+		{
+			name:    "no quorum",
+			msg:     func() *spectypes.SignedSSVMessage { return decided(ks, identifier, 10, 1, 2) },
+			wantErr: "don't reach the quorum",
+		},
+		{
+			name: "signer outside the committee",
+			msg: func() *spectypes.SignedSSVMessage {
+				msg := decided(ks, identifier, 10, 1, 2, 3)
+				msg.OperatorIDs[2] = 5
+				return msg
+			},
+			wantErr: "isn't in the committee",
+		},
+		{
+			name: "tampered full data",
+			msg: func() *spectypes.SignedSSVMessage {
+				msg := decided(ks, identifier, 10, 1, 2, 3)
+				msg.FullData = []byte("other data")
+				return msg
+			},
+			wantErr: "doesn't match the root",
+		},
+		{
+			name: "invalid signature",
+			msg: func() *spectypes.SignedSSVMessage {
+				msg := decided(ks, identifier, 10, 1, 2, 3)
+				msg.Signatures[0], msg.Signatures[1] = msg.Signatures[1], msg.Signatures[0]
+				return msg
+			},
+			wantErr: "invalid signature of operator 1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := verifyDecided(verifier, tt.msg(), identifier, operators, 0, 15)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, specqbft.Height(10), msg.Height)
+		})
+	}
+}
+
+func TestRequestValidate(t *testing.T) {
+	identifier := make([]byte, len(spectypes.MessageID{}))
+	require.NoError(t, (&Request{Identifier: identifier, From: 10, To: 10 + MaxSlots - 1}).Validate())
+	require.ErrorContains(t, (&Request{Identifier: identifier, From: 10, To: 10 + MaxSlots}).Validate(), "exceeds the maximum")
+	require.ErrorContains(t, (&Request{Identifier: identifier, From: 11, To: 10}).Validate(), "is after")
+	require.ErrorContains(t, (&Request{Identifier: identifier[1:], From: 10, To: 10}).Validate(), "invalid identifier length")
+}
@@ -0,0 +1,36 @@
+package backfill
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+var (
+	metricsInstances = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssv_backfill_instances",
+		Help: "Count of decided instances backfilled from peers",
+	}, []string{"role"})
+	metricsParticipants = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssv_backfill_participants",
+		Help: "Count of slots whose participants were backfilled from peers",
+	}, []string{"role"})
+	metricsInvalidMessages = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "ssv_backfill_invalid_messages",
+		Help: "Count of decided messages from peers which failed verification",
+	})
+)
+
+func init() {
+	allMetrics := []prometheus.Collector{
+		metricsInstances,
+		metricsParticipants,
+		metricsInvalidMessages,
+	}
+	logger := zap.L()
+	for _, c := range allMetrics {
+		if err := prometheus.Register(c); err != nil {
+			logger.Debug("could not register prometheus collector")
+		}
+	}
+}
@@ -0,0 +1,67 @@
+// Package backfill recovers the decided instances and participants a node missed while it was offline,
+// by requesting the decided messages of the missing slots from its peers and verifying them
+// against the operator keys of their committee.
+package backfill
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/libp2p/go-libp2p/core/protocol"
+	spectypes "github.com/ssvlabs/ssv-spec/types"
+)
+
+// Protocol is the stream protocol through which decided messages are requested from peers.
+const Protocol protocol.ID = "/ssv/decided-history/0.0.1"
+
+// MaxSlots is the maximum number of slots a single request may cover.
+const MaxSlots = 64
+
+// Request asks a peer for the decided messages of an identifier in a slot range, inclusive.
+type Request struct {
+	Identifier []byte      `json:"identifier"`
+	From       phase0.Slot `json:"from"`
+	To         phase0.Slot `json:"to"`
+}
+
+// Validate returns an error if the request can't be served.
+func (r *Request) Validate() error {
+	if len(r.Identifier) != len(spectypes.MessageID{}) {
+		return fmt.Errorf("invalid identifier length %d", len(r.Identifier))
+	}
+	if r.From > r.To {
+		return fmt.Errorf("from slot %d is after to slot %d", r.From, r.To)
+	}
+	if r.To-r.From >= MaxSlots {
+		return fmt.Errorf("range of %d slots exceeds the maximum of %d", r.To-r.From+1, MaxSlots)
+	}
+	return nil
+}
+
+// Encode returns the encoded request.
+func (r *Request) Encode() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+// Decode decodes the request from the given data.
+func (r *Request) Decode(data []byte) error {
+	return json.Unmarshal(data, r)
+}
+
+// Response holds the encoded decided messages a peer has in the requested range,
+// or the reason it couldn't serve the request.
+type Response struct {
+	Decided [][]byte `json:"decided,omitempty"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// Encode returns the encoded response.
+func (r *Response) Encode() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+// Decode decodes the response from the given data.
+func (r *Response) Decode(data []byte) error {
+	return json.Unmarshal(data, r)
+}
@@ -0,0 +1,110 @@
+package backfill
+
+import (
+	"fmt"
+	"time"
+
+	libp2pnetwork "github.com/libp2p/go-libp2p/core/network"
+	leakybucket "github.com/prysmaticlabs/prysm/v4/container/leaky-bucket"
+	specqbft "github.com/ssvlabs/ssv-spec/qbft"
+	spectypes "github.com/ssvlabs/ssv-spec/types"
+	"go.uber.org/zap"
+
+	"github.com/ssvlabs/ssv/exporter/convert"
+	"github.com/ssvlabs/ssv/ibft/storage"
+	"github.com/ssvlabs/ssv/logging/fields"
+	"github.com/ssvlabs/ssv/network/streams"
+)
+
+const (
+	// requestLimitRate is the rate of requests per second a peer may make,
+	// and requestLimitBurst lets a peer request a whole backfill at once.
+	requestLimitRate   = 2
+	requestLimitBurst  = MaxRange/MaxSlots + 1
+	requestLimitPeriod = time.Second
+)
+
+// Server serves the decided messages of the historical instances this node stored.
+// Only full nodes store historical instances, so other nodes respond with no messages.
+type Server struct {
+	streams streams.StreamController
+	stores  *storage.QBFTStores
+	limiter *leakybucket.Collector
+}
+
+// NewServer returns a Server of the given stores.
+func NewServer(streams streams.StreamController, stores *storage.QBFTStores) *Server {
+	return &Server{
+		streams: streams,
+		stores:  stores,
+		limiter: leakybucket.NewCollector(requestLimitRate, requestLimitBurst, requestLimitPeriod, true),
+	}
+}
+
+// Handler returns the handler of Protocol streams.
+func (s *Server) Handler(logger *zap.Logger) libp2pnetwork.StreamHandler {
+	return func(stream libp2pnetwork.Stream) {
+		peerID := stream.Conn().RemotePeer()
+		logger := logger.With(fields.PeerID(peerID))
+		data, respond, done, err := s.streams.HandleStream(logger, stream)
+		defer done()
+		if err != nil {
+			logger.Debug("could not read decided history request", zap.Error(err))
+			return
+		}
+		var response *Response
+		if s.limiter.Remaining(peerID.String()) <= 0 {
+			logger.Debug("rate limited decided history request")
+			response = &Response{Error: "too many requests"}
+		} else {
+			s.limiter.Add(peerID.String(), 1)
+			response, err = s.handle(data)
+		}
+		if err != nil {
+			logger.Debug("could not serve decided history request", zap.Error(err))
+			response = &Response{Error: err.Error()}
+		}
+		encoded, err := response.Encode()
+		if err != nil {
+			logger.Debug("could not encode decided history response", zap.Error(err))
+			return
+		}
+		if err := respond(encoded); err != nil {
+			logger.Debug("could not respond to decided history request", zap.Error(err))
+		}
+	}
+}
+
+// handle returns the response to the given encoded request.
+func (s *Server) handle(data []byte) (*Response, error) {
+	var request Request
+	if err := request.Decode(data); err != nil {
+		return nil, fmt.Errorf("could not decode request: %w", err)
+	}
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+
+	identifier := spectypes.MessageID(request.Identifier)
+	store := s.stores.Get(convert.RunnerRole(identifier.GetRoleType()))
+	if store == nil {
+		return nil, fmt.Errorf("unknown role %d", identifier.GetRoleType())
+	}
+	instances, err := store.GetInstancesInRange(request.Identifier, specqbft.Height(request.From), specqbft.Height(request.To))
+	if err != nil {
+		return nil, fmt.Errorf("could not get instances: %w", err)
+	}
+
+	response := &Response{}
+	for _, instance := range instances {
+		if instance.DecidedMessage == nil {
+			continue
+		}
+		encoded, err := instance.DecidedMessage.Encode()
+		if err != nil {
+			return nil, fmt.Errorf("could not encode decided message: %w", err)
+		}
+		response.Decided = append(response.Decided, encoded)
+	}
+	return response, nil
+}
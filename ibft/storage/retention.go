@@ -0,0 +1,172 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"go.uber.org/zap"
+
+	"github.com/ssvlabs/ssv/exporter/convert"
+	"github.com/ssvlabs/ssv/logging/fields"
+	"github.com/ssvlabs/ssv/protocol/v2/blockchain/beacon"
+	qbftstorage "github.com/ssvlabs/ssv/protocol/v2/qbft/storage"
+	"github.com/ssvlabs/ssv/storage/basedb"
+)
+
+// Roles are the roles whose decided instances and participants are stored.
+var Roles = []convert.RunnerRole{
+	convert.RoleCommittee,
+	convert.RoleAttester,
+	convert.RoleProposer,
+	convert.RoleSyncCommittee,
+	convert.RoleAggregator,
+	convert.RoleSyncCommitteeContribution,
+	convert.RoleValidatorRegistration,
+	convert.RoleVoluntaryExit,
+}
+
+// RetentionConfig holds the configuration of how long decided instances and participants are kept.
+type RetentionConfig struct {
+	Window   string            `yaml:"Window" env:"QBFT_RETENTION_WINDOW" env-description:"How long decided instances and participants of every role are kept, as a duration (e.g. 720h) or a number of slots. Empty keeps them forever"`
+	Roles    map[string]string `yaml:"Roles" env:"QBFT_RETENTION_ROLES" env-description:"Retention windows of specific roles, overriding Window, e.g. PROPOSER:2160h"`
+	Interval time.Duration     `yaml:"Interval" env:"QBFT_RETENTION_INTERVAL" env-default:"1h" env-description:"Interval between pruning cycles. Set to 0 to disable."`
+}
+
+// Retention is the number of recent slots whose decided instances and participants are kept, by role.
+// Roles without a window are kept forever.
+type Retention map[convert.RunnerRole]phase0.Slot
+
+// ParseRetention parses the retention windows of the configuration into slots of the given duration.
+func ParseRetention(cfg RetentionConfig, slotDuration time.Duration) (Retention, error) {
+	retention := make(Retention)
+	if cfg.Window != "" {
+		window, err := parseWindow(cfg.Window, slotDuration)
+		if err != nil {
+			return nil, err
+		}
+		for _, role := range Roles {
+			retention[role] = window
+		}
+	}
+
+	for name, value := range cfg.Roles {
+		role, ok := parseRole(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown role %q", name)
+		}
+		if value == "" {
+			delete(retention, role)
+			continue
+		}
+		window, err := parseWindow(value, slotDuration)
+		if err != nil {
+			return nil, fmt.Errorf("invalid window of role %s: %w", name, err)
+		}
+		retention[role] = window
+	}
+	return retention, nil
+}
+
+// parseWindow parses either a number of slots or a duration, which is rounded up to whole slots.
+func parseWindow(value string, slotDuration time.Duration) (phase0.Slot, error) {
+	var window phase0.Slot
+	if slots, err := strconv.ParseUint(value, 10, 64); err == nil {
+		window = phase0.Slot(slots)
+	} else {
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, fmt.Errorf("invalid window %q, expected a duration or a number of slots", value)
+		}
+		window = phase0.Slot((duration + slotDuration - 1) / slotDuration)
+	}
+	if window == 0 {
+		return 0, fmt.Errorf("window %q must be positive", value)
+	}
+	return window, nil
+}
+
+func parseRole(name string) (convert.RunnerRole, bool) {
+	for _, role := range Roles {
+		if role.String() == name {
+			return role, true
+		}
+	}
+	return 0, false
+}
+
+// Pruner periodically removes the decided instances and participants which are out of their retention window.
+type Pruner struct {
+	logger    *zap.Logger
+	stores    *QBFTStores
+	retention Retention
+	network   beacon.BeaconNetwork
+	gc        basedb.GarbageCollector
+}
+
+// NewPruner returns a Pruner of the given stores. If gc isn't nil, it runs a quick garbage collection
+// cycle after removing anything, to reclaim the disk space.
+func NewPruner(logger *zap.Logger, stores *QBFTStores, retention Retention, network beacon.BeaconNetwork, gc basedb.GarbageCollector) *Pruner {
+	return &Pruner{
+		logger:    logger,
+		stores:    stores,
+		retention: retention,
+		network:   network,
+		gc:        gc,
+	}
+}
+
+// Prune removes what is out of the retention window of each role at the current slot,
+// and returns the number of removed entries.
+func (p *Pruner) Prune() (int, error) {
+	currentSlot := p.network.EstimatedCurrentSlot()
+	removed := 0
+	err := p.stores.Each(func(role convert.RunnerRole, store qbftstorage.QBFTStore) error {
+		window, ok := p.retention[role]
+		if !ok || currentSlot < window {
+			return nil
+		}
+		n, err := store.Prune(currentSlot - window)
+		if err != nil {
+			return fmt.Errorf("could not prune %s: %w", role, err)
+		}
+		removed += n
+		return nil
+	})
+	return removed, err
+}
+
+// Run prunes the stores at the given interval until the context is done. A zero interval disables pruning.
+func (p *Pruner) Run(ctx context.Context, interval time.Duration) {
+	if len(p.retention) == 0 {
+		return
+	}
+	if interval <= 0 {
+		p.logger.Warn("pruning of decided instances is disabled by a zero interval, the retention windows aren't applied")
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		start := time.Now()
+		removed, err := p.Prune()
+		if err != nil {
+			p.logger.Error("could not prune decided instances", zap.Error(err))
+		} else if removed > 0 {
+			if p.gc != nil {
+				if err := p.gc.QuickGC(ctx); err != nil {
+					p.logger.Error("could not collect garbage after pruning", zap.Error(err))
+				}
+			}
+			p.logger.Debug("pruned decided instances", zap.Int("removed", removed), fields.Duration(start))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"crypto/rsa"
+	"testing"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	specqbft "github.com/ssvlabs/ssv-spec/qbft"
+	spectypes "github.com/ssvlabs/ssv-spec/types"
+	"github.com/ssvlabs/ssv-spec/types/testingutils"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ssvlabs/ssv/exporter/convert"
+	"github.com/ssvlabs/ssv/logging"
+	"github.com/ssvlabs/ssv/networkconfig"
+	qbftstorage "github.com/ssvlabs/ssv/protocol/v2/qbft/storage"
+	"github.com/ssvlabs/ssv/storage/basedb"
+	"github.com/ssvlabs/ssv/storage/kv"
+)
+
+func TestParseRetention(t *testing.T) {
+	retention, err := ParseRetention(RetentionConfig{
+		Window: "100",
+		Roles:  map[string]string{"PROPOSER": "1h", "COMMITTEE": ""},
+	}, 12*time.Second)
+	require.NoError(t, err)
+	require.Len(t, retention, len(Roles)-1)
+	require.Equal(t, phase0.Slot(100), retention[convert.RoleAttester])
+	require.Equal(t, phase0.Slot(300), retention[convert.RoleProposer])
+	require.NotContains(t, retention, convert.RoleCommittee)
+
+	retention, err = ParseRetention(RetentionConfig{Roles: map[string]string{"AGGREGATOR": "30s"}}, 12*time.Second)
+	require.NoError(t, err)
+	require.Equal(t, Retention{convert.RoleAggregator: 3}, retention)
+
+	_, err = ParseRetention(RetentionConfig{Window: "0"}, 12*time.Second)
+	require.ErrorContains(t, err, "must be positive")
+	_, err = ParseRetention(RetentionConfig{Window: "a week"}, 12*time.Second)
+	require.ErrorContains(t, err, "invalid window")
+	_, err = ParseRetention(RetentionConfig{Roles: map[string]string{"ATTESTATION": "1"}}, 12*time.Second)
+	require.ErrorContains(t, err, "unknown role")
+}
+
+func TestPruner(t *testing.T) {
+	logger := logging.TestLogger(t)
+	db, err := kv.NewInMemory(logger, basedb.Options{})
+	require.NoError(t, err)
+	defer db.Close()
+
+	network := networkconfig.TestNetwork
+	currentSlot := network.Beacon.EstimatedCurrentSlot()
+	ks := testingutils.Testing4SharesSet()
+
+	// SYNC_COMMITTEE is a prefix of SYNC_COMMITTEE_CONTRIBUTION, whose entries must be kept.
+	roles := []convert.RunnerRole{convert.RoleSyncCommittee, convert.RoleSyncCommitteeContribution}
+	stores := NewStoresFromRoles(db, roles...)
+	for _, role := range roles {
+		msgID := convert.NewMsgID(network.DomainType(), []byte("pk"), role)
+		for _, slot := range []phase0.Slot{currentSlot - 10, currentSlot - 1} {
+			require.NoError(t, stores.Get(role).SaveParticipants(msgID, slot, []spectypes.OperatorID{1, 2, 3}))
+			require.NoError(t, stores.Get(role).SaveHighestAndHistoricalInstance(&qbftstorage.StoredInstance{
+				State: &specqbft.State{
+					ID:     msgID[:],
+					Height: specqbft.Height(slot),
+				},
+				DecidedMessage: testingutils.TestingCommitMultiSignerMessageWithHeightAndIdentifier(
+					[]*rsa.PrivateKey{ks.OperatorKeys[1], ks.OperatorKeys[2], ks.OperatorKeys[3]},
+					[]spectypes.OperatorID{1, 2, 3},
+					specqbft.Height(slot),
+					msgID[:],
+				),
+			}))
+		}
+	}
+
+	pruner := NewPruner(logger, stores, Retention{convert.RoleSyncCommittee: 5}, network.Beacon, db)
+	removed, err := pruner.Prune()
+	require.NoError(t, err)
+	require.Equal(t, 2, removed)
+
+	for _, role := range roles {
+		msgID := convert.NewMsgID(network.DomainType(), []byte("pk"), role)
+		participants, err := stores.Get(role).GetParticipantsInRange(msgID, currentSlot-10, currentSlot)
+		require.NoError(t, err)
+		instances, err := stores.Get(role).GetInstancesInRange(msgID[:], specqbft.Height(currentSlot-10), specqbft.Height(currentSlot))
+		require.NoError(t, err)
+		highest, err := stores.Get(role).GetHighestInstance(msgID[:])
+		require.NoError(t, err)
+		require.Equal(t, specqbft.Height(currentSlot-1), highest.State.Height)
+
+		if role == convert.RoleSyncCommittee {
+			require.Len(t, participants, 1)
+			require.Len(t, instances, 1)
+			require.Equal(t, currentSlot-1, participants[0].Slot)
+		} else {
+			require.Len(t, participants, 2)
+			require.Len(t, instances, 2)
+		}
+	}
+
+	removed, err = pruner.Prune()
+	require.NoError(t, err)
+	require.Zero(t, removed)
+}
@@ -1,8 +1,11 @@
 package storage
 
 import (
+	"bytes"
 	"encoding/binary"
 	"fmt"
+	"slices"
+
 	"github.com/attestantio/go-eth2-client/spec/phase0"
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
@@ -21,6 +24,16 @@ const (
 	highestInstanceKey = "highest_instance"
 	instanceKey        = "instance"
 	participantsKey    = "participants"
+	// lateParticipantsKey holds the participants which signed after the quorum was reached.
+	lateParticipantsKey = "late_participants"
+	// slotIndexKey prefixes the index of the historical instances and participants by slot. Its keys are made
+	// of the big endian slot, the key ID and the identifier, so that pruning reads them in order up to its cutoff.
+	slotIndexKey = "slot_index/"
+
+	// identifierSize is the size of the message IDs which instances and participants are stored by.
+	identifierSize = len(convert.MessageID{})
+	// pruneBatchSize is the number of keys removed per transaction when pruning.
+	pruneBatchSize = 1000
 )
 
 var (
@@ -96,7 +109,13 @@ func (i *ibftStorage) saveInstance(inst *qbftstorage.StoredInstance, toHistory,
 	}
 
 	if toHistory {
-		err = i.save(value, instanceKey, inst.State.ID, uInt64ToByteSlice(uint64(inst.State.Height)))
+		err = i.db.Update(func(txn basedb.Txn) error {
+			prefix := append(i.prefix, inst.State.ID...)
+			if err := txn.Set(prefix, i.key(instanceKey, uInt64ToByteSlice(uint64(inst.State.Height))), value); err != nil {
+				return err
+			}
+			return i.indexSlot(txn, instanceKey, inst.State.ID, phase0.Slot(inst.State.Height))
+		})
 		if err != nil {
 			return errors.Wrap(err, "could not save historical instance")
 		}
@@ -154,23 +173,178 @@ func (i *ibftStorage) CleanAllInstances(logger *zap.Logger, msgID []byte) error
 	return nil
 }
 
-func (i *ibftStorage) SaveParticipants(identifier convert.MessageID, slot phase0.Slot, operators []spectypes.OperatorID) error {
-	bytes, err := encodeOperators(operators)
+// Prune removes the historical instances and participants of slots below the given one,
+// and returns the number of removed entries. Highest instances are kept.
+// Instance heights are their slots since the Alan fork.
+func (i *ibftStorage) Prune(below phase0.Slot) (int, error) {
+	var keys [][]byte
+	err := i.db.GetKeys(i.slotIndexPrefix(), nil, slotKey(below), func(key []byte) error {
+		keys = append(keys, key)
+		return nil
+	})
 	if err != nil {
-		return err
+		return 0, fmt.Errorf("could not iterate slot index: %w", err)
+	}
+
+	for start := 0; start < len(keys); start += pruneBatchSize {
+		batch := keys[start:min(start+pruneBatchSize, len(keys))]
+		err := i.db.Update(func(txn basedb.Txn) error {
+			for _, key := range batch {
+				slot, id, identifier, ok := parseSlotIndexKey(key)
+				if !ok {
+					return fmt.Errorf("invalid slot index key %x", key)
+				}
+				if err := txn.Delete(append(i.prefix, identifier...), i.key(id, uInt64ToByteSlice(uint64(slot)))); err != nil {
+					return err
+				}
+				if err := txn.Delete(i.slotIndexPrefix(), key); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return start, fmt.Errorf("could not delete instances: %w", err)
+		}
 	}
-	if err := i.save(bytes, participantsKey, identifier[:], uInt64ToByteSlice(uint64(slot))); err != nil {
+	return len(keys), nil
+}
+
+func (i *ibftStorage) IndexSlots() (int, error) {
+	type entry struct {
+		id         string
+		identifier []byte
+		slot       phase0.Slot
+	}
+	var entries []entry
+	err := i.db.GetKeys(i.prefix, nil, nil, func(key []byte) error {
+		if id, slot, ok := historicalSlot(key); ok {
+			entries = append(entries, entry{id: id, identifier: key[:identifierSize], slot: slot})
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("could not iterate instances: %w", err)
+	}
+
+	for start := 0; start < len(entries); start += pruneBatchSize {
+		batch := entries[start:min(start+pruneBatchSize, len(entries))]
+		err := i.db.Update(func(txn basedb.Txn) error {
+			for _, e := range batch {
+				if err := i.indexSlot(txn, e.id, e.identifier, e.slot); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return start, fmt.Errorf("could not index instances: %w", err)
+		}
+	}
+	return len(entries), nil
+}
+
+// historicalSlot returns the key ID and the slot of a historical instance or participants key, which is made of
+// the identifier, the key ID and the height or slot. Stores of roles whose name prefixes another role's
+// name (such as SYNC_COMMITTEE) also iterate over the other role's keys, which are told apart by their length.
+func historicalSlot(key []byte) (string, phase0.Slot, bool) {
+	if len(key) < identifierSize {
+		return "", 0, false
+	}
+	rest := key[identifierSize:]
+	for _, id := range historicalKeys {
+		if len(rest) == len(id)+8 && string(rest[:len(id)]) == id {
+			return id, phase0.Slot(binary.LittleEndian.Uint64(rest[len(id):])), true
+		}
+	}
+	return "", 0, false
+}
+
+// historicalKeys are the IDs of the keys which are indexed by slot.
+var historicalKeys = []string{instanceKey, participantsKey, lateParticipantsKey}
+
+func (i *ibftStorage) slotIndexPrefix() []byte {
+	return slices.Concat(i.prefix, []byte(slotIndexKey))
+}
+
+func (i *ibftStorage) indexSlot(rw basedb.ReadWriter, id string, identifier []byte, slot phase0.Slot) error {
+	return rw.Set(i.slotIndexPrefix(), slices.Concat(slotKey(slot), []byte(id), identifier), []byte{})
+}
+
+func parseSlotIndexKey(key []byte) (phase0.Slot, string, []byte, bool) {
+	if len(key) < 8 {
+		return 0, "", nil, false
+	}
+	rest := key[8:]
+	for _, id := range historicalKeys {
+		if bytes.HasPrefix(rest, []byte(id)) {
+			return phase0.Slot(binary.BigEndian.Uint64(key)), id, rest[len(id):], true
+		}
+	}
+	return 0, "", nil, false
+}
+
+func slotKey(slot phase0.Slot) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, uint64(slot))
+	return b
+}
+
+// SaveParticipants saves the participants of the given slot, replacing the previously saved ones.
+func (i *ibftStorage) SaveParticipants(identifier convert.MessageID, slot phase0.Slot, operators []spectypes.OperatorID) error {
+	txn := i.db.Begin()
+	defer txn.Discard()
+
+	if err := i.saveParticipants(txn, participantsKey, identifier, slot, operators); err != nil {
 		return fmt.Errorf("could not save participants: %w", err)
 	}
+	return txn.Commit()
+}
 
-	return nil
+// MergeParticipants saves the participants of the given slot, adding to the previously saved ones.
+// Participants added after the first save signed after the quorum was reached, so they're also saved as late.
+func (i *ibftStorage) MergeParticipants(identifier convert.MessageID, slot phase0.Slot, operators []spectypes.OperatorID) error {
+	txn := i.db.Begin()
+	defer txn.Discard()
+
+	existing, found, err := i.getParticipants(txn, participantsKey, identifier, slot)
+	if err != nil {
+		return fmt.Errorf("could not get participants: %w", err)
+	}
+	merged := operators
+	if found {
+		late, _, err := i.getParticipants(txn, lateParticipantsKey, identifier, slot)
+		if err != nil {
+			return fmt.Errorf("could not get late participants: %w", err)
+		}
+		var added []spectypes.OperatorID
+		for _, operator := range operators {
+			if !slices.Contains(existing, operator) {
+				added = append(added, operator)
+			}
+		}
+		if len(added) == 0 {
+			return nil
+		}
+		merged = sortedUnion(existing, added)
+		if err := i.saveParticipants(txn, lateParticipantsKey, identifier, slot, sortedUnion(late, added)); err != nil {
+			return fmt.Errorf("could not save late participants: %w", err)
+		}
+	}
+	if err := i.saveParticipants(txn, participantsKey, identifier, slot, merged); err != nil {
+		return fmt.Errorf("could not save participants: %w", err)
+	}
+	return txn.Commit()
 }
 
 func (i *ibftStorage) GetParticipantsInRange(identifier convert.MessageID, from, to phase0.Slot) ([]qbftstorage.ParticipantsRangeEntry, error) {
+	txn := i.db.BeginRead()
+	defer txn.Discard()
+
 	participantsRange := make([]qbftstorage.ParticipantsRangeEntry, 0)
 
 	for slot := from; slot <= to; slot++ {
-		participants, err := i.GetParticipants(identifier, slot)
+		participants, _, err := i.getParticipants(txn, participantsKey, identifier, slot)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get participants: %w", err)
 		}
@@ -179,27 +353,56 @@ func (i *ibftStorage) GetParticipantsInRange(identifier convert.MessageID, from,
 			continue
 		}
 
+		late, _, err := i.getParticipants(txn, lateParticipantsKey, identifier, slot)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get late participants: %w", err)
+		}
+
 		participantsRange = append(participantsRange, qbftstorage.ParticipantsRangeEntry{
 			Slot:       slot,
 			Signers:    participants,
+			Late:       late,
 			Identifier: identifier,
 		})
+
+		if slot == to {
+			break
+		}
 	}
 
 	return participantsRange, nil
 }
 
 func (i *ibftStorage) GetParticipants(identifier convert.MessageID, slot phase0.Slot) ([]spectypes.OperatorID, error) {
-	val, found, err := i.get(participantsKey, identifier[:], uInt64ToByteSlice(uint64(slot)))
+	operators, _, err := i.getParticipants(i.db, participantsKey, identifier, slot)
+	return operators, err
+}
+
+func (i *ibftStorage) getParticipants(r basedb.Reader, id string, identifier convert.MessageID, slot phase0.Slot) ([]spectypes.OperatorID, bool, error) {
+	prefix := append(i.prefix, identifier[:]...)
+	obj, found, err := r.Get(prefix, i.key(id, uInt64ToByteSlice(uint64(slot))))
+	if err != nil || !found {
+		return nil, found, err
+	}
+	return decodeOperators(obj.Value), true, nil
+}
+
+func (i *ibftStorage) saveParticipants(rw basedb.ReadWriter, id string, identifier convert.MessageID, slot phase0.Slot, operators []spectypes.OperatorID) error {
+	encoded, err := encodeOperators(operators)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	if !found {
-		return nil, nil
+	prefix := append(i.prefix, identifier[:]...)
+	if err := rw.Set(prefix, i.key(id, uInt64ToByteSlice(uint64(slot))), encoded); err != nil {
+		return err
 	}
+	return i.indexSlot(rw, id, identifier[:], slot)
+}
 
-	operators := decodeOperators(val)
-	return operators, nil
+func sortedUnion(a, b []spectypes.OperatorID) []spectypes.OperatorID {
+	union := slices.Concat(a, b)
+	slices.Sort(union)
+	return slices.Compact(union)
 }
 
 func (i *ibftStorage) save(value []byte, id string, pk []byte, keyParams ...[]byte) error {
@@ -242,7 +445,8 @@ func uInt64ToByteSlice(n uint64) []byte {
 }
 
 func encodeOperators(operators []spectypes.OperatorID) ([]byte, error) {
-	if len(operators) != 4 && len(operators) != 7 && len(operators) != 13 {
+	// Participants are any subset of a committee of up to 13 operators.
+	if len(operators) == 0 || len(operators) > 13 {
 		return nil, fmt.Errorf("invalid operators list size: %d", len(operators))
 	}
 	encoded := make([]byte, len(operators)*8)
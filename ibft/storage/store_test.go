@@ -12,6 +12,7 @@ import (
 	spectypes "github.com/ssvlabs/ssv-spec/types"
 	"github.com/ssvlabs/ssv-spec/types/testingutils"
 
+	"github.com/ssvlabs/ssv/exporter/convert"
 	"github.com/ssvlabs/ssv/logging"
 	"github.com/ssvlabs/ssv/networkconfig"
 	qbftstorage "github.com/ssvlabs/ssv/protocol/v2/qbft/storage"
@@ -208,3 +209,77 @@ func TestEncodeDecodeOperators(t *testing.T) {
 		})
 	}
 }
+
+func TestSaveParticipants(t *testing.T) {
+	storage, err := newTestIbftStorage(logging.TestLogger(t), "test")
+	require.NoError(t, err)
+	msgID := convert.NewMsgID(networkconfig.TestNetwork.DomainType(), []byte("pk"), convert.RoleAttester)
+
+	// Saving replaces the previously saved participants.
+	require.NoError(t, storage.SaveParticipants(msgID, 10, []spectypes.OperatorID{1, 2, 3, 4}))
+	require.NoError(t, storage.SaveParticipants(msgID, 10, []spectypes.OperatorID{1, 2, 3}))
+
+	participants, err := storage.GetParticipants(msgID, 10)
+	require.NoError(t, err)
+	require.Equal(t, []spectypes.OperatorID{1, 2, 3}, participants)
+
+	entries, err := storage.GetParticipantsInRange(msgID, 9, 12)
+	require.NoError(t, err)
+	require.Equal(t, []qbftstorage.ParticipantsRangeEntry{
+		{Slot: 10, Signers: []spectypes.OperatorID{1, 2, 3}, Identifier: msgID},
+	}, entries)
+}
+
+func TestMergeParticipants(t *testing.T) {
+	storage, err := newTestIbftStorage(logging.TestLogger(t), "test")
+	require.NoError(t, err)
+	msgID := convert.NewMsgID(networkconfig.TestNetwork.DomainType(), []byte("pk"), convert.RoleAttester)
+
+	// The first quorum is on time, and later signers are late.
+	require.NoError(t, storage.MergeParticipants(msgID, 10, []spectypes.OperatorID{3, 1, 2}))
+	require.NoError(t, storage.MergeParticipants(msgID, 10, []spectypes.OperatorID{1, 2, 3}))
+	require.NoError(t, storage.MergeParticipants(msgID, 10, []spectypes.OperatorID{1, 2, 3, 4}))
+	require.NoError(t, storage.MergeParticipants(msgID, 12, []spectypes.OperatorID{2, 3, 4}))
+
+	participants, err := storage.GetParticipants(msgID, 10)
+	require.NoError(t, err)
+	require.Equal(t, []spectypes.OperatorID{1, 2, 3, 4}, participants)
+
+	entries, err := storage.GetParticipantsInRange(msgID, 9, 12)
+	require.NoError(t, err)
+	require.Equal(t, []qbftstorage.ParticipantsRangeEntry{
+		{Slot: 10, Signers: []spectypes.OperatorID{1, 2, 3, 4}, Late: []spectypes.OperatorID{4}, Identifier: msgID},
+		{Slot: 12, Signers: []spectypes.OperatorID{2, 3, 4}, Identifier: msgID},
+	}, entries)
+}
+
+func TestIndexSlots(t *testing.T) {
+	logger := logging.TestLogger(t)
+	db, err := kv.NewInMemory(logger, basedb.Options{})
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := New(db, "test")
+	msgID := convert.NewMsgID(networkconfig.TestNetwork.DomainType(), []byte("pk"), convert.RoleProposer)
+	require.NoError(t, store.SaveParticipants(msgID, 10, []spectypes.OperatorID{1, 2, 3}))
+
+	// Participants saved before the slot index aren't pruned until they're indexed.
+	encoded, err := encodeOperators([]spectypes.OperatorID{1, 2, 3})
+	require.NoError(t, err)
+	require.NoError(t, db.Set(append([]byte("test"), msgID[:]...), []byte(participantsKey+string(uInt64ToByteSlice(5))), encoded))
+
+	removed, err := store.Prune(20)
+	require.NoError(t, err)
+	require.Equal(t, 1, removed)
+
+	indexed, err := store.IndexSlots()
+	require.NoError(t, err)
+	require.Equal(t, 1, indexed)
+
+	removed, err = store.Prune(6)
+	require.NoError(t, err)
+	require.Equal(t, 1, removed)
+	participants, err := store.GetParticipants(msgID, 5)
+	require.NoError(t, err)
+	require.Empty(t, participants)
+}
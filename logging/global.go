@@ -13,6 +13,24 @@ import (
 	"go.uber.org/zap/zapcore"
 )
 
+// globalLevel is the level of the global logger, which can be changed at runtime.
+var globalLevel = zap.NewAtomicLevel()
+
+// Level returns the current level of the global logger.
+func Level() zapcore.Level {
+	return globalLevel.Level()
+}
+
+// SetLevel changes the level of the global logger at runtime.
+func SetLevel(levelName string) error {
+	level, err := parseConfigLevel(levelName)
+	if err != nil {
+		return err
+	}
+	globalLevel.SetLevel(level)
+	return nil
+}
+
 func parseConfigLevel(levelName string) (zapcore.Level, error) {
 	return zapcore.ParseLevel(levelName)
 }
@@ -48,13 +66,12 @@ func SetGlobalLogger(levelName string, levelEncoderName string, logFormat string
 
 	levelEncoder := parseConfigLevelEncoder(levelEncoderName)
 
-	lv := zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
-		return lvl >= level
-	})
+	globalLevel.SetLevel(level)
+	lv := globalLevel
 
 	cfg := zap.Config{
 		Encoding:    logFormat,
-		Level:       zap.NewAtomicLevelAt(level),
+		Level:       globalLevel,
 		OutputPaths: []string{"stdout"},
 		EncoderConfig: zapcore.EncoderConfig{
 			MessageKey:  "msg",
@@ -0,0 +1,22 @@
+package logging
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestSetLevel(t *testing.T) {
+	require.NoError(t, SetGlobalLogger("info", "capital", "console", nil))
+	require.Equal(t, zapcore.InfoLevel, Level())
+	require.Nil(t, zap.L().Check(zapcore.DebugLevel, "debug"))
+
+	require.NoError(t, SetLevel("debug"))
+	require.Equal(t, zapcore.DebugLevel, Level())
+	require.NotNil(t, zap.L().Check(zapcore.DebugLevel, "debug"))
+
+	require.Error(t, SetLevel("verbose"))
+	require.Equal(t, zapcore.DebugLevel, Level())
+}
@@ -0,0 +1,226 @@
+package signatureverifier
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync/atomic"
+	"time"
+
+	spectypes "github.com/ssvlabs/ssv-spec/types"
+)
+
+const (
+	// DefaultBatchWindow is how long a batch collects verifications while earlier batches are being verified.
+	DefaultBatchWindow = time.Millisecond
+	// DefaultMaxBatchSize is the number of verifications which triggers verifying a batch before its window ends.
+	DefaultMaxBatchSize = 256
+)
+
+// Config holds the configuration of batched signature verification.
+type Config struct {
+	Batched      bool          `yaml:"Batched" env:"SIGNATURE_VERIFICATION_BATCHED" env-default:"false" env-description:"Collect signature verifications into batches which are verified in parallel"`
+	BatchWindow  time.Duration `yaml:"BatchWindow" env:"SIGNATURE_VERIFICATION_BATCH_WINDOW" env-default:"1ms" env-description:"How long a batch of signature verifications is collected for while earlier ones are being verified"`
+	MaxBatchSize int           `yaml:"MaxBatchSize" env:"SIGNATURE_VERIFICATION_MAX_BATCH_SIZE" env-default:"256" env-description:"Number of signature verifications which triggers verifying a batch early"`
+	Workers      int           `yaml:"Workers" env:"SIGNATURE_VERIFICATION_WORKERS" env-description:"Number of concurrent signature verifications, defaults to the number of CPUs"`
+}
+
+// Metrics records metrics about batched signature verification.
+type Metrics interface {
+	SignatureVerificationBatch(size int, duration time.Duration)
+}
+
+type nopMetrics struct{}
+
+func (nopMetrics) SignatureVerificationBatch(int, time.Duration) {}
+
+// BatchVerifier is a SignatureVerifier which collects the verifications requested
+// over a short window and verifies them together on a bounded pool of workers.
+//
+// Operator signatures are RSA, which unlike BLS has no sound aggregate verification,
+// so a batch can't be accepted or rejected as a whole. Instead, identical verifications
+// within a batch (which are common, since the same message arrives from several peers)
+// are verified once, and the distinct ones are verified individually in parallel,
+// so that an invalid signature only fails its own verification.
+//
+// A batch only waits for more verifications while earlier batches are being verified.
+// When the verifier is idle, a verification starts right away, so a lone message isn't delayed.
+//
+// Verifications are only batched while Run is running. Before it starts and after
+// it returns, they are verified in the calling goroutine.
+type BatchVerifier struct {
+	verifier     *signatureVerifier
+	batchWindow  time.Duration
+	maxBatchSize int
+	workers      chan struct{}
+	metrics      Metrics
+	requests     chan *verification
+	done         chan struct{}
+	// inFlight is the number of distinct verifications which are being verified.
+	inFlight atomic.Int64
+}
+
+// BatchOption configures a BatchVerifier.
+type BatchOption func(*BatchVerifier)
+
+// WithBatchWindow sets how long a batch collects verifications.
+func WithBatchWindow(window time.Duration) BatchOption {
+	return func(bv *BatchVerifier) {
+		bv.batchWindow = window
+	}
+}
+
+// WithMaxBatchSize sets the number of verifications which triggers verifying a batch early.
+func WithMaxBatchSize(size int) BatchOption {
+	return func(bv *BatchVerifier) {
+		bv.maxBatchSize = size
+	}
+}
+
+// WithWorkers sets the number of concurrent verifications.
+func WithWorkers(workers int) BatchOption {
+	return func(bv *BatchVerifier) {
+		bv.workers = make(chan struct{}, workers)
+	}
+}
+
+// WithMetrics sets the metrics reporter.
+func WithMetrics(metrics Metrics) BatchOption {
+	return func(bv *BatchVerifier) {
+		bv.metrics = metrics
+	}
+}
+
+// NewBatchVerifier returns a BatchVerifier of the signatures of the operators in the given store.
+func NewBatchVerifier(operatorStore OperatorStore, opts ...BatchOption) *BatchVerifier {
+	bv := &BatchVerifier{
+		verifier:     NewSignatureVerifier(operatorStore).(*signatureVerifier),
+		batchWindow:  DefaultBatchWindow,
+		maxBatchSize: DefaultMaxBatchSize,
+		workers:      make(chan struct{}, runtime.GOMAXPROCS(0)),
+		metrics:      nopMetrics{},
+		requests:     make(chan *verification),
+		done:         make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(bv)
+	}
+	return bv
+}
+
+type verificationKey struct {
+	operatorID spectypes.OperatorID
+	signature  string
+	data       string
+}
+
+type verification struct {
+	key    verificationKey
+	result chan error
+}
+
+// VerifySignature verifies the signature of the message as part of the current batch,
+// and blocks until it's verified.
+func (bv *BatchVerifier) VerifySignature(operatorID spectypes.OperatorID, message *spectypes.SSVMessage, signature []byte) error {
+	if len(signature) != 256 {
+		return fmt.Errorf("invalid signature length")
+	}
+
+	encodedMsg, err := message.Encode()
+	if err != nil {
+		return err
+	}
+
+	v := &verification{
+		key: verificationKey{
+			operatorID: operatorID,
+			signature:  string(signature),
+			data:       string(encodedMsg),
+		},
+		result: make(chan error, 1),
+	}
+	select {
+	case bv.requests <- v:
+		return <-v.result
+	case <-bv.done:
+		return bv.verify(v.key)
+	}
+}
+
+// Run collects verifications into batches and verifies them until the context is done.
+func (bv *BatchVerifier) Run(ctx context.Context) {
+	defer close(bv.done)
+
+	batch := make(map[verificationKey][]*verification, bv.maxBatchSize)
+	size := 0
+	timer := time.NewTimer(bv.batchWindow)
+	timer.Stop()
+	timerSet := false
+	for {
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			if size > 0 {
+				bv.verifyBatch(batch, size)
+			}
+			return
+		case v := <-bv.requests:
+			batch[v.key] = append(batch[v.key], v)
+			size++
+			// Wait for more verifications only while there are others to wait for.
+			if bv.inFlight.Load() > 0 && size < bv.maxBatchSize {
+				if !timerSet {
+					timer.Reset(bv.batchWindow)
+					timerSet = true
+				}
+				continue
+			}
+			if timerSet && !timer.Stop() {
+				<-timer.C
+			}
+		case <-timer.C:
+		}
+
+		timerSet = false
+		bv.verifyBatch(batch, size)
+		batch = make(map[verificationKey][]*verification, bv.maxBatchSize)
+		size = 0
+	}
+}
+
+// verifyBatch verifies each distinct verification of the batch once on the worker pool,
+// and replies to all of its requests. It blocks while all the workers are busy,
+// which holds back the following batches.
+func (bv *BatchVerifier) verifyBatch(batch map[verificationKey][]*verification, size int) {
+	start := time.Now()
+	pending := make(chan struct{}, len(batch))
+	bv.inFlight.Add(int64(len(batch)))
+	for key, requests := range batch {
+		bv.workers <- struct{}{}
+		go func() {
+			defer func() {
+				<-bv.workers
+				pending <- struct{}{}
+			}()
+			err := bv.verify(key)
+			bv.inFlight.Add(-1)
+			for _, v := range requests {
+				v.result <- err
+			}
+		}()
+	}
+	go func() {
+		for range batch {
+			<-pending
+		}
+		bv.metrics.SignatureVerificationBatch(size, time.Since(start))
+	}()
+}
+
+func (bv *BatchVerifier) verify(key verificationKey) error {
+	operatorPubKey, err := bv.verifier.operatorPublicKey(key.operatorID)
+	if err != nil {
+		return err
+	}
+	return operatorPubKey.Verify([]byte(key.data), []byte(key.signature))
+}
@@ -0,0 +1,174 @@
+package signatureverifier
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	spectypes "github.com/ssvlabs/ssv-spec/types"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ssvlabs/ssv/operator/keys"
+	registrystorage "github.com/ssvlabs/ssv/registry/storage"
+)
+
+type testMetrics struct {
+	mu      sync.Mutex
+	batches []int
+}
+
+func (m *testMetrics) SignatureVerificationBatch(size int, _ time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.batches = append(m.batches, size)
+}
+
+func (m *testMetrics) verified() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	total := 0
+	for _, size := range m.batches {
+		total += size
+	}
+	return total
+}
+
+type signedMessage struct {
+	operatorID spectypes.OperatorID
+	message    *spectypes.SSVMessage
+	signature  []byte
+}
+
+func newSignedMessages(t testing.TB, operatorStore *MockOperatorStore, count int) []signedMessage {
+	privateKey, err := keys.GeneratePrivateKey()
+	require.NoError(t, err)
+	publicKey, err := privateKey.Public().Base64()
+	require.NoError(t, err)
+	operatorStore.EXPECT().GetOperatorData(gomock.Any(), spectypes.OperatorID(1)).
+		Return(&registrystorage.OperatorData{ID: 1, PublicKey: publicKey}, true, nil).AnyTimes()
+
+	messages := make([]signedMessage, count)
+	for i := range messages {
+		message := &spectypes.SSVMessage{
+			MsgType: spectypes.SSVConsensusMsgType,
+			Data:    []byte{byte(i), byte(i >> 8)},
+		}
+		encodedMsg, err := message.Encode()
+		require.NoError(t, err)
+		signature, err := privateKey.Sign(encodedMsg)
+		require.NoError(t, err)
+		messages[i] = signedMessage{operatorID: 1, message: message, signature: signature}
+	}
+	return messages
+}
+
+func TestBatchVerifier(t *testing.T) {
+	operatorStore := NewMockOperatorStore(gomock.NewController(t))
+	messages := newSignedMessages(t, operatorStore, 4)
+	metrics := &testMetrics{}
+	bv := NewBatchVerifier(operatorStore, WithBatchWindow(50*time.Millisecond), WithWorkers(2), WithMetrics(metrics))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		bv.Run(ctx)
+		close(stopped)
+	}()
+
+	// A valid signature, the same one twice, an invalid one and one of an unknown operator.
+	invalid := messages[3]
+	invalid.signature = messages[2].signature
+	unknown := messages[0]
+	unknown.operatorID = 2
+	operatorStore.EXPECT().GetOperatorData(gomock.Any(), spectypes.OperatorID(2)).Return(nil, false, nil).AnyTimes()
+	requests := []signedMessage{messages[0], messages[1], messages[1], invalid, unknown}
+
+	errs := make([]error, len(requests))
+	var wg sync.WaitGroup
+	for i, m := range requests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = bv.VerifySignature(m.operatorID, m.message, m.signature)
+		}()
+	}
+	wg.Wait()
+
+	require.NoError(t, errs[0])
+	require.NoError(t, errs[1])
+	require.NoError(t, errs[2])
+	require.Error(t, errs[3])
+	require.ErrorContains(t, errs[4], "operator not found")
+	require.ErrorContains(t, bv.VerifySignature(1, messages[0].message, []byte{1}), "invalid signature length")
+	require.Eventually(t, func() bool { return metrics.verified() == len(requests) }, time.Second, 10*time.Millisecond)
+
+	// Signatures are verified inline once Run returns.
+	cancel()
+	<-stopped
+	require.NoError(t, bv.VerifySignature(messages[2].operatorID, messages[2].message, messages[2].signature))
+	require.Error(t, bv.VerifySignature(invalid.operatorID, invalid.message, invalid.signature))
+}
+
+func TestBatchVerifier_Idle(t *testing.T) {
+	operatorStore := NewMockOperatorStore(gomock.NewController(t))
+	messages := newSignedMessages(t, operatorStore, 2)
+	bv := NewBatchVerifier(operatorStore, WithBatchWindow(time.Hour))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go bv.Run(ctx)
+
+	// Lone verifications don't wait for the window of a batch.
+	for _, m := range messages {
+		verified := make(chan error, 1)
+		go func() {
+			verified <- bv.VerifySignature(m.operatorID, m.message, m.signature)
+		}()
+		select {
+		case err := <-verified:
+			require.NoError(t, err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("verification waited for the batch window")
+		}
+	}
+}
+
+func BenchmarkSignatureVerifier(b *testing.B) {
+	operatorStore := NewMockOperatorStore(gomock.NewController(b))
+	messages := newSignedMessages(b, operatorStore, 64)
+	verifier := NewSignatureVerifier(operatorStore)
+
+	// Messages are validated concurrently, and each of them is received from several peers.
+	b.SetParallelism(32)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for i := 0; pb.Next(); i++ {
+			m := messages[i%len(messages)]
+			if err := verifier.VerifySignature(m.operatorID, m.message, m.signature); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkBatchVerifier(b *testing.B) {
+	operatorStore := NewMockOperatorStore(gomock.NewController(b))
+	messages := newSignedMessages(b, operatorStore, 64)
+	bv := NewBatchVerifier(operatorStore)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go bv.Run(ctx)
+
+	// Messages are validated concurrently, and each of them is received from several peers.
+	b.SetParallelism(32)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for i := 0; pb.Next(); i++ {
+			m := messages[i%len(messages)]
+			if err := bv.VerifySignature(m.operatorID, m.message, m.signature); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -39,26 +39,9 @@ func (sv *signatureVerifier) VerifySignature(operatorID spectypes.OperatorID, me
 		return fmt.Errorf("invalid signature length")
 	}
 
-	sv.operatorIDToPubkeyCacheMu.Lock()
-	operatorPubKey, ok := sv.operatorIDToPubkeyCache[operatorID]
-	sv.operatorIDToPubkeyCacheMu.Unlock()
-	if !ok {
-		operator, found, err := sv.operatorStore.GetOperatorData(nil, operatorID)
-		if err != nil {
-			return err
-		}
-		if !found {
-			return fmt.Errorf("operator not found")
-		}
-
-		operatorPubKey, err = keys.PublicKeyFromString(string(operator.PublicKey))
-		if err != nil {
-			return err
-		}
-
-		sv.operatorIDToPubkeyCacheMu.Lock()
-		sv.operatorIDToPubkeyCache[operatorID] = operatorPubKey
-		sv.operatorIDToPubkeyCacheMu.Unlock()
+	operatorPubKey, err := sv.operatorPublicKey(operatorID)
+	if err != nil {
+		return err
 	}
 
 	encodedMsg, err := message.Encode()
@@ -68,3 +51,31 @@ func (sv *signatureVerifier) VerifySignature(operatorID spectypes.OperatorID, me
 
 	return operatorPubKey.Verify(encodedMsg, signature)
 }
+
+func (sv *signatureVerifier) operatorPublicKey(operatorID spectypes.OperatorID) (keys.OperatorPublicKey, error) {
+	sv.operatorIDToPubkeyCacheMu.Lock()
+	operatorPubKey, ok := sv.operatorIDToPubkeyCache[operatorID]
+	sv.operatorIDToPubkeyCacheMu.Unlock()
+	if ok {
+		return operatorPubKey, nil
+	}
+
+	operator, found, err := sv.operatorStore.GetOperatorData(nil, operatorID)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("operator not found")
+	}
+
+	operatorPubKey, err = keys.PublicKeyFromString(string(operator.PublicKey))
+	if err != nil {
+		return nil, err
+	}
+
+	sv.operatorIDToPubkeyCacheMu.Lock()
+	sv.operatorIDToPubkeyCache[operatorID] = operatorPubKey
+	sv.operatorIDToPubkeyCacheMu.Unlock()
+
+	return operatorPubKey, nil
+}
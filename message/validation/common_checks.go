@@ -16,13 +16,17 @@ func (mv *messageValidator) validateSlotTime(messageSlot phase0.Slot, role spect
 	if earliness := mv.messageEarliness(messageSlot, receivedAt); earliness > clockErrorTolerance {
 		e := ErrEarlySlotMessage
 		e.got = fmt.Sprintf("early by %v", earliness)
-		return e
+		if err := mv.violation(e); err != nil {
+			return err
+		}
 	}
 
 	if lateness := mv.messageLateness(messageSlot, role, receivedAt); lateness > clockErrorTolerance {
 		e := ErrLateSlotMessage
 		e.got = fmt.Sprintf("late by %v", lateness)
-		return e
+		if err := mv.violation(e); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -71,7 +75,7 @@ func (mv *messageValidator) validateDutyCount(
 		err := ErrTooManyDutiesPerEpoch
 		err.got = fmt.Sprintf("%v (role %v)", dutyCount, msgID.GetRoleType())
 		err.want = fmt.Sprintf("less than %v", dutyLimit)
-		return err
+		return mv.violation(err)
 	}
 
 	return nil
@@ -124,7 +128,9 @@ func (mv *messageValidator) validateBeaconDuty(
 		// Non-committee roles always have one validator index.
 		validatorIndex := indices[0]
 		if mv.dutyStore.Proposer.ValidatorDuty(epoch, slot, validatorIndex) == nil {
-			return ErrNoDuty
+			if err := mv.violation(ErrNoDuty); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -134,7 +140,9 @@ func (mv *messageValidator) validateBeaconDuty(
 		// Non-committee roles always have one validator index.
 		validatorIndex := indices[0]
 		if mv.dutyStore.SyncCommittee.Duty(period, validatorIndex) == nil {
-			return ErrNoDuty
+			if err := mv.violation(ErrNoDuty); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -162,7 +162,9 @@ func (mv *messageValidator) validateQBFTLogic(
 			err := ErrSignerNotLeader
 			err.got = signedSSVMessage.OperatorIDs[0]
 			err.want = leader
-			return err
+			if err := mv.violation(err); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -183,7 +185,9 @@ func (mv *messageValidator) validateQBFTLogic(
 				err := ErrRoundAlreadyAdvanced
 				err.want = signerState.Round
 				err.got = consensusMessage.Round
-				return err
+				if err := mv.violation(err); err != nil {
+					return err
+				}
 			}
 
 			if consensusMessage.Round == signerState.Round {
@@ -207,7 +211,9 @@ func (mv *messageValidator) validateQBFTLogic(
 
 			// Rule: Decided msg can't have the same signers as previously sent before for the same duty
 			if _, ok := signerState.SeenSigners[encodedOperators]; ok {
-				return ErrDecidedWithSameSigners
+				if err := mv.violation(ErrDecidedWithSameSigners); err != nil {
+					return err
+				}
 			}
 		}
 	}
@@ -237,7 +243,9 @@ func (mv *messageValidator) validateQBFTMessageByDutyLogic(
 				e := ErrSlotAlreadyAdvanced
 				e.got = consensusMessage.Height
 				e.want = maxSlot
-				return e
+				if err := mv.violation(e); err != nil {
+					return err
+				}
 			}
 		}
 	}
@@ -285,7 +293,7 @@ func (mv *messageValidator) validateQBFTMessageByDutyLogic(
 		err := ErrRoundTooHigh
 		err.got = fmt.Sprintf("%v (%v role)", consensusMessage.Round, message.RunnerRoleToString(role))
 		err.want = fmt.Sprintf("%v (%v role)", maxRound, message.RunnerRoleToString(role))
-		return err
+		return mv.violation(err)
 	}
 
 	return nil
@@ -433,7 +441,7 @@ func (mv *messageValidator) roundBelongsToAllowedSpread(
 		e := ErrEstimatedRoundNotInAllowedSpread
 		e.got = fmt.Sprintf("%v (%v role)", consensusMessage.Round, message.RunnerRoleToString(role))
 		e.want = fmt.Sprintf("between %v and %v (%v role) / %v passed", lowestAllowed, highestAllowed, message.RunnerRoleToString(role), sinceSlotStart)
-		return e
+		return mv.violation(e)
 	}
 
 	return nil
@@ -14,12 +14,15 @@ import (
 )
 
 type Error struct {
+	rule     string
 	text     string
 	got      any
 	want     any
 	innerErr error
 	reject   bool
 	silent   bool
+	// skippable is whether validation can go on after the rule is violated, which its log-only mode requires.
+	skippable bool
 }
 
 func (e Error) Error() string {
@@ -51,71 +54,76 @@ func (e Error) Text() string {
 	return e.text
 }
 
+// Rule returns the name of the violated rule.
+func (e Error) Rule() string {
+	return e.rule
+}
+
 var (
-	ErrWrongDomain                             = Error{text: "wrong domain"}
-	ErrNoShareMetadata                         = Error{text: "share has no metadata"}
-	ErrUnknownValidator                        = Error{text: "unknown validator"}
-	ErrValidatorLiquidated                     = Error{text: "validator is liquidated"}
-	ErrValidatorNotAttesting                   = Error{text: "validator is not attesting"}
-	ErrEarlySlotMessage                        = Error{text: "message was sent before slot starts"}
-	ErrLateSlotMessage                         = Error{text: "current time is above duty's start +34(committee and aggregator) or +3(else) slots"}
-	ErrSlotAlreadyAdvanced                     = Error{text: "signer has already advanced to a later slot"}
-	ErrRoundAlreadyAdvanced                    = Error{text: "signer has already advanced to a later round"}
-	ErrDecidedWithSameSigners                  = Error{text: "decided with same number of signers"}
-	ErrPubSubDataTooBig                        = Error{text: "pub-sub message data too big"}
-	ErrIncorrectTopic                          = Error{text: "incorrect topic"}
-	ErrNonExistentCommitteeID                  = Error{text: "committee ID doesn't exist"}
-	ErrRoundTooHigh                            = Error{text: "round is too high for this role"}
-	ErrValidatorIndexMismatch                  = Error{text: "partial signature validator index not found"}
-	ErrTooManyDutiesPerEpoch                   = Error{text: "too many duties per epoch"}
-	ErrNoDuty                                  = Error{text: "no duty for this epoch"}
-	ErrEstimatedRoundNotInAllowedSpread        = Error{text: "message round is too far from estimated"}
-	ErrEmptyData                               = Error{text: "empty data", reject: true}
-	ErrMismatchedIdentifier                    = Error{text: "identifier mismatch", reject: true}
-	ErrSignatureVerification                   = Error{text: "signature verification", reject: true}
-	ErrPubSubMessageHasNoData                  = Error{text: "pub-sub message has no data", reject: true}
-	ErrMalformedPubSubMessage                  = Error{text: "pub-sub message is malformed", reject: true}
-	ErrNilSignedSSVMessage                     = Error{text: "signed ssv message is nil", reject: true}
-	ErrNilSSVMessage                           = Error{text: "ssv message is nil", reject: true}
-	ErrSSVDataTooBig                           = Error{text: "ssv message data too big", reject: true}
-	ErrInvalidRole                             = Error{text: "invalid role", reject: true}
-	ErrUnexpectedConsensusMessage              = Error{text: "unexpected consensus message for this role", reject: true}
-	ErrNoSigners                               = Error{text: "no signers", reject: true}
-	ErrWrongRSASignatureSize                   = Error{text: "wrong RSA signature size", reject: true}
-	ErrZeroSigner                              = Error{text: "zero signer ID", reject: true}
-	ErrSignerNotInCommittee                    = Error{text: "signer is not in committee", reject: true}
-	ErrDuplicatedSigner                        = Error{text: "signer is duplicated", reject: true}
-	ErrSignerNotLeader                         = Error{text: "signer is not leader", reject: true}
-	ErrSignersNotSorted                        = Error{text: "signers are not sorted", reject: true}
-	ErrInconsistentSigners                     = Error{text: "signer is not expected", reject: true}
-	ErrInvalidHash                             = Error{text: "root doesn't match full data hash", reject: true}
-	ErrFullDataHash                            = Error{text: "couldn't hash root", reject: true}
-	ErrUndecodableMessageData                  = Error{text: "message data could not be decoded", reject: true}
-	ErrEventMessage                            = Error{text: "unexpected event message", reject: true}
-	ErrUnknownSSVMessageType                   = Error{text: "unknown SSV message type", reject: true}
-	ErrUnknownQBFTMessageType                  = Error{text: "unknown QBFT message type", reject: true}
-	ErrInvalidPartialSignatureType             = Error{text: "unknown partial signature message type", reject: true}
-	ErrPartialSignatureTypeRoleMismatch        = Error{text: "partial signature type and role don't match", reject: true}
-	ErrNonDecidedWithMultipleSigners           = Error{text: "non-decided with multiple signers", reject: true}
-	ErrDecidedNotEnoughSigners                 = Error{text: "not enough signers in decided message", reject: true}
-	ErrDifferentProposalData                   = Error{text: "different proposal data", reject: true}
-	ErrMalformedPrepareJustifications          = Error{text: "malformed prepare justifications", reject: true}
-	ErrUnexpectedPrepareJustifications         = Error{text: "prepare justifications unexpected for this message type", reject: true}
-	ErrMalformedRoundChangeJustifications      = Error{text: "malformed round change justifications", reject: true}
-	ErrUnexpectedRoundChangeJustifications     = Error{text: "round change justifications unexpected for this message type", reject: true}
-	ErrNoPartialSignatureMessages              = Error{text: "no partial signature messages", reject: true}
-	ErrNoValidators                            = Error{text: "no validators for this committee ID", reject: true}
-	ErrNoSignatures                            = Error{text: "no signatures", reject: true}
-	ErrSignersAndSignaturesWithDifferentLength = Error{text: "signature and operator ID length mismatch", reject: true}
-	ErrPartialSigOneSigner                     = Error{text: "partial signature message must have only one signer", reject: true}
-	ErrPrepareOrCommitWithFullData             = Error{text: "prepare or commit with full data", reject: true}
-	ErrFullDataNotInConsensusMessage           = Error{text: "full data not in consensus message", reject: true}
-	ErrTripleValidatorIndexInPartialSignatures = Error{text: "triple validator index in partial signatures", reject: true}
-	ErrZeroRound                               = Error{text: "zero round", reject: true}
-	ErrDuplicatedMessage                       = Error{text: "message is duplicated", reject: true}
-	ErrInvalidPartialSignatureTypeCount        = Error{text: "sent more partial signature messages of a certain type than allowed", reject: true}
-	ErrTooManyPartialSignatureMessages         = Error{text: "too many partial signature messages", reject: true}
-	ErrEncodeOperators                         = Error{text: "encode operators", reject: true}
+	ErrWrongDomain                             = newRule("wrong_domain", Error{text: "wrong domain"})
+	ErrNoShareMetadata                         = newRule("no_share_metadata", Error{text: "share has no metadata"})
+	ErrUnknownValidator                        = newRule("unknown_validator", Error{text: "unknown validator"})
+	ErrValidatorLiquidated                     = newRule("validator_liquidated", Error{text: "validator is liquidated"})
+	ErrValidatorNotAttesting                   = newRule("validator_not_attesting", Error{text: "validator is not attesting", skippable: true})
+	ErrEarlySlotMessage                        = newRule("early_slot_message", Error{text: "message was sent before slot starts", skippable: true})
+	ErrLateSlotMessage                         = newRule("late_slot_message", Error{text: "current time is above duty's start +34(committee and aggregator) or +3(else) slots", skippable: true})
+	ErrSlotAlreadyAdvanced                     = newRule("slot_already_advanced", Error{text: "signer has already advanced to a later slot", skippable: true})
+	ErrRoundAlreadyAdvanced                    = newRule("round_already_advanced", Error{text: "signer has already advanced to a later round", skippable: true})
+	ErrDecidedWithSameSigners                  = newRule("decided_with_same_signers", Error{text: "decided with same number of signers", skippable: true})
+	ErrPubSubDataTooBig                        = newRule("pub_sub_data_too_big", Error{text: "pub-sub message data too big"})
+	ErrIncorrectTopic                          = newRule("incorrect_topic", Error{text: "incorrect topic", skippable: true})
+	ErrNonExistentCommitteeID                  = newRule("non_existent_committee_id", Error{text: "committee ID doesn't exist"})
+	ErrRoundTooHigh                            = newRule("round_too_high", Error{text: "round is too high for this role", skippable: true})
+	ErrValidatorIndexMismatch                  = newRule("validator_index_mismatch", Error{text: "partial signature validator index not found"})
+	ErrTooManyDutiesPerEpoch                   = newRule("too_many_duties_per_epoch", Error{text: "too many duties per epoch", skippable: true})
+	ErrNoDuty                                  = newRule("no_duty", Error{text: "no duty for this epoch", skippable: true})
+	ErrEstimatedRoundNotInAllowedSpread        = newRule("estimated_round_not_in_allowed_spread", Error{text: "message round is too far from estimated", skippable: true})
+	ErrEmptyData                               = newRule("empty_data", Error{text: "empty data", reject: true})
+	ErrMismatchedIdentifier                    = newRule("mismatched_identifier", Error{text: "identifier mismatch", reject: true})
+	ErrSignatureVerification                   = newRule("signature_verification", Error{text: "signature verification", reject: true})
+	ErrPubSubMessageHasNoData                  = newRule("pub_sub_message_has_no_data", Error{text: "pub-sub message has no data", reject: true})
+	ErrMalformedPubSubMessage                  = newRule("malformed_pub_sub_message", Error{text: "pub-sub message is malformed", reject: true})
+	ErrNilSignedSSVMessage                     = newRule("nil_signed_ssv_message", Error{text: "signed ssv message is nil", reject: true})
+	ErrNilSSVMessage                           = newRule("nil_ssv_message", Error{text: "ssv message is nil", reject: true})
+	ErrSSVDataTooBig                           = newRule("ssv_data_too_big", Error{text: "ssv message data too big", reject: true})
+	ErrInvalidRole                             = newRule("invalid_role", Error{text: "invalid role", reject: true})
+	ErrUnexpectedConsensusMessage              = newRule("unexpected_consensus_message", Error{text: "unexpected consensus message for this role", reject: true})
+	ErrNoSigners                               = newRule("no_signers", Error{text: "no signers", reject: true})
+	ErrWrongRSASignatureSize                   = newRule("wrong_rsa_signature_size", Error{text: "wrong RSA signature size", reject: true})
+	ErrZeroSigner                              = newRule("zero_signer", Error{text: "zero signer ID", reject: true})
+	ErrSignerNotInCommittee                    = newRule("signer_not_in_committee", Error{text: "signer is not in committee", reject: true})
+	ErrDuplicatedSigner                        = newRule("duplicated_signer", Error{text: "signer is duplicated", reject: true})
+	ErrSignerNotLeader                         = newRule("signer_not_leader", Error{text: "signer is not leader", reject: true, skippable: true})
+	ErrSignersNotSorted                        = newRule("signers_not_sorted", Error{text: "signers are not sorted", reject: true})
+	ErrInconsistentSigners                     = newRule("inconsistent_signers", Error{text: "signer is not expected", reject: true})
+	ErrInvalidHash                             = newRule("invalid_hash", Error{text: "root doesn't match full data hash", reject: true})
+	ErrFullDataHash                            = newRule("full_data_hash", Error{text: "couldn't hash root", reject: true})
+	ErrUndecodableMessageData                  = newRule("undecodable_message_data", Error{text: "message data could not be decoded", reject: true})
+	ErrEventMessage                            = newRule("event_message", Error{text: "unexpected event message", reject: true})
+	ErrUnknownSSVMessageType                   = newRule("unknown_ssv_message_type", Error{text: "unknown SSV message type", reject: true})
+	ErrUnknownQBFTMessageType                  = newRule("unknown_qbft_message_type", Error{text: "unknown QBFT message type", reject: true})
+	ErrInvalidPartialSignatureType             = newRule("invalid_partial_signature_type", Error{text: "unknown partial signature message type", reject: true})
+	ErrPartialSignatureTypeRoleMismatch        = newRule("partial_signature_type_role_mismatch", Error{text: "partial signature type and role don't match", reject: true})
+	ErrNonDecidedWithMultipleSigners           = newRule("non_decided_with_multiple_signers", Error{text: "non-decided with multiple signers", reject: true})
+	ErrDecidedNotEnoughSigners                 = newRule("decided_not_enough_signers", Error{text: "not enough signers in decided message", reject: true})
+	ErrDifferentProposalData                   = newRule("different_proposal_data", Error{text: "different proposal data", reject: true})
+	ErrMalformedPrepareJustifications          = newRule("malformed_prepare_justifications", Error{text: "malformed prepare justifications", reject: true})
+	ErrUnexpectedPrepareJustifications         = newRule("unexpected_prepare_justifications", Error{text: "prepare justifications unexpected for this message type", reject: true})
+	ErrMalformedRoundChangeJustifications      = newRule("malformed_round_change_justifications", Error{text: "malformed round change justifications", reject: true})
+	ErrUnexpectedRoundChangeJustifications     = newRule("unexpected_round_change_justifications", Error{text: "round change justifications unexpected for this message type", reject: true})
+	ErrNoPartialSignatureMessages              = newRule("no_partial_signature_messages", Error{text: "no partial signature messages", reject: true})
+	ErrNoValidators                            = newRule("no_validators", Error{text: "no validators for this committee ID", reject: true})
+	ErrNoSignatures                            = newRule("no_signatures", Error{text: "no signatures", reject: true})
+	ErrSignersAndSignaturesWithDifferentLength = newRule("signers_and_signatures_with_different_length", Error{text: "signature and operator ID length mismatch", reject: true})
+	ErrPartialSigOneSigner                     = newRule("partial_sig_one_signer", Error{text: "partial signature message must have only one signer", reject: true})
+	ErrPrepareOrCommitWithFullData             = newRule("prepare_or_commit_with_full_data", Error{text: "prepare or commit with full data", reject: true})
+	ErrFullDataNotInConsensusMessage           = newRule("full_data_not_in_consensus_message", Error{text: "full data not in consensus message", reject: true})
+	ErrTripleValidatorIndexInPartialSignatures = newRule("triple_validator_index_in_partial_signatures", Error{text: "triple validator index in partial signatures", reject: true})
+	ErrZeroRound                               = newRule("zero_round", Error{text: "zero round", reject: true})
+	ErrDuplicatedMessage                       = newRule("duplicated_message", Error{text: "message is duplicated", reject: true})
+	ErrInvalidPartialSignatureTypeCount        = newRule("invalid_partial_signature_type_count", Error{text: "sent more partial signature messages of a certain type than allowed", reject: true})
+	ErrTooManyPartialSignatureMessages         = newRule("too_many_partial_signature_messages", Error{text: "too many partial signature messages", reject: true})
+	ErrEncodeOperators                         = newRule("encode_operators", Error{text: "encode operators", reject: true})
 )
 
 func (mv *messageValidator) handleValidationError(peerID peer.ID, decodedMessage *queue.SSVMessage, err error) pubsub.ValidationResult {
@@ -132,7 +140,10 @@ func (mv *messageValidator) handleValidationError(peerID peer.ID, decodedMessage
 		return pubsub.ValidationIgnore
 	}
 
-	if !valErr.Reject() {
+	mode := mv.ruleMode(valErr.Rule())
+	mv.metrics.MessageValidationRuleViolation(valErr.Rule(), string(mode))
+
+	if !valErr.Reject() || mode == RuleModeIgnore {
 		if !valErr.Silent() {
 			logger.Debug("ignoring invalid message", zap.Error(valErr))
 		}
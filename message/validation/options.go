@@ -5,6 +5,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/ssvlabs/ssv/monitoring/metricsreporter"
+	"github.com/ssvlabs/ssv/storage/basedb"
 )
 
 // Option represents a functional option for configuring a messageValidator.
@@ -31,3 +32,25 @@ func WithSelfAccept(selfPID peer.ID, selfAccept bool) Option {
 		mv.selfAccept = selfAccept
 	}
 }
+
+// WithRuleModes sets the modes of rules by name, as returned by ParseRuleModes.
+func WithRuleModes(modes map[string]RuleMode) Option {
+	return func(mv *messageValidator) {
+		mv.ruleModes = modes
+	}
+}
+
+// WithDefaultRuleMode sets the mode of the rules without a mode set with WithRuleModes,
+// which is either RuleModeEnforce or RuleModeIgnore, since only some rules support RuleModeLogOnly.
+func WithDefaultRuleMode(mode RuleMode) Option {
+	return func(mv *messageValidator) {
+		mv.defaultRuleMode = mode
+	}
+}
+
+// WithStateStore persists the state in the given database, to be restored after restarts.
+func WithStateStore(db basedb.Database) Option {
+	return func(mv *messageValidator) {
+		mv.stateDB = db
+	}
+}
@@ -151,7 +151,9 @@ func (mv *messageValidator) validatePartialSigMessagesByDutyLogic(
 			e := ErrSlotAlreadyAdvanced
 			e.got = partialSignatureMessages.Slot
 			e.want = maxSlot
-			return e
+			if err := mv.violation(e); err != nil {
+				return err
+			}
 		}
 	}
 
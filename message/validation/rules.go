@@ -0,0 +1,92 @@
+package validation
+
+// rules.go contains the registry of rules and their modes.
+
+import (
+	"fmt"
+	"slices"
+
+	"go.uber.org/zap"
+	"golang.org/x/exp/maps"
+)
+
+// RuleMode is how the violations of a rule are handled.
+type RuleMode string
+
+const (
+	// RuleModeEnforce ignores or rejects messages violating the rule, as the rule decides.
+	RuleModeEnforce RuleMode = "enforce"
+	// RuleModeIgnore ignores messages violating the rule, instead of rejecting them and penalizing their peers.
+	RuleModeIgnore RuleMode = "ignore"
+	// RuleModeLogOnly only logs and counts the violations, and goes on validating the message.
+	// It's only supported by rules which don't need to pass for the following rules to be checked.
+	RuleModeLogOnly RuleMode = "log-only"
+)
+
+// Config holds the message validation configuration.
+type Config struct {
+	RuleModes map[string]string `yaml:"RuleModes" env:"MESSAGE_VALIDATION_RULE_MODES" env-description:"Modes of message validation rules by name (enforce, ignore or log-only), e.g. round_too_high:ignore,late_slot_message:log-only"`
+}
+
+// rules are the registered rules by name.
+var rules = make(map[string]Error)
+
+// newRule registers the rule, whose violations are reported as the given error.
+func newRule(name string, err Error) Error {
+	if _, ok := rules[name]; ok {
+		panic(fmt.Sprintf("message validation rule %q is already registered", name))
+	}
+	err.rule = name
+	rules[name] = err
+	return err
+}
+
+// Rules returns the sorted names of the registered rules.
+func Rules() []string {
+	names := maps.Keys(rules)
+	slices.Sort(names)
+	return names
+}
+
+// ParseRuleModes validates the modes of rules by name.
+func ParseRuleModes(modes map[string]string) (map[string]RuleMode, error) {
+	parsed := make(map[string]RuleMode, len(modes))
+	for name, mode := range modes {
+		rule, ok := rules[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown message validation rule %q", name)
+		}
+		switch RuleMode(mode) {
+		case RuleModeEnforce, RuleModeIgnore:
+		case RuleModeLogOnly:
+			if !rule.skippable {
+				return nil, fmt.Errorf("message validation rule %q doesn't support the %s mode", name, mode)
+			}
+		default:
+			return nil, fmt.Errorf("unknown mode %q of message validation rule %q", mode, name)
+		}
+		parsed[name] = RuleMode(mode)
+	}
+	return parsed, nil
+}
+
+func (mv *messageValidator) ruleMode(rule string) RuleMode {
+	if mode, ok := mv.ruleModes[rule]; ok {
+		return mode
+	}
+	if mv.defaultRuleMode == "" {
+		return RuleModeEnforce
+	}
+	return mv.defaultRuleMode
+}
+
+// violation returns the error of the violated rule, unless the rule is in log-only mode,
+// in which case the violation is logged and counted, and nil is returned to go on validating.
+func (mv *messageValidator) violation(err Error) error {
+	if mv.ruleMode(err.rule) != RuleModeLogOnly {
+		return err
+	}
+	mv.metrics.MessageValidationRuleViolation(err.rule, string(RuleModeLogOnly))
+	mv.logger.Debug("message violates log-only rule", zap.String("rule", err.rule), zap.Error(err))
+	return nil
+}
@@ -0,0 +1,42 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRuleModes(t *testing.T) {
+	require.Contains(t, Rules(), "round_too_high")
+	require.Equal(t, "round_too_high", ErrRoundTooHigh.Rule())
+
+	modes, err := ParseRuleModes(map[string]string{
+		"round_too_high":    "ignore",
+		"late_slot_message": "log-only",
+		"zero_round":        "enforce",
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]RuleMode{
+		"round_too_high":    RuleModeIgnore,
+		"late_slot_message": RuleModeLogOnly,
+		"zero_round":        RuleModeEnforce,
+	}, modes)
+
+	_, err = ParseRuleModes(map[string]string{"unknown": "ignore"})
+	require.ErrorContains(t, err, "unknown message validation rule")
+	_, err = ParseRuleModes(map[string]string{"round_too_high": "skip"})
+	require.ErrorContains(t, err, "unknown mode")
+	// Validation can't go on without a valid signature.
+	_, err = ParseRuleModes(map[string]string{"signature_verification": "log-only"})
+	require.ErrorContains(t, err, "doesn't support")
+}
+
+func TestDefaultRuleMode(t *testing.T) {
+	mv := &messageValidator{}
+	require.Equal(t, RuleModeEnforce, mv.ruleMode("round_too_high"))
+
+	WithRuleModes(map[string]RuleMode{"round_too_high": RuleModeEnforce})(mv)
+	WithDefaultRuleMode(RuleModeIgnore)(mv)
+	require.Equal(t, RuleModeEnforce, mv.ruleMode("round_too_high"))
+	require.Equal(t, RuleModeIgnore, mv.ruleMode("zero_round"))
+}
@@ -0,0 +1,249 @@
+package validation
+
+// state_store.go persists the consensus state, so that it survives restarts.
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	specqbft "github.com/ssvlabs/ssv-spec/qbft"
+	spectypes "github.com/ssvlabs/ssv-spec/types"
+	"go.uber.org/zap"
+
+	"github.com/ssvlabs/ssv/storage/basedb"
+)
+
+var (
+	statePrefix     = []byte("message_validation_state-")
+	stateMetaPrefix = []byte("message_validation_state_meta-")
+	savedSlotKey    = []byte("saved_slot")
+)
+
+// StatePersister is implemented by message validators which can persist their state.
+type StatePersister interface {
+	// RestoreState restores the saved state, unless it's too old to be relevant.
+	RestoreState() error
+	// SaveState saves the current state.
+	SaveState() error
+	// PersistState saves the state every epoch and once more when the context is done.
+	PersistState(ctx context.Context)
+}
+
+type operatorStateJSON struct {
+	Signer          spectypes.OperatorID
+	MaxSlot         phase0.Slot
+	MaxEpoch        phase0.Epoch
+	LastEpochDuties uint64
+	PrevEpochDuties uint64
+	States          []*signerStateJSON
+}
+
+type signerStateJSON struct {
+	Slot          phase0.Slot
+	Round         specqbft.Round
+	MessageCounts MessageCounts
+	ProposalData  []byte
+	SeenSigners   [][]byte
+}
+
+// RestoreState restores the state saved by SaveState, unless it was saved before the oldest stored slot.
+func (mv *messageValidator) RestoreState() error {
+	if mv.stateDB == nil {
+		return nil
+	}
+
+	obj, found, err := mv.stateDB.Get(stateMetaPrefix, savedSlotKey)
+	if err != nil {
+		return fmt.Errorf("could not get saved slot: %w", err)
+	}
+	if !found {
+		return nil
+	}
+	savedSlot := phase0.Slot(binary.BigEndian.Uint64(obj.Value))
+	storedSlotCount := mv.storedSlotCount()
+	if currentSlot := mv.netCfg.Beacon.EstimatedCurrentSlot(); currentSlot >= savedSlot+storedSlotCount {
+		mv.logger.Info("not restoring stale message validation state",
+			zap.Uint64("saved_slot", uint64(savedSlot)),
+			zap.Uint64("current_slot", uint64(currentSlot)))
+		return nil
+	}
+
+	restored := make(map[consensusID]*consensusState)
+	err = mv.stateDB.GetAll(statePrefix, func(_ int, obj basedb.Obj) error {
+		if len(obj.Key) < 4 {
+			return fmt.Errorf("invalid state key %x", obj.Key)
+		}
+		id := consensusID{
+			Role:           spectypes.RunnerRole(binary.BigEndian.Uint32(obj.Key)), // #nosec G115
+			DutyExecutorID: string(obj.Key[4:]),
+		}
+		var operators []*operatorStateJSON
+		if err := json.Unmarshal(obj.Value, &operators); err != nil {
+			return fmt.Errorf("could not decode state: %w", err)
+		}
+
+		cs := &consensusState{
+			state:           make(map[spectypes.OperatorID]*OperatorState, len(operators)),
+			storedSlotCount: storedSlotCount,
+		}
+		for _, operator := range operators {
+			os := newOperatorState(storedSlotCount)
+			os.maxSlot = operator.MaxSlot
+			os.maxEpoch = operator.MaxEpoch
+			os.lastEpochDuties = operator.LastEpochDuties
+			os.prevEpochDuties = operator.PrevEpochDuties
+			for _, s := range operator.States {
+				signerState := &SignerState{
+					Slot:          s.Slot,
+					Round:         s.Round,
+					MessageCounts: s.MessageCounts,
+					ProposalData:  s.ProposalData,
+					SeenSigners:   make(map[[sha256.Size]byte]struct{}, len(s.SeenSigners)),
+				}
+				for _, signers := range s.SeenSigners {
+					if len(signers) != sha256.Size {
+						return fmt.Errorf("invalid seen signers length %d", len(signers))
+					}
+					signerState.SeenSigners[[sha256.Size]byte(signers)] = struct{}{}
+				}
+				os.state[uint64(s.Slot)%uint64(storedSlotCount)] = signerState
+			}
+			cs.state[operator.Signer] = os
+		}
+		restored[id] = cs
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	mv.consensusStateIndexMu.Lock()
+	defer mv.consensusStateIndexMu.Unlock()
+	for id, cs := range restored {
+		mv.consensusStateIndex[id] = cs
+	}
+
+	mv.logger.Info("restored message validation state",
+		zap.Uint64("saved_slot", uint64(savedSlot)),
+		zap.Int("states", len(restored)))
+	return nil
+}
+
+// SaveState replaces the saved state with the current one.
+func (mv *messageValidator) SaveState() error {
+	if mv.stateDB == nil {
+		return nil
+	}
+
+	mv.saveStateMu.Lock()
+	defer mv.saveStateMu.Unlock()
+
+	savedSlot := mv.netCfg.Beacon.EstimatedCurrentSlot()
+
+	mv.consensusStateIndexMu.Lock()
+	ids := make([]consensusID, 0, len(mv.consensusStateIndex))
+	states := make([]*consensusState, 0, len(mv.consensusStateIndex))
+	for id, cs := range mv.consensusStateIndex {
+		ids = append(ids, id)
+		states = append(states, cs)
+	}
+	mv.consensusStateIndexMu.Unlock()
+
+	values := make([][]byte, len(states))
+	for i, cs := range states {
+		value, err := json.Marshal(mv.snapshotConsensusState(ids[i], cs))
+		if err != nil {
+			return fmt.Errorf("could not encode state: %w", err)
+		}
+		values[i] = value
+	}
+
+	// The saved slot is removed first, so that a partially saved state isn't restored.
+	if err := mv.stateDB.Delete(stateMetaPrefix, savedSlotKey); err != nil {
+		return fmt.Errorf("could not delete saved slot: %w", err)
+	}
+	if err := mv.stateDB.DropPrefix(statePrefix); err != nil {
+		return fmt.Errorf("could not drop saved state: %w", err)
+	}
+	err := mv.stateDB.SetMany(statePrefix, len(values), func(i int) (basedb.Obj, error) {
+		key := binary.BigEndian.AppendUint32(nil, uint32(ids[i].Role)) // #nosec G115
+		return basedb.Obj{Key: append(key, ids[i].DutyExecutorID...), Value: values[i]}, nil
+	})
+	if err != nil {
+		return fmt.Errorf("could not save state: %w", err)
+	}
+	if err := mv.stateDB.Set(stateMetaPrefix, savedSlotKey, binary.BigEndian.AppendUint64(nil, uint64(savedSlot))); err != nil {
+		return fmt.Errorf("could not save saved slot: %w", err)
+	}
+	return nil
+}
+
+// PersistState saves the state every epoch and once more when the context is done.
+func (mv *messageValidator) PersistState(ctx context.Context) {
+	if mv.stateDB == nil {
+		return
+	}
+
+	ticker := time.NewTicker(mv.netCfg.Beacon.SlotDurationSec() * time.Duration(mv.netCfg.Beacon.SlotsPerEpoch()))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			if err := mv.SaveState(); err != nil {
+				mv.logger.Warn("failed to save message validation state", zap.Error(err))
+			}
+			return
+		case <-ticker.C:
+			if err := mv.SaveState(); err != nil {
+				mv.logger.Warn("failed to save message validation state", zap.Error(err))
+			}
+		}
+	}
+}
+
+// snapshotConsensusState copies the state while holding the validation lock of its message ID,
+// since signer states are updated in place during validation.
+func (mv *messageValidator) snapshotConsensusState(id consensusID, cs *consensusState) []*operatorStateJSON {
+	validationMu := mv.obtainValidationLock(spectypes.NewMsgID(mv.netCfg.DomainType(), []byte(id.DutyExecutorID), id.Role))
+	validationMu.Lock()
+	defer validationMu.Unlock()
+
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	operators := make([]*operatorStateJSON, 0, len(cs.state))
+	for signer, os := range cs.state {
+		os.mu.RLock()
+		operator := &operatorStateJSON{
+			Signer:          signer,
+			MaxSlot:         os.maxSlot,
+			MaxEpoch:        os.maxEpoch,
+			LastEpochDuties: os.lastEpochDuties,
+			PrevEpochDuties: os.prevEpochDuties,
+		}
+		for _, s := range os.state {
+			if s == nil {
+				continue
+			}
+			signerState := &signerStateJSON{
+				Slot:          s.Slot,
+				Round:         s.Round,
+				MessageCounts: s.MessageCounts,
+				ProposalData:  s.ProposalData,
+				SeenSigners:   make([][]byte, 0, len(s.SeenSigners)),
+			}
+			for signers := range s.SeenSigners {
+				signerState.SeenSigners = append(signerState.SeenSigners, signers[:])
+			}
+			operator.States = append(operator.States, signerState)
+		}
+		os.mu.RUnlock()
+		operators = append(operators, operator)
+	}
+	return operators
+}
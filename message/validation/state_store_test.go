@@ -0,0 +1,56 @@
+package validation
+
+import (
+	"encoding/binary"
+	"testing"
+
+	spectypes "github.com/ssvlabs/ssv-spec/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ssvlabs/ssv/logging"
+	"github.com/ssvlabs/ssv/networkconfig"
+	"github.com/ssvlabs/ssv/storage/basedb"
+	"github.com/ssvlabs/ssv/storage/kv"
+)
+
+func TestStateStore(t *testing.T) {
+	db, err := kv.NewInMemory(logging.TestLogger(t), basedb.Options{})
+	require.NoError(t, err)
+	defer db.Close()
+
+	netCfg := networkconfig.TestNetwork
+	newValidator := func() *messageValidator {
+		return New(netCfg, nil, nil, nil, WithStateStore(db)).(*messageValidator)
+	}
+
+	slot := netCfg.Beacon.EstimatedCurrentSlot()
+	epoch := netCfg.Beacon.EstimatedEpochAtSlot(slot)
+	msgID := spectypes.NewMsgID(netCfg.DomainType(), []byte("validator"), spectypes.RoleProposer)
+
+	validator := newValidator()
+	signerState := NewSignerState(slot, 2)
+	signerState.MessageCounts.Prepare = 1
+	signerState.ProposalData = []byte("data")
+	encodedOperators, err := encodeOperators([]spectypes.OperatorID{1, 2, 3})
+	require.NoError(t, err)
+	signerState.SeenSigners[encodedOperators] = struct{}{}
+	operatorState := validator.consensusState(msgID).GetOrCreate(1)
+	operatorState.Set(slot-1, epoch, NewSignerState(slot-1, 1))
+	operatorState.Set(slot, epoch, signerState)
+	require.NoError(t, validator.SaveState())
+
+	restored := newValidator()
+	require.NoError(t, restored.RestoreState())
+	restoredState := restored.consensusState(msgID).GetOrCreate(1)
+	require.Equal(t, slot, restoredState.MaxSlot())
+	require.Equal(t, uint64(2), restoredState.DutyCount(epoch))
+	require.Equal(t, signerState, restoredState.Get(slot))
+	require.Equal(t, slot-1, restoredState.Get(slot-1).Slot)
+
+	// Stale state is not restored.
+	staleSlot := slot - restored.storedSlotCount()
+	require.NoError(t, db.Set(stateMetaPrefix, savedSlotKey, binary.BigEndian.AppendUint64(nil, uint64(staleSlot))))
+	restored = newValidator()
+	require.NoError(t, restored.RestoreState())
+	require.Nil(t, restored.consensusState(msgID).GetOrCreate(1).Get(slot))
+}
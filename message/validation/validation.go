@@ -24,6 +24,7 @@ import (
 	"github.com/ssvlabs/ssv/operator/duties/dutystore"
 	"github.com/ssvlabs/ssv/protocol/v2/ssv/queue"
 	"github.com/ssvlabs/ssv/registry/storage"
+	"github.com/ssvlabs/ssv/storage/basedb"
 )
 
 // MessageValidator defines methods for validating pubsub messages.
@@ -49,6 +50,16 @@ type messageValidator struct {
 
 	selfPID    peer.ID
 	selfAccept bool
+
+	// ruleModes are the modes of rules by name. Rules without a mode have defaultRuleMode.
+	ruleModes map[string]RuleMode
+	// defaultRuleMode is the mode of rules without a mode, RuleModeEnforce unless set.
+	defaultRuleMode RuleMode
+
+	// stateDB is where the state is persisted, if set.
+	stateDB basedb.Database
+	// saveStateMu serializes saving the state, which replaces the saved state in several writes.
+	saveStateMu sync.Mutex
 }
 
 // New returns a new MessageValidator with the given network configuration and options.
@@ -68,6 +79,7 @@ func New(
 		validatorStore:      validatorStore,
 		dutyStore:           dutyStore,
 		signatureVerifier:   signatureVerifier,
+		defaultRuleMode:     RuleModeEnforce,
 	}
 
 	for _, opt := range opts {
@@ -180,7 +192,9 @@ func (mv *messageValidator) committeeChecks(signedSSVMessage *spectypes.SignedSS
 		e := ErrIncorrectTopic
 		e.got = fmt.Sprintf("topic %v / base name %v", topic, topicBaseName)
 		e.want = messageTopics
-		return e
+		if err := mv.violation(e); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -251,7 +265,9 @@ func (mv *messageValidator) getCommitteeAndValidatorIndices(msgID spectypes.Mess
 	if !validator.IsAttesting(mv.netCfg.Beacon.EstimatedCurrentEpoch()) {
 		e := ErrValidatorNotAttesting
 		e.got = validator.BeaconMetadata.Status.String()
-		return CommitteeInfo{}, e
+		if err := mv.violation(e); err != nil {
+			return CommitteeInfo{}, err
+		}
 	}
 
 	var operators []spectypes.OperatorID
@@ -278,7 +294,7 @@ func (mv *messageValidator) consensusState(messageID spectypes.MessageID) *conse
 	if _, ok := mv.consensusStateIndex[id]; !ok {
 		cs := &consensusState{
 			state:           make(map[spectypes.OperatorID]*OperatorState),
-			storedSlotCount: phase0.Slot(mv.netCfg.Beacon.SlotsPerEpoch()) * 2, // store last two epochs to calculate duty count
+			storedSlotCount: mv.storedSlotCount(),
 		}
 		mv.consensusStateIndex[id] = cs
 	}
@@ -286,6 +302,11 @@ func (mv *messageValidator) consensusState(messageID spectypes.MessageID) *conse
 	return mv.consensusStateIndex[id]
 }
 
+// storedSlotCount returns the number of recent slots whose state is kept.
+func (mv *messageValidator) storedSlotCount() phase0.Slot {
+	return phase0.Slot(mv.netCfg.Beacon.SlotsPerEpoch()) * 2 // store last two epochs to calculate duty count
+}
+
 func (mv *messageValidator) reportPubSubMetrics(pmsg *pubsub.Message) (done func()) {
 	mv.metrics.ActiveMsgValidation(pmsg.GetTopic())
 	mv.metrics.MessagesReceivedFromPeer(pmsg.ReceivedFrom)
@@ -1475,6 +1475,31 @@ func Test_ValidateSSVMessage(t *testing.T) {
 		require.ErrorContains(t, err, ErrIncorrectTopic.Error())
 	})
 
+	// Receive a message with an incorrect topic while the rule is in log-only mode
+	t.Run("incorrect topic in log-only mode", func(t *testing.T) {
+		validator := New(netCfg, validatorStore, dutyStore, signatureVerifier,
+			WithRuleModes(map[string]RuleMode{ErrIncorrectTopic.Rule(): RuleModeLogOnly}),
+		).(*messageValidator)
+
+		slot := netCfg.Beacon.FirstSlotAtEpoch(1)
+
+		signedSSVMessage := generateSignedMessage(ks, committeeIdentifier, slot)
+
+		receivedAt := netCfg.Beacon.GetSlotStartTime(slot)
+		_, err = validator.handleSignedSSVMessage(signedSSVMessage, "incorrect", receivedAt)
+		require.NoError(t, err)
+	})
+
+	// Rejected rules in ignore mode only ignore messages
+	t.Run("rejected rule in ignore mode", func(t *testing.T) {
+		validator := New(netCfg, validatorStore, dutyStore, signatureVerifier,
+			WithRuleModes(map[string]RuleMode{ErrZeroRound.Rule(): RuleModeIgnore}),
+		).(*messageValidator)
+
+		require.Equal(t, pubsub.ValidationIgnore, validator.handleValidationError("", nil, ErrZeroRound))
+		require.Equal(t, pubsub.ValidationReject, validator.handleValidationError("", nil, ErrNoSigners))
+	})
+
 	// Receive nil signed ssv message
 	t.Run("nil signed ssv message", func(t *testing.T) {
 		validator := New(netCfg, validatorStore, dutyStore, signatureVerifier).(*messageValidator)
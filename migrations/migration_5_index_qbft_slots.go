@@ -0,0 +1,27 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	ibftstorage "github.com/ssvlabs/ssv/ibft/storage"
+	"github.com/ssvlabs/ssv/logging/fields"
+)
+
+// migration_5_index_qbft_slots indexes the decided instances and participants saved so far by slot,
+// so that the retention pruning reaches them.
+var migration_5_index_qbft_slots = Migration{
+	Name: "migration_5_index_qbft_slots",
+	Run: func(ctx context.Context, logger *zap.Logger, opt Options, key []byte, completed CompletedFunc) error {
+		for _, role := range ibftstorage.Roles {
+			indexed, err := ibftstorage.New(opt.Db, role.String()).IndexSlots()
+			if err != nil {
+				return fmt.Errorf("failed to index %s instances: %w", role, err)
+			}
+			logger.Debug("indexed decided instances by slot", zap.Stringer("role", role), fields.Count(indexed))
+		}
+		return completed(opt.Db)
+	},
+}
@@ -58,6 +58,14 @@ var (
 		Name: "ssv_execution_client_last_fetched_block",
 		Help: "Last fetched block by execution client",
 	})
+	executionClientInconsistencies = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "ssv_execution_client_inconsistencies",
+		Help: "Count of execution client endpoints found on a different fork than previously seen blocks",
+	})
+	registryReorgs = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "ssv_registry_reorgs",
+		Help: "Count of reorgs of processed registry event blocks which were rolled back",
+	})
 	validatorStatus = promauto.NewGaugeVec(prometheus.GaugeOpts{
 		Name: "ssv:validator:v2:status",
 		Help: "Validator status",
@@ -78,6 +86,10 @@ var (
 		Name: "ssv_message_validation",
 		Help: "Message validation result",
 	}, []string{"status", "reason", "role", "round"})
+	messageValidationRuleViolations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssv_message_validation_rule_violations",
+		Help: "Count of messages violating each message validation rule, by the rule's mode",
+	}, []string{"rule", "mode"})
 	messageValidationSSVType = promauto.NewCounterVec(prometheus.CounterOpts{
 		Name: "ssv_message_validation_ssv_type",
 		Help: "SSV message type",
@@ -96,6 +108,16 @@ var (
 		Help:    "Signature validation duration (seconds)",
 		Buckets: []float64{0.001, 0.005, 0.010, 0.020, 0.050},
 	}, []string{})
+	signatureVerificationBatchSize = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "ssv_signature_verification_batch_size",
+		Help:    "Number of signature verifications in a batch",
+		Buckets: []float64{1, 2, 4, 8, 16, 32, 64, 128, 256, 512},
+	}, []string{})
+	signatureVerificationBatchDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "ssv_signature_verification_batch_duration_seconds",
+		Help:    "Signature verification batch duration (seconds)",
+		Buckets: []float64{0.001, 0.005, 0.010, 0.020, 0.050, 0.100},
+	}, []string{})
 	messageSize = promauto.NewHistogramVec(prometheus.HistogramOpts{
 		Name:    "ssv_message_size",
 		Help:    "Message size",
@@ -117,6 +139,14 @@ var (
 		Name: "ssv_message_queue_drops",
 		Help: "The amount of message dropped from the validator's msg queue",
 	}, []string{"msg_id"})
+	queueMessageDrops = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssv_queue_message_drops",
+		Help: "The amount of messages dropped from committee and validator queues, either when pushed to a full queue or when evicted",
+	}, []string{"committee", "role", "kind", "evicted"})
+	queueDepth = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ssv_queue_depth",
+		Help: "Number of messages in committee and validator queues",
+	}, []string{"committee", "role"})
 	messageQueueSize = promauto.NewGaugeVec(prometheus.GaugeOpts{
 		Name: "ssv_message_queue_size",
 		Help: "Size of message queue",
@@ -169,6 +199,7 @@ type MetricsReporter interface {
 	ExecutionClientSyncing()
 	ExecutionClientFailure()
 	ExecutionClientLastFetchedBlock(block uint64)
+	ExecutionClientInconsistent()
 	OperatorPublicKey(operatorID spectypes.OperatorID, publicKey []byte)
 	ValidatorInactive(publicKey []byte)
 	ValidatorNoIndex(publicKey []byte)
@@ -188,20 +219,25 @@ type MetricsReporter interface {
 	MessageValidationRSAVerifications()
 	LastBlockProcessed(block uint64)
 	LogsProcessingError(err error)
+	RegistryReorged()
 	MessageAccepted(role spectypes.RunnerRole, round specqbft.Round)
 	MessageIgnored(reason string, role spectypes.RunnerRole, round specqbft.Round)
 	MessageRejected(reason string, role spectypes.RunnerRole, round specqbft.Round)
+	MessageValidationRuleViolation(rule string, mode string)
 	SSVMessageType(msgType spectypes.MsgType)
 	GenesisSSVMessageType(msgType genesisspectypes.MsgType)
 	ConsensusMsgType(msgType specqbft.MessageType, signers int)
 	MessageValidationDuration(duration time.Duration, labels ...string)
 	SignatureValidationDuration(duration time.Duration, labels ...string)
+	SignatureVerificationBatch(size int, duration time.Duration)
 	MessageSize(size int)
 	ActiveMsgValidation(topic string)
 	ActiveMsgValidationDone(topic string)
 	IncomingQueueMessage(messageID spectypes.MessageID)
 	OutgoingQueueMessage(messageID spectypes.MessageID)
 	DroppedQueueMessage(messageID spectypes.MessageID)
+	QueueMessageDropped(committee, role, kind string, evicted bool)
+	QueueDepth(committee, role string, depth int)
 	MessageQueueSize(size int)
 	MessageQueueCapacity(size int)
 	MessageTimeInQueue(messageID spectypes.MessageID, d time.Duration)
@@ -236,15 +272,20 @@ func New(opts ...Option) MetricsReporter {
 		eventProcessingFailed,
 		operatorIndex,
 		messageValidationResult,
+		messageValidationRuleViolations,
 		messageValidationSSVType,
 		messageValidationConsensusType,
 		messageValidationDuration,
 		signatureValidationDuration,
+		signatureVerificationBatchSize,
+		signatureVerificationBatchDuration,
 		messageSize,
 		activeMsgValidation,
 		incomingQueueMessages,
 		outgoingQueueMessages,
 		droppedQueueMessages,
+		queueMessageDrops,
+		queueDepth,
 		messageQueueSize,
 		messageQueueCapacity,
 		messageTimeInQueue,
@@ -294,6 +335,10 @@ func (m *metricsReporter) ExecutionClientLastFetchedBlock(block uint64) {
 	executionClientLastFetchedBlock.Set(float64(block))
 }
 
+func (m *metricsReporter) ExecutionClientInconsistent() {
+	executionClientInconsistencies.Inc()
+}
+
 func (m *metricsReporter) OperatorPublicKey(operatorID spectypes.OperatorID, publicKey []byte) {
 	pkHash := fmt.Sprintf("%x", sha256.Sum256(publicKey))
 	operatorIndex.WithLabelValues(pkHash, strconv.FormatUint(operatorID, 10)).Set(float64(operatorID))
@@ -357,6 +402,10 @@ func (m *metricsReporter) MessageValidationRSAVerifications() {
 func (m *metricsReporter) LastBlockProcessed(uint64) {}
 func (m *metricsReporter) LogsProcessingError(error) {}
 
+func (m *metricsReporter) RegistryReorged() {
+	registryReorgs.Inc()
+}
+
 func (m *metricsReporter) MessageAccepted(role spectypes.RunnerRole, round specqbft.Round) {
 	messageValidationResult.WithLabelValues(
 		messageAccepted,
@@ -384,6 +433,10 @@ func (m *metricsReporter) MessageRejected(reason string, role spectypes.RunnerRo
 	).Inc()
 }
 
+func (m *metricsReporter) MessageValidationRuleViolation(rule string, mode string) {
+	messageValidationRuleViolations.WithLabelValues(rule, mode).Inc()
+}
+
 func (m *metricsReporter) SSVMessageType(msgType spectypes.MsgType) {
 	messageValidationSSVType.WithLabelValues(ssvmessage.MsgTypeToString(msgType)).Inc()
 }
@@ -403,6 +456,11 @@ func (m *metricsReporter) SignatureValidationDuration(duration time.Duration, la
 	signatureValidationDuration.WithLabelValues(labels...).Observe(duration.Seconds())
 }
 
+func (m *metricsReporter) SignatureVerificationBatch(size int, duration time.Duration) {
+	signatureVerificationBatchSize.WithLabelValues().Observe(float64(size))
+	signatureVerificationBatchDuration.WithLabelValues().Observe(duration.Seconds())
+}
+
 func (m *metricsReporter) MessageSize(size int) {
 	messageSize.WithLabelValues().Observe(float64(size))
 }
@@ -427,6 +485,14 @@ func (m *metricsReporter) DroppedQueueMessage(messageID spectypes.MessageID) {
 	droppedQueueMessages.WithLabelValues(messageID.String()).Inc()
 }
 
+func (m *metricsReporter) QueueMessageDropped(committee, role, kind string, evicted bool) {
+	queueMessageDrops.WithLabelValues(committee, role, kind, strconv.FormatBool(evicted)).Inc()
+}
+
+func (m *metricsReporter) QueueDepth(committee, role string, depth int) {
+	queueDepth.WithLabelValues(committee, role).Set(float64(depth))
+}
+
 func (m *metricsReporter) MessageQueueSize(size int) {
 	messageQueueSize.WithLabelValues().Set(float64(size))
 }
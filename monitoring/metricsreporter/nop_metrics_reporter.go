@@ -22,6 +22,7 @@ func (n *nopMetrics) ExecutionClientReady()
 func (n *nopMetrics) ExecutionClientSyncing()                                             {}
 func (n *nopMetrics) ExecutionClientFailure()                                             {}
 func (n *nopMetrics) ExecutionClientLastFetchedBlock(block uint64)                        {}
+func (n *nopMetrics) ExecutionClientInconsistent()                                        {}
 func (n *nopMetrics) OperatorPublicKey(operatorID spectypes.OperatorID, publicKey []byte) {}
 func (n *nopMetrics) ValidatorInactive(publicKey []byte)                                  {}
 func (n *nopMetrics) ValidatorNoIndex(publicKey []byte)                                   {}
@@ -41,6 +42,7 @@ func (n *nopMetrics) MessagesReceivedTotal()
 func (n *nopMetrics) MessageValidationRSAVerifications()                                  {}
 func (n *nopMetrics) LastBlockProcessed(block uint64)                                     {}
 func (n *nopMetrics) LogsProcessingError(err error)                                       {}
+func (n *nopMetrics) RegistryReorged()                                                    {}
 func (n *nopMetrics) GenesisMessageAccepted(role genesisspectypes.BeaconRole, round genesisspecqbft.Round) {
 }
 func (n *nopMetrics) GenesisMessageIgnored(reason string, role genesisspectypes.BeaconRole, round genesisspecqbft.Round) {
@@ -51,17 +53,21 @@ func (n *nopMetrics) MessageAccepted(role spectypes.RunnerRole, round specqbft.R
 func (n *nopMetrics) MessageIgnored(reason string, role spectypes.RunnerRole, round specqbft.Round) {}
 func (n *nopMetrics) MessageRejected(reason string, role spectypes.RunnerRole, round specqbft.Round) {
 }
+func (n *nopMetrics) MessageValidationRuleViolation(rule string, mode string)              {}
 func (n *nopMetrics) SSVMessageType(msgType spectypes.MsgType)                             {}
 func (n *nopMetrics) GenesisSSVMessageType(msgType genesisspectypes.MsgType)               {}
 func (n *nopMetrics) ConsensusMsgType(msgType specqbft.MessageType, signers int)           {}
 func (n *nopMetrics) MessageValidationDuration(duration time.Duration, labels ...string)   {}
 func (n *nopMetrics) SignatureValidationDuration(duration time.Duration, labels ...string) {}
+func (n *nopMetrics) SignatureVerificationBatch(size int, duration time.Duration)          {}
 func (n *nopMetrics) MessageSize(size int)                                                 {}
 func (n *nopMetrics) ActiveMsgValidation(topic string)                                     {}
 func (n *nopMetrics) ActiveMsgValidationDone(topic string)                                 {}
 func (n *nopMetrics) IncomingQueueMessage(messageID spectypes.MessageID)                   {}
 func (n *nopMetrics) OutgoingQueueMessage(messageID spectypes.MessageID)                   {}
 func (n *nopMetrics) DroppedQueueMessage(messageID spectypes.MessageID)                    {}
+func (n *nopMetrics) QueueMessageDropped(committee, role, kind string, evicted bool)       {}
+func (n *nopMetrics) QueueDepth(committee, role string, depth int)                         {}
 func (n *nopMetrics) MessageQueueSize(size int)                                            {}
 func (n *nopMetrics) MessageQueueCapacity(size int)                                        {}
 func (n *nopMetrics) MessageTimeInQueue(messageID spectypes.MessageID, d time.Duration)    {}
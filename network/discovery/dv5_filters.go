@@ -23,6 +23,21 @@ func (dvs *DiscV5Service) limitNodeFilter(node *enode.Node) bool {
 //	return dvs.forkv == forkv
 //}
 
+// bannedNodeFilter returns false if the node's peer ID or IP address is banned
+func (dvs *DiscV5Service) bannedNodeFilter(node *enode.Node) bool {
+	if dvs.bans == nil {
+		return true
+	}
+	if ip := node.IP(); ip != nil && dvs.bans.IsIPBanned(ip) {
+		return false
+	}
+	pid, err := PeerID(node)
+	if err != nil {
+		return false
+	}
+	return !dvs.bans.IsPeerBanned(pid)
+}
+
 // badNodeFilter checks if the node was pruned or have a bad score
 func (dvs *DiscV5Service) badNodeFilter(logger *zap.Logger) func(node *enode.Node) bool {
 	return func(node *enode.Node) bool {
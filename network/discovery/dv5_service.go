@@ -57,6 +57,8 @@ type DiscV5Service struct {
 
 	conns      peers.ConnectionIndex
 	subnetsIdx peers.SubnetsIndex
+	bans       peers.BanIndex
+	allowList  *peers.AllowList
 
 	conn       *net.UDPConn
 	sharedConn *SharedUDPConn
@@ -74,6 +76,8 @@ func newDiscV5Service(pctx context.Context, logger *zap.Logger, discOpts *Option
 		cancel:        cancel,
 		conns:         discOpts.ConnIndex,
 		subnetsIdx:    discOpts.SubnetsIdx,
+		bans:          discOpts.BanIndex,
+		allowList:     discOpts.AllowList,
 		networkConfig: discOpts.NetworkConfig,
 		subnets:       discOpts.DiscV5Opts.Subnets,
 		publishLock:   make(chan struct{}, 1),
@@ -184,11 +188,20 @@ func (dvs *DiscV5Service) checkPeer(logger *zap.Logger, e PeerEvent) error {
 	dvs.subnetsIdx.UpdatePeerSubnets(e.AddrInfo.ID, nodeSubnets)
 
 	// Filters
+	if !dvs.allowList.IsAllowed(e.AddrInfo.ID) {
+		metricRejectedNodes.Inc()
+		return errors.New("not in allow-list")
+	}
+	if !dvs.bannedNodeFilter(e.Node) {
+		metricRejectedNodes.Inc()
+		return errors.New("banned")
+	}
 	if !dvs.limitNodeFilter(e.Node) {
 		metricRejectedNodes.Inc()
 		return errors.New("reached limit")
 	}
-	if !dvs.sharedSubnetsFilter(1)(e.Node) {
+	// Members of a private cluster are connected regardless of their subnets.
+	if dvs.allowList == nil && !dvs.sharedSubnetsFilter(1)(e.Node) {
 		metricRejectedNodes.Inc()
 		return errors.New("no shared subnets")
 	}
@@ -38,6 +38,8 @@ type Options struct {
 	DiscV5Opts    *DiscV5Options
 	ConnIndex     peers.ConnectionIndex
 	SubnetsIdx    peers.SubnetsIndex
+	BanIndex      peers.BanIndex
+	AllowList     *peers.AllowList
 	HostAddress   string
 	HostDNS       string
 	NetworkConfig networkconfig.NetworkConfig
@@ -20,6 +20,7 @@ import (
 	"github.com/ssvlabs/ssv/monitoring/metricsreporter"
 	"github.com/ssvlabs/ssv/network"
 	"github.com/ssvlabs/ssv/network/commons"
+	"github.com/ssvlabs/ssv/network/peers"
 	"github.com/ssvlabs/ssv/networkconfig"
 	operatordatastore "github.com/ssvlabs/ssv/operator/datastore"
 	"github.com/ssvlabs/ssv/operator/keys"
@@ -39,6 +40,11 @@ type Config struct {
 	Discovery    string   `yaml:"Discovery" env:"P2P_DISCOVERY" env-description:"Discovery system to use" env-default:"discv5"`
 	TrustedPeers []string `yaml:"TrustedPeers" env:"TRUSTED_PEERS" env-default:"" env-description:"List of peers to connect to."`
 
+	// PrivateCluster limits the node to peer only with AllowedPeers (and TrustedPeers),
+	// discovered through the configured Bootnodes only.
+	PrivateCluster bool     `yaml:"PrivateCluster" env:"P2P_PRIVATE_CLUSTER" env-description:"Flag to only peer with the allowed peers, e.g. the operators of a private cluster"`
+	AllowedPeers   []string `yaml:"AllowedPeers" env:"P2P_ALLOWED_PEERS" env-default:"" env-description:"ENRs, multiaddrs or peer IDs of the peers of a private cluster"`
+
 	TCPPort     uint16 `yaml:"TcpPort" env:"TCP_PORT" env-default:"13001" env-description:"TCP port for p2p transport"`
 	UDPPort     uint16 `yaml:"UdpPort" env:"UDP_PORT" env-default:"12001" env-description:"UDP port for discovery"`
 	HostAddress string `yaml:"HostAddress" env:"HOST_ADDRESS" env-description:"External ip node is exposed for discovery"`
@@ -89,6 +95,11 @@ type Config struct {
 
 	DisableIPRateLimit bool `yaml:"DisableIPRateLimit" env:"DISABLE_IP_RATE_LIMIT" default:"false" env-description:"Flag to turn on/off IP rate limiting"`
 
+	PeerScoreHalfLife time.Duration `yaml:"PeerScoreHalfLife" env:"P2P_PEER_SCORE_HALF_LIFE" env-default:"6h" env-description:"Time it takes persisted peer scores to decay by half"`
+
+	// Reputation keeps the peer scores and bans across restarts, optional (kept in memory if nil)
+	Reputation *peers.Reputation
+
 	GetValidatorStats network.GetValidatorStats
 
 	// PeerScoreInspector is called periodically to inspect the peer scores.
@@ -178,7 +189,14 @@ func (c *Config) configureAddrs(logger *zap.Logger, opts []libp2p.Option) ([]lib
 }
 
 // TransformBootnodes converts bootnodes string and convert it to slice
+// A private cluster only uses the configured bootnodes.
 func (c *Config) TransformBootnodes() []string {
+	if c.PrivateCluster {
+		if c.Bootnodes == "" {
+			return nil
+		}
+		return strings.Split(c.Bootnodes, ";")
+	}
 
 	if c.Bootnodes == "" {
 		return c.Network.Bootnodes
@@ -53,6 +53,7 @@ const (
 	peersReportingInterval             = 60 * time.Second
 	peerIdentitiesReportingInterval    = 5 * time.Minute
 	topicsReportingInterval            = 180 * time.Second
+	reputationSavingInterval           = 5 * time.Minute
 	maximumIrrelevantPeersToDisconnect = 3
 )
 
@@ -66,6 +67,11 @@ type HostProvider interface {
 	Host() host.Host
 }
 
+// StreamControllerProvider holds the stream controller instance
+type StreamControllerProvider interface {
+	StreamController() streams.StreamController
+}
+
 // p2pNetwork implements network.P2PNetwork
 type p2pNetwork struct {
 	parentCtx context.Context
@@ -104,6 +110,9 @@ type p2pNetwork struct {
 	operatorPKHashToPKCache *hashmap.Map[string, []byte] // used for metrics
 	operatorSigner          keys.OperatorSigner
 	operatorDataStore       operatordatastore.OperatorDataStore
+	reputation              *peers.Reputation
+	// allowList limits the peers to the members of a private cluster, if not nil
+	allowList *peers.AllowList
 }
 
 // New creates a new p2p network
@@ -128,10 +137,23 @@ func New(logger *zap.Logger, cfg *Config, mr Metrics) (*p2pNetwork, error) {
 		operatorSigner:          cfg.OperatorSigner,
 		operatorDataStore:       cfg.OperatorDataStore,
 		metrics:                 mr,
+		reputation:              cfg.Reputation,
+	}
+	if n.reputation == nil {
+		reputation, err := peers.NewReputation(nil, cfg.PeerScoreHalfLife)
+		if err != nil {
+			return nil, fmt.Errorf("could not create peer reputation: %w", err)
+		}
+		n.reputation = reputation
 	}
 	if err := n.parseTrustedPeers(); err != nil {
 		return nil, err
 	}
+	if cfg.PrivateCluster {
+		if err := n.setupPrivateCluster(); err != nil {
+			return nil, err
+		}
+	}
 	return n, nil
 }
 
@@ -161,6 +183,11 @@ func (n *p2pNetwork) Host() host.Host {
 	return n.host
 }
 
+// StreamController implements StreamControllerProvider
+func (n *p2pNetwork) StreamController() streams.StreamController {
+	return n.streamCtrl
+}
+
 // PeersIndex returns the peers index
 func (n *p2pNetwork) PeersIndex() peers.Index {
 	return n.idx
@@ -254,10 +281,14 @@ func (n *p2pNetwork) Start(logger *zap.Logger) error {
 	logger.Info("starting p2p",
 		zap.String("my_address", strings.Join(maStrs, ",")),
 		zap.Int("trusted_peers", len(n.trustedPeers)),
+		zap.Bool("private_cluster", n.cfg.PrivateCluster),
+		zap.Int("allowed_peers", n.allowList.Len()),
 	)
 
 	go n.startDiscovery(logger, connector)
 
+	go n.reputation.Run(n.ctx, logger, reputationSavingInterval)
+
 	async.Interval(n.ctx, connManagerBalancingInterval, n.peersBalancing(logger))
 	// don't report metrics in tests
 	if n.cfg.Metrics != nil {
@@ -288,6 +319,11 @@ func (n *p2pNetwork) peersBalancing(logger *zap.Logger) func() {
 		// Disconnect from bad peers
 		connMgr.DisconnectFromBadPeers(logger, n.host.Network(), allPeers)
 
+		// Members of a private cluster are all kept regardless of their subnets
+		if n.allowList != nil {
+			return
+		}
+
 		// Check if it has the maximum number of connections
 		currentCount := len(allPeers)
 		if currentCount < n.cfg.MaxPeers {
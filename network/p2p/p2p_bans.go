@@ -0,0 +1,69 @@
+package p2pv1
+
+import (
+	manet "github.com/multiformats/go-multiaddr/net"
+	"go.uber.org/zap"
+
+	"github.com/ssvlabs/ssv/logging/fields"
+	"github.com/ssvlabs/ssv/network/peers"
+)
+
+// PeerBanner bans peer IDs, IP addresses and CIDR ranges
+type PeerBanner interface {
+	// Bans returns the active bans
+	Bans() []peers.Ban
+	// Ban bans the given target and disconnects from the peers it matches
+	Ban(target, reason string) (peers.Ban, error)
+	// Unban removes the ban of the given target, returning false if it wasn't banned
+	Unban(target string) (bool, error)
+}
+
+// Bans implements PeerBanner
+func (n *p2pNetwork) Bans() []peers.Ban {
+	return n.reputation.Bans()
+}
+
+// Ban implements PeerBanner
+func (n *p2pNetwork) Ban(target, reason string) (peers.Ban, error) {
+	ban, err := n.reputation.Ban(target, reason)
+	if err != nil {
+		return peers.Ban{}, err
+	}
+	n.interfaceLogger.Info("banned peer", zap.String("target", ban.Target), zap.String("reason", ban.Reason))
+	if n.host != nil {
+		n.disconnectBannedPeers()
+	}
+	return ban, nil
+}
+
+// Unban implements PeerBanner
+func (n *p2pNetwork) Unban(target string) (bool, error) {
+	unbanned, err := n.reputation.Unban(target)
+	if err != nil {
+		return false, err
+	}
+	if unbanned {
+		n.interfaceLogger.Info("unbanned peer", zap.String("target", target))
+	}
+	return unbanned, nil
+}
+
+// disconnectBannedPeers closes the connections with banned peers and IP addresses
+func (n *p2pNetwork) disconnectBannedPeers() {
+	net := n.host.Network()
+	for _, conn := range net.Conns() {
+		id := conn.RemotePeer()
+		banned := n.reputation.IsPeerBanned(id)
+		if !banned {
+			if ip, err := manet.ToIP(conn.RemoteMultiaddr()); err == nil {
+				banned = n.reputation.IsIPBanned(ip)
+			}
+		}
+		if !banned {
+			continue
+		}
+		if err := net.ClosePeer(id); err != nil {
+			n.interfaceLogger.Debug("could not disconnect from banned peer", fields.PeerID(id), zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package p2pv1
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/pkg/errors"
+
+	"github.com/ssvlabs/ssv/network/discovery"
+	"github.com/ssvlabs/ssv/network/peers"
+)
+
+// parseAllowedPeers parses the allowed peers of a private cluster,
+// which are ENRs, multiaddrs ending with a peer ID, or bare peer IDs.
+// It returns the peer IDs and the addresses of the peers whose addresses are known.
+func parseAllowedPeers(entries []string) ([]peer.ID, []peer.AddrInfo, error) {
+	var ids []peer.ID
+	var static []peer.AddrInfo
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		switch {
+		case entry == "":
+			continue
+		case strings.HasPrefix(entry, "enr:"):
+			nodes, err := discovery.ParseENR(enode.ValidSchemes, true, entry)
+			if err != nil {
+				return nil, nil, fmt.Errorf("could not parse allowed peer ENR: %w", err)
+			}
+			addrInfo, err := discovery.ToPeer(nodes[0])
+			if err != nil {
+				return nil, nil, fmt.Errorf("could not parse allowed peer ENR: %w", err)
+			}
+			ids = append(ids, addrInfo.ID)
+			static = append(static, *addrInfo)
+		case strings.HasPrefix(entry, "/"):
+			addrInfo, err := peer.AddrInfoFromString(entry)
+			if err != nil {
+				return nil, nil, fmt.Errorf("could not parse allowed peer multiaddr: %w", err)
+			}
+			ids = append(ids, addrInfo.ID)
+			static = append(static, *addrInfo)
+		default:
+			id, err := peer.Decode(entry)
+			if err != nil {
+				return nil, nil, fmt.Errorf("could not parse allowed peer ID: %w", err)
+			}
+			ids = append(ids, id)
+		}
+	}
+	return ids, static, nil
+}
+
+// setupPrivateCluster limits the peers to the allowed and trusted peers,
+// and connects to the allowed peers whose addresses are known like to trusted peers.
+func (n *p2pNetwork) setupPrivateCluster() error {
+	ids, static, err := parseAllowedPeers(n.cfg.AllowedPeers)
+	if err != nil {
+		return err
+	}
+	trusted := make(map[peer.ID]struct{}, len(n.trustedPeers))
+	for _, addrInfo := range n.trustedPeers {
+		ids = append(ids, addrInfo.ID)
+		trusted[addrInfo.ID] = struct{}{}
+	}
+	if len(ids) == 0 {
+		return errors.New("private cluster requires allowed or trusted peers")
+	}
+	for i := range static {
+		if _, ok := trusted[static[i].ID]; !ok {
+			n.trustedPeers = append(n.trustedPeers, &static[i])
+			trusted[static[i].ID] = struct{}{}
+		}
+	}
+	n.allowList = peers.NewAllowList(ids...)
+	return nil
+}
@@ -0,0 +1,68 @@
+package p2pv1
+
+import (
+	"net"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/p2p/enr"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ssvlabs/ssv/network/discovery"
+)
+
+func TestParseAllowedPeers(t *testing.T) {
+	sk, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	var record enr.Record
+	record.Set(enr.IPv4(net.IPv4(10, 0, 0, 1)))
+	record.Set(enr.TCP(13001))
+	record.Set(enr.UDP(12001))
+	require.NoError(t, enode.SignV4(&record, sk))
+	node, err := enode.New(enode.ValidSchemes, &record)
+	require.NoError(t, err)
+	enrID, err := discovery.PeerID(node)
+	require.NoError(t, err)
+
+	const (
+		multiaddrID = "16Uiu2HAkvaBh2xjstjs1koEx3jpBn5Hsnz7Bv8pE4SuwFySkiAuf"
+		bareID      = "16Uiu2HAm8DvHBbJMmHoWXzdGysLbDRCbMjvRmHuTTZb4h1hMUPh6"
+	)
+	ids, static, err := parseAllowedPeers([]string{
+		node.String(),
+		"/ip4/10.0.0.2/tcp/13001/p2p/" + multiaddrID,
+		" " + bareID,
+		"",
+	})
+	require.NoError(t, err)
+	require.Len(t, ids, 3)
+	require.Equal(t, enrID, ids[0])
+	require.Equal(t, multiaddrID, ids[1].String())
+	require.Equal(t, bareID, ids[2].String())
+	require.Len(t, static, 2)
+	require.Equal(t, "/ip4/10.0.0.1/tcp/13001", static[0].Addrs[0].String())
+	require.Equal(t, "/ip4/10.0.0.2/tcp/13001", static[1].Addrs[0].String())
+
+	_, _, err = parseAllowedPeers([]string{"enr:-invalid"})
+	require.ErrorContains(t, err, "could not parse allowed peer ENR")
+	_, _, err = parseAllowedPeers([]string{"not-a-peer"})
+	require.ErrorContains(t, err, "could not parse allowed peer ID")
+
+	n := &p2pNetwork{cfg: &Config{
+		PrivateCluster: true,
+		AllowedPeers:   []string{node.String(), bareID},
+		TrustedPeers:   []string{"/ip4/10.0.0.2/tcp/13001/p2p/" + multiaddrID},
+	}}
+	require.NoError(t, n.parseTrustedPeers())
+	require.NoError(t, n.setupPrivateCluster())
+	require.Equal(t, 3, n.allowList.Len())
+	require.True(t, n.allowList.IsAllowed(enrID))
+	require.True(t, n.allowList.IsAllowed(ids[1]))
+	require.False(t, n.allowList.IsAllowed(peer.ID("other")))
+	require.Len(t, n.trustedPeers, 2)
+
+	n = &p2pNetwork{cfg: &Config{PrivateCluster: true}}
+	require.ErrorContains(t, n.setupPrivateCluster(), "requires allowed or trusted peers")
+}
@@ -128,7 +128,7 @@ func (n *p2pNetwork) SetupHost(logger *zap.Logger) error {
 	if err != nil {
 		return errors.Wrap(err, "could not create resource manager")
 	}
-	n.connGater = connections.NewConnectionGater(logger, n.cfg.DisableIPRateLimit, n.connectionsAtLimit, n.IsBadPeer)
+	n.connGater = connections.NewConnectionGater(logger, n.cfg.DisableIPRateLimit, n.connectionsAtLimit, n.IsBadPeer, n.reputation, n.allowList)
 	opts = append(opts, libp2p.ResourceManager(rmgr), libp2p.ConnectionGater(n.connGater))
 	host, err := libp2p.New(opts...)
 	if err != nil {
@@ -187,7 +187,8 @@ func (n *p2pNetwork) setupPeerServices(logger *zap.Logger) error {
 		return libPrivKey
 	}
 
-	n.idx = peers.NewPeersIndex(logger, n.host.Network(), self, n.getMaxPeers, getPrivKey, p2pcommons.Subnets(), 10*time.Minute, peers.NewGossipScoreIndex())
+	n.idx = peers.NewPeersIndex(logger, n.host.Network(), self, n.getMaxPeers, getPrivKey, p2pcommons.Subnets(), 10*time.Minute,
+		peers.NewGossipScoreIndex(peers.WithReputation(n.reputation)), n.reputation)
 	logger.Debug("peers index is ready")
 
 	var ids identify.IDService
@@ -230,7 +231,7 @@ func (n *p2pNetwork) setupPeerServices(logger *zap.Logger) error {
 	n.host.SetStreamHandler(peers.NodeInfoProtocol, handshaker.Handler(logger))
 	logger.Debug("handshaker is ready")
 
-	n.connHandler = connections.NewConnHandler(n.ctx, handshaker, subnetsProvider, n.idx, n.idx, n.idx, n.metrics)
+	n.connHandler = connections.NewConnHandler(n.ctx, handshaker, subnetsProvider, n.idx, n.idx, n.idx, n.allowList, n.metrics)
 	n.host.Network().Notify(n.connHandler.Handle(logger))
 	logger.Debug("connection handler is ready")
 
@@ -268,6 +269,8 @@ func (n *p2pNetwork) setupDiscovery(logger *zap.Logger) error {
 		DiscV5Opts:    discV5Opts,
 		ConnIndex:     n.idx,
 		SubnetsIdx:    n.idx,
+		BanIndex:      n.reputation,
+		AllowList:     n.allowList,
 		HostAddress:   n.cfg.HostAddress,
 		HostDNS:       n.cfg.HostDNS,
 		NetworkConfig: n.cfg.Network,
@@ -309,6 +312,15 @@ func (n *p2pNetwork) setupPubsub(logger *zap.Logger) error {
 		cfg.ScoreIndex = nil
 	}
 
+	// A private cluster is a small mesh of trusted peers, so they're direct peers which
+	// always get our messages, and aren't scored so they can't be pruned or graylisted.
+	if n.allowList != nil {
+		cfg.ScoreIndex = nil
+		for _, addrInfo := range n.trustedPeers {
+			cfg.StaticPeers = append(cfg.StaticPeers, *addrInfo)
+		}
+	}
+
 	midHandler := topics.NewMsgIDHandler(n.ctx, n.cfg.Network, time.Minute*2)
 	n.msgResolver = midHandler
 	cfg.MsgIDHandler = midHandler
@@ -0,0 +1,37 @@
+package peers
+
+import (
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// AllowList is the set of the only peers which are allowed to connect, e.g. the members of a private cluster.
+// A nil AllowList allows every peer.
+type AllowList struct {
+	ids map[peer.ID]struct{}
+}
+
+// NewAllowList returns an AllowList of the given peers.
+func NewAllowList(ids ...peer.ID) *AllowList {
+	a := &AllowList{ids: make(map[peer.ID]struct{}, len(ids))}
+	for _, id := range ids {
+		a.ids[id] = struct{}{}
+	}
+	return a
+}
+
+// IsAllowed returns whether the given peer is allowed to connect.
+func (a *AllowList) IsAllowed(id peer.ID) bool {
+	if a == nil {
+		return true
+	}
+	_, ok := a.ids[id]
+	return ok
+}
+
+// Len returns the number of allowed peers.
+func (a *AllowList) Len() int {
+	if a == nil {
+		return 0
+	}
+	return len(a.ids)
+}
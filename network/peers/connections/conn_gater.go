@@ -12,8 +12,10 @@ import (
 	ma "github.com/multiformats/go-multiaddr"
 	manet "github.com/multiformats/go-multiaddr/net"
 	leakybucket "github.com/prysmaticlabs/prysm/v4/container/leaky-bucket"
-	"github.com/ssvlabs/ssv/logging/fields"
 	"go.uber.org/zap"
+
+	"github.com/ssvlabs/ssv/logging/fields"
+	"github.com/ssvlabs/ssv/network/peers"
 )
 
 const (
@@ -35,16 +37,22 @@ type connGater struct {
 	atLimit   func() bool
 	ipLimiter *leakybucket.Collector
 	isBadPeer BadPeerF
+	bans      peers.BanIndex
+	allowList *peers.AllowList
 }
 
-// NewConnectionGater creates a new instance of ConnectionGater
-func NewConnectionGater(logger *zap.Logger, disable bool, atLimit func() bool, isBadPeerF BadPeerF) connmgr.ConnectionGater {
+// NewConnectionGater creates a new instance of ConnectionGater.
+// Connections with peers or IP addresses banned in the given index (if not nil) are blocked,
+// and so are connections with peers outside the given allow-list (if not nil).
+func NewConnectionGater(logger *zap.Logger, disable bool, atLimit func() bool, isBadPeerF BadPeerF, bans peers.BanIndex, allowList *peers.AllowList) connmgr.ConnectionGater {
 	return &connGater{
 		logger:    logger,
 		disable:   disable,
 		atLimit:   atLimit,
 		ipLimiter: leakybucket.NewCollector(ipLimitRate, ipLimitBurst, ipLimitPeriod, true),
 		isBadPeer: isBadPeerF,
+		bans:      bans,
+		allowList: allowList,
 	}
 }
 
@@ -52,6 +60,14 @@ func NewConnectionGater(logger *zap.Logger, disable bool, atLimit func() bool, i
 // to the addresses of that peer being available/resolved. Blocking connections
 // at this stage is typical for blacklisting scenarios
 func (n *connGater) InterceptPeerDial(id peer.ID) bool {
+	if !n.allowList.IsAllowed(id) {
+		n.logger.Debug("preventing outbound connection due to peer not in allow-list", fields.PeerID(id))
+		return false
+	}
+	if n.bans != nil && n.bans.IsPeerBanned(id) {
+		n.logger.Debug("preventing outbound connection due to banned peer", fields.PeerID(id))
+		return false
+	}
 	return true
 }
 
@@ -59,10 +75,18 @@ func (n *connGater) InterceptPeerDial(id peer.ID) bool {
 // particular address. Blocking connections at this stage is typical for
 // address filtering.
 func (n *connGater) InterceptAddrDial(id peer.ID, multiaddr ma.Multiaddr) bool {
+	if !n.allowList.IsAllowed(id) {
+		n.logger.Debug("preventing outbound connection due to peer not in allow-list", fields.PeerID(id))
+		return false
+	}
 	if n.isBadPeer(n.logger, id) {
 		n.logger.Debug("preventing outbound connection due to bad peer", fields.PeerID(id))
 		return false
 	}
+	if n.isBannedAddr(multiaddr) {
+		n.logger.Debug("preventing outbound connection due to banned IP", fields.PeerID(id), zap.String("addr", multiaddr.String()))
+		return false
+	}
 	return true
 }
 
@@ -71,10 +95,14 @@ func (n *connGater) InterceptAddrDial(id peer.ID, multiaddr ma.Multiaddr) bool {
 // accept already secure and/or multiplexed connections (e.g. possibly QUIC)
 // MUST call this method regardless, for correctness/consistency.
 func (n *connGater) InterceptAccept(multiaddrs libp2pnetwork.ConnMultiaddrs) bool {
+	remoteAddr := multiaddrs.RemoteMultiaddr()
+	if n.isBannedAddr(remoteAddr) {
+		n.logger.Debug("connection rejected due to banned IP", zap.String("remote_addr", remoteAddr.String()))
+		return false
+	}
 	if n.disable {
 		return true
 	}
-	remoteAddr := multiaddrs.RemoteMultiaddr()
 	if !n.validateDial(remoteAddr) {
 		// Yield this goroutine to allow others to run in-between connection attempts.
 		runtime.Gosched()
@@ -88,6 +116,10 @@ func (n *connGater) InterceptAccept(multiaddrs libp2pnetwork.ConnMultiaddrs) boo
 // InterceptSecured is called for both inbound and outbound connections,
 // after a security handshake has taken place and we've authenticated the peer.
 func (n *connGater) InterceptSecured(direction libp2pnetwork.Direction, id peer.ID, multiaddrs libp2pnetwork.ConnMultiaddrs) bool {
+	if !n.allowList.IsAllowed(id) {
+		n.logger.Debug("rejecting connection due to peer not in allow-list", fields.PeerID(id))
+		return false
+	}
 	if n.isBadPeer(n.logger, id) {
 		n.logger.Debug("rejecting inbound connection due to bad peer", fields.PeerID(id))
 		return false
@@ -102,6 +134,18 @@ func (n *connGater) InterceptUpgraded(conn libp2pnetwork.Conn) (bool, control.Di
 	return true, 0
 }
 
+// isBannedAddr returns whether the IP address of the given multiaddr is banned.
+func (n *connGater) isBannedAddr(addr multiaddr.Multiaddr) bool {
+	if n.bans == nil {
+		return false
+	}
+	ip, err := manet.ToIP(addr)
+	if err != nil {
+		return false
+	}
+	return n.bans.IsIPBanned(ip)
+}
+
 func (n *connGater) validateDial(addr multiaddr.Multiaddr) bool {
 	ip, err := manet.ToIP(addr)
 	if err != nil {
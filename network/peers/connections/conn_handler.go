@@ -31,10 +31,12 @@ type connHandler struct {
 	subnetsIndex    peers.SubnetsIndex
 	connIdx         peers.ConnectionIndex
 	peerInfos       peers.PeerInfoIndex
+	allowList       *peers.AllowList
 	metrics         Metrics
 }
 
-// NewConnHandler creates a new connection handler
+// NewConnHandler creates a new connection handler.
+// If allowList isn't nil, its peers are kept regardless of their subnets.
 func NewConnHandler(
 	ctx context.Context,
 	handshaker Handshaker,
@@ -42,6 +44,7 @@ func NewConnHandler(
 	subnetsIndex peers.SubnetsIndex,
 	connIdx peers.ConnectionIndex,
 	peerInfos peers.PeerInfoIndex,
+	allowList *peers.AllowList,
 	mr Metrics,
 ) ConnHandler {
 	return &connHandler{
@@ -51,6 +54,7 @@ func NewConnHandler(
 		subnetsIndex:    subnetsIndex,
 		connIdx:         connIdx,
 		peerInfos:       peerInfos,
+		allowList:       allowList,
 		metrics:         mr,
 	}
 }
@@ -135,7 +139,7 @@ func (ch *connHandler) Handle(logger *zap.Logger) *libp2pnetwork.NotifyBundle {
 				}
 			}
 
-			if !ch.sharesEnoughSubnets(logger, conn) {
+			if ch.allowList == nil && !ch.sharesEnoughSubnets(logger, conn) {
 				return errors.New("peer doesn't share enough subnets")
 			}
 			return nil
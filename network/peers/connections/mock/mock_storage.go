@@ -116,6 +116,11 @@ func (m NodeStorage) DeleteRecipientData(txn basedb.ReadWriter, owner common.Add
 	panic("implement me")
 }
 
+func (m NodeStorage) ListRecipients(r basedb.Reader) ([]registrystorage.RecipientData, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
 func (m NodeStorage) GetRecipientsPrefix() []byte {
 	//TODO implement me
 	panic("implement me")
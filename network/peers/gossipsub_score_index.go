@@ -7,22 +7,41 @@ import (
 	"github.com/ssvlabs/ssv/network/topics/params"
 )
 
+// gossipScoreName is the name the gossip scores are kept by in the Reputation.
+const gossipScoreName = "gossip"
+
 // Implements GossipScoreIndex
 type gossipScoreIndex struct {
 	score map[peer.ID]float64
 	mutex sync.RWMutex
 
 	graylistThreshold float64
+	reputation        *Reputation
 }
 
-func NewGossipScoreIndex() *gossipScoreIndex {
+// GossipScoreIndexOption configures a gossipScoreIndex.
+type GossipScoreIndexOption func(*gossipScoreIndex)
+
+// WithReputation keeps the gossip scores across restarts in the given Reputation.
+// Since gossipsub scores start over on every run, the decayed score of the previous runs is added to them.
+func WithReputation(reputation *Reputation) GossipScoreIndexOption {
+	return func(g *gossipScoreIndex) {
+		g.reputation = reputation
+	}
+}
+
+func NewGossipScoreIndex(opts ...GossipScoreIndexOption) *gossipScoreIndex {
 
 	graylistThreshold := params.PeerScoreThresholds().GraylistThreshold
 
-	return &gossipScoreIndex{
+	g := &gossipScoreIndex{
 		score:             make(map[peer.ID]float64),
 		graylistThreshold: graylistThreshold,
 	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
 }
 
 func (g *gossipScoreIndex) GetGossipScore(peerID peer.ID) (float64, bool) {
@@ -32,6 +51,9 @@ func (g *gossipScoreIndex) GetGossipScore(peerID peer.ID) (float64, bool) {
 	if score, exists := g.score[peerID]; exists {
 		return score, true
 	}
+	if g.reputation != nil {
+		return g.reputation.Score(peerID, gossipScoreName)
+	}
 	return 0.0, false
 }
 
@@ -42,6 +64,10 @@ func (g *gossipScoreIndex) SetScores(peerScores map[peer.ID]float64) {
 	g.clear()
 	// Copy the map
 	for peerID, score := range peerScores {
+		if g.reputation != nil {
+			score += g.reputation.Baseline(peerID, gossipScoreName)
+			g.reputation.SetScore(peerID, gossipScoreName, score)
+		}
 		g.score[peerID] = score
 	}
 }
@@ -36,15 +36,18 @@ type peersIndex struct {
 	maxPeers MaxPeersProvider
 
 	gossipScoreIndex GossipScoreIndex
+	reputation       *Reputation
 }
 
-// NewPeersIndex creates a new Index
+// NewPeersIndex creates a new Index.
+// The given reputation, if not nil, keeps the scores across restarts and tells which peers are banned.
 func NewPeersIndex(logger *zap.Logger, network libp2pnetwork.Network, self *records.NodeInfo, maxPeers MaxPeersProvider,
-	netKeyProvider NetworkKeyProvider, subnetsCount int, pruneTTL time.Duration, gossipScoreIndex GossipScoreIndex) *peersIndex {
+	netKeyProvider NetworkKeyProvider, subnetsCount int, pruneTTL time.Duration, gossipScoreIndex GossipScoreIndex,
+	reputation *Reputation) *peersIndex {
 
 	return &peersIndex{
 		network:          network,
-		scoreIdx:         newScoreIndex(),
+		scoreIdx:         newScoreIndex(reputation),
 		SubnetsIndex:     NewSubnetsIndex(subnetsCount),
 		PeerInfoIndex:    NewPeerInfoIndex(),
 		self:             self,
@@ -52,15 +55,21 @@ func NewPeersIndex(logger *zap.Logger, network libp2pnetwork.Network, self *reco
 		maxPeers:         maxPeers,
 		netKeyProvider:   netKeyProvider,
 		gossipScoreIndex: gossipScoreIndex,
+		reputation:       reputation,
 	}
 }
 
 // IsBad returns whether the given peer is bad.
 // a peer is considered to be bad if one of the following applies:
+// - banned
 // - bad gossip score
 // - pruned (that was not expired)
 // - bad score
 func (pi *peersIndex) IsBad(logger *zap.Logger, id peer.ID) bool {
+	if pi.reputation != nil && pi.reputation.IsPeerBanned(id) {
+		logger.Debug("bad peer (banned)")
+		return true
+	}
 	if isBad, _ := pi.HasBadGossipScore(id); isBad {
 		return true
 	}
@@ -0,0 +1,386 @@
+package peers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"go.uber.org/zap"
+
+	"github.com/ssvlabs/ssv/storage/basedb"
+)
+
+var (
+	scoresPrefix = []byte("peers/scores/")
+	bansPrefix   = []byte("peers/bans/")
+)
+
+const (
+	// DefaultScoreHalfLife is the default time it takes persisted scores to decay by half.
+	DefaultScoreHalfLife = 6 * time.Hour
+	// minPersistedScore is the absolute score below which decayed scores are forgotten.
+	minPersistedScore = 0.01
+)
+
+// BanIndex tells whether peers or IP addresses are banned.
+type BanIndex interface {
+	// IsPeerBanned returns whether the given peer is banned.
+	IsPeerBanned(id peer.ID) bool
+	// IsIPBanned returns whether the given IP address is banned.
+	IsIPBanned(ip net.IP) bool
+}
+
+// Ban is a ban of a peer ID, or of an IP address or CIDR range.
+type Ban struct {
+	Target  string    `json:"target"`
+	Reason  string    `json:"reason,omitempty"`
+	Created time.Time `json:"created"`
+
+	// network is the banned range of IP addresses, or nil if a peer ID is banned.
+	network *net.IPNet
+}
+
+// isRange returns whether a CIDR range is banned, rather than a peer ID or a single IP address.
+func (b Ban) isRange() bool {
+	return b.network != nil && strings.Contains(b.Target, "/")
+}
+
+// ParseBan returns a Ban of the given target, which is either a peer ID, an IP address or a CIDR range.
+func ParseBan(target, reason string) (Ban, error) {
+	ban := Ban{Reason: reason}
+	if _, network, err := net.ParseCIDR(target); err == nil {
+		ban.network = network
+		ban.Target = network.String()
+		return ban, nil
+	}
+	if ip := net.ParseIP(target); ip != nil {
+		bits := 8 * net.IPv6len
+		if ip4 := ip.To4(); ip4 != nil {
+			ip, bits = ip4, 8*net.IPv4len
+		}
+		ban.network = &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}
+		ban.Target = ip.String()
+		return ban, nil
+	}
+	id, err := peer.Decode(target)
+	if err != nil {
+		return Ban{}, fmt.Errorf("%q is neither a peer ID, an IP address nor a CIDR range", target)
+	}
+	ban.Target = id.String()
+	return ban, nil
+}
+
+// decayingScore is a score which decays towards zero since it was updated.
+type decayingScore struct {
+	Value   float64   `json:"value"`
+	Updated time.Time `json:"updated"`
+}
+
+func (s decayingScore) at(now time.Time, halfLife time.Duration) float64 {
+	elapsed := now.Sub(s.Updated)
+	if elapsed <= 0 {
+		return s.Value
+	}
+	return s.Value * math.Pow(0.5, float64(elapsed)/float64(halfLife))
+}
+
+type scoreKey struct {
+	peerID peer.ID
+	name   string
+}
+
+// Reputation keeps the scores of peers and the bans of peers and IP addresses across restarts.
+// Persisted scores decay by half every half-life, so peers eventually recover from bad scores.
+type Reputation struct {
+	db       basedb.Database
+	halfLife time.Duration
+	now      func() time.Time
+
+	mu sync.RWMutex
+	// baseline are the scores persisted by previous runs.
+	baseline map[scoreKey]decayingScore
+	// scores are the latest scores, including the baseline ones which weren't updated.
+	scores map[scoreKey]decayingScore
+	dirty  map[scoreKey]struct{}
+	// bans are keyed by their target, so that peer IDs and single IP addresses are looked up directly.
+	bans map[string]Ban
+	// rangeBans are the bans of CIDR ranges, which IP addresses are matched against one by one.
+	rangeBans []Ban
+}
+
+// NewReputation returns a Reputation with the scores and bans persisted in the given database.
+// If db is nil, nothing is persisted.
+func NewReputation(db basedb.Database, halfLife time.Duration) (*Reputation, error) {
+	if halfLife <= 0 {
+		halfLife = DefaultScoreHalfLife
+	}
+	r := &Reputation{
+		db:       db,
+		halfLife: halfLife,
+		now:      time.Now,
+		baseline: make(map[scoreKey]decayingScore),
+		scores:   make(map[scoreKey]decayingScore),
+		dirty:    make(map[scoreKey]struct{}),
+		bans:     make(map[string]Ban),
+	}
+	if db == nil {
+		return r, nil
+	}
+
+	err := db.GetAll(scoresPrefix, func(_ int, obj basedb.Obj) error {
+		encodedID, name, ok := strings.Cut(string(obj.Key), "/")
+		if !ok {
+			return fmt.Errorf("invalid score key %q", obj.Key)
+		}
+		id, err := peer.Decode(encodedID)
+		if err != nil {
+			return fmt.Errorf("invalid peer ID of score key %q: %w", obj.Key, err)
+		}
+		var score decayingScore
+		if err := json.Unmarshal(obj.Value, &score); err != nil {
+			return fmt.Errorf("could not decode score: %w", err)
+		}
+		key := scoreKey{peerID: id, name: name}
+		r.baseline[key] = score
+		r.scores[key] = score
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not load peer scores: %w", err)
+	}
+
+	err = db.GetAll(bansPrefix, func(_ int, obj basedb.Obj) error {
+		var stored Ban
+		if err := json.Unmarshal(obj.Value, &stored); err != nil {
+			return fmt.Errorf("could not decode ban: %w", err)
+		}
+		ban, err := ParseBan(stored.Target, stored.Reason)
+		if err != nil {
+			return err
+		}
+		ban.Created = stored.Created
+		r.addBan(ban)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not load peer bans: %w", err)
+	}
+	return r, nil
+}
+
+// Score returns the latest score of the given name of the peer, decayed since it was updated.
+func (r *Reputation) Score(id peer.ID, name string) (float64, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	score, ok := r.scores[scoreKey{peerID: id, name: name}]
+	if !ok {
+		return 0, false
+	}
+	return score.at(r.now(), r.halfLife), true
+}
+
+// Baseline returns the score of the given name of the peer which was persisted by previous runs,
+// decayed since it was updated, or zero if there's no such score.
+func (r *Reputation) Baseline(id peer.ID, name string) float64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	score, ok := r.baseline[scoreKey{peerID: id, name: name}]
+	if !ok {
+		return 0
+	}
+	return score.at(r.now(), r.halfLife)
+}
+
+// SetScore updates the score of the given name of the peer. It's persisted by the next Save.
+func (r *Reputation) SetScore(id peer.ID, name string, value float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := scoreKey{peerID: id, name: name}
+	r.scores[key] = decayingScore{Value: value, Updated: r.now()}
+	r.dirty[key] = struct{}{}
+}
+
+// Save persists the scores which were updated since the last save,
+// and forgets the ones which decayed to nearly zero.
+func (r *Reputation) Save() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	var remove []scoreKey
+	for key, score := range r.scores {
+		if math.Abs(score.at(now, r.halfLife)) < minPersistedScore {
+			remove = append(remove, key)
+		}
+	}
+	for _, key := range remove {
+		delete(r.scores, key)
+		delete(r.baseline, key)
+		delete(r.dirty, key)
+	}
+	if r.db == nil {
+		r.dirty = make(map[scoreKey]struct{})
+		return nil
+	}
+
+	err := r.db.Update(func(txn basedb.Txn) error {
+		for _, key := range remove {
+			if err := txn.Delete(scoresPrefix, scoreDBKey(key)); err != nil {
+				return err
+			}
+		}
+		for key := range r.dirty {
+			encoded, err := json.Marshal(r.scores[key])
+			if err != nil {
+				return err
+			}
+			if err := txn.Set(scoresPrefix, scoreDBKey(key), encoded); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("could not save peer scores: %w", err)
+	}
+	r.dirty = make(map[scoreKey]struct{})
+	return nil
+}
+
+// Run saves the scores at the given interval until the context is done, and once more then.
+func (r *Reputation) Run(ctx context.Context, logger *zap.Logger, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			if err := r.Save(); err != nil {
+				logger.Warn("could not save peer scores", zap.Error(err))
+			}
+			return
+		case <-ticker.C:
+			if err := r.Save(); err != nil {
+				logger.Warn("could not save peer scores", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Ban bans the given peer ID, IP address or CIDR range until it's unbanned.
+func (r *Reputation) Ban(target, reason string) (Ban, error) {
+	ban, err := ParseBan(target, reason)
+	if err != nil {
+		return Ban{}, err
+	}
+	ban.Created = r.now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.db != nil {
+		encoded, err := json.Marshal(ban)
+		if err != nil {
+			return Ban{}, err
+		}
+		if err := r.db.Set(bansPrefix, []byte(ban.Target), encoded); err != nil {
+			return Ban{}, fmt.Errorf("could not save ban: %w", err)
+		}
+	}
+	r.addBan(ban)
+	return ban, nil
+}
+
+// Unban removes the ban of the given target, and returns false if it wasn't banned.
+func (r *Reputation) Unban(target string) (bool, error) {
+	ban, err := ParseBan(target, "")
+	if err != nil {
+		return false, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.bans[ban.Target]; !ok {
+		return false, nil
+	}
+	if r.db != nil {
+		if err := r.db.Delete(bansPrefix, []byte(ban.Target)); err != nil {
+			return false, fmt.Errorf("could not delete ban: %w", err)
+		}
+	}
+	delete(r.bans, ban.Target)
+	r.rangeBans = slices.DeleteFunc(r.rangeBans, func(rangeBan Ban) bool {
+		return rangeBan.Target == ban.Target
+	})
+	return true, nil
+}
+
+func (r *Reputation) addBan(ban Ban) {
+	if _, ok := r.bans[ban.Target]; ok {
+		r.rangeBans = slices.DeleteFunc(r.rangeBans, func(rangeBan Ban) bool {
+			return rangeBan.Target == ban.Target
+		})
+	}
+	r.bans[ban.Target] = ban
+	if ban.isRange() {
+		r.rangeBans = append(r.rangeBans, ban)
+	}
+}
+
+// Bans returns the bans, sorted by their creation time and target.
+func (r *Reputation) Bans() []Ban {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	bans := make([]Ban, 0, len(r.bans))
+	for _, ban := range r.bans {
+		bans = append(bans, ban)
+	}
+	sort.Slice(bans, func(i, j int) bool {
+		if !bans[i].Created.Equal(bans[j].Created) {
+			return bans[i].Created.Before(bans[j].Created)
+		}
+		return bans[i].Target < bans[j].Target
+	})
+	return bans
+}
+
+// IsPeerBanned implements BanIndex.
+func (r *Reputation) IsPeerBanned(id peer.ID) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, ok := r.bans[id.String()]
+	return ok
+}
+
+// IsIPBanned implements BanIndex.
+func (r *Reputation) IsIPBanned(ip net.IP) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if _, ok := r.bans[ip.String()]; ok {
+		return true
+	}
+	for _, ban := range r.rangeBans {
+		if ban.network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func scoreDBKey(key scoreKey) []byte {
+	return []byte(key.peerID.String() + "/" + key.name)
+}
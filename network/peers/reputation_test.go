@@ -0,0 +1,138 @@
+package peers
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ssvlabs/ssv/logging"
+	"github.com/ssvlabs/ssv/storage/basedb"
+	"github.com/ssvlabs/ssv/storage/kv"
+)
+
+func newTestReputation(t *testing.T, db basedb.Database, now time.Time) *Reputation {
+	r, err := NewReputation(db, time.Hour)
+	require.NoError(t, err)
+	r.now = func() time.Time { return now }
+	return r
+}
+
+func TestReputationScores(t *testing.T) {
+	db, err := kv.NewInMemory(logging.TestLogger(t), basedb.Options{})
+	require.NoError(t, err)
+	defer db.Close()
+
+	pids, err := createPeerIDs(2)
+	require.NoError(t, err)
+	start := time.Now()
+
+	r := newTestReputation(t, db, start)
+	r.SetScore(pids[0], gossipScoreName, -8)
+	r.SetScore(pids[0], "validation", 4)
+	require.NoError(t, r.Save())
+
+	// After a restart, the scores decayed by half.
+	r = newTestReputation(t, db, start.Add(time.Hour))
+	score, ok := r.Score(pids[0], gossipScoreName)
+	require.True(t, ok)
+	require.InDelta(t, -4, score, 1e-9)
+	require.InDelta(t, -4, r.Baseline(pids[0], gossipScoreName), 1e-9)
+	_, ok = r.Score(pids[1], gossipScoreName)
+	require.False(t, ok)
+
+	// The live gossip scores start over, so the baseline is added to them.
+	gossipIdx := NewGossipScoreIndex(WithReputation(r))
+	score, ok = gossipIdx.GetGossipScore(pids[0])
+	require.True(t, ok)
+	require.InDelta(t, -4, score, 1e-9)
+	gossipIdx.SetScores(map[peer.ID]float64{pids[0]: 1})
+	score, ok = gossipIdx.GetGossipScore(pids[0])
+	require.True(t, ok)
+	require.InDelta(t, -3, score, 1e-9)
+	_, ok = gossipIdx.GetGossipScore(pids[1])
+	require.False(t, ok)
+
+	scoreIdx := newScoreIndex(r)
+	scores, err := scoreIdx.GetScore(pids[0], "validation", "dummy")
+	require.NoError(t, err)
+	require.Len(t, scores, 1)
+	require.InDelta(t, 2, scores[0].Value, 1e-9)
+	require.NoError(t, scoreIdx.Score(pids[1], &NodeScore{Name: "validation", Value: -1}))
+	require.NoError(t, r.Save())
+
+	r = newTestReputation(t, db, start.Add(time.Hour))
+	score, ok = r.Score(pids[1], "validation")
+	require.True(t, ok)
+	require.InDelta(t, -1, score, 1e-9)
+
+	// Scores which decayed to nearly zero are forgotten.
+	r.now = func() time.Time { return start.Add(24 * time.Hour) }
+	require.NoError(t, r.Save())
+	r = newTestReputation(t, db, start.Add(24*time.Hour))
+	_, ok = r.Score(pids[0], gossipScoreName)
+	require.False(t, ok)
+}
+
+func TestReputationBans(t *testing.T) {
+	db, err := kv.NewInMemory(logging.TestLogger(t), basedb.Options{})
+	require.NoError(t, err)
+	defer db.Close()
+
+	pids, err := createPeerIDs(2)
+	require.NoError(t, err)
+
+	r := newTestReputation(t, db, time.Now())
+	_, err = r.Ban("not-a-peer", "")
+	require.ErrorContains(t, err, "neither a peer ID")
+
+	ban, err := r.Ban(pids[0].String(), "spam")
+	require.NoError(t, err)
+	require.Equal(t, pids[0].String(), ban.Target)
+	ban, err = r.Ban("10.0.0.1", "")
+	require.NoError(t, err)
+	require.Equal(t, "10.0.0.1", ban.Target)
+	ban, err = r.Ban("192.168.1.7/24", "")
+	require.NoError(t, err)
+	require.Equal(t, "192.168.1.0/24", ban.Target)
+
+	// Bans are kept across restarts.
+	r = newTestReputation(t, db, time.Now())
+	require.Len(t, r.Bans(), 3)
+	for _, ban := range r.Bans() {
+		if ban.Target == pids[0].String() {
+			require.Equal(t, "spam", ban.Reason)
+		}
+	}
+	require.True(t, r.IsPeerBanned(pids[0]))
+	require.False(t, r.IsPeerBanned(pids[1]))
+	require.True(t, r.IsIPBanned(net.ParseIP("10.0.0.1")))
+	require.False(t, r.IsIPBanned(net.ParseIP("10.0.0.2")))
+	require.True(t, r.IsIPBanned(net.ParseIP("192.168.1.200")))
+	require.False(t, r.IsIPBanned(net.ParseIP("192.168.2.1")))
+
+	require.True(t, r.IsIPBanned(net.ParseIP("::ffff:10.0.0.1")))
+
+	// Banning a range again replaces its ban.
+	_, err = r.Ban("192.168.1.0/24", "again")
+	require.NoError(t, err)
+	require.Len(t, r.rangeBans, 1)
+
+	unbanned, err := r.Unban("192.168.1.0/24")
+	require.NoError(t, err)
+	require.True(t, unbanned)
+	require.False(t, r.IsIPBanned(net.ParseIP("192.168.1.200")))
+	unbanned, err = r.Unban("192.168.1.0/24")
+	require.NoError(t, err)
+	require.False(t, unbanned)
+	unbanned, err = r.Unban(pids[0].String())
+	require.NoError(t, err)
+	require.True(t, unbanned)
+
+	r = newTestReputation(t, db, time.Now())
+	require.Len(t, r.Bans(), 1)
+	require.False(t, r.IsPeerBanned(pids[0]))
+	require.False(t, r.IsIPBanned(net.ParseIP("192.168.1.200")))
+}
@@ -11,12 +11,15 @@ import (
 type scoresIndex struct {
 	scores map[peer.ID][]*NodeScore
 	lock   *sync.RWMutex
+	// reputation keeps the scores across restarts, if not nil.
+	reputation *Reputation
 }
 
-func newScoreIndex() ScoreIndex {
+func newScoreIndex(reputation *Reputation) ScoreIndex {
 	return &scoresIndex{
-		scores: map[peer.ID][]*NodeScore{},
-		lock:   &sync.RWMutex{},
+		scores:     map[peer.ID][]*NodeScore{},
+		lock:       &sync.RWMutex{},
+		reputation: reputation,
 	}
 }
 
@@ -27,6 +30,9 @@ func (s *scoresIndex) Score(id peer.ID, scores ...*NodeScore) error {
 
 scoresLoop:
 	for _, score := range scores {
+		if s.reputation != nil {
+			s.reputation.SetScore(id, score.Name, score.Value)
+		}
 		existing, ok := s.scores[id]
 		if !ok {
 			existing = make([]*NodeScore, 0)
@@ -47,10 +53,7 @@ func (s *scoresIndex) GetScore(id peer.ID, names ...string) ([]NodeScore, error)
 	s.lock.RLock()
 	defer s.lock.RUnlock()
 
-	peerScores, ok := s.scores[id]
-	if !ok {
-		return nil, nil
-	}
+	peerScores := s.scores[id]
 	var scores []NodeScore
 wantedScoresLoop:
 	for _, name := range names {
@@ -60,6 +63,12 @@ wantedScoresLoop:
 				continue wantedScoresLoop
 			}
 		}
+		// fallback to the score persisted by previous runs, if any
+		if s.reputation != nil {
+			if value, ok := s.reputation.Score(id, name); ok {
+				scores = append(scores, NodeScore{Name: name, Value: value})
+			}
+		}
 	}
 	return scores, nil
 }
@@ -20,7 +20,7 @@ func TestScoresIndex(t *testing.T) {
 	pid, err := peer.IDFromPrivateKey(sk)
 	require.NoError(t, err)
 
-	si := newScoreIndex()
+	si := newScoreIndex(nil)
 
 	require.NoError(t, si.Score(pid, &NodeScore{
 		Name:  "decided",
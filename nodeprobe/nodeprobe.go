@@ -3,6 +3,7 @@ package nodeprobe
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -18,11 +19,35 @@ type Node interface {
 	Healthy(ctx context.Context) error
 }
 
+// MultiNode is a Node backed by several endpoints (e.g. multiple beacon nodes),
+// which is healthy as long as enough of its endpoints are.
+type MultiNode interface {
+	Node
+	// EndpointStatuses returns the status of each endpoint as of the latest Healthy call, keyed by address.
+	EndpointStatuses() map[string]Status
+}
+
+// Status is the result of a health check.
+type Status struct {
+	Err       error
+	Latency   time.Duration
+	CheckedAt time.Time
+}
+
+// NamedStatus is a Status of a node or of one of its endpoints.
+type NamedStatus struct {
+	Node     string
+	Endpoint string
+	Status
+}
+
 type Prober struct {
 	logger           *zap.Logger
 	interval         time.Duration
 	nodes            map[string]Node
 	nodesMu          sync.Mutex
+	statuses         map[string]Status
+	statusesMu       sync.RWMutex
 	healthy          atomic.Bool
 	cond             *sync.Cond
 	unhealthyHandler func()
@@ -34,6 +59,7 @@ func NewProber(logger *zap.Logger, unhealthyHandler func(), nodes map[string]Nod
 		unhealthyHandler: unhealthyHandler,
 		interval:         probeInterval,
 		nodes:            nodes,
+		statuses:         map[string]Status{},
 		cond:             sync.NewCond(&sync.Mutex{}),
 	}
 }
@@ -93,7 +119,9 @@ func (p *Prober) probe(ctx context.Context) {
 				}
 			}()
 
+			start := time.Now()
 			err = node.Healthy(ctx)
+			p.setStatus(name, Status{Err: err, Latency: time.Since(start), CheckedAt: start})
 			if err != nil {
 				p.logger.Error("node is not healthy", zap.String("node", name), zap.Error(err))
 			}
@@ -119,6 +147,47 @@ func (p *Prober) probe(ctx context.Context) {
 	p.cond.Broadcast()
 }
 
+func (p *Prober) setStatus(name string, status Status) {
+	p.statusesMu.Lock()
+	defer p.statusesMu.Unlock()
+
+	p.statuses[name] = status
+}
+
+// Statuses returns the latest probe result of every node, followed by the statuses
+// of the individual endpoints of each MultiNode, sorted by node name and endpoint.
+func (p *Prober) Statuses() []NamedStatus {
+	p.nodesMu.Lock()
+	multiNodes := make(map[string]MultiNode)
+	for name, node := range p.nodes {
+		if mn, ok := node.(MultiNode); ok {
+			multiNodes[name] = mn
+		}
+	}
+	p.nodesMu.Unlock()
+
+	p.statusesMu.RLock()
+	statuses := make([]NamedStatus, 0, len(p.statuses))
+	for name, status := range p.statuses {
+		statuses = append(statuses, NamedStatus{Node: name, Status: status})
+	}
+	p.statusesMu.RUnlock()
+
+	for name, mn := range multiNodes {
+		for endpoint, status := range mn.EndpointStatuses() {
+			statuses = append(statuses, NamedStatus{Node: name, Endpoint: endpoint, Status: status})
+		}
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].Node != statuses[j].Node {
+			return statuses[i].Node < statuses[j].Node
+		}
+		return statuses[i].Endpoint < statuses[j].Endpoint
+	})
+	return statuses
+}
+
 func (p *Prober) Wait() {
 	p.logger.Info("waiting until nodes are healthy")
 
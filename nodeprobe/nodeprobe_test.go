@@ -81,3 +81,44 @@ func (sc *node) Healthy(context.Context) error {
 	}
 	return nil
 }
+
+func TestProber_Statuses(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	single := &node{}
+	single.healthy.Store(nil)
+
+	endpointErr := fmt.Errorf("syncing")
+	multi := &multiNode{statuses: map[string]Status{
+		"http://a": {},
+		"http://b": {Err: endpointErr},
+	}}
+	multi.healthy.Store(nil)
+
+	prober := NewProber(zap.L(), nil, map[string]Node{"single": single, "multi": multi})
+	prober.interval = 10 * time.Millisecond
+	prober.Start(ctx)
+	prober.Wait()
+
+	statuses := prober.Statuses()
+	require.Len(t, statuses, 4)
+	require.Equal(t, "multi", statuses[0].Node)
+	require.Empty(t, statuses[0].Endpoint)
+	require.NoError(t, statuses[0].Err)
+	require.Equal(t, "http://a", statuses[1].Endpoint)
+	require.NoError(t, statuses[1].Err)
+	require.Equal(t, "http://b", statuses[2].Endpoint)
+	require.ErrorIs(t, statuses[2].Err, endpointErr)
+	require.Equal(t, "single", statuses[3].Node)
+	require.False(t, statuses[3].CheckedAt.IsZero())
+}
+
+type multiNode struct {
+	node
+	statuses map[string]Status
+}
+
+func (mn *multiNode) EndpointStatuses() map[string]Status {
+	return mn.statuses
+}
@@ -0,0 +1,184 @@
+// Package clusterhealth reports how the operators of a committee participate in its duties,
+// based on the decided participants saved in the QBFT storage.
+package clusterhealth
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	spectypes "github.com/ssvlabs/ssv-spec/types"
+
+	"github.com/ssvlabs/ssv/exporter/convert"
+	ibftstorage "github.com/ssvlabs/ssv/ibft/storage"
+	"github.com/ssvlabs/ssv/protocol/v2/blockchain/beacon"
+	ssvtypes "github.com/ssvlabs/ssv/protocol/v2/types"
+	registrystorage "github.com/ssvlabs/ssv/registry/storage"
+	"github.com/ssvlabs/ssv/utils/casts"
+)
+
+const (
+	// DefaultEpochs is the default size of the window of recent epochs the health is computed over.
+	DefaultEpochs = 1
+	// MaxEpochs is the largest window of recent epochs the health can be computed over.
+	MaxEpochs = 4
+	// MaxLookups is the largest number of participants lookups, one per slot of each role of each validator,
+	// a health computation may do, so that large committees are limited to smaller windows.
+	MaxLookups = 100_000
+)
+
+// ErrWindowTooLarge is returned when the window the health is requested over is too large for the committee.
+var ErrWindowTooLarge = errors.New("window too large")
+
+// Config holds the cluster health configuration.
+type Config struct {
+	Enabled bool `yaml:"Enabled" env:"CLUSTER_HEALTH" env-default:"false" env-description:"Save the participants of the duties performed by the operator to compute the health of its clusters"`
+}
+
+// roles are the beacon roles whose participants are saved.
+var roles = []spectypes.BeaconRole{
+	spectypes.BNRoleAttester,
+	spectypes.BNRoleSyncCommittee,
+	spectypes.BNRoleAggregator,
+	spectypes.BNRoleSyncCommitteeContribution,
+	spectypes.BNRoleProposer,
+}
+
+type Status string
+
+const (
+	// StatusHealthy is the status of an operator which signed every duty before the quorum was reached.
+	StatusHealthy Status = "healthy"
+	// StatusDegraded is the status of an operator which signed some duties late or not at all.
+	StatusDegraded Status = "degraded"
+	// StatusMissing is the status of an operator which didn't sign any duty.
+	StatusMissing Status = "missing"
+	// StatusUnknown is the status of operators of committees without any saved duty in the window.
+	StatusUnknown Status = "unknown"
+)
+
+// OperatorHealth is the participation of an operator in the duties of a committee.
+type OperatorHealth struct {
+	OperatorID spectypes.OperatorID
+	// Signed is the number of duties the operator signed before the quorum was reached.
+	Signed int
+	// Late is the number of duties the operator signed after the quorum was reached.
+	Late int
+	// Missing is the number of duties the operator didn't sign.
+	Missing int
+	// LastSignedSlot is the last slot of a duty the operator signed, or zero if there is none.
+	LastSignedSlot phase0.Slot
+	Status         Status
+}
+
+// Health is the participation of the operators of a committee in its duties over a window of slots.
+type Health struct {
+	CommitteeID spectypes.CommitteeID
+	FromSlot    phase0.Slot
+	ToSlot      phase0.Slot
+	// Duties is the number of duties with saved participants in the window.
+	Duties    int
+	Quorum    uint64
+	Operators []*OperatorHealth
+}
+
+// Responsive returns the number of operators which signed any duty in the window.
+func (h *Health) Responsive() uint64 {
+	var responsive uint64
+	for _, operator := range h.Operators {
+		if operator.Signed+operator.Late > 0 {
+			responsive++
+		}
+	}
+	return responsive
+}
+
+// Checker computes the health of committees.
+type Checker struct {
+	network beacon.BeaconNetwork
+	domain  spectypes.DomainType
+	stores  *ibftstorage.QBFTStores
+}
+
+// NewChecker returns a Checker reading the participants saved in the given stores.
+func NewChecker(network beacon.BeaconNetwork, domain spectypes.DomainType, stores *ibftstorage.QBFTStores) *Checker {
+	return &Checker{
+		network: network,
+		domain:  domain,
+		stores:  stores,
+	}
+}
+
+// CommitteeHealth returns the health of the committee over the given number of recent epochs,
+// ending with the last completed slot.
+func (c *Checker) CommitteeHealth(committee *registrystorage.Committee, epochs uint64) (*Health, error) {
+	if epochs == 0 {
+		epochs = DefaultEpochs
+	}
+	if epochs > MaxEpochs {
+		return nil, fmt.Errorf("%w: %d epochs exceeds the maximum of %d", ErrWindowTooLarge, epochs, MaxEpochs)
+	}
+	if lookups := uint64(len(committee.Validators)*len(roles)) * epochs * c.network.SlotsPerEpoch(); lookups > MaxLookups {
+		return nil, fmt.Errorf("%w: %d epochs of %d validators exceeds the maximum of %d lookups", ErrWindowTooLarge, epochs, len(committee.Validators), MaxLookups)
+	}
+
+	health := &Health{
+		CommitteeID: committee.ID,
+		Operators:   make([]*OperatorHealth, len(committee.Operators)),
+	}
+	health.Quorum, _ = ssvtypes.ComputeQuorumAndPartialQuorum(uint64(len(committee.Operators)))
+	if currentSlot := c.network.EstimatedCurrentSlot(); currentSlot > 0 {
+		health.ToSlot = currentSlot - 1
+	}
+	if window := phase0.Slot(epochs * c.network.SlotsPerEpoch()); health.ToSlot >= window {
+		health.FromSlot = health.ToSlot - window + 1
+	}
+
+	for i, operatorID := range committee.Operators {
+		health.Operators[i] = &OperatorHealth{OperatorID: operatorID}
+	}
+
+	for _, share := range committee.Validators {
+		for _, role := range roles {
+			store := c.stores.Get(casts.BeaconRoleToConvertRole(role))
+			if store == nil {
+				continue
+			}
+			msgID := convert.NewMsgID(c.domain, share.ValidatorPubKey[:], casts.BeaconRoleToConvertRole(role))
+			entries, err := store.GetParticipantsInRange(msgID, health.FromSlot, health.ToSlot)
+			if err != nil {
+				return nil, fmt.Errorf("could not get %s participants: %w", role, err)
+			}
+			for _, entry := range entries {
+				health.Duties++
+				for _, operator := range health.Operators {
+					switch {
+					case slices.Contains(entry.Late, operator.OperatorID):
+						operator.Late++
+					case slices.Contains(entry.Signers, operator.OperatorID):
+						operator.Signed++
+					default:
+						operator.Missing++
+						continue
+					}
+					operator.LastSignedSlot = max(operator.LastSignedSlot, entry.Slot)
+				}
+			}
+		}
+	}
+
+	for _, operator := range health.Operators {
+		switch {
+		case health.Duties == 0:
+			operator.Status = StatusUnknown
+		case operator.Signed+operator.Late == 0:
+			operator.Status = StatusMissing
+		case operator.Late+operator.Missing == 0:
+			operator.Status = StatusHealthy
+		default:
+			operator.Status = StatusDegraded
+		}
+	}
+	return health, nil
+}
@@ -0,0 +1,83 @@
+package clusterhealth
+
+import (
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	spectypes "github.com/ssvlabs/ssv-spec/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ssvlabs/ssv/exporter/convert"
+	ibftstorage "github.com/ssvlabs/ssv/ibft/storage"
+	"github.com/ssvlabs/ssv/logging"
+	"github.com/ssvlabs/ssv/networkconfig"
+	"github.com/ssvlabs/ssv/operator/dutyhistory"
+	ssvtypes "github.com/ssvlabs/ssv/protocol/v2/types"
+	registrystorage "github.com/ssvlabs/ssv/registry/storage"
+	"github.com/ssvlabs/ssv/storage/basedb"
+	"github.com/ssvlabs/ssv/storage/kv"
+)
+
+func TestCommitteeHealth(t *testing.T) {
+	logger := logging.TestLogger(t)
+	db, err := kv.NewInMemory(logger, basedb.Options{})
+	require.NoError(t, err)
+	defer db.Close()
+
+	network := networkconfig.TestNetwork
+	stores := ibftstorage.NewStoresFromRoles(db, convert.RoleAttester, convert.RoleProposer)
+	checker := NewChecker(network.Beacon, network.DomainType(), stores)
+	recorder := NewParticipantsRecorder(logger, network.DomainType(), stores)
+
+	share := &ssvtypes.SSVShare{}
+	share.ValidatorPubKey = spectypes.ValidatorPK{1}
+	committee := &registrystorage.Committee{
+		ID:         ssvtypes.ComputeCommitteeID([]spectypes.OperatorID{1, 2, 3, 4}),
+		Operators:  []spectypes.OperatorID{1, 2, 3, 4},
+		Validators: []*ssvtypes.SSVShare{share},
+	}
+
+	health, err := checker.CommitteeHealth(committee, 0)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), health.Quorum)
+	require.Zero(t, health.Duties)
+	require.Equal(t, StatusUnknown, health.Operators[0].Status)
+
+	slot := network.Beacon.EstimatedCurrentSlot() - 2
+	record := func(role spectypes.BeaconRole, participants ...spectypes.OperatorID) {
+		recorder.RecordDuty(share.ValidatorPubKey, &dutyhistory.Record{Role: role, Slot: slot, Participants: participants})
+	}
+	record(spectypes.BNRoleAttester, 1, 2, 3)
+	record(spectypes.BNRoleProposer, 1, 2, 4)
+	// Sync committee participants aren't saved without a store.
+	record(spectypes.BNRoleSyncCommittee, 1, 2, 3)
+	recorder.RecordLateSigner(share.ValidatorPubKey, spectypes.BNRoleAttester, slot, 4)
+	// Late signers of duties without saved participants are ignored.
+	recorder.RecordLateSigner(share.ValidatorPubKey, spectypes.BNRoleAttester, slot-1, 4)
+	for len(recorder.pending) > 0 {
+		require.NoError(t, recorder.save(<-recorder.pending))
+	}
+
+	health, err = checker.CommitteeHealth(committee, 1)
+	require.NoError(t, err)
+	require.Equal(t, 2, health.Duties)
+	require.Equal(t, slot+1, health.ToSlot)
+	require.Equal(t, slot+2-phase0.Slot(network.Beacon.SlotsPerEpoch()), health.FromSlot)
+	require.Equal(t, uint64(4), health.Responsive())
+	require.Equal(t, []*OperatorHealth{
+		{OperatorID: 1, Signed: 2, LastSignedSlot: slot, Status: StatusHealthy},
+		{OperatorID: 2, Signed: 2, LastSignedSlot: slot, Status: StatusHealthy},
+		{OperatorID: 3, Signed: 1, Missing: 1, LastSignedSlot: slot, Status: StatusDegraded},
+		{OperatorID: 4, Signed: 1, Late: 1, LastSignedSlot: slot, Status: StatusDegraded},
+	}, health.Operators)
+
+	_, err = checker.CommitteeHealth(committee, MaxEpochs+1)
+	require.ErrorIs(t, err, ErrWindowTooLarge)
+
+	// Large committees are limited to smaller windows.
+	for len(committee.Validators)*len(roles)*int(network.Beacon.SlotsPerEpoch()) <= MaxLookups {
+		committee.Validators = append(committee.Validators, share)
+	}
+	_, err = checker.CommitteeHealth(committee, 1)
+	require.ErrorIs(t, err, ErrWindowTooLarge)
+}
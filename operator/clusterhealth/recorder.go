@@ -0,0 +1,115 @@
+package clusterhealth
+
+import (
+	"context"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	spectypes "github.com/ssvlabs/ssv-spec/types"
+	"go.uber.org/zap"
+
+	"github.com/ssvlabs/ssv/exporter/convert"
+	ibftstorage "github.com/ssvlabs/ssv/ibft/storage"
+	"github.com/ssvlabs/ssv/operator/dutyhistory"
+	"github.com/ssvlabs/ssv/utils/casts"
+)
+
+const participantsBufferSize = 4096
+
+type pendingParticipants struct {
+	pubKey       spectypes.ValidatorPK
+	role         spectypes.BeaconRole
+	slot         phase0.Slot
+	participants []spectypes.OperatorID
+	late         bool
+}
+
+// ParticipantsRecorder saves the participants of the duties performed by the node,
+// so that operator nodes have the participants which exporter nodes save of every decided duty.
+type ParticipantsRecorder struct {
+	logger *zap.Logger
+	domain spectypes.DomainType
+	stores *ibftstorage.QBFTStores
+
+	pending chan pendingParticipants
+}
+
+// NewParticipantsRecorder returns a ParticipantsRecorder saving into the given stores.
+func NewParticipantsRecorder(logger *zap.Logger, domain spectypes.DomainType, stores *ibftstorage.QBFTStores) *ParticipantsRecorder {
+	return &ParticipantsRecorder{
+		logger:  logger,
+		domain:  domain,
+		stores:  stores,
+		pending: make(chan pendingParticipants, participantsBufferSize),
+	}
+}
+
+// RecordDuty queues the participants of the duty to be saved by Run.
+// It implements the runners' DutyRecorder.
+func (r *ParticipantsRecorder) RecordDuty(pubKey spectypes.ValidatorPK, record *dutyhistory.Record) {
+	if len(record.Participants) == 0 {
+		return
+	}
+	r.enqueue(pendingParticipants{
+		pubKey:       pubKey,
+		role:         record.Role,
+		slot:         record.Slot,
+		participants: record.Participants,
+	})
+}
+
+// RecordLateSigner queues the operator to be saved by Run as a late participant of the duty.
+// It implements the runners' LateSignerRecorder.
+func (r *ParticipantsRecorder) RecordLateSigner(pubKey spectypes.ValidatorPK, role spectypes.BeaconRole, slot phase0.Slot, signer spectypes.OperatorID) {
+	r.enqueue(pendingParticipants{
+		pubKey:       pubKey,
+		role:         role,
+		slot:         slot,
+		participants: []spectypes.OperatorID{signer},
+		late:         true,
+	})
+}
+
+// enqueue never blocks: the participants are dropped if the queue is full.
+func (r *ParticipantsRecorder) enqueue(pending pendingParticipants) {
+	select {
+	case r.pending <- pending:
+	default:
+		r.logger.Debug("dropped participants because the queue is full",
+			zap.Uint64("slot", uint64(pending.slot)),
+			zap.String("role", pending.role.String()))
+	}
+}
+
+// Run saves the queued participants in the order they were recorded, until the context is done.
+func (r *ParticipantsRecorder) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case pending := <-r.pending:
+			if err := r.save(pending); err != nil {
+				r.logger.Warn("failed to save participants",
+					zap.Uint64("slot", uint64(pending.slot)),
+					zap.String("role", pending.role.String()),
+					zap.Error(err))
+			}
+		}
+	}
+}
+
+func (r *ParticipantsRecorder) save(pending pendingParticipants) error {
+	role := casts.BeaconRoleToConvertRole(pending.role)
+	store := r.stores.Get(role)
+	if store == nil {
+		return nil
+	}
+	msgID := convert.NewMsgID(r.domain, pending.pubKey[:], role)
+	if pending.late {
+		// Late signers are only meaningful in addition to the participants of a completed duty.
+		participants, err := store.GetParticipants(msgID, pending.slot)
+		if err != nil || len(participants) == 0 {
+			return err
+		}
+	}
+	return store.MergeParticipants(msgID, pending.slot, pending.participants)
+}
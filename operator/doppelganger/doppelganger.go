@@ -0,0 +1,293 @@
+package doppelganger
+
+import (
+	"context"
+	"encoding/hex"
+	"sort"
+	"sync"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	spectypes "github.com/ssvlabs/ssv-spec/types"
+	"go.uber.org/zap"
+
+	"github.com/ssvlabs/ssv/beacon/goclient"
+	"github.com/ssvlabs/ssv/logging/fields"
+	"github.com/ssvlabs/ssv/operator/slotticker"
+	beaconprotocol "github.com/ssvlabs/ssv/protocol/v2/blockchain/beacon"
+	ssvtypes "github.com/ssvlabs/ssv/protocol/v2/types"
+)
+
+// DefaultEpochs is the default number of epochs a validator is monitored for before it's considered safe.
+const DefaultEpochs = 2
+
+// Config holds the doppelganger protection configuration.
+type Config struct {
+	Enabled bool   `yaml:"Enabled" env:"DOPPELGANGER_PROTECTION" env-default:"false" env-description:"Refuse to sign for validators until they were seen offline for a number of epochs after being started, to avoid slashing when the same validator is run elsewhere. Validators which are already live when started, such as those kept live by the other operators of their cluster, are reported as possible doppelgangers and aren't signed for. Intended for migrating or restoring whole clusters, whose operators should all enable it"`
+	Epochs  uint64 `yaml:"Epochs" env:"DOPPELGANGER_EPOCHS" env-default:"2" env-description:"Number of epochs a validator must be seen offline before signing for it"`
+}
+
+// Status is the doppelganger protection status of a validator.
+type Status string
+
+const (
+	// StatusMonitoring means the validator is being checked for liveness and signing is blocked.
+	StatusMonitoring Status = "monitoring"
+	// StatusSafe means the validator wasn't seen live elsewhere and signing is allowed.
+	StatusSafe Status = "safe"
+	// StatusDetected means the validator was seen live elsewhere and signing is blocked until restart.
+	StatusDetected Status = "detected"
+)
+
+// ValidatorState is the doppelganger protection state of a validator.
+type ValidatorState struct {
+	ValidatorIndex  phase0.ValidatorIndex
+	ValidatorPubKey spectypes.ValidatorPK
+	Status          Status
+	// StartEpoch is the epoch in which monitoring started. Only later epochs are checked.
+	StartEpoch phase0.Epoch
+	// EpochsChecked is the number of epochs the validator was checked and seen offline.
+	EpochsChecked uint64
+	// DetectedEpoch is the epoch in which the validator was seen live, if detected.
+	DetectedEpoch phase0.Epoch
+	// LiveAtStart is whether the validator was already live in the first checked epoch, so it was detected
+	// as a possible doppelganger. Its liveness can't be tied to this operator's own signatures, which are
+	// blocked while monitoring, so it might as well be run elsewhere as by the other operators of its cluster.
+	LiveAtStart bool
+}
+
+// BeaconNode provides validator liveness.
+type BeaconNode interface {
+	ValidatorLiveness(ctx context.Context, epoch phase0.Epoch, indices []phase0.ValidatorIndex) ([]goclient.ValidatorLiveness, error)
+}
+
+// Handler blocks signing for validators until they're known not to be run elsewhere.
+type Handler interface {
+	// Start checks the liveness of monitored validators every epoch until the context is done.
+	Start(ctx context.Context)
+	// StartMonitoring starts monitoring the given share's validator, if not already monitored.
+	StartMonitoring(share *ssvtypes.SSVShare)
+	// StopMonitoring stops monitoring the given validator.
+	StopMonitoring(validatorIndex phase0.ValidatorIndex)
+	// CanSign returns whether it's safe to sign with the given share public key.
+	// Shares of validators which aren't monitored are allowed to sign.
+	CanSign(sharePubKey []byte) bool
+	// States returns the states of all monitored validators, ordered by validator index.
+	States() []ValidatorState
+}
+
+// Options holds the needed dependencies
+type Options struct {
+	Logger             *zap.Logger
+	Network            beaconprotocol.Network
+	BeaconNode         BeaconNode
+	SlotTickerProvider slotticker.Provider
+	Epochs             uint64
+}
+
+type handler struct {
+	logger             *zap.Logger
+	network            beaconprotocol.Network
+	beaconNode         BeaconNode
+	slotTickerProvider slotticker.Provider
+	epochs             uint64
+
+	mu     sync.RWMutex
+	states map[phase0.ValidatorIndex]*ValidatorState
+	// shares maps hex-encoded share public keys to their validator index.
+	shares map[string]phase0.ValidatorIndex
+}
+
+// NewHandler returns a new doppelganger protection Handler.
+func NewHandler(opts *Options) Handler {
+	epochs := opts.Epochs
+	if epochs == 0 {
+		epochs = DefaultEpochs
+	}
+	return &handler{
+		logger:             opts.Logger.Named("doppelganger"),
+		network:            opts.Network,
+		beaconNode:         opts.BeaconNode,
+		slotTickerProvider: opts.SlotTickerProvider,
+		epochs:             epochs,
+		states:             map[phase0.ValidatorIndex]*ValidatorState{},
+		shares:             map[string]phase0.ValidatorIndex{},
+	}
+}
+
+func (h *handler) Start(ctx context.Context) {
+	ticker := h.slotTickerProvider()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.Next():
+			slot := ticker.Slot()
+			// Check the previous epoch in the last slot of the current one,
+			// giving the beacon node time to observe its attestations.
+			if uint64(slot+1)%h.network.SlotsPerEpoch() != 0 {
+				continue
+			}
+			epoch := h.network.EstimatedEpochAtSlot(slot)
+			if epoch == 0 {
+				continue
+			}
+			h.checkEpoch(ctx, epoch-1)
+		}
+	}
+}
+
+func (h *handler) StartMonitoring(share *ssvtypes.SSVShare) {
+	if !share.HasBeaconMetadata() {
+		return
+	}
+	index := share.BeaconMetadata.Index
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.shares[hex.EncodeToString(share.SharePubKey)] = index
+	if _, ok := h.states[index]; ok {
+		return
+	}
+	state := &ValidatorState{
+		ValidatorIndex:  index,
+		ValidatorPubKey: share.ValidatorPubKey,
+		Status:          StatusMonitoring,
+		StartEpoch:      h.network.EstimatedCurrentEpoch(),
+	}
+	h.states[index] = state
+	metricsValidators.WithLabelValues(string(StatusMonitoring)).Inc()
+
+	h.logger.Info("started doppelganger monitoring",
+		zap.Uint64("validator_index", uint64(index)),
+		fields.PubKey(share.ValidatorPubKey[:]),
+		zap.Uint64("start_epoch", uint64(state.StartEpoch)),
+		zap.Uint64("epochs", h.epochs),
+	)
+}
+
+func (h *handler) StopMonitoring(validatorIndex phase0.ValidatorIndex) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	state, ok := h.states[validatorIndex]
+	if !ok {
+		return
+	}
+	metricsValidators.WithLabelValues(string(state.Status)).Dec()
+	delete(h.states, validatorIndex)
+	for sharePubKey, index := range h.shares {
+		if index == validatorIndex {
+			delete(h.shares, sharePubKey)
+		}
+	}
+}
+
+func (h *handler) CanSign(sharePubKey []byte) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	index, ok := h.shares[hex.EncodeToString(sharePubKey)]
+	if !ok {
+		return true
+	}
+	state, ok := h.states[index]
+	if !ok {
+		return true
+	}
+	return state.Status == StatusSafe
+}
+
+func (h *handler) States() []ValidatorState {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	states := make([]ValidatorState, 0, len(h.states))
+	for _, state := range h.states {
+		states = append(states, *state)
+	}
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].ValidatorIndex < states[j].ValidatorIndex
+	})
+	return states
+}
+
+// checkEpoch checks the liveness of validators in the given epoch,
+// if it's after the epoch in which they started being monitored. Any liveness is detected as a doppelganger,
+// since this operator doesn't sign for monitored validators, including liveness in the first checked epoch,
+// which can't be told apart from the validator's cluster keeping it live.
+func (h *handler) checkEpoch(ctx context.Context, epoch phase0.Epoch) {
+	h.mu.RLock()
+	var indices []phase0.ValidatorIndex
+	for index, state := range h.states {
+		if state.Status == StatusMonitoring && state.StartEpoch < epoch {
+			indices = append(indices, index)
+		}
+	}
+	h.mu.RUnlock()
+
+	if len(indices) == 0 {
+		return
+	}
+
+	liveness, err := h.beaconNode.ValidatorLiveness(ctx, epoch, indices)
+	if err != nil {
+		// The epoch isn't counted, so monitoring is extended rather than cut short.
+		h.logger.Warn("could not check validator liveness", fields.Epoch(epoch), zap.Error(err))
+		return
+	}
+	live := make(map[phase0.ValidatorIndex]bool, len(liveness))
+	for _, l := range liveness {
+		live[l.Index] = l.IsLive
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, index := range indices {
+		state, ok := h.states[index]
+		if !ok || state.Status != StatusMonitoring {
+			continue
+		}
+		isLive, ok := live[index]
+		if !ok {
+			h.logger.Warn("liveness missing for validator", zap.Uint64("validator_index", uint64(index)), fields.Epoch(epoch))
+			continue
+		}
+		if isLive && state.EpochsChecked == 0 {
+			state.LiveAtStart = true
+			h.setStatus(state, StatusDetected)
+			state.DetectedEpoch = epoch
+			h.logger.Error("🚨 possible doppelganger: validator is already live, by its cluster or elsewhere, refusing to sign for it until restart",
+				zap.Uint64("validator_index", uint64(index)),
+				fields.PubKey(state.ValidatorPubKey[:]),
+				fields.Epoch(epoch),
+			)
+			continue
+		}
+		if isLive {
+			h.setStatus(state, StatusDetected)
+			state.DetectedEpoch = epoch
+			h.logger.Error("🚨 doppelganger detected: validator is live elsewhere, refusing to sign for it until restart",
+				zap.Uint64("validator_index", uint64(index)),
+				fields.PubKey(state.ValidatorPubKey[:]),
+				fields.Epoch(epoch),
+			)
+			continue
+		}
+		state.EpochsChecked++
+		if state.EpochsChecked >= h.epochs {
+			h.setStatus(state, StatusSafe)
+			h.logger.Info("validator passed doppelganger monitoring, signing enabled",
+				zap.Uint64("validator_index", uint64(index)),
+				fields.PubKey(state.ValidatorPubKey[:]),
+			)
+		}
+	}
+}
+
+func (h *handler) setStatus(state *ValidatorState, status Status) {
+	metricsValidators.WithLabelValues(string(state.Status)).Dec()
+	metricsValidators.WithLabelValues(string(status)).Inc()
+	state.Status = status
+}
@@ -0,0 +1,115 @@
+package doppelganger
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	spectypes "github.com/ssvlabs/ssv-spec/types"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/ssvlabs/ssv/beacon/goclient"
+	beaconprotocol "github.com/ssvlabs/ssv/protocol/v2/blockchain/beacon"
+	ssvtypes "github.com/ssvlabs/ssv/protocol/v2/types"
+)
+
+type mockBeaconNode struct {
+	live    map[phase0.ValidatorIndex]bool
+	err     error
+	queried []phase0.Epoch
+}
+
+func (m *mockBeaconNode) ValidatorLiveness(_ context.Context, epoch phase0.Epoch, indices []phase0.ValidatorIndex) ([]goclient.ValidatorLiveness, error) {
+	m.queried = append(m.queried, epoch)
+	if m.err != nil {
+		return nil, m.err
+	}
+	liveness := make([]goclient.ValidatorLiveness, 0, len(indices))
+	for _, index := range indices {
+		liveness = append(liveness, goclient.ValidatorLiveness{Index: index, IsLive: m.live[index]})
+	}
+	return liveness, nil
+}
+
+func testShare(index phase0.ValidatorIndex) *ssvtypes.SSVShare {
+	share := &ssvtypes.SSVShare{
+		Share: spectypes.Share{
+			ValidatorIndex: index,
+			SharePubKey:    []byte{byte(index), 0x01},
+		},
+		Metadata: ssvtypes.Metadata{
+			BeaconMetadata: &beaconprotocol.ValidatorMetadata{Index: index},
+		},
+	}
+	share.ValidatorPubKey[0] = byte(index)
+	return share
+}
+
+func TestHandler(t *testing.T) {
+	network := beaconprotocol.NewNetwork(spectypes.MainNetwork)
+	beaconNode := &mockBeaconNode{live: map[phase0.ValidatorIndex]bool{}}
+	h := NewHandler(&Options{
+		Logger:     zap.NewNop(),
+		Network:    network,
+		BeaconNode: beaconNode,
+		Epochs:     2,
+	}).(*handler)
+
+	safeShare, doppelgangerShare, clusterShare := testShare(1), testShare(2), testShare(3)
+	beaconNode.live[3] = true
+	h.StartMonitoring(safeShare)
+	h.StartMonitoring(doppelgangerShare)
+	h.StartMonitoring(clusterShare)
+	startEpoch := network.EstimatedCurrentEpoch()
+
+	// Unmonitored shares are allowed to sign, monitored ones aren't until they're safe.
+	require.True(t, h.CanSign([]byte{0xff}))
+	require.False(t, h.CanSign(safeShare.SharePubKey))
+	require.False(t, h.CanSign(doppelgangerShare.SharePubKey))
+	require.False(t, h.CanSign(clusterShare.SharePubKey))
+
+	// The start epoch itself isn't checked.
+	h.checkEpoch(context.Background(), startEpoch)
+	require.Empty(t, beaconNode.queried)
+
+	// Failed checks aren't counted.
+	beaconNode.err = errors.New("unavailable")
+	h.checkEpoch(context.Background(), startEpoch+1)
+	require.Len(t, beaconNode.queried, 1)
+	require.EqualValues(t, 0, h.States()[0].EpochsChecked)
+	beaconNode.err = nil
+
+	// A validator which is live to begin with may be run elsewhere, even if it's kept live by its cluster.
+	h.checkEpoch(context.Background(), startEpoch+1)
+	states := h.States()
+	require.Len(t, states, 3)
+	require.Equal(t, StatusMonitoring, states[0].Status)
+	require.EqualValues(t, 1, states[0].EpochsChecked)
+	require.Equal(t, StatusMonitoring, states[1].Status)
+	require.Equal(t, StatusDetected, states[2].Status)
+	require.True(t, states[2].LiveAtStart)
+	require.Equal(t, startEpoch+1, states[2].DetectedEpoch)
+	require.False(t, h.CanSign(clusterShare.SharePubKey))
+
+	// A validator which was inactive and turns live is run elsewhere.
+	beaconNode.live[2] = true
+	h.checkEpoch(context.Background(), startEpoch+2)
+	states = h.States()
+	require.Equal(t, StatusSafe, states[0].Status)
+	require.False(t, states[0].LiveAtStart)
+	require.Equal(t, StatusDetected, states[1].Status)
+	require.False(t, states[1].LiveAtStart)
+	require.Equal(t, startEpoch+2, states[1].DetectedEpoch)
+	require.True(t, h.CanSign(safeShare.SharePubKey))
+	require.False(t, h.CanSign(doppelgangerShare.SharePubKey))
+
+	// Restarting monitoring of a tracked validator doesn't reset its state.
+	h.StartMonitoring(doppelgangerShare)
+	require.False(t, h.CanSign(doppelgangerShare.SharePubKey))
+
+	h.StopMonitoring(doppelgangerShare.BeaconMetadata.Index)
+	require.Len(t, h.States(), 2)
+	require.True(t, h.CanSign(doppelgangerShare.SharePubKey))
+}
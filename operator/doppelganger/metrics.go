@@ -0,0 +1,11 @@
+package doppelganger
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var metricsValidators = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "ssv_doppelganger_validators",
+	Help: "Number of validators by doppelganger protection status",
+}, []string{"status"})
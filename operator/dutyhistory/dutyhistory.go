@@ -0,0 +1,233 @@
+// Package dutyhistory keeps a compact record of the duties performed for each validator,
+// to find out why a duty was missed without grepping logs.
+package dutyhistory
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/pkg/errors"
+	specqbft "github.com/ssvlabs/ssv-spec/qbft"
+	spectypes "github.com/ssvlabs/ssv-spec/types"
+	"go.uber.org/zap"
+
+	"github.com/ssvlabs/ssv/protocol/v2/blockchain/beacon"
+	"github.com/ssvlabs/ssv/storage/basedb"
+)
+
+const (
+	// Records are bucketed by epoch, so that expired epochs can be dropped at once.
+	recordsPrefix = "duty_history-"
+	metaPrefix    = "duty_history_meta-"
+	prunedKey     = "pruned_epoch"
+
+	// DefaultRetentionEpochs is the default number of epochs records are kept for (about a day).
+	DefaultRetentionEpochs = 225
+
+	recordsBufferSize = 4096
+)
+
+// Config holds the duty history configuration.
+type Config struct {
+	Enabled         bool   `yaml:"Enabled" env:"DUTY_HISTORY" env-default:"false" env-description:"Keep a record of the duties performed for each validator"`
+	RetentionEpochs uint64 `yaml:"RetentionEpochs" env:"DUTY_HISTORY_RETENTION_EPOCHS" env-default:"225" env-description:"Number of epochs to keep the duty history for"`
+}
+
+// Record is the outcome of a duty of a validator.
+type Record struct {
+	Role spectypes.BeaconRole
+	Slot phase0.Slot
+	// Round is the QBFT round in which consensus was reached, or zero if it wasn't.
+	Round specqbft.Round
+	// Rounds are the QBFT rounds the duty went through, whether or not consensus was reached.
+	Rounds    []RoundStart `json:",omitempty"`
+	DecidedAt time.Time
+	// Participants are the operators whose signatures were used for the submitted signature.
+	Participants []spectypes.OperatorID
+	Submitted    bool
+	// Error is the reason the duty failed, such as an error of the beacon node.
+	Error string
+}
+
+// RoundStart is when the QBFT instance of a duty moved to a round.
+type RoundStart struct {
+	Round     specqbft.Round
+	StartedAt time.Time
+}
+
+type pendingRecord struct {
+	pubKey spectypes.ValidatorPK
+	record *Record
+}
+
+// Store is the duty history of all validators.
+type Store struct {
+	logger          *zap.Logger
+	db              basedb.Database
+	network         beacon.BeaconNetwork
+	retentionEpochs phase0.Epoch
+
+	pending chan pendingRecord
+}
+
+// New returns a Store keeping records of the given number of recent epochs.
+func New(logger *zap.Logger, db basedb.Database, network beacon.BeaconNetwork, retentionEpochs uint64) *Store {
+	if retentionEpochs == 0 {
+		retentionEpochs = DefaultRetentionEpochs
+	}
+	return &Store{
+		logger:          logger,
+		db:              db,
+		network:         network,
+		retentionEpochs: phase0.Epoch(retentionEpochs),
+		pending:         make(chan pendingRecord, recordsBufferSize),
+	}
+}
+
+// RecordDuty queues the record to be saved by Run, replacing any record of the same validator, role and slot.
+// It never blocks: the record is dropped if the queue is full.
+func (s *Store) RecordDuty(pubKey spectypes.ValidatorPK, record *Record) {
+	select {
+	case s.pending <- pendingRecord{pubKey: pubKey, record: record}:
+	default:
+		s.logger.Debug("dropped duty record because the queue is full",
+			zap.Uint64("slot", uint64(record.Slot)),
+			zap.String("role", record.Role.String()))
+	}
+}
+
+// Run saves the queued records and prunes expired ones every epoch, until the context is done.
+func (s *Store) Run(ctx context.Context) {
+	pruneTicker := time.NewTicker(s.network.SlotDurationSec() * time.Duration(s.network.SlotsPerEpoch()))
+	defer pruneTicker.Stop()
+
+	if err := s.Prune(); err != nil {
+		s.logger.Warn("failed to prune duty history", zap.Error(err))
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-pruneTicker.C:
+			if err := s.Prune(); err != nil {
+				s.logger.Warn("failed to prune duty history", zap.Error(err))
+			}
+		case first := <-s.pending:
+			// Save whatever else is queued in the same transaction.
+			batch := []pendingRecord{first}
+			for len(batch) < recordsBufferSize && len(s.pending) > 0 {
+				batch = append(batch, <-s.pending)
+			}
+			if err := s.save(batch); err != nil {
+				s.logger.Warn("failed to save duty records", zap.Int("count", len(batch)), zap.Error(err))
+			}
+		}
+	}
+}
+
+// Save saves the record right away.
+func (s *Store) Save(pubKey spectypes.ValidatorPK, record *Record) error {
+	return s.save([]pendingRecord{{pubKey: pubKey, record: record}})
+}
+
+func (s *Store) save(batch []pendingRecord) error {
+	return s.db.Update(func(txn basedb.Txn) error {
+		for _, pending := range batch {
+			value, err := json.Marshal(pending.record)
+			if err != nil {
+				return errors.Wrap(err, "could not encode record")
+			}
+			epoch := s.network.EstimatedEpochAtSlot(pending.record.Slot)
+			key := make([]byte, 16)
+			binary.BigEndian.PutUint64(key, uint64(pending.record.Slot))
+			binary.BigEndian.PutUint64(key[8:], uint64(pending.record.Role))
+			if err := txn.Set(recordsKeyPrefix(epoch, pending.pubKey), key, value); err != nil {
+				return errors.Wrap(err, "could not save record")
+			}
+		}
+		return nil
+	})
+}
+
+// Duties returns the retained records of the given validator between the given slots (inclusive),
+// ordered by slot and role.
+func (s *Store) Duties(pubKey spectypes.ValidatorPK, from, to phase0.Slot) ([]*Record, error) {
+	fromEpoch := s.network.EstimatedEpochAtSlot(from)
+	if oldest := s.oldestEpoch(); fromEpoch < oldest {
+		fromEpoch = oldest
+	}
+	toEpoch := s.network.EstimatedEpochAtSlot(to)
+	if current := s.network.EstimatedCurrentEpoch(); toEpoch > current {
+		toEpoch = current
+	}
+
+	records := make([]*Record, 0)
+	for epoch := fromEpoch; epoch <= toEpoch; epoch++ {
+		err := s.db.GetAll(recordsKeyPrefix(epoch, pubKey), func(_ int, obj basedb.Obj) error {
+			record := &Record{}
+			if err := json.Unmarshal(obj.Value, record); err != nil {
+				return errors.Wrap(err, "could not decode record")
+			}
+			if record.Slot >= from && record.Slot <= to {
+				records = append(records, record)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].Slot != records[j].Slot {
+			return records[i].Slot < records[j].Slot
+		}
+		return records[i].Role < records[j].Role
+	})
+	return records, nil
+}
+
+// Prune deletes the records of epochs older than the retention.
+func (s *Store) Prune() error {
+	oldest := s.oldestEpoch()
+
+	obj, found, err := s.db.Get([]byte(metaPrefix), []byte(prunedKey))
+	if err != nil {
+		return errors.Wrap(err, "could not get pruned epoch")
+	}
+	// Without a previous prune, there are no records older than the retention.
+	from := oldest
+	if found {
+		from = phase0.Epoch(binary.BigEndian.Uint64(obj.Value))
+	}
+
+	for epoch := from; epoch < oldest; epoch++ {
+		if err := s.db.DropPrefix(recordsEpochPrefix(epoch)); err != nil {
+			return errors.Wrapf(err, "could not drop records of epoch %d", epoch)
+		}
+	}
+	if found && from >= oldest {
+		return nil
+	}
+	return s.db.Set([]byte(metaPrefix), []byte(prunedKey), binary.BigEndian.AppendUint64(nil, uint64(oldest)))
+}
+
+// oldestEpoch returns the oldest epoch whose records are retained.
+func (s *Store) oldestEpoch() phase0.Epoch {
+	current := s.network.EstimatedCurrentEpoch()
+	if current < s.retentionEpochs {
+		return 0
+	}
+	return current - s.retentionEpochs
+}
+
+func recordsEpochPrefix(epoch phase0.Epoch) []byte {
+	return binary.BigEndian.AppendUint64([]byte(recordsPrefix), uint64(epoch))
+}
+
+func recordsKeyPrefix(epoch phase0.Epoch, pubKey spectypes.ValidatorPK) []byte {
+	return append(recordsEpochPrefix(epoch), pubKey[:]...)
+}
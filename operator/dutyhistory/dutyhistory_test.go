@@ -0,0 +1,102 @@
+package dutyhistory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	spectypes "github.com/ssvlabs/ssv-spec/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ssvlabs/ssv/logging"
+	"github.com/ssvlabs/ssv/protocol/v2/blockchain/beacon"
+	"github.com/ssvlabs/ssv/storage/basedb"
+	"github.com/ssvlabs/ssv/storage/kv"
+)
+
+func newStore(t *testing.T, db basedb.Database, retentionEpochs uint64) (*Store, beacon.BeaconNetwork) {
+	network := beacon.NewNetwork(spectypes.MainNetwork)
+	return New(logging.TestLogger(t), db, network, retentionEpochs), network
+}
+
+func newDB(t *testing.T) basedb.Database {
+	db, err := kv.NewInMemory(logging.TestLogger(t), basedb.Options{})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func TestDuties(t *testing.T) {
+	s, network := newStore(t, newDB(t), 10)
+	pk1, pk2 := spectypes.ValidatorPK{1}, spectypes.ValidatorPK{2}
+	slot := network.EstimatedCurrentSlot()
+
+	attestation := &Record{Role: spectypes.BNRoleAttester, Slot: slot - 40, Round: 1, DecidedAt: time.Now().UTC(), Participants: []spectypes.OperatorID{1, 2, 3}, Submitted: true}
+	proposal := &Record{Role: spectypes.BNRoleProposer, Slot: slot - 40, Error: "failed to get beacon block"}
+	syncCommittee := &Record{Role: spectypes.BNRoleSyncCommittee, Slot: slot - 1, Round: 2, Submitted: true}
+	require.NoError(t, s.Save(pk1, syncCommittee))
+	require.NoError(t, s.Save(pk1, proposal))
+	require.NoError(t, s.Save(pk1, attestation))
+	require.NoError(t, s.Save(pk2, &Record{Role: spectypes.BNRoleAttester, Slot: slot - 40}))
+
+	records, err := s.Duties(pk1, slot-100, slot)
+	require.NoError(t, err)
+	require.Equal(t, []*Record{attestation, proposal, syncCommittee}, records)
+
+	records, err = s.Duties(pk1, slot-40, slot-40)
+	require.NoError(t, err)
+	require.Equal(t, []*Record{attestation, proposal}, records)
+
+	// A later record of the same duty replaces the earlier one.
+	retried := &Record{Role: spectypes.BNRoleProposer, Slot: slot - 40, Round: 3, Submitted: true}
+	require.NoError(t, s.Save(pk1, retried))
+	records, err = s.Duties(pk1, slot-40, slot-40)
+	require.NoError(t, err)
+	require.Equal(t, []*Record{attestation, retried}, records)
+
+	records, err = s.Duties(spectypes.ValidatorPK{3}, slot-100, slot)
+	require.NoError(t, err)
+	require.Empty(t, records)
+}
+
+func TestRecordDuty(t *testing.T) {
+	s, network := newStore(t, newDB(t), 10)
+	pk := spectypes.ValidatorPK{1}
+	slot := network.EstimatedCurrentSlot()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Run(ctx)
+
+	record := &Record{Role: spectypes.BNRoleAttester, Slot: slot, Submitted: true}
+	s.RecordDuty(pk, record)
+	require.Eventually(t, func() bool {
+		records, err := s.Duties(pk, slot, slot)
+		require.NoError(t, err)
+		return len(records) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestPrune(t *testing.T) {
+	db := newDB(t)
+	s, network := newStore(t, db, 10)
+	pk := spectypes.ValidatorPK{1}
+	epoch := network.EstimatedCurrentEpoch()
+	slotAt := func(epoch phase0.Epoch) phase0.Slot {
+		return network.FirstSlotAtEpoch(epoch)
+	}
+
+	require.NoError(t, s.Prune())
+	require.NoError(t, s.Save(pk, &Record{Role: spectypes.BNRoleAttester, Slot: slotAt(epoch - 5)}))
+	require.NoError(t, s.Save(pk, &Record{Role: spectypes.BNRoleAttester, Slot: slotAt(epoch)}))
+
+	// Shortening the retention prunes the records which are now too old.
+	shorter, _ := newStore(t, db, 2)
+	require.NoError(t, shorter.Prune())
+
+	records, err := s.Duties(pk, slotAt(epoch-10), slotAt(epoch+1))
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	require.Equal(t, slotAt(epoch), records[0].Slot)
+}
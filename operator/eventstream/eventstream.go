@@ -0,0 +1,313 @@
+// Package eventstream publishes the node's activity, such as decided instances, duty outcomes
+// and validator lifecycle changes, to subscribers of the SSV API.
+package eventstream
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	specqbft "github.com/ssvlabs/ssv-spec/qbft"
+	spectypes "github.com/ssvlabs/ssv-spec/types"
+	"go.uber.org/zap"
+
+	"github.com/ssvlabs/ssv/operator/dutyhistory"
+	qbftstorage "github.com/ssvlabs/ssv/protocol/v2/qbft/storage"
+	registrystorage "github.com/ssvlabs/ssv/registry/storage"
+)
+
+const (
+	// DefaultHistorySize is the default number of recent events kept for subscribers to resume from.
+	DefaultHistorySize = 16384
+
+	subscriptionBufferSize = 256
+)
+
+type Type string
+
+const (
+	TypeDecided              Type = "decided"
+	TypeDuty                 Type = "duty"
+	TypeValidatorAdded       Type = "validator_added"
+	TypeValidatorRemoved     Type = "validator_removed"
+	TypeValidatorLiquidated  Type = "validator_liquidated"
+	TypeValidatorReactivated Type = "validator_reactivated"
+)
+
+// Event is an activity of the node concerning one or more validators of a cluster.
+type Event struct {
+	// ID increases with every published event, so that subscribers can resume after the last event they received.
+	// IDs start from the time the node started, so they keep increasing across restarts.
+	ID   uint64
+	Type Type
+	Slot phase0.Slot
+	// Role is the name of the beacon role of decided and duty events.
+	Role       string
+	Validators []spectypes.ValidatorPK
+	Owner      ethcommon.Address
+	// Operators are the sorted operator IDs of the validators' cluster.
+	Operators []spectypes.OperatorID
+	// Data is one of DecidedData, DutyData or LifecycleData, depending on the type.
+	Data any
+}
+
+type DecidedData struct {
+	Signers []spectypes.OperatorID `json:"signers"`
+}
+
+type DutyData struct {
+	Round        specqbft.Round         `json:"round"`
+	Rounds       []RoundStart           `json:"rounds"`
+	DecidedAt    *time.Time             `json:"decided_at,omitempty"`
+	Participants []spectypes.OperatorID `json:"participants"`
+	Submitted    bool                   `json:"submitted"`
+	Error        string                 `json:"error,omitempty"`
+}
+
+type RoundStart struct {
+	Round     specqbft.Round `json:"round"`
+	StartedAt time.Time      `json:"started_at"`
+}
+
+type LifecycleData struct {
+	BlockNumber uint64         `json:"block_number"`
+	TxHash      ethcommon.Hash `json:"tx_hash"`
+}
+
+// Filter selects events. Empty fields match every event.
+type Filter struct {
+	Types      []Type
+	Validators []spectypes.ValidatorPK
+	// Clusters are sets of operator IDs, which match events of clusters with exactly these operators.
+	Clusters [][]spectypes.OperatorID
+	Owners   []ethcommon.Address
+	Roles    []string
+}
+
+// Match returns whether the event matches all the filter's fields.
+func (f *Filter) Match(event *Event) bool {
+	if len(f.Types) > 0 && !slices.Contains(f.Types, event.Type) {
+		return false
+	}
+	if len(f.Validators) > 0 && !slices.ContainsFunc(event.Validators, func(pk spectypes.ValidatorPK) bool {
+		return slices.Contains(f.Validators, pk)
+	}) {
+		return false
+	}
+	if len(f.Clusters) > 0 && !slices.ContainsFunc(f.Clusters, func(cluster []spectypes.OperatorID) bool {
+		return slices.Equal(sortedOperators(cluster), event.Operators)
+	}) {
+		return false
+	}
+	if len(f.Owners) > 0 && !slices.Contains(f.Owners, event.Owner) {
+		return false
+	}
+	if len(f.Roles) > 0 && !slices.Contains(f.Roles, event.Role) {
+		return false
+	}
+	return true
+}
+
+// Subscription receives the published events matching its filter.
+type Subscription struct {
+	filter Filter
+	events chan *Event
+}
+
+// Events returns the channel of events, which is closed when the subscription is cancelled
+// or when the subscriber falls too far behind.
+func (s *Subscription) Events() <-chan *Event {
+	return s.events
+}
+
+// Stream publishes events to its subscribers, keeping the recent events to resume from.
+type Stream struct {
+	logger     *zap.Logger
+	validators registrystorage.BaseValidatorStore
+
+	mu            sync.Mutex
+	lastID        uint64
+	history       []*Event
+	historyStart  int
+	historyLength int
+	subscriptions map[*Subscription]struct{}
+}
+
+// New returns a Stream keeping the given number of recent events.
+func New(logger *zap.Logger, validators registrystorage.BaseValidatorStore, historySize int) *Stream {
+	if historySize <= 0 {
+		historySize = DefaultHistorySize
+	}
+	// IDs start from the microseconds since the Unix epoch, which a restarted node can't have published
+	// more events than, and which stay exact as JSON numbers.
+	return &Stream{
+		logger:        logger,
+		validators:    validators,
+		lastID:        uint64(time.Now().UnixMicro()),
+		history:       make([]*Event, historySize),
+		subscriptions: make(map[*Subscription]struct{}),
+	}
+}
+
+// Publish assigns the event an ID and sends it to the matching subscribers.
+// Subscribers which can't keep up are unsubscribed rather than blocking the publisher.
+func (s *Stream) Publish(event *Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastID++
+	event.ID = s.lastID
+	s.history[(s.historyStart+s.historyLength)%len(s.history)] = event
+	if s.historyLength < len(s.history) {
+		s.historyLength++
+	} else {
+		s.historyStart = (s.historyStart + 1) % len(s.history)
+	}
+
+	for sub := range s.subscriptions {
+		if !sub.filter.Match(event) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			s.logger.Debug("dropping slow event stream subscriber")
+			s.unsubscribe(sub)
+		}
+	}
+}
+
+// Subscribe returns the recent events matching the filter which are newer than afterID (if not zero)
+// and not older than fromSlot, and a subscription to the events published from now on.
+// An afterID newer than the last published event, such as one from before the clock was set back,
+// replays all the recent events.
+func (s *Stream) Subscribe(filter Filter, fromSlot phase0.Slot, afterID uint64) ([]*Event, *Subscription) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if afterID > s.lastID {
+		afterID = 0
+	}
+
+	var recent []*Event
+	for i := 0; i < s.historyLength; i++ {
+		event := s.history[(s.historyStart+i)%len(s.history)]
+		if event.ID > afterID && event.Slot >= fromSlot && filter.Match(event) {
+			recent = append(recent, event)
+		}
+	}
+
+	sub := &Subscription{
+		filter: filter,
+		events: make(chan *Event, subscriptionBufferSize),
+	}
+	s.subscriptions[sub] = struct{}{}
+	return recent, sub
+}
+
+// Unsubscribe cancels the subscription.
+func (s *Stream) Unsubscribe(sub *Subscription) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.unsubscribe(sub)
+}
+
+func (s *Stream) unsubscribe(sub *Subscription) {
+	if _, ok := s.subscriptions[sub]; ok {
+		delete(s.subscriptions, sub)
+		close(sub.events)
+	}
+}
+
+// HandleDecided publishes a decided instance of a validator observed by an exporter.
+// It has the signature of a qbft controller's NewDecidedHandler.
+func (s *Stream) HandleDecided(msg qbftstorage.ParticipantsRangeEntry) {
+	event := &Event{
+		Type: TypeDecided,
+		Slot: msg.Slot,
+		Role: msg.Identifier.GetRoleType().String(),
+		Data: DecidedData{Signers: msg.Signers},
+	}
+	s.setValidator(event, msg.Identifier.GetDutyExecutorID())
+	s.Publish(event)
+}
+
+// RecordDecided publishes the decided instance of a duty of one of the operator's validators.
+// It implements the runners' DecidedRecorder.
+func (s *Stream) RecordDecided(pubKey spectypes.ValidatorPK, role spectypes.BeaconRole, slot phase0.Slot, signers []spectypes.OperatorID) {
+	event := &Event{
+		Type: TypeDecided,
+		Slot: slot,
+		Role: role.String(),
+		Data: DecidedData{Signers: signers},
+	}
+	s.setValidator(event, pubKey[:])
+	s.Publish(event)
+}
+
+// RecordDuty publishes the outcome of a duty of a validator.
+// It implements the runners' DutyRecorder.
+func (s *Stream) RecordDuty(pubKey spectypes.ValidatorPK, record *dutyhistory.Record) {
+	data := DutyData{
+		Round:        record.Round,
+		Rounds:       []RoundStart{},
+		Participants: record.Participants,
+		Submitted:    record.Submitted,
+		Error:        record.Error,
+	}
+	for _, round := range record.Rounds {
+		data.Rounds = append(data.Rounds, RoundStart{Round: round.Round, StartedAt: round.StartedAt})
+	}
+	if data.Participants == nil {
+		data.Participants = []spectypes.OperatorID{}
+	}
+	if !record.DecidedAt.IsZero() {
+		decidedAt := record.DecidedAt
+		data.DecidedAt = &decidedAt
+	}
+	event := &Event{
+		Type: TypeDuty,
+		Slot: record.Slot,
+		Role: record.Role.String(),
+		Data: data,
+	}
+	s.setValidator(event, pubKey[:])
+	s.Publish(event)
+}
+
+// PublishLifecycle publishes a change of the given validators of a cluster at the given slot.
+func (s *Stream) PublishLifecycle(eventType Type, slot phase0.Slot, owner ethcommon.Address, operatorIDs []uint64, validators []spectypes.ValidatorPK, data LifecycleData) {
+	s.Publish(&Event{
+		Type:       eventType,
+		Slot:       slot,
+		Validators: validators,
+		Owner:      owner,
+		Operators:  sortedOperators(operatorIDs),
+		Data:       data,
+	})
+}
+
+func (s *Stream) setValidator(event *Event, pubKey []byte) {
+	var pk spectypes.ValidatorPK
+	copy(pk[:], pubKey)
+	event.Validators = []spectypes.ValidatorPK{pk}
+
+	share, found := s.validators.Validator(pubKey)
+	if !found {
+		return
+	}
+	event.Owner = share.OwnerAddress
+	event.Operators = make([]spectypes.OperatorID, len(share.Committee))
+	for i, member := range share.Committee {
+		event.Operators[i] = member.Signer
+	}
+	slices.Sort(event.Operators)
+}
+
+func sortedOperators(operatorIDs []spectypes.OperatorID) []spectypes.OperatorID {
+	sorted := slices.Clone(operatorIDs)
+	slices.Sort(sorted)
+	return sorted
+}
@@ -0,0 +1,152 @@
+package eventstream
+
+import (
+	"testing"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	spectypes "github.com/ssvlabs/ssv-spec/types"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ssvlabs/ssv/exporter/convert"
+	"github.com/ssvlabs/ssv/logging"
+	"github.com/ssvlabs/ssv/operator/dutyhistory"
+	qbftstorage "github.com/ssvlabs/ssv/protocol/v2/qbft/storage"
+	ssvtypes "github.com/ssvlabs/ssv/protocol/v2/types"
+	"github.com/ssvlabs/ssv/registry/storage/mocks"
+)
+
+var (
+	owner      = ethcommon.HexToAddress("0x1")
+	validatorA = spectypes.ValidatorPK{1}
+	validatorB = spectypes.ValidatorPK{2}
+)
+
+func newStream(t *testing.T, historySize int) *Stream {
+	ctrl := gomock.NewController(t)
+	validators := mocks.NewMockBaseValidatorStore(ctrl)
+	validators.EXPECT().Validator(gomock.Any()).DoAndReturn(func(pubKey []byte) (*ssvtypes.SSVShare, bool) {
+		if spectypes.ValidatorPK(pubKey) != validatorA {
+			return nil, false
+		}
+		share := &ssvtypes.SSVShare{}
+		share.OwnerAddress = owner
+		share.ValidatorPubKey = validatorA
+		share.Committee = []*spectypes.ShareMember{{Signer: 4}, {Signer: 2}, {Signer: 3}, {Signer: 1}}
+		return share, true
+	}).AnyTimes()
+	return New(logging.TestLogger(t), validators, historySize)
+}
+
+func TestFilter(t *testing.T) {
+	s := newStream(t, 10)
+	firstID := s.lastID + 1
+	_, sub := s.Subscribe(Filter{
+		Validators: []spectypes.ValidatorPK{validatorA},
+		Clusters:   [][]spectypes.OperatorID{{1, 2, 3, 4}},
+		Roles:      []string{"ATTESTER", "PROPOSER"},
+	}, 0, 0)
+
+	s.RecordDuty(validatorB, &dutyhistory.Record{Role: spectypes.BNRoleAttester, Slot: 1})
+	s.RecordDuty(validatorA, &dutyhistory.Record{Role: spectypes.BNRoleAggregator, Slot: 2})
+	s.RecordDuty(validatorA, &dutyhistory.Record{Role: spectypes.BNRoleAttester, Slot: 3, Submitted: true})
+	s.HandleDecided(qbftstorage.ParticipantsRangeEntry{
+		Slot:       4,
+		Signers:    []spectypes.OperatorID{1, 2, 3},
+		Identifier: convert.NewMsgID(spectypes.DomainType{}, validatorA[:], convert.RoleProposer),
+	})
+	s.RecordDecided(validatorA, spectypes.BNRoleAttester, 5, []spectypes.OperatorID{2, 3, 4})
+	s.PublishLifecycle(TypeValidatorAdded, 5, owner, []uint64{3, 1, 2, 4}, []spectypes.ValidatorPK{validatorA}, LifecycleData{})
+
+	event := <-sub.Events()
+	require.Equal(t, TypeDuty, event.Type)
+	require.Equal(t, firstID+2, event.ID)
+	require.Equal(t, owner, event.Owner)
+	require.Equal(t, []spectypes.OperatorID{1, 2, 3, 4}, event.Operators)
+	require.Equal(t, DutyData{Rounds: []RoundStart{}, Participants: []spectypes.OperatorID{}, Submitted: true}, event.Data)
+
+	event = <-sub.Events()
+	require.Equal(t, TypeDecided, event.Type)
+	require.Equal(t, "PROPOSER", event.Role)
+	require.Equal(t, DecidedData{Signers: []spectypes.OperatorID{1, 2, 3}}, event.Data)
+
+	// Decided events of the operator's own duties.
+	event = <-sub.Events()
+	require.Equal(t, TypeDecided, event.Type)
+	require.Equal(t, "ATTESTER", event.Role)
+	require.Equal(t, phase0.Slot(5), event.Slot)
+	require.Equal(t, []spectypes.OperatorID{1, 2, 3, 4}, event.Operators)
+	require.Equal(t, DecidedData{Signers: []spectypes.OperatorID{2, 3, 4}}, event.Data)
+
+	// Lifecycle events have no role.
+	require.Empty(t, sub.Events())
+
+	_, sub = s.Subscribe(Filter{Types: []Type{TypeValidatorAdded}, Owners: []ethcommon.Address{owner}}, 0, 0)
+	s.PublishLifecycle(TypeValidatorAdded, 6, owner, []uint64{1, 2, 3, 4}, []spectypes.ValidatorPK{validatorB}, LifecycleData{BlockNumber: 10})
+	s.PublishLifecycle(TypeValidatorRemoved, 7, owner, []uint64{1, 2, 3, 4}, []spectypes.ValidatorPK{validatorB}, LifecycleData{BlockNumber: 11})
+	event = <-sub.Events()
+	require.Equal(t, []spectypes.ValidatorPK{validatorB}, event.Validators)
+	require.Equal(t, phase0.Slot(6), event.Slot)
+	require.Equal(t, LifecycleData{BlockNumber: 10}, event.Data)
+	require.Empty(t, sub.Events())
+}
+
+func TestResume(t *testing.T) {
+	s := newStream(t, 3)
+	firstID := s.lastID + 1
+	for slot := 1; slot <= 5; slot++ {
+		s.RecordDuty(validatorA, &dutyhistory.Record{Role: spectypes.BNRoleAttester, Slot: phase0.Slot(slot)})
+	}
+
+	slots := func(events []*Event) []int {
+		var slots []int
+		for _, event := range events {
+			slots = append(slots, int(event.Slot))
+		}
+		return slots
+	}
+
+	// Only the most recent events are kept.
+	recent, _ := s.Subscribe(Filter{}, 0, 0)
+	require.Equal(t, []int{3, 4, 5}, slots(recent))
+
+	recent, _ = s.Subscribe(Filter{}, 4, 0)
+	require.Equal(t, []int{4, 5}, slots(recent))
+
+	recent, sub := s.Subscribe(Filter{}, 0, firstID+3)
+	require.Equal(t, []int{5}, slots(recent))
+
+	// IDs from before a restart are older than the IDs of the restarted stream.
+	time.Sleep(time.Millisecond)
+	restarted := newStream(t, 3)
+	require.Greater(t, restarted.lastID, firstID+4)
+
+	// IDs newer than the last event, such as from before the clock was set back, replay all the recent events.
+	recent, _ = s.Subscribe(Filter{}, 0, firstID+100)
+	require.Equal(t, []int{3, 4, 5}, slots(recent))
+
+	s.RecordDuty(validatorA, &dutyhistory.Record{Role: spectypes.BNRoleAttester, Slot: 6})
+	event := <-sub.Events()
+	require.Equal(t, firstID+5, event.ID)
+}
+
+func TestSlowSubscriber(t *testing.T) {
+	s := newStream(t, 10)
+	_, slow := s.Subscribe(Filter{}, 0, 0)
+
+	for i := 0; i <= subscriptionBufferSize; i++ {
+		s.RecordDuty(validatorA, &dutyhistory.Record{Role: spectypes.BNRoleAttester, Slot: phase0.Slot(i)})
+	}
+
+	// The buffered events are still delivered before the channel is closed.
+	received := 0
+	for range slow.Events() {
+		received++
+	}
+	require.Equal(t, subscriptionBufferSize, received)
+
+	// Unsubscribing again is a no-op.
+	s.Unsubscribe(slow)
+}
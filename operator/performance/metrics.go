@@ -0,0 +1,42 @@
+package performance
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+var (
+	metricsMessageDelay = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "ssv_operator_message_delay_seconds",
+		Help:    "Delay of operators' prepares and commits since the proposal of their round (seconds)",
+		Buckets: []float64{0.02, 0.05, 0.1, 0.2, 0.5, 1, 1.5, 2, 5},
+	}, []string{"operator_id", "type"})
+	metricsRounds = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssv_operator_rounds",
+		Help: "Count of rounds operators were expected to take part in",
+	}, []string{"operator_id"})
+	metricsMissedRounds = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssv_operator_missed_rounds",
+		Help: "Count of rounds operators missed",
+	}, []string{"operator_id"})
+	metricsRoundChangesInitiated = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssv_operator_round_changes_initiated",
+		Help: "Count of rounds whose first round change was from the operator",
+	}, []string{"operator_id"})
+)
+
+func init() {
+	allMetrics := []prometheus.Collector{
+		metricsMessageDelay,
+		metricsRounds,
+		metricsMissedRounds,
+		metricsRoundChangesInitiated,
+	}
+	logger := zap.L()
+	for _, c := range allMetrics {
+		if err := prometheus.Register(c); err != nil {
+			logger.Debug("could not register prometheus collector")
+		}
+	}
+}
@@ -0,0 +1,183 @@
+// Package performance scores how quickly and reliably operators take part in the consensus
+// of the committees this node shares with them, based on the QBFT messages it receives from them.
+package performance
+
+import (
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	specqbft "github.com/ssvlabs/ssv-spec/qbft"
+	spectypes "github.com/ssvlabs/ssv-spec/types"
+)
+
+// DefaultWindow is the default number of recent delays the median delays are computed over.
+const DefaultWindow = 256
+
+// Performance is the performance of an operator since the node started.
+type Performance struct {
+	OperatorID spectypes.OperatorID
+	// PrepareDelay is the median delay of the operator's recent prepares since the proposal of their round was received.
+	PrepareDelay time.Duration
+	// CommitDelay is the median delay of the operator's recent commits since the proposal of their round was received.
+	CommitDelay time.Duration
+	// Messages is the number of prepares and commits the medians are computed over.
+	Messages int
+	// Rounds is the number of rounds the operator was expected to take part in.
+	Rounds uint64
+	// MissedRounds is the number of rounds which timed out without the operator's prepare or commit,
+	// or without its proposal if it was the leader.
+	MissedRounds uint64
+	// RoundChangesInitiated is the number of rounds the operator's round change was the first one of.
+	RoundChangesInitiated uint64
+}
+
+// Reliability returns the share of rounds the operator didn't miss, or 1 if it wasn't expected in any.
+func (p Performance) Reliability() float64 {
+	if p.Rounds == 0 {
+		return 1
+	}
+	return 1 - float64(p.MissedRounds)/float64(p.Rounds)
+}
+
+// delayWindow holds the most recent delays, up to its capacity.
+type delayWindow struct {
+	delays []time.Duration
+	next   int
+}
+
+func (w *delayWindow) add(delay time.Duration, size int) {
+	if len(w.delays) < size {
+		w.delays = append(w.delays, delay)
+		return
+	}
+	w.delays[w.next] = delay
+	w.next = (w.next + 1) % size
+}
+
+func (w *delayWindow) median() time.Duration {
+	if len(w.delays) == 0 {
+		return 0
+	}
+	sorted := slices.Clone(w.delays)
+	slices.Sort(sorted)
+	return sorted[len(sorted)/2]
+}
+
+type operatorStats struct {
+	prepareDelays         delayWindow
+	commitDelays          delayWindow
+	rounds                uint64
+	missedRounds          uint64
+	roundChangesInitiated uint64
+}
+
+// Tracker keeps the performance of operators. It implements qbft.PerformanceRecorder,
+// and is shared by the instances of all committees.
+type Tracker struct {
+	window int
+
+	mu        sync.RWMutex
+	operators map[spectypes.OperatorID]*operatorStats
+}
+
+// Option configures a Tracker.
+type Option func(*Tracker)
+
+// WithWindow sets the number of recent delays the median delays are computed over.
+func WithWindow(window int) Option {
+	return func(t *Tracker) {
+		t.window = window
+	}
+}
+
+// NewTracker returns an empty Tracker.
+func NewTracker(opts ...Option) *Tracker {
+	t := &Tracker{
+		window:    DefaultWindow,
+		operators: make(map[spectypes.OperatorID]*operatorStats),
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// stats returns the stats of the operator, and must be called with mu locked.
+func (t *Tracker) stats(operatorID spectypes.OperatorID) *operatorStats {
+	stats, ok := t.operators[operatorID]
+	if !ok {
+		stats = &operatorStats{}
+		t.operators[operatorID] = stats
+	}
+	return stats
+}
+
+// MessageDelay records the delay of an operator's prepare or commit since the proposal of its round was received.
+func (t *Tracker) MessageDelay(operatorID spectypes.OperatorID, msgType specqbft.MessageType, delay time.Duration) {
+	var label string
+	t.mu.Lock()
+	switch msgType {
+	case specqbft.PrepareMsgType:
+		t.stats(operatorID).prepareDelays.add(delay, t.window)
+		label = "prepare"
+	case specqbft.CommitMsgType:
+		t.stats(operatorID).commitDelays.add(delay, t.window)
+		label = "commit"
+	}
+	t.mu.Unlock()
+
+	if label != "" {
+		metricsMessageDelay.WithLabelValues(operatorLabel(operatorID), label).Observe(delay.Seconds())
+	}
+}
+
+// RoundObserved records a round the operator was expected to take part in, and whether it missed it.
+func (t *Tracker) RoundObserved(operatorID spectypes.OperatorID, missed bool) {
+	t.mu.Lock()
+	stats := t.stats(operatorID)
+	stats.rounds++
+	if missed {
+		stats.missedRounds++
+	}
+	t.mu.Unlock()
+
+	metricsRounds.WithLabelValues(operatorLabel(operatorID)).Inc()
+	if missed {
+		metricsMissedRounds.WithLabelValues(operatorLabel(operatorID)).Inc()
+	}
+}
+
+// RoundChangeInitiated records that the operator's round change was the first one of a round.
+func (t *Tracker) RoundChangeInitiated(operatorID spectypes.OperatorID) {
+	t.mu.Lock()
+	t.stats(operatorID).roundChangesInitiated++
+	t.mu.Unlock()
+
+	metricsRoundChangesInitiated.WithLabelValues(operatorLabel(operatorID)).Inc()
+}
+
+// Performance returns the performance of the operator, and false if nothing was recorded about it.
+func (t *Tracker) Performance(operatorID spectypes.OperatorID) (Performance, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	stats, ok := t.operators[operatorID]
+	if !ok {
+		return Performance{}, false
+	}
+	return Performance{
+		OperatorID:            operatorID,
+		PrepareDelay:          stats.prepareDelays.median(),
+		CommitDelay:           stats.commitDelays.median(),
+		Messages:              len(stats.prepareDelays.delays) + len(stats.commitDelays.delays),
+		Rounds:                stats.rounds,
+		MissedRounds:          stats.missedRounds,
+		RoundChangesInitiated: stats.roundChangesInitiated,
+	}, true
+}
+
+func operatorLabel(operatorID spectypes.OperatorID) string {
+	return strconv.FormatUint(operatorID, 10)
+}
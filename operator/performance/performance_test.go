@@ -0,0 +1,50 @@
+package performance
+
+import (
+	"testing"
+	"time"
+
+	specqbft "github.com/ssvlabs/ssv-spec/qbft"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ssvlabs/ssv/protocol/v2/qbft"
+)
+
+var _ qbft.PerformanceRecorder = (*Tracker)(nil)
+
+func TestTracker(t *testing.T) {
+	tracker := NewTracker(WithWindow(3))
+
+	_, found := tracker.Performance(1)
+	require.False(t, found)
+
+	for _, delay := range []time.Duration{500, 100, 300, 200} {
+		tracker.MessageDelay(1, specqbft.PrepareMsgType, delay*time.Millisecond)
+	}
+	tracker.MessageDelay(1, specqbft.CommitMsgType, time.Second)
+	tracker.RoundObserved(1, false)
+	tracker.RoundObserved(1, false)
+	tracker.RoundObserved(1, false)
+	tracker.RoundObserved(1, true)
+	tracker.RoundChangeInitiated(1)
+	tracker.RoundObserved(2, true)
+
+	performance, found := tracker.Performance(1)
+	require.True(t, found)
+	require.Equal(t, Performance{
+		OperatorID: 1,
+		// The oldest prepare delay is out of the window.
+		PrepareDelay:          200 * time.Millisecond,
+		CommitDelay:           time.Second,
+		Messages:              4,
+		Rounds:                4,
+		MissedRounds:          1,
+		RoundChangesInitiated: 1,
+	}, performance)
+	require.Equal(t, 0.75, performance.Reliability())
+
+	performance, found = tracker.Performance(2)
+	require.True(t, found)
+	require.Zero(t, performance.Reliability())
+	require.Equal(t, float64(1), Performance{}.Reliability())
+}
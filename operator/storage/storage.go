@@ -138,6 +138,10 @@ func (s *storage) DeleteRecipientData(rw basedb.ReadWriter, owner common.Address
 	return s.recipientStore.DeleteRecipientData(rw, owner)
 }
 
+func (s *storage) ListRecipients(r basedb.Reader) ([]registrystorage.RecipientData, error) {
+	return s.recipientStore.ListRecipients(r)
+}
+
 func (s *storage) GetNextNonce(r basedb.Reader, owner common.Address) (registrystorage.Nonce, error) {
 	return s.recipientStore.GetNextNonce(r, owner)
 }
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/attestantio/go-eth2-client/spec/bellatrix"
@@ -30,6 +31,7 @@ import (
 	"github.com/ssvlabs/ssv/network"
 	"github.com/ssvlabs/ssv/networkconfig"
 	operatordatastore "github.com/ssvlabs/ssv/operator/datastore"
+	"github.com/ssvlabs/ssv/operator/doppelganger"
 	"github.com/ssvlabs/ssv/operator/duties"
 	"github.com/ssvlabs/ssv/operator/slotticker"
 	nodestorage "github.com/ssvlabs/ssv/operator/storage"
@@ -100,6 +102,11 @@ type ControllerOptions struct {
 	ValidatorsMap              *validators.ValidatorsMap
 	NetworkConfig              networkconfig.NetworkConfig
 	Graffiti                   []byte
+	DoppelgangerHandler        doppelganger.Handler
+	DutyRecorder               runner.DutyRecorder
+	QueueDropPolicy            queue.DropPolicy
+	TimeoutPolicy              roundtimer.TimeoutPolicy
+	PerformanceRecorder        qbft.PerformanceRecorder
 
 	// worker flags
 	WorkersCount    int `yaml:"MsgWorkersCount" env:"MSG_WORKERS_COUNT" env-default:"256" env-description:"Number of goroutines to use for message workers"`
@@ -125,6 +132,9 @@ type Controller interface {
 	AllActiveIndices(epoch phase0.Epoch, afterInit bool) []phase0.ValidatorIndex
 	GetValidator(pubKey spectypes.ValidatorPK) (*validators.ValidatorContainer, bool)
 	UpdateValidatorMetaDataLoop()
+	// RefreshValidatorsMetadata fetches and updates the metadata of the given validators right away,
+	// or of all non-liquidated validators if none are given, and returns the number of validators refreshed.
+	RefreshValidatorsMetadata(pubKeys [][]byte) (int, error)
 	ForkListener(logger *zap.Logger)
 	StartNetworkHandlers()
 	GetOperatorShares() []*ssvtypes.SSVShare
@@ -136,6 +146,10 @@ type Controller interface {
 	IndicesChangeChan() chan struct{}
 	ValidatorExitChan() <-chan duties.ExitDescriptor
 
+	// SetDraining makes the controller stop or resume executing new duties, e.g. around maintenance.
+	SetDraining(draining bool)
+	Draining() bool
+
 	StopValidator(pubKey spectypes.ValidatorPK) error
 	LiquidateCluster(owner common.Address, operatorIDs []uint64, toLiquidate []*ssvtypes.SSVShare) error
 	ReactivateCluster(owner common.Address, operatorIDs []uint64, toReactivate []*ssvtypes.SSVShare) error
@@ -201,6 +215,7 @@ type controller struct {
 	committeeValidatorSetup chan struct{}
 
 	metadataUpdateInterval time.Duration
+	metadataUpdateMu       sync.Mutex
 
 	operatorsIDs         *sync.Map
 	network              P2PNetwork
@@ -216,6 +231,11 @@ type controller struct {
 	recentlyStartedValidators uint64
 	indicesChange             chan struct{}
 	validatorExitCh           chan duties.ExitDescriptor
+
+	// doppelgangerHandler is nil when doppelganger protection is disabled.
+	doppelgangerHandler doppelganger.Handler
+
+	draining atomic.Bool
 }
 
 // NewController creates a new validator controller instance
@@ -238,16 +258,20 @@ func NewController(logger *zap.Logger, options ControllerOptions) Controller {
 		GenesisBeacon: options.GenesisBeacon,
 		Storage:       options.StorageMap,
 		//Share:   nil,  // set per validator
-		Signer:            options.BeaconSigner,
-		OperatorSigner:    options.OperatorSigner,
-		DutyRunners:       nil, // set per validator
-		NewDecidedHandler: options.NewDecidedHandler,
-		FullNode:          options.FullNode,
-		Exporter:          options.Exporter,
-		GasLimit:          options.GasLimit,
-		MessageValidator:  options.MessageValidator,
-		Metrics:           options.Metrics,
-		Graffiti:          options.Graffiti,
+		Signer:              options.BeaconSigner,
+		OperatorSigner:      options.OperatorSigner,
+		DutyRunners:         nil, // set per validator
+		NewDecidedHandler:   options.NewDecidedHandler,
+		FullNode:            options.FullNode,
+		Exporter:            options.Exporter,
+		GasLimit:            options.GasLimit,
+		MessageValidator:    options.MessageValidator,
+		Metrics:             options.Metrics,
+		Graffiti:            options.Graffiti,
+		DutyRecorder:        options.DutyRecorder,
+		QueueDropPolicy:     options.QueueDropPolicy,
+		TimeoutPolicy:       options.TimeoutPolicy,
+		PerformanceRecorder: options.PerformanceRecorder,
 		GenesisOptions: validator.GenesisOptions{
 			Network:           options.GenesisControllerOptions.Network,
 			Signer:            options.GenesisControllerOptions.KeyManager,
@@ -324,7 +348,8 @@ func NewController(logger *zap.Logger, options ControllerOptions) Controller {
 		validatorExitCh:         make(chan duties.ExitDescriptor),
 		committeeValidatorSetup: make(chan struct{}, 1),
 
-		messageValidator: options.MessageValidator,
+		messageValidator:    options.MessageValidator,
+		doppelgangerHandler: options.DoppelgangerHandler,
 	}
 	ctrl.genesisCtx, ctrl.cancelGenesisCtx = context.WithCancel(options.Context)
 
@@ -701,7 +726,22 @@ func (c *controller) GetValidator(pubKey spectypes.ValidatorPK) (*validators.Val
 	return c.validatorsMap.GetValidator(pubKey)
 }
 
+func (c *controller) SetDraining(draining bool) {
+	if c.draining.Swap(draining) != draining {
+		c.logger.Info("changed draining state", zap.Bool("draining", draining))
+	}
+}
+
+func (c *controller) Draining() bool {
+	return c.draining.Load()
+}
+
 func (c *controller) ExecuteGenesisDuty(logger *zap.Logger, duty *genesisspectypes.Duty) {
+	if c.draining.Load() {
+		logger.Debug("skipping duty while draining")
+		return
+	}
+
 	// because we're using the same duty for more than 1 duty (e.g. attest + aggregator) there is an error in bls.Deserialize func for cgo pointer to pointer,
 	// so we need to copy the pubkey to avoid pointer.
 	var pk phase0.BLSPubKey
@@ -729,6 +769,11 @@ func (c *controller) ExecuteGenesisDuty(logger *zap.Logger, duty *genesisspectyp
 }
 
 func (c *controller) ExecuteDuty(logger *zap.Logger, duty *spectypes.ValidatorDuty) {
+	if c.draining.Load() {
+		logger.Debug("skipping duty while draining")
+		return
+	}
+
 	// because we're using the same duty for more than 1 duty (e.g. attest + aggregator) there is an error in bls.Deserialize func for cgo pointer to pointer.
 	// so we need to copy the pubkey val to avoid pointer
 	pk := make([]byte, 48)
@@ -755,6 +800,11 @@ func (c *controller) ExecuteDuty(logger *zap.Logger, duty *spectypes.ValidatorDu
 }
 
 func (c *controller) ExecuteCommitteeDuty(logger *zap.Logger, committeeID spectypes.CommitteeID, duty *spectypes.CommitteeDuty) {
+	if c.draining.Load() {
+		logger.Debug("skipping duty while draining")
+		return
+	}
+
 	if cm, ok := c.validatorsMap.GetCommittee(committeeID); ok {
 		ssvMsg, err := CreateCommitteeDutyExecuteMsg(duty, committeeID, c.networkConfig.DomainType())
 		if err != nil {
@@ -866,6 +916,9 @@ func (c *controller) onShareStop(pubKey spectypes.ValidatorPK) {
 	// stop instance
 	v.Stop()
 	c.logger.Debug("validator was stopped", fields.PubKey(pubKey[:]))
+	if c.doppelgangerHandler != nil && v.Share().HasBeaconMetadata() {
+		c.doppelgangerHandler.StopMonitoring(v.Share().BeaconMetadata.Index)
+	}
 	vc, ok := c.validatorsMap.GetCommittee(v.Share().CommitteeID())
 	if ok {
 		vc.RemoveShare(v.Share().Share.ValidatorIndex)
@@ -964,7 +1017,10 @@ func (c *controller) onShareInit(share *ssvtypes.SSVShare) (*validators.Validato
 
 		committeeRunnerFunc := SetupCommitteeRunners(ctx, opts)
 
-		vc = validator.NewCommittee(ctx, cancel, logger, c.beacon.GetBeaconNetwork(), operator, committeeRunnerFunc, nil)
+		vc = validator.NewCommittee(ctx, cancel, logger, c.beacon.GetBeaconNetwork(), operator, committeeRunnerFunc, nil,
+			validator.WithCommitteeMetrics(c.metrics),
+			validator.WithQueueDropPolicy(opts.QueueDropPolicy),
+		)
 		vc.AddShare(&share.Share)
 		c.validatorsMap.PutCommittee(operator.CommitteeID, vc)
 
@@ -1078,6 +1134,10 @@ func (c *controller) startValidator(v *validators.ValidatorContainer) (bool, err
 	if v.Share().BeaconMetadata.Index == 0 {
 		return false, errors.New("could not start validator: index not found")
 	}
+	if c.doppelgangerHandler != nil {
+		// Signing stays blocked until the validator is known not to be run elsewhere.
+		c.doppelgangerHandler.StartMonitoring(v.Share())
+	}
 	started, err := c.validatorStart(v)
 	if err != nil {
 		c.metrics.ValidatorError(v.Share().ValidatorPubKey[:])
@@ -1186,7 +1246,28 @@ func (c *controller) UpdateValidatorMetaDataLoop() {
 	}
 }
 
+func (c *controller) RefreshValidatorsMetadata(pubKeys [][]byte) (int, error) {
+	const batchSize = 512
+
+	if len(pubKeys) == 0 {
+		for _, share := range c.sharesStorage.List(nil, registrystorage.ByNotLiquidated()) {
+			pubKeys = append(pubKeys, share.ValidatorPubKey[:])
+		}
+	}
+	for start := 0; start < len(pubKeys); start += batchSize {
+		end := min(start+batchSize, len(pubKeys))
+		if err := c.fetchAndUpdateValidatorsMetadata(c.logger, pubKeys[start:end], c.beacon); err != nil {
+			return start, err
+		}
+	}
+	return len(pubKeys), nil
+}
+
 func (c *controller) fetchAndUpdateValidatorsMetadata(logger *zap.Logger, pks [][]byte, beacon beaconprotocol.BeaconNode) error {
+	// Serialize updates, which may come from both the update loop and RefreshValidatorsMetadata.
+	c.metadataUpdateMu.Lock()
+	defer c.metadataUpdateMu.Unlock()
+
 	// Fetch metadata for all validators.
 	c.recentlyStartedValidators = 0
 	beforeUpdate := c.AllActiveIndices(c.beacon.GetBeaconNetwork().EstimatedCurrentEpoch(), false)
@@ -1241,8 +1322,10 @@ func SetupCommitteeRunners(
 			},
 			Storage:     options.Storage.Get(convert.RunnerRole(role)),
 			Network:     options.Network,
-			Timer:       roundtimer.New(ctx, options.NetworkConfig.Beacon, role, nil),
+			Timer:       roundtimer.New(ctx, options.NetworkConfig.Beacon, role, nil, roundtimer.WithTimeoutPolicy(options.TimeoutPolicy, options.Operator.CommitteeID)),
 			CutOffRound: roundtimer.CutOffRound,
+
+			PerformanceRecorder: options.PerformanceRecorder,
 		}
 
 		identifier := spectypes.NewMsgID(options.NetworkConfig.AlanDomainType, options.Operator.CommitteeID[:], role)
@@ -1268,7 +1351,9 @@ func SetupCommitteeRunners(
 		if err != nil {
 			return nil, err
 		}
-		return crunner.(*runner.CommitteeRunner), nil
+		committeeRunner := crunner.(*runner.CommitteeRunner)
+		committeeRunner.BaseRunner.DutyRecorder = options.DutyRecorder
+		return committeeRunner, nil
 	}
 }
 
@@ -1305,8 +1390,10 @@ func SetupRunners(
 			},
 			Storage:     options.Storage.Get(convert.RunnerRole(role)),
 			Network:     options.Network,
-			Timer:       roundtimer.New(ctx, options.NetworkConfig.Beacon, role, nil),
+			Timer:       roundtimer.New(ctx, options.NetworkConfig.Beacon, role, nil, roundtimer.WithTimeoutPolicy(options.TimeoutPolicy, options.Operator.CommitteeID)),
 			CutOffRound: roundtimer.CutOffRound,
+
+			PerformanceRecorder: options.PerformanceRecorder,
 		}
 		config.ValueCheckF = valueCheckF
 
@@ -1353,6 +1440,9 @@ func SetupRunners(
 		if err != nil {
 			return nil, errors.Wrap(err, "could not create duty runner")
 		}
+		if dutyRunner := runners[role]; dutyRunner != nil {
+			dutyRunner.GetBaseRunner().DutyRecorder = options.DutyRecorder
+		}
 	}
 	return runners, nil
 }
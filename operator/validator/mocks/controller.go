@@ -65,6 +65,20 @@ func (mr *MockControllerMockRecorder) AllActiveIndices(epoch, afterInit any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AllActiveIndices", reflect.TypeOf((*MockController)(nil).AllActiveIndices), epoch, afterInit)
 }
 
+// Draining mocks base method.
+func (m *MockController) Draining() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Draining")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Draining indicates an expected call of Draining.
+func (mr *MockControllerMockRecorder) Draining() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Draining", reflect.TypeOf((*MockController)(nil).Draining))
+}
+
 // ExecuteCommitteeDuty mocks base method.
 func (m *MockController) ExecuteCommitteeDuty(logger *zap.Logger, committeeID types0.CommitteeID, duty *types0.CommitteeDuty) {
 	m.ctrl.T.Helper()
@@ -201,6 +215,21 @@ func (mr *MockControllerMockRecorder) LiquidateCluster(owner, operatorIDs, toLiq
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LiquidateCluster", reflect.TypeOf((*MockController)(nil).LiquidateCluster), owner, operatorIDs, toLiquidate)
 }
 
+// RefreshValidatorsMetadata mocks base method.
+func (m *MockController) RefreshValidatorsMetadata(pubKeys [][]byte) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshValidatorsMetadata", pubKeys)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefreshValidatorsMetadata indicates an expected call of RefreshValidatorsMetadata.
+func (mr *MockControllerMockRecorder) RefreshValidatorsMetadata(pubKeys any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshValidatorsMetadata", reflect.TypeOf((*MockController)(nil).RefreshValidatorsMetadata), pubKeys)
+}
+
 // ReactivateCluster mocks base method.
 func (m *MockController) ReactivateCluster(owner common.Address, operatorIDs []uint64, toReactivate []*types1.SSVShare) error {
 	m.ctrl.T.Helper()
@@ -215,6 +244,18 @@ func (mr *MockControllerMockRecorder) ReactivateCluster(owner, operatorIDs, toRe
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReactivateCluster", reflect.TypeOf((*MockController)(nil).ReactivateCluster), owner, operatorIDs, toReactivate)
 }
 
+// SetDraining mocks base method.
+func (m *MockController) SetDraining(draining bool) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetDraining", draining)
+}
+
+// SetDraining indicates an expected call of SetDraining.
+func (mr *MockControllerMockRecorder) SetDraining(draining any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDraining", reflect.TypeOf((*MockController)(nil).SetDraining), draining)
+}
+
 // StartNetworkHandlers mocks base method.
 func (m *MockController) StartNetworkHandlers() {
 	m.ctrl.T.Helper()
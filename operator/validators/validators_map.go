@@ -162,6 +162,23 @@ func (vm *ValidatorsMap) RemoveCommittee(pubKey spectypes.CommitteeID) *validato
 	return nil
 }
 
+// DumpCommitteeQueues returns the messages in the queues of the committee and of its validators.
+func (vm *ValidatorsMap) DumpCommitteeQueues(committeeID spectypes.CommitteeID) ([]validator.QueueDump, bool) {
+	committee, ok := vm.GetCommittee(committeeID)
+	if !ok {
+		return nil, false
+	}
+
+	dumps := committee.DumpQueues()
+	vm.ForEachValidator(func(vc *ValidatorContainer) bool {
+		if v := vc.Validator(); v != nil && v.Share.CommitteeID() == committeeID {
+			dumps = append(dumps, v.DumpQueues()...)
+		}
+		return true
+	})
+	return dumps, true
+}
+
 // SizeCommittees returns the number of committees in the map
 func (vm *ValidatorsMap) SizeCommittees() int {
 	vm.mlock.RLock()
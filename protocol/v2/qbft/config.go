@@ -1,6 +1,8 @@
 package qbft
 
 import (
+	"time"
+
 	specqbft "github.com/ssvlabs/ssv-spec/qbft"
 	spectypes "github.com/ssvlabs/ssv-spec/types"
 
@@ -31,6 +33,18 @@ type IConfig interface {
 	GetTimer() roundtimer.Timer
 	// GetRoundCutOff returns the round cut off
 	GetCutOffRound() specqbft.Round
+	// GetPerformanceRecorder returns the recorder of operator performance, or nil
+	GetPerformanceRecorder() PerformanceRecorder
+}
+
+// PerformanceRecorder records how the operators of a committee take part in the rounds of its instances.
+type PerformanceRecorder interface {
+	// MessageDelay records the delay of an operator's prepare or commit since the proposal of its round was received.
+	MessageDelay(operatorID spectypes.OperatorID, msgType specqbft.MessageType, delay time.Duration)
+	// RoundObserved records a round the operator was expected to take part in, and whether it missed it.
+	RoundObserved(operatorID spectypes.OperatorID, missed bool)
+	// RoundChangeInitiated records that the operator's round change was the first one of a round.
+	RoundChangeInitiated(operatorID spectypes.OperatorID)
 }
 
 type Config struct {
@@ -42,6 +56,8 @@ type Config struct {
 	Network      specqbft.Network
 	Timer        roundtimer.Timer
 	CutOffRound  specqbft.Round
+	// PerformanceRecorder is optional.
+	PerformanceRecorder PerformanceRecorder
 }
 
 // GetShareSigner returns a BeaconSigner instance
@@ -82,3 +98,8 @@ func (c *Config) GetTimer() roundtimer.Timer {
 func (c *Config) GetCutOffRound() specqbft.Round {
 	return c.CutOffRound
 }
+
+// GetPerformanceRecorder returns the recorder of operator performance, or nil
+func (c *Config) GetPerformanceRecorder() PerformanceRecorder {
+	return c.PerformanceRecorder
+}
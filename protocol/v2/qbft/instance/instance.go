@@ -4,6 +4,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 	specqbft "github.com/ssvlabs/ssv-spec/qbft"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/ssvlabs/ssv/logging/fields"
 	"github.com/ssvlabs/ssv/protocol/v2/qbft"
+	"github.com/ssvlabs/ssv/protocol/v2/qbft/roundtimer"
 	ssvtypes "github.com/ssvlabs/ssv/protocol/v2/types"
 )
 
@@ -29,6 +31,19 @@ type Instance struct {
 	StartValue []byte
 
 	metrics *metrics
+
+	// performance and roundChanges are observed for the config's PerformanceRecorder.
+	performance  roundPerformance
+	roundChanges map[specqbft.Round]struct{}
+
+	// rounds are the rounds the instance went through.
+	rounds []RoundStart
+}
+
+// RoundStart is when an instance moved to a round.
+type RoundStart struct {
+	Round specqbft.Round
+	Time  time.Time
 }
 
 func NewInstance(
@@ -136,6 +151,7 @@ func (i *Instance) ProcessMsg(logger *zap.Logger, msg *specqbft.ProcessingMessag
 	}
 
 	res := i.processMsgF.Run(func() interface{} {
+		i.observePerformance(msg)
 
 		switch msg.QBFTMessage.MsgType {
 		case specqbft.ProposalMsgType:
@@ -145,6 +161,12 @@ func (i *Instance) ProcessMsg(logger *zap.Logger, msg *specqbft.ProcessingMessag
 		case specqbft.CommitMsgType:
 			decided, decidedValue, aggregatedCommit, err = i.UponCommit(logger, msg, i.State.CommitContainer)
 			if decided {
+				if !i.State.Decided {
+					if observer, ok := i.config.GetTimer().(roundtimer.DecidedObserver); ok {
+						observer.Decided(msg.QBFTMessage.Round)
+					}
+					i.endRoundPerformance(false)
+				}
 				i.State.Decided = decided
 				i.State.DecidedValue = decidedValue
 			}
@@ -250,10 +272,18 @@ func (i *Instance) Decode(data []byte) error {
 
 // bumpToRound sets round and sends current round metrics.
 func (i *Instance) bumpToRound(round specqbft.Round) {
+	if len(i.rounds) == 0 || i.rounds[len(i.rounds)-1].Round != round {
+		i.rounds = append(i.rounds, RoundStart{Round: round, Time: time.Now()})
+	}
 	i.State.Round = round
 	i.metrics.SetRound(round)
 }
 
+// Rounds returns the rounds the instance went through, in order.
+func (i *Instance) Rounds() []RoundStart {
+	return i.rounds
+}
+
 // CanProcessMessages will return true if instance can process messages
 func (i *Instance) CanProcessMessages() bool {
 	return !i.forceStop && i.State.Round < i.config.GetCutOffRound()
@@ -7,6 +7,8 @@ import (
 	spectypes "github.com/ssvlabs/ssv-spec/types"
 	"github.com/ssvlabs/ssv-spec/types/testingutils"
 	"github.com/stretchr/testify/require"
+
+	"github.com/ssvlabs/ssv/protocol/v2/qbft"
 )
 
 func TestInstance_Marshaling(t *testing.T) {
@@ -74,3 +76,18 @@ func TestInstance_Marshaling(t *testing.T) {
 	require.NoError(t, err)
 	require.EqualValues(t, byts, bytsDecoded)
 }
+
+func TestInstance_Rounds(t *testing.T) {
+	i := NewInstance(&qbft.Config{}, testingutils.TestingCommitteeMember(testingutils.Testing4SharesSet()), []byte{1, 2, 3, 4}, specqbft.FirstHeight, nil)
+	require.Empty(t, i.Rounds())
+
+	i.bumpToRound(specqbft.FirstRound)
+	i.bumpToRound(3)
+	i.bumpToRound(3)
+
+	rounds := i.Rounds()
+	require.Len(t, rounds, 2)
+	require.Equal(t, specqbft.FirstRound, rounds[0].Round)
+	require.Equal(t, specqbft.Round(3), rounds[1].Round)
+	require.False(t, rounds[1].Time.Before(rounds[0].Time))
+}
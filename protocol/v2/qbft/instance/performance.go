@@ -0,0 +1,99 @@
+package instance
+
+import (
+	"time"
+
+	specqbft "github.com/ssvlabs/ssv-spec/qbft"
+	spectypes "github.com/ssvlabs/ssv-spec/types"
+)
+
+type responseKey struct {
+	operatorID spectypes.OperatorID
+	msgType    specqbft.MessageType
+}
+
+// roundPerformance is what the instance received from the operators in a round.
+type roundPerformance struct {
+	round specqbft.Round
+	// proposalTime is when the proposal of the round was received, or zero if it wasn't.
+	proposalTime time.Time
+	responses    map[responseKey]struct{}
+}
+
+// observePerformance records the delay of prepares and commits since the proposal of their round,
+// and the first round change of each round. It's called with valid messages only.
+func (i *Instance) observePerformance(msg *specqbft.ProcessingMessage) {
+	recorder := i.config.GetPerformanceRecorder()
+	if recorder == nil || len(msg.SignedMessage.OperatorIDs) != 1 {
+		return
+	}
+	signer := msg.SignedMessage.OperatorIDs[0]
+	round := msg.QBFTMessage.Round
+
+	switch msg.QBFTMessage.MsgType {
+	case specqbft.ProposalMsgType:
+		if round > i.performance.round {
+			i.performance = roundPerformance{round: round}
+		}
+		if round == i.performance.round && i.performance.proposalTime.IsZero() {
+			i.performance.proposalTime = time.Now()
+			i.performance.responses = make(map[responseKey]struct{})
+		}
+	case specqbft.PrepareMsgType, specqbft.CommitMsgType:
+		if round != i.performance.round || i.performance.proposalTime.IsZero() {
+			return
+		}
+		key := responseKey{operatorID: signer, msgType: msg.QBFTMessage.MsgType}
+		if _, ok := i.performance.responses[key]; ok {
+			return
+		}
+		i.performance.responses[key] = struct{}{}
+		recorder.MessageDelay(signer, msg.QBFTMessage.MsgType, time.Since(i.performance.proposalTime))
+	case specqbft.RoundChangeMsgType:
+		i.roundChangeInitiated(round, signer)
+	}
+}
+
+// roundChangeInitiated records the operator as the initiator of the change to the given round,
+// unless another operator's round change to it came first.
+func (i *Instance) roundChangeInitiated(round specqbft.Round, operatorID spectypes.OperatorID) {
+	recorder := i.config.GetPerformanceRecorder()
+	if recorder == nil {
+		return
+	}
+	if i.roundChanges == nil {
+		i.roundChanges = make(map[specqbft.Round]struct{})
+	}
+	if _, ok := i.roundChanges[round]; ok {
+		return
+	}
+	i.roundChanges[round] = struct{}{}
+	recorder.RoundChangeInitiated(operatorID)
+}
+
+// endRoundPerformance records which operators took part in the current round when it ends.
+// Operators which didn't respond are only counted as missing rounds which timed out,
+// since they might still respond after a round is decided. If the proposal of a round which
+// timed out wasn't received, only its leader is counted as missing it.
+func (i *Instance) endRoundPerformance(timedOut bool) {
+	recorder := i.config.GetPerformanceRecorder()
+	if recorder == nil {
+		return
+	}
+	if i.performance.round != i.State.Round || i.performance.proposalTime.IsZero() {
+		if timedOut {
+			recorder.RoundObserved(proposer(i.State, i.config, i.State.Round), true)
+		}
+		return
+	}
+
+	for _, operator := range i.State.CommitteeMember.Committee {
+		_, prepared := i.performance.responses[responseKey{operator.OperatorID, specqbft.PrepareMsgType}]
+		_, committed := i.performance.responses[responseKey{operator.OperatorID, specqbft.CommitMsgType}]
+		if prepared || committed {
+			recorder.RoundObserved(operator.OperatorID, false)
+		} else if timedOut {
+			recorder.RoundObserved(operator.OperatorID, true)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package instance
+
+import (
+	"testing"
+	"time"
+
+	specqbft "github.com/ssvlabs/ssv-spec/qbft"
+	spectypes "github.com/ssvlabs/ssv-spec/types"
+	"github.com/ssvlabs/ssv-spec/types/testingutils"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ssvlabs/ssv/protocol/v2/qbft"
+)
+
+type testPerformanceRecorder struct {
+	delays       map[spectypes.OperatorID][]specqbft.MessageType
+	rounds       map[spectypes.OperatorID]int
+	missed       map[spectypes.OperatorID]int
+	roundChanges []spectypes.OperatorID
+}
+
+func (r *testPerformanceRecorder) MessageDelay(operatorID spectypes.OperatorID, msgType specqbft.MessageType, _ time.Duration) {
+	r.delays[operatorID] = append(r.delays[operatorID], msgType)
+}
+
+func (r *testPerformanceRecorder) RoundObserved(operatorID spectypes.OperatorID, missed bool) {
+	r.rounds[operatorID]++
+	if missed {
+		r.missed[operatorID]++
+	}
+}
+
+func (r *testPerformanceRecorder) RoundChangeInitiated(operatorID spectypes.OperatorID) {
+	r.roundChanges = append(r.roundChanges, operatorID)
+}
+
+func TestInstance_Performance(t *testing.T) {
+	keySet := testingutils.Testing4SharesSet()
+	recorder := &testPerformanceRecorder{
+		delays: map[spectypes.OperatorID][]specqbft.MessageType{},
+		rounds: map[spectypes.OperatorID]int{},
+		missed: map[spectypes.OperatorID]int{},
+	}
+	config := &qbft.Config{
+		ProposerF: func(state *specqbft.State, round specqbft.Round) spectypes.OperatorID {
+			return spectypes.OperatorID(round)
+		},
+		PerformanceRecorder: recorder,
+	}
+	i := NewInstance(config, testingutils.TestingCommitteeMember(keySet), []byte{1, 2, 3, 4}, specqbft.FirstHeight, nil)
+
+	msg := func(signer spectypes.OperatorID, msgType specqbft.MessageType, round specqbft.Round) *specqbft.ProcessingMessage {
+		return testingutils.ToProcessingMessage(testingutils.SignQBFTMsg(keySet.OperatorKeys[signer], signer, &specqbft.Message{
+			MsgType:    msgType,
+			Height:     specqbft.FirstHeight,
+			Round:      round,
+			Identifier: []byte{1, 2, 3, 4},
+			Root:       testingutils.TestingQBFTRootData,
+		}))
+	}
+
+	// Prepares before the proposal have nothing to be compared to.
+	i.observePerformance(msg(2, specqbft.PrepareMsgType, specqbft.FirstRound))
+	i.observePerformance(msg(1, specqbft.ProposalMsgType, specqbft.FirstRound))
+	i.observePerformance(msg(2, specqbft.PrepareMsgType, specqbft.FirstRound))
+	i.observePerformance(msg(2, specqbft.PrepareMsgType, specqbft.FirstRound))
+	i.observePerformance(msg(2, specqbft.CommitMsgType, specqbft.FirstRound))
+	i.observePerformance(msg(3, specqbft.PrepareMsgType, specqbft.FirstRound))
+	require.Equal(t, map[spectypes.OperatorID][]specqbft.MessageType{
+		2: {specqbft.PrepareMsgType, specqbft.CommitMsgType},
+		3: {specqbft.PrepareMsgType},
+	}, recorder.delays)
+
+	// Operator 4 initiates the round change, and the operators which didn't respond miss the round.
+	i.observePerformance(msg(4, specqbft.RoundChangeMsgType, 2))
+	i.observePerformance(msg(3, specqbft.RoundChangeMsgType, 2))
+	i.endRoundPerformance(true)
+	i.roundChangeInitiated(2, i.State.CommitteeMember.OperatorID)
+	require.Equal(t, []spectypes.OperatorID{4}, recorder.roundChanges)
+	require.Equal(t, map[spectypes.OperatorID]int{1: 1, 2: 1, 3: 1, 4: 1}, recorder.rounds)
+	require.Equal(t, map[spectypes.OperatorID]int{1: 1, 4: 1}, recorder.missed)
+
+	// Without the proposal, only the leader misses the round.
+	i.bumpToRound(2)
+	i.endRoundPerformance(true)
+	require.Equal(t, map[spectypes.OperatorID]int{1: 1, 2: 1, 4: 1}, recorder.missed)
+
+	// Operators which didn't respond by the time the round is decided don't miss it.
+	i.bumpToRound(3)
+	i.observePerformance(msg(3, specqbft.ProposalMsgType, 3))
+	i.observePerformance(msg(3, specqbft.CommitMsgType, 3))
+	i.endRoundPerformance(false)
+	require.Equal(t, map[spectypes.OperatorID]int{1: 1, 2: 2, 3: 2, 4: 1}, recorder.rounds)
+	require.Equal(t, map[spectypes.OperatorID]int{1: 1, 2: 1, 4: 1}, recorder.missed)
+}
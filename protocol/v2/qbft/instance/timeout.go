@@ -16,6 +16,9 @@ func (i *Instance) UponRoundTimeout(logger *zap.Logger) error {
 	newRound := i.State.Round + 1
 	logger.Debug("⌛ round timed out", fields.Round(newRound))
 
+	i.endRoundPerformance(true)
+	i.roundChangeInitiated(newRound, i.State.CommitteeMember.OperatorID)
+
 	// TODO: previously this was done outside of a defer, which caused the
 	// round to be bumped before the round change message was created & broadcasted.
 	// Remember to track the impact of this change and revert/modify if necessary.
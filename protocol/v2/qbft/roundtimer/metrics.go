@@ -0,0 +1,37 @@
+package roundtimer
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+var (
+	metricsRoundChanges = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssv_qbft_round_changes",
+		Help: "Number of rounds QBFT instances changed to after their first round",
+	}, []string{"role"})
+	metricsDecidedRound = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "ssv_qbft_decided_round",
+		Help:    "Round QBFT instances were decided in",
+		Buckets: []float64{1, 2, 3, 4, 5, 6, 8, 10, 12},
+	}, []string{"role"})
+	metricsQuickTimeout = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ssv_qbft_quick_timeout_seconds",
+		Help: "Quick round timeout of the latest QBFT instance (seconds)",
+	}, []string{"role"})
+)
+
+func init() {
+	allMetrics := []prometheus.Collector{
+		metricsRoundChanges,
+		metricsDecidedRound,
+		metricsQuickTimeout,
+	}
+	logger := zap.L()
+	for _, c := range allMetrics {
+		if err := prometheus.Register(c); err != nil {
+			logger.Debug("could not register prometheus collector")
+		}
+	}
+}
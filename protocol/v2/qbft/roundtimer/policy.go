@@ -0,0 +1,155 @@
+package roundtimer
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	specqbft "github.com/ssvlabs/ssv-spec/qbft"
+	spectypes "github.com/ssvlabs/ssv-spec/types"
+)
+
+const (
+	// MaxQuickTimeout is the longest quick timeout a policy may set. Longer timeouts delay
+	// the round change when the leader is down for too long to complete duties in time.
+	MaxQuickTimeout = 2 * QuickTimeout
+
+	// adaptiveMultiplier is how many times the typical decide latency the adaptive quick timeout is.
+	adaptiveMultiplier = 2
+	// adaptiveWeight is the weight of a new decide latency in the moving average.
+	adaptiveWeight = 0.1
+)
+
+const (
+	StaticPolicy   = "static"
+	AdaptivePolicy = "adaptive"
+)
+
+// TimeoutPolicy decides the timeouts of the rounds of instances.
+//
+// Message validation estimates the current round of messages from the default timeouts,
+// so policies never time out sooner than the defaults, or else this node's messages
+// would be from rounds higher than its peers allow. Timing out later is safe,
+// since the node catches up on round changes from its peers.
+type TimeoutPolicy interface {
+	// TimeoutOptions returns the timeouts of the next instance of the committee and role.
+	TimeoutOptions(committeeID spectypes.CommitteeID, role spectypes.RunnerRole) TimeoutOptions
+	// ObserveDecided is called when an instance of the committee and role is decided,
+	// with the time since the round it was decided in started.
+	ObserveDecided(committeeID spectypes.CommitteeID, role spectypes.RunnerRole, round specqbft.Round, latency time.Duration)
+}
+
+// Config holds the configuration of round timeouts.
+type Config struct {
+	Policy                string         `yaml:"Policy" env:"ROUND_TIMEOUT_POLICY" env-default:"static" env-description:"Round timeout policy, either static or adaptive (learns from the decide latencies of each committee)"`
+	QuickTimeout          time.Duration  `yaml:"QuickTimeout" env:"ROUND_QUICK_TIMEOUT" env-default:"2s" env-description:"Timeout of quick rounds, between 2s and 4s. The adaptive policy doesn't go below it"`
+	SlowTimeout           time.Duration  `yaml:"SlowTimeout" env:"ROUND_SLOW_TIMEOUT" env-default:"2m" env-description:"Timeout of slow rounds, at least 2m"`
+	QuickTimeoutThreshold specqbft.Round `yaml:"QuickTimeoutThreshold" env:"ROUND_QUICK_TIMEOUT_THRESHOLD" env-default:"8" env-description:"Last quick round, at most 8"`
+}
+
+// NewPolicy returns the configured TimeoutPolicy.
+func NewPolicy(cfg Config) (TimeoutPolicy, error) {
+	if cfg.QuickTimeout < QuickTimeout || cfg.QuickTimeout > MaxQuickTimeout {
+		return nil, fmt.Errorf("quick timeout must be between %s and %s", QuickTimeout, MaxQuickTimeout)
+	}
+	if cfg.SlowTimeout < SlowTimeout {
+		return nil, fmt.Errorf("slow timeout must be at least %s", SlowTimeout)
+	}
+	if cfg.QuickTimeoutThreshold > QuickTimeoutThreshold {
+		return nil, fmt.Errorf("quick timeout threshold must be at most %d", QuickTimeoutThreshold)
+	}
+	options := TimeoutOptions{
+		quickThreshold: cfg.QuickTimeoutThreshold,
+		quick:          cfg.QuickTimeout,
+		slow:           cfg.SlowTimeout,
+	}
+
+	switch cfg.Policy {
+	case StaticPolicy, "":
+		return NewStaticPolicy(options), nil
+	case AdaptivePolicy:
+		return NewAdaptivePolicy(options), nil
+	default:
+		return nil, fmt.Errorf("unknown round timeout policy %q", cfg.Policy)
+	}
+}
+
+// DefaultTimeoutOptions returns the timeouts of the protocol.
+func DefaultTimeoutOptions() TimeoutOptions {
+	return TimeoutOptions{
+		quickThreshold: QuickTimeoutThreshold,
+		quick:          QuickTimeout,
+		slow:           SlowTimeout,
+	}
+}
+
+type staticPolicy struct {
+	options TimeoutOptions
+}
+
+// NewStaticPolicy returns a TimeoutPolicy with the same timeouts for every committee and role.
+func NewStaticPolicy(options TimeoutOptions) TimeoutPolicy {
+	return &staticPolicy{options: options}
+}
+
+func (p *staticPolicy) TimeoutOptions(spectypes.CommitteeID, spectypes.RunnerRole) TimeoutOptions {
+	return p.options
+}
+
+func (p *staticPolicy) ObserveDecided(spectypes.CommitteeID, spectypes.RunnerRole, specqbft.Round, time.Duration) {
+}
+
+type adaptiveKey struct {
+	committeeID spectypes.CommitteeID
+	role        spectypes.RunnerRole
+}
+
+type adaptivePolicy struct {
+	base TimeoutOptions
+
+	mu        sync.Mutex
+	latencies map[adaptiveKey]time.Duration
+}
+
+// NewAdaptivePolicy returns a TimeoutPolicy which sets the quick timeout of each committee and role
+// to twice the moving average of its decide latencies, between the given quick timeout and MaxQuickTimeout.
+// It's meant for committees whose operators are far apart, and which decide too close to the quick timeout.
+//
+// Operators of a committee may learn different timeouts, which is safe because a node
+// which times out later follows its peers' round changes.
+func NewAdaptivePolicy(base TimeoutOptions) TimeoutPolicy {
+	return &adaptivePolicy{
+		base:      base,
+		latencies: make(map[adaptiveKey]time.Duration),
+	}
+}
+
+func (p *adaptivePolicy) TimeoutOptions(committeeID spectypes.CommitteeID, role spectypes.RunnerRole) TimeoutOptions {
+	p.mu.Lock()
+	latency, ok := p.latencies[adaptiveKey{committeeID, role}]
+	p.mu.Unlock()
+
+	options := p.base
+	if ok {
+		options.quick = min(max(latency*adaptiveMultiplier, p.base.quick), MaxQuickTimeout)
+	}
+	return options
+}
+
+func (p *adaptivePolicy) ObserveDecided(committeeID spectypes.CommitteeID, role spectypes.RunnerRole, round specqbft.Round, latency time.Duration) {
+	// Slow rounds are too long for their latency to tell how long quick rounds should be.
+	if round > p.base.quickThreshold {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key := adaptiveKey{committeeID, role}
+	average, ok := p.latencies[key]
+	if !ok {
+		p.latencies[key] = latency
+		return
+	}
+	p.latencies[key] = average + time.Duration(adaptiveWeight*float64(latency-average))
+}
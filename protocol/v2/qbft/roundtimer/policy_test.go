@@ -0,0 +1,81 @@
+package roundtimer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	specqbft "github.com/ssvlabs/ssv-spec/qbft"
+	spectypes "github.com/ssvlabs/ssv-spec/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPolicy(t *testing.T) {
+	cfg := Config{
+		Policy:                StaticPolicy,
+		QuickTimeout:          3 * time.Second,
+		SlowTimeout:           SlowTimeout,
+		QuickTimeoutThreshold: 6,
+	}
+	policy, err := NewPolicy(cfg)
+	require.NoError(t, err)
+	require.Equal(t, TimeoutOptions{quickThreshold: 6, quick: 3 * time.Second, slow: SlowTimeout},
+		policy.TimeoutOptions(spectypes.CommitteeID{}, spectypes.RoleCommittee))
+
+	invalid := cfg
+	invalid.QuickTimeout = time.Second
+	_, err = NewPolicy(invalid)
+	require.ErrorContains(t, err, "quick timeout must be between")
+
+	invalid = cfg
+	invalid.QuickTimeoutThreshold = QuickTimeoutThreshold + 1
+	_, err = NewPolicy(invalid)
+	require.ErrorContains(t, err, "quick timeout threshold")
+
+	invalid = cfg
+	invalid.Policy = "fast"
+	_, err = NewPolicy(invalid)
+	require.ErrorContains(t, err, "unknown round timeout policy")
+}
+
+func TestAdaptivePolicy(t *testing.T) {
+	policy := NewAdaptivePolicy(DefaultTimeoutOptions())
+	slowCommittee, fastCommittee := spectypes.CommitteeID{1}, spectypes.CommitteeID{2}
+
+	// Without observations, and for fast committees, the base timeouts are kept.
+	require.Equal(t, DefaultTimeoutOptions(), policy.TimeoutOptions(slowCommittee, spectypes.RoleCommittee))
+	policy.ObserveDecided(fastCommittee, spectypes.RoleCommittee, specqbft.FirstRound, 300*time.Millisecond)
+	require.Equal(t, QuickTimeout, policy.TimeoutOptions(fastCommittee, spectypes.RoleCommittee).quick)
+
+	// Slow committees get longer quick timeouts, up to the maximum.
+	policy.ObserveDecided(slowCommittee, spectypes.RoleCommittee, specqbft.FirstRound, 1500*time.Millisecond)
+	require.Equal(t, 3*time.Second, policy.TimeoutOptions(slowCommittee, spectypes.RoleCommittee).quick)
+	// The average moves to 2.5s, which would be a 5s timeout.
+	policy.ObserveDecided(slowCommittee, spectypes.RoleCommittee, specqbft.FirstRound, 11500*time.Millisecond)
+	require.Equal(t, MaxQuickTimeout, policy.TimeoutOptions(slowCommittee, spectypes.RoleCommittee).quick)
+
+	// Other roles and slow rounds aren't affected.
+	require.Equal(t, QuickTimeout, policy.TimeoutOptions(slowCommittee, spectypes.RoleProposer).quick)
+	policy.ObserveDecided(fastCommittee, spectypes.RoleCommittee, QuickTimeoutThreshold+1, time.Minute)
+	require.Equal(t, QuickTimeout, policy.TimeoutOptions(fastCommittee, spectypes.RoleCommittee).quick)
+}
+
+func TestRoundTimerPolicy(t *testing.T) {
+	committeeID := spectypes.CommitteeID{1}
+	policy := NewAdaptivePolicy(DefaultTimeoutOptions())
+	policy.ObserveDecided(committeeID, spectypes.RoleCommittee, specqbft.FirstRound, 1500*time.Millisecond)
+
+	timer := New(context.Background(), setupMockBeaconNetwork(t), spectypes.RoleCommittee, nil, WithTimeoutPolicy(policy, committeeID))
+	timer.TimeoutForRound(1, specqbft.FirstRound)
+	require.Equal(t, 3*time.Second, timer.timeoutOptions.quick)
+
+	// The timeouts of a running instance don't change.
+	policy.ObserveDecided(committeeID, spectypes.RoleCommittee, specqbft.FirstRound, 3*time.Second)
+	timer.TimeoutForRound(1, 2)
+	require.Equal(t, 3*time.Second, timer.timeoutOptions.quick)
+
+	// The instance decided right away, which shortens the next timeouts.
+	before := policy.TimeoutOptions(committeeID, spectypes.RoleCommittee).quick
+	timer.Decided(2)
+	require.Less(t, policy.TimeoutOptions(committeeID, spectypes.RoleCommittee).quick, before)
+}
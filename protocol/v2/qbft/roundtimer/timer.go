@@ -30,6 +30,12 @@ type Timer interface {
 	TimeoutForRound(height specqbft.Height, round specqbft.Round)
 }
 
+// DecidedObserver is implemented by timers which are told when their instance is decided.
+type DecidedObserver interface {
+	// Decided is called once the instance is decided in the given round.
+	Decided(round specqbft.Round)
+}
+
 type BeaconNetwork interface {
 	GetSlotStartTime(slot phase0.Slot) time.Time
 	SlotDurationSec() time.Duration
@@ -59,25 +65,42 @@ type RoundTimer struct {
 	role spectypes.RunnerRole
 	// beaconNetwork is the beacon network
 	beaconNetwork BeaconNetwork
+	// policy decides the timeoutOptions of each instance, unless it's nil
+	policy TimeoutPolicy
+	// committeeID is the committee of the instance
+	committeeID spectypes.CommitteeID
+	// roundStart is when the current round started
+	roundStart time.Time
+}
+
+// Option configures a RoundTimer.
+type Option func(*RoundTimer)
+
+// WithTimeoutPolicy sets the policy which decides the timeouts of the committee's instances.
+func WithTimeoutPolicy(policy TimeoutPolicy, committeeID spectypes.CommitteeID) Option {
+	return func(t *RoundTimer) {
+		t.policy = policy
+		t.committeeID = committeeID
+	}
 }
 
 // New creates a new instance of RoundTimer.
-func New(pctx context.Context, beaconNetwork BeaconNetwork, role spectypes.RunnerRole, done OnRoundTimeoutF) *RoundTimer {
+func New(pctx context.Context, beaconNetwork BeaconNetwork, role spectypes.RunnerRole, done OnRoundTimeoutF, opts ...Option) *RoundTimer {
 	ctx, cancelCtx := context.WithCancel(pctx)
-	return &RoundTimer{
-		mtx:           &sync.RWMutex{},
-		ctx:           ctx,
-		cancelCtx:     cancelCtx,
-		timer:         nil,
-		done:          done,
-		role:          role,
-		beaconNetwork: beaconNetwork,
-		timeoutOptions: TimeoutOptions{
-			quickThreshold: QuickTimeoutThreshold,
-			quick:          QuickTimeout,
-			slow:           SlowTimeout,
-		},
+	t := &RoundTimer{
+		mtx:            &sync.RWMutex{},
+		ctx:            ctx,
+		cancelCtx:      cancelCtx,
+		timer:          nil,
+		done:           done,
+		role:           role,
+		beaconNetwork:  beaconNetwork,
+		timeoutOptions: DefaultTimeoutOptions(),
 	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
 }
 
 // RoundTimeout calculates the timeout duration for a specific role, height, and round.
@@ -155,6 +178,20 @@ func (t *RoundTimer) Round() specqbft.Round {
 
 // TimeoutForRound times out for a given round.
 func (t *RoundTimer) TimeoutForRound(height specqbft.Height, round specqbft.Round) {
+	// The timeouts of an instance are decided when it starts, so that its rounds are consistent.
+	if round == specqbft.FirstRound {
+		if t.policy != nil {
+			t.timeoutOptions = t.policy.TimeoutOptions(t.committeeID, t.role)
+		}
+		metricsQuickTimeout.WithLabelValues(t.role.String()).Set(t.timeoutOptions.quick.Seconds())
+	} else {
+		metricsRoundChanges.WithLabelValues(t.role.String()).Inc()
+	}
+
+	t.mtx.Lock() // write to t.roundStart
+	t.roundStart = time.Now()
+	t.mtx.Unlock()
+
 	atomic.StoreUint64(&t.round, uint64(round))
 	timeout := t.RoundTimeout(height, round)
 
@@ -175,6 +212,18 @@ func (t *RoundTimer) TimeoutForRound(height specqbft.Height, round specqbft.Roun
 	go t.waitForRound(round, timer.C)
 }
 
+// Decided records the round the instance was decided in, and tells the policy how long the round took until then.
+func (t *RoundTimer) Decided(round specqbft.Round) {
+	metricsDecidedRound.WithLabelValues(t.role.String()).Observe(float64(round))
+
+	t.mtx.RLock() // read t.roundStart
+	roundStart := t.roundStart
+	t.mtx.RUnlock()
+	if t.policy != nil && !roundStart.IsZero() {
+		t.policy.ObserveDecided(t.committeeID, t.role, round, time.Since(roundStart))
+	}
+}
+
 func (t *RoundTimer) waitForRound(round specqbft.Round, timeout <-chan time.Time) {
 	ctx, cancel := context.WithCancel(t.ctx)
 	defer cancel()
@@ -27,8 +27,10 @@ func (si *StoredInstance) Decode(data []byte) error {
 }
 
 type ParticipantsRangeEntry struct {
-	Slot       phase0.Slot
-	Signers    []spectypes.OperatorID
+	Slot    phase0.Slot
+	Signers []spectypes.OperatorID
+	// Late are the signers which signed after the quorum was reached.
+	Late       []spectypes.OperatorID
 	Identifier convert.MessageID
 }
 
@@ -58,11 +60,22 @@ type InstanceStore interface {
 	// SaveParticipants save participants in quorum.
 	SaveParticipants(identifier convert.MessageID, slot phase0.Slot, operators []spectypes.OperatorID) error
 
+	// MergeParticipants adds to the participants in quorum, saving the added ones as late.
+	MergeParticipants(identifier convert.MessageID, slot phase0.Slot, operators []spectypes.OperatorID) error
+
 	// GetParticipantsInRange returns participants in quorum for the given slot range.
 	GetParticipantsInRange(identifier convert.MessageID, from, to phase0.Slot) ([]ParticipantsRangeEntry, error)
 
 	// GetParticipants returns participants in quorum for the given slot.
 	GetParticipants(identifier convert.MessageID, slot phase0.Slot) ([]spectypes.OperatorID, error)
+
+	// Prune removes the historical instances and participants of slots below the given one,
+	// and returns the number of removed entries. Highest instances are kept.
+	Prune(below phase0.Slot) (int, error)
+
+	// IndexSlots indexes the historical instances and participants saved before they were indexed by slot,
+	// so that Prune finds them, and returns the number of indexed entries.
+	IndexSlots() (int, error)
 }
 
 // QBFTStore is the store used by QBFT components
@@ -0,0 +1,124 @@
+package queue
+
+import (
+	"fmt"
+
+	specqbft "github.com/ssvlabs/ssv-spec/qbft"
+	spectypes "github.com/ssvlabs/ssv-spec/types"
+
+	ssvtypes "github.com/ssvlabs/ssv/protocol/v2/types"
+)
+
+// MessageKind classifies messages for drop policies and metrics.
+type MessageKind string
+
+const (
+	KindProposal      MessageKind = "proposal"
+	KindPrepare       MessageKind = "prepare"
+	KindCommit        MessageKind = "commit"
+	KindDecided       MessageKind = "decided"
+	KindRoundChange   MessageKind = "round_change"
+	KindPreConsensus  MessageKind = "pre_consensus"
+	KindPostConsensus MessageKind = "post_consensus"
+	KindEvent         MessageKind = "event"
+	KindUnknown       MessageKind = "unknown"
+)
+
+// Kind returns the kind of the message. Commit messages with more than one signer are decided messages.
+func Kind(m *SSVMessage) MessageKind {
+	switch body := m.Body.(type) {
+	case *specqbft.Message:
+		switch body.MsgType {
+		case specqbft.ProposalMsgType:
+			return KindProposal
+		case specqbft.PrepareMsgType:
+			return KindPrepare
+		case specqbft.CommitMsgType:
+			if m.SignedSSVMessage != nil && len(m.SignedSSVMessage.OperatorIDs) > 1 {
+				return KindDecided
+			}
+			return KindCommit
+		case specqbft.RoundChangeMsgType:
+			return KindRoundChange
+		}
+	case *spectypes.PartialSignatureMessages:
+		if body.Type == spectypes.PostConsensusPartialSig {
+			return KindPostConsensus
+		}
+		return KindPreConsensus
+	case *ssvtypes.EventMsg:
+		return KindEvent
+	}
+	return KindUnknown
+}
+
+// DropAction is what a full queue does with an incoming message.
+type DropAction string
+
+const (
+	// DropIncoming drops the incoming message, and is the default action.
+	DropIncoming DropAction = "drop-incoming"
+	// EvictStale evicts the oldest queued message of a lower height (or slot) than the incoming one,
+	// or drops the incoming message if there's none.
+	EvictStale DropAction = "evict-stale"
+	// EvictOldest evicts the oldest queued message.
+	EvictOldest DropAction = "evict-oldest"
+)
+
+// Config holds the configuration of message queues.
+type Config struct {
+	DropPolicy map[string]string `yaml:"DropPolicy" env:"QUEUE_DROP_POLICY" env-description:"Actions of full message queues by the kind of incoming messages (drop-incoming, evict-stale or evict-oldest), e.g. decided:evict-stale"`
+}
+
+// DropPolicy is the action a full queue takes for incoming messages by their kind.
+// Events are never evicted, since they drive the execution of duties.
+type DropPolicy map[MessageKind]DropAction
+
+// ParseDropPolicy parses actions by message kind, such as {"decided": "evict-stale"}.
+func ParseDropPolicy(actions map[string]string) (DropPolicy, error) {
+	policy := make(DropPolicy, len(actions))
+	for kind, action := range actions {
+		switch MessageKind(kind) {
+		case KindProposal, KindPrepare, KindCommit, KindDecided, KindRoundChange, KindPreConsensus, KindPostConsensus, KindEvent:
+		default:
+			return nil, fmt.Errorf("unknown message kind %q", kind)
+		}
+		switch DropAction(action) {
+		case DropIncoming, EvictStale, EvictOldest:
+		default:
+			return nil, fmt.Errorf("unknown drop action %q for message kind %q", action, kind)
+		}
+		policy[MessageKind(kind)] = DropAction(action)
+	}
+	return policy, nil
+}
+
+// action returns the action for the incoming message.
+func (p DropPolicy) action(incoming *SSVMessage) DropAction {
+	if action, ok := p[Kind(incoming)]; ok {
+		return action
+	}
+	return DropIncoming
+}
+
+// evicts returns true if the queued message may be evicted to make room for the incoming one.
+func (p DropPolicy) evicts(queued, incoming *SSVMessage) bool {
+	if Kind(queued) == KindEvent {
+		return false
+	}
+	switch p.action(incoming) {
+	case EvictOldest:
+		return true
+	case EvictStale:
+		queuedSlot, err := queued.Slot()
+		if err != nil {
+			return false
+		}
+		incomingSlot, err := incoming.Slot()
+		if err != nil {
+			return false
+		}
+		return queuedSlot < incomingSlot
+	}
+	return false
+}
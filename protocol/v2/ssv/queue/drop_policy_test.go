@@ -0,0 +1,99 @@
+package queue
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/ssvlabs/ssv-spec/qbft"
+	spectypes "github.com/ssvlabs/ssv-spec/types"
+	"github.com/stretchr/testify/require"
+)
+
+type testLabeledMetrics struct {
+	mu      sync.Mutex
+	dropped map[string]int
+	depth   int
+}
+
+func (m *testLabeledMetrics) QueueMessageDropped(committee, role, kind string, evicted bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if evicted {
+		kind += " evicted"
+	}
+	m.dropped[committee+" "+role+" "+kind]++
+}
+
+func (m *testLabeledMetrics) QueueDepth(_, _ string, depth int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.depth = depth
+}
+
+func TestParseDropPolicy(t *testing.T) {
+	policy, err := ParseDropPolicy(map[string]string{"decided": "evict-stale", "prepare": "drop-incoming"})
+	require.NoError(t, err)
+	require.Equal(t, DropPolicy{KindDecided: EvictStale, KindPrepare: DropIncoming}, policy)
+
+	_, err = ParseDropPolicy(map[string]string{"decide": "evict-stale"})
+	require.ErrorContains(t, err, "unknown message kind")
+	_, err = ParseDropPolicy(map[string]string{"decided": "evict"})
+	require.ErrorContains(t, err, "unknown drop action")
+}
+
+func TestPriorityQueue_DropPolicy(t *testing.T) {
+	decode := func(msg mockMessage) *SSVMessage {
+		decoded, err := DecodeSignedSSVMessage(msg.ssvMessage(mockState))
+		require.NoError(t, err)
+		return decoded
+	}
+	var (
+		timeout   = decode(mockTimeoutMessage{Role: spectypes.RoleCommittee, Height: 99})
+		current   = decode(mockConsensusMessage{Height: 100, Type: qbft.PrepareMsgType})
+		stale     = decode(mockConsensusMessage{Height: 99, Type: qbft.PrepareMsgType})
+		decided   = decode(mockConsensusMessage{Height: 100, Decided: true})
+		postCons  = decode(mockNonConsensusMessage{Type: spectypes.PostConsensusPartialSig, Slot: 100})
+		roundChng = decode(mockConsensusMessage{Height: 101, Type: qbft.RoundChangeMsgType})
+	)
+	require.Equal(t, KindDecided, Kind(decided))
+	require.Equal(t, KindEvent, Kind(timeout))
+
+	metrics := &testLabeledMetrics{dropped: map[string]int{}}
+	queue := New(3,
+		WithDropPolicy(DropPolicy{KindDecided: EvictStale, KindRoundChange: EvictOldest}),
+		WithLabeledMetrics(metrics, "committee", "COMMITTEE"),
+	)
+	require.True(t, queue.TryPush(timeout))
+	require.True(t, queue.TryPush(current))
+	require.True(t, queue.TryPush(stale))
+
+	// Messages without an eviction action are dropped when the queue is full.
+	require.False(t, queue.TryPush(postCons))
+	// The stale message is evicted for the decided message, while the older timeout event is kept.
+	require.True(t, queue.TryPush(decided))
+	// Nothing is stale anymore.
+	require.False(t, queue.TryPush(decided))
+	// The oldest message which isn't an event is evicted.
+	require.True(t, queue.TryPush(roundChng))
+	require.Equal(t, []*SSVMessage{timeout, decided, roundChng}, queue.Dump())
+	require.Equal(t, 3, queue.Len())
+
+	require.Equal(t, map[string]int{
+		"committee COMMITTEE post_consensus":  1,
+		"committee COMMITTEE prepare evicted": 2,
+		"committee COMMITTEE decided":         1,
+	}, metrics.dropped)
+
+	require.Equal(t, timeout, queue.Pop(context.Background(), NewCommitteeQueuePrioritizer(mockState), FilterAny))
+	require.Equal(t, 2, metrics.depth)
+	require.Len(t, queue.Dump(), 2)
+
+	// Messages which were already read from the inbox are evicted as well.
+	roundChng2 := decode(mockConsensusMessage{Height: 102, Type: qbft.RoundChangeMsgType})
+	roundChng3 := decode(mockConsensusMessage{Height: 103, Type: qbft.RoundChangeMsgType})
+	require.True(t, queue.TryPush(roundChng2))
+	require.True(t, queue.TryPush(roundChng3))
+	require.Equal(t, []*SSVMessage{roundChng, roundChng2, roundChng3}, queue.Dump())
+	require.Equal(t, 1, metrics.dropped["committee COMMITTEE decided evicted"])
+}
@@ -9,6 +9,15 @@ type Metrics interface {
 	DroppedQueueMessage(messageID spectypes.MessageID)
 }
 
+// LabeledMetrics records metrics about a Queue, labelled by the committee and role it belongs to.
+type LabeledMetrics interface {
+	// QueueMessageDropped records a message which was dropped, either when it was pushed to the full queue,
+	// or when it was evicted to make room for another message.
+	QueueMessageDropped(committee, role, kind string, evicted bool)
+	// QueueDepth records the number of messages in the queue, after a message is popped.
+	QueueDepth(committee, role string, depth int)
+}
+
 type queueWithMetrics struct {
 	Queue
 	metrics Metrics
@@ -2,6 +2,8 @@ package queue
 
 import (
 	"context"
+	"slices"
+	"sync"
 	"time"
 )
 
@@ -37,20 +39,63 @@ type Queue interface {
 
 	// Len returns the number of messages in the queue.
 	Len() int
+
+	// Dump returns the messages in the queue, from the oldest, without removing them.
+	Dump() []*SSVMessage
 }
 
 type priorityQueue struct {
+	// mu guards head, headLen and lastRead, so that Dump and pushes with a drop policy
+	// can be called while popping.
+	mu       sync.Mutex
 	head     *item
+	headLen  int
 	inbox    chan *SSVMessage
 	lastRead time.Time
+
+	// wake wakes a Pop which waits for the inbox after messages were moved
+	// from the inbox to the head by others.
+	wake chan struct{}
+
+	dropPolicy DropPolicy
+
+	metrics   LabeledMetrics
+	committee string
+	role      string
+}
+
+// Option configures a Queue.
+type Option func(*priorityQueue)
+
+// WithDropPolicy sets the policy which decides what to drop when the queue is full.
+// Without it, incoming messages are dropped. With it, TryPush takes the lock Pop takes,
+// and the capacity bounds the messages read by Pop as well as the unread ones.
+func WithDropPolicy(policy DropPolicy) Option {
+	return func(q *priorityQueue) {
+		q.dropPolicy = policy
+	}
+}
+
+// WithLabeledMetrics records the drops and depth of the queue, labelled by its committee and role.
+func WithLabeledMetrics(metrics LabeledMetrics, committee, role string) Option {
+	return func(q *priorityQueue) {
+		q.metrics = metrics
+		q.committee = committee
+		q.role = role
+	}
 }
 
 // New returns an implementation of Queue optimized for concurrent push and sequential pop.
 // Pops aren't thread-safe, so don't call Pop from multiple goroutines.
-func New(capacity int) Queue {
-	return &priorityQueue{
+func New(capacity int, opts ...Option) Queue {
+	q := &priorityQueue{
 		inbox: make(chan *SSVMessage, capacity),
+		wake:  make(chan struct{}, 1),
+	}
+	for _, opt := range opts {
+		opt(q)
 	}
+	return q
 }
 
 // NewDefault returns an implementation of Queue optimized for concurrent push and sequential pop,
@@ -64,27 +109,103 @@ func (q *priorityQueue) Push(msg *SSVMessage) {
 }
 
 func (q *priorityQueue) TryPush(msg *SSVMessage) bool {
+	if len(q.dropPolicy) == 0 {
+		select {
+		case q.inbox <- msg:
+			return true
+		default:
+			q.dropped(msg, false)
+			return false
+		}
+	}
+
+	// With a drop policy, the queue is bounded by its capacity as a whole, so that the messages
+	// already read from the inbox can be evicted as well. The unread messages are read into the head,
+	// and the oldest message which the policy allows is evicted from there.
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.len() >= cap(q.inbox) {
+		if q.dropPolicy.action(msg) == DropIncoming {
+			q.dropped(msg, false)
+			return false
+		}
+		if q.readInbox() > 0 {
+			q.notify()
+		}
+		evicted := q.evict(msg)
+		if evicted == nil {
+			q.dropped(msg, false)
+			return false
+		}
+		q.dropped(evicted, true)
+	}
+
 	select {
 	case q.inbox <- msg:
 		return true
 	default:
+		// Blocking pushes filled the inbox meanwhile.
+		q.dropped(msg, false)
 		return false
 	}
 }
 
+// evict removes the oldest message of the head which the drop policy allows to evict
+// for the incoming message, and returns it, or nil if there is none.
+func (q *priorityQueue) evict(incoming *SSVMessage) *SSVMessage {
+	// The head is ordered from the newest, so the last match is the oldest.
+	var prior, evicted *item
+	for prev, i := (*item)(nil), q.head; i != nil; prev, i = i, i.next {
+		if q.dropPolicy.evicts(i.message, incoming) {
+			prior, evicted = prev, i
+		}
+	}
+	if evicted == nil {
+		return nil
+	}
+	if prior == nil {
+		q.head = evicted.next
+	} else {
+		prior.next = evicted.next
+	}
+	q.headLen--
+	return evicted.message
+}
+
+// notify wakes a Pop which waits for the inbox, if there is one.
+func (q *priorityQueue) notify() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (q *priorityQueue) dropped(msg *SSVMessage, evicted bool) {
+	if q.metrics != nil {
+		q.metrics.QueueMessageDropped(q.committee, q.role, string(Kind(msg)), evicted)
+	}
+}
+
 func (q *priorityQueue) TryPop(prioritizer MessagePrioritizer, filter Filter) *SSVMessage {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
 	// Read any pending messages from the inbox.
 	q.readInbox()
 
 	// Pop the highest priority message.
 	if q.head != nil {
-		return q.pop(prioritizer, filter)
+		return q.popped(q.pop(prioritizer, filter))
 	}
 
 	return nil
 }
 
 func (q *priorityQueue) Pop(ctx context.Context, prioritizer MessagePrioritizer, filter Filter) *SSVMessage {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
 	// Read any pending messages from the inbox, if enough time has passed.
 	// inboxReadFrequency is a tradeoff between responsiveness and computational cost,
 	// since reading the inbox is more expensive than just reading the head.
@@ -95,24 +216,31 @@ func (q *priorityQueue) Pop(ctx context.Context, prioritizer MessagePrioritizer,
 	// Try to pop immediately.
 	if q.head != nil {
 		if m := q.pop(prioritizer, filter); m != nil {
-			return m
+			return q.popped(m)
 		}
 	}
 
-	// Wait for a message to be pushed.
+	// Wait for a message to be pushed, without holding the lock.
 Wait:
 	for {
+		q.mu.Unlock()
 		select {
 		case msg := <-q.inbox:
-			if q.head == nil {
-				q.head = &item{message: msg}
-			} else {
-				q.head = &item{message: msg, next: q.head}
-			}
+			q.mu.Lock()
+			q.pushHead(msg)
 			if filter(msg) {
 				break Wait
 			}
+		case <-q.wake:
+			// Others read the inbox into the head, which may hold the awaited message now.
+			q.mu.Lock()
+			for i := q.head; i != nil; i = i.next {
+				if filter(i.message) {
+					break Wait
+				}
+			}
 		case <-ctx.Done():
+			q.mu.Lock()
 			break Wait
 		}
 	}
@@ -122,33 +250,44 @@ Wait:
 
 	// Pop the highest priority message.
 	if q.head != nil {
-		return q.pop(prioritizer, filter)
+		return q.popped(q.pop(prioritizer, filter))
 	}
 
 	return nil
 }
 
-func (q *priorityQueue) readInbox() {
+// popped records the depth of the queue after a message is popped.
+func (q *priorityQueue) popped(msg *SSVMessage) *SSVMessage {
+	if q.metrics != nil && msg != nil {
+		q.metrics.QueueDepth(q.committee, q.role, q.len())
+	}
+	return msg
+}
+
+// readInbox moves the unread messages to the head, and returns how many there were.
+func (q *priorityQueue) readInbox() int {
 	q.lastRead = time.Now()
 
-	for {
+	for n := 0; ; n++ {
 		select {
 		case msg := <-q.inbox:
-			if q.head == nil {
-				q.head = &item{message: msg}
-			} else {
-				q.head = &item{message: msg, next: q.head}
-			}
+			q.pushHead(msg)
 		default:
-			return
+			return n
 		}
 	}
 }
 
+func (q *priorityQueue) pushHead(msg *SSVMessage) {
+	q.head = &item{message: msg, next: q.head}
+	q.headLen++
+}
+
 func (q *priorityQueue) pop(prioritizer MessagePrioritizer, filter Filter) *SSVMessage {
 	if q.head.next == nil {
 		if m := q.head.message; filter(m) {
 			q.head = nil
+			q.headLen--
 			return m
 		}
 		return nil
@@ -181,19 +320,43 @@ func (q *priorityQueue) pop(prioritizer MessagePrioritizer, filter Filter) *SSVM
 	} else {
 		prior.next = highest.next
 	}
+	q.headLen--
 	return highest.message
 }
 
 func (q *priorityQueue) Empty() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
 	return q.head == nil && len(q.inbox) == 0
 }
 
 func (q *priorityQueue) Len() int {
-	n := len(q.inbox)
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.len()
+}
+
+func (q *priorityQueue) len() int {
+	return len(q.inbox) + q.headLen
+}
+
+func (q *priorityQueue) Dump() []*SSVMessage {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	// The unread messages can only be seen by reading them, so a Pop waiting for them is woken.
+	if q.readInbox() > 0 {
+		q.notify()
+	}
+
+	var messages []*SSVMessage
 	for i := q.head; i != nil; i = i.next {
-		n++
+		messages = append(messages, i.message)
 	}
-	return n
+	slices.Reverse(messages)
+	return messages
 }
 
 // item is a node in a linked list of DecodedSSVMessage.
@@ -220,6 +220,39 @@ func TestPriorityQueue_Pop_NothingThenSomething(t *testing.T) {
 	require.Equal(t, queue.Len(), 2)
 }
 
+func TestPriorityQueue_Pop_WhileDumping(t *testing.T) {
+	prioritizer := NewMessagePrioritizer(mockState)
+	matchHeight2 := func(msg *SSVMessage) bool {
+		return msg.Body.(*qbft.Message).Height == 2
+	}
+
+	// Dumping reads the messages a waiting Pop may be waiting for, which mustn't stall it.
+	for i := 0; i < 20; i++ {
+		queue := NewDefault().(*priorityQueue)
+		popped := make(chan *SSVMessage)
+		go func() {
+			popped <- queue.Pop(context.Background(), prioritizer, matchHeight2)
+		}()
+		time.Sleep(time.Millisecond)
+
+		// Pop takes the first message and waits for the lock, while the second one is left in the inbox
+		// for either Pop or Dump to read.
+		queue.mu.Lock()
+		decodeAndPush(t, queue, mockConsensusMessage{Height: 1, Type: qbft.CommitMsgType}, mockState)
+		msg := decodeAndPush(t, queue, mockConsensusMessage{Height: 2, Type: qbft.CommitMsgType}, mockState)
+		go queue.Dump()
+		time.Sleep(time.Millisecond)
+		queue.mu.Unlock()
+
+		select {
+		case m := <-popped:
+			require.Equal(t, msg, m)
+		case <-time.After(time.Second):
+			require.FailNow(t, "pop stalled")
+		}
+	}
+}
+
 func TestPriorityQueue_Pop_WithLoopForNonMatchingAndMatchingMessages(t *testing.T) {
 	queue := NewDefault()
 	require.True(t, queue.Empty())
@@ -110,7 +110,9 @@ func (r *AggregatorRunner) ProcessPreConsensus(logger *zap.Logger, signedMsg *sp
 	duty = r.GetState().StartingDuty.(*spectypes.ValidatorDuty)
 	res, ver, err := r.GetBeaconNode().SubmitAggregateSelectionProof(duty.Slot, duty.CommitteeIndex, duty.CommitteeLength, duty.ValidatorIndex, fullSig)
 	if err != nil {
-		return errors.Wrap(err, "failed to submit aggregate and proof")
+		err = errors.Wrap(err, "failed to submit aggregate and proof")
+		r.BaseRunner.recordDutyFailure(err)
+		return err
 	}
 	r.metrics.ContinueDutyFullFlow()
 
@@ -239,11 +241,14 @@ func (r *AggregatorRunner) ProcessPostConsensus(logger *zap.Logger, signedMsg *s
 			logger.Error("❌ could not submit to Beacon chain reconstructed contribution and proof",
 				fields.SubmissionTime(time.Since(start)),
 				zap.Error(err))
-			return errors.Wrap(err, "could not submit to Beacon chain reconstructed signed aggregate")
+			err = errors.Wrap(err, "could not submit to Beacon chain reconstructed signed aggregate")
+			r.BaseRunner.recordValidatorDutySubmission(root, err)
+			return err
 		}
 
 		r.metrics.EndDutyFullFlow(r.GetState().RunningInstance.State.Round)
 		r.metrics.RoleSubmitted()
+		r.BaseRunner.recordValidatorDutySubmission(root, nil)
 
 		logger.Debug("✅ successful submitted aggregate",
 			fields.SubmissionTime(time.Since(start)),
@@ -253,7 +253,8 @@ func (cr *CommitteeRunner) ProcessConsensus(logger *zap.Logger, msg *spectypes.S
 		case spectypes.BNRoleSyncCommittee:
 			validDuties++
 			blockRoot := beaconVote.BlockRoot
-			partialMsg, err := cr.BaseRunner.signBeaconObject(cr, duty, spectypes.SSZBytes(blockRoot[:]), duty.DutySlot(),
+			syncCommitteeRoot := ssvtypes.SyncCommitteeBlockRoot{SSZBytes: blockRoot[:], Slot: duty.DutySlot()}
+			partialMsg, err := cr.BaseRunner.signBeaconObject(cr, duty, syncCommitteeRoot, duty.DutySlot(),
 				spectypes.DomainSyncCommittee)
 			if err != nil {
 				return errors.Wrap(err, "failed signing sync committee message")
@@ -395,6 +396,7 @@ func (cr *CommitteeRunner) ProcessPostConsensus(logger *zap.Logger, signedMsg *s
 				)
 
 				anyErr = errors.Wrap(err, "got post-consensus quorum but it has invalid signatures")
+				cr.BaseRunner.recordDuty(pubKey, role, nil, anyErr)
 				continue
 			}
 			specSig := phase0.BLSSignature{}
@@ -444,7 +446,11 @@ func (cr *CommitteeRunner) ProcessPostConsensus(logger *zap.Logger, signedMsg *s
 		submissionStart := time.Now()
 		if err := cr.beacon.SubmitAttestations(attestations); err != nil {
 			logger.Error("❌ failed to submit attestation", zap.Error(err))
-			return errors.Wrap(err, "could not submit to Beacon chain reconstructed attestation")
+			err = errors.Wrap(err, "could not submit to Beacon chain reconstructed attestation")
+			for validator := range attestationsToSubmit {
+				cr.recordDutySubmission(spectypes.BNRoleAttester, validator, attestationMap[validator], err)
+			}
+			return err
 		}
 
 		logger.Info("✅ successfully submitted attestations",
@@ -457,6 +463,7 @@ func (cr *CommitteeRunner) ProcessPostConsensus(logger *zap.Logger, signedMsg *s
 		// Record successful submissions
 		for validator := range attestationsToSubmit {
 			cr.RecordSubmission(spectypes.BNRoleAttester, validator)
+			cr.recordDutySubmission(spectypes.BNRoleAttester, validator, attestationMap[validator], nil)
 		}
 	}
 
@@ -470,7 +477,11 @@ func (cr *CommitteeRunner) ProcessPostConsensus(logger *zap.Logger, signedMsg *s
 		submissionStart := time.Now()
 		if err := cr.beacon.SubmitSyncMessages(syncCommitteeMessages); err != nil {
 			logger.Error("❌ failed to submit sync committee", zap.Error(err))
-			return errors.Wrap(err, "could not submit to Beacon chain reconstructed signed sync committee")
+			err = errors.Wrap(err, "could not submit to Beacon chain reconstructed signed sync committee")
+			for validator := range syncCommitteeMessagesToSubmit {
+				cr.recordDutySubmission(spectypes.BNRoleSyncCommittee, validator, committeeMap[validator], err)
+			}
+			return err
 		}
 		logger.Info("✅ successfully submitted sync committee",
 			fields.Height(cr.BaseRunner.QBFTController.Height),
@@ -482,6 +493,7 @@ func (cr *CommitteeRunner) ProcessPostConsensus(logger *zap.Logger, signedMsg *s
 		// Record successful submissions
 		for validator := range syncCommitteeMessagesToSubmit {
 			cr.RecordSubmission(spectypes.BNRoleSyncCommittee, validator)
+			cr.recordDutySubmission(spectypes.BNRoleSyncCommittee, validator, committeeMap[validator], nil)
 		}
 	}
 
@@ -526,6 +538,13 @@ func (cr *CommitteeRunner) RecordSubmission(role spectypes.BeaconRole, valIdx ph
 	cr.submittedDuties[role][valIdx] = struct{}{}
 }
 
+// recordDutySubmission records the outcome of submitting the signed root of a validator into the duty history.
+func (cr *CommitteeRunner) recordDutySubmission(role spectypes.BeaconRole, validator phase0.ValidatorIndex, root [32]byte, err error) {
+	if share, ok := cr.BaseRunner.Share[validator]; ok {
+		cr.BaseRunner.recordDuty(share.ValidatorPubKey, role, postConsensusSigners(cr.BaseRunner.State, validator, root), err)
+	}
+}
+
 // HasSubmitted -- Returns true if there is a record of submission for the (role, validator index, slot) tuple
 func (cr *CommitteeRunner) HasSubmitted(role spectypes.BeaconRole, valIdx phase0.ValidatorIndex) bool {
 	if _, ok := cr.submittedDuties[role]; !ok {
@@ -669,7 +688,9 @@ func (cr *CommitteeRunner) executeDuty(logger *zap.Logger, duty spectypes.Duty)
 	slot := duty.DutySlot()
 	attData, _, err := cr.GetBeaconNode().GetAttestationData(slot, 0)
 	if err != nil {
-		return errors.Wrap(err, "failed to get attestation data")
+		err = errors.Wrap(err, "failed to get attestation data")
+		cr.BaseRunner.recordDutyFailure(err)
+		return err
 	}
 	//TODO committeeIndex is 0, is this correct?
 	logger = logger.With(
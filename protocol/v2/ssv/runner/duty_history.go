@@ -0,0 +1,156 @@
+package runner
+
+import (
+	"slices"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	spectypes "github.com/ssvlabs/ssv-spec/types"
+
+	"github.com/ssvlabs/ssv/operator/dutyhistory"
+)
+
+// DutyRecorder records the outcome of duties into the duty history.
+type DutyRecorder interface {
+	RecordDuty(pubKey spectypes.ValidatorPK, record *dutyhistory.Record)
+}
+
+// LateSignerRecorder is implemented by duty recorders which also record the operators
+// whose post-consensus signatures arrived after the duty was completed.
+type LateSignerRecorder interface {
+	RecordLateSigner(pubKey spectypes.ValidatorPK, role spectypes.BeaconRole, slot phase0.Slot, signer spectypes.OperatorID)
+}
+
+// DecidedRecorder is implemented by duty recorders which also record the consensus
+// decisions of duties, along with the operators who signed the decided message.
+type DecidedRecorder interface {
+	RecordDecided(pubKey spectypes.ValidatorPK, role spectypes.BeaconRole, slot phase0.Slot, signers []spectypes.OperatorID)
+}
+
+// DutyRecorders records duties into each of its recorders.
+type DutyRecorders []DutyRecorder
+
+func (r DutyRecorders) RecordDuty(pubKey spectypes.ValidatorPK, record *dutyhistory.Record) {
+	for _, recorder := range r {
+		recorder.RecordDuty(pubKey, record)
+	}
+}
+
+func (r DutyRecorders) RecordLateSigner(pubKey spectypes.ValidatorPK, role spectypes.BeaconRole, slot phase0.Slot, signer spectypes.OperatorID) {
+	for _, recorder := range r {
+		if lateSignerRecorder, ok := recorder.(LateSignerRecorder); ok {
+			lateSignerRecorder.RecordLateSigner(pubKey, role, slot, signer)
+		}
+	}
+}
+
+func (r DutyRecorders) RecordDecided(pubKey spectypes.ValidatorPK, role spectypes.BeaconRole, slot phase0.Slot, signers []spectypes.OperatorID) {
+	for _, recorder := range r {
+		if decidedRecorder, ok := recorder.(DecidedRecorder); ok {
+			decidedRecorder.RecordDecided(pubKey, role, slot, signers)
+		}
+	}
+}
+
+// recordDuty records the outcome of the running duty of the given validator, if the duty history is enabled.
+func (b *BaseRunner) recordDuty(pubKey spectypes.ValidatorPK, role spectypes.BeaconRole, participants []spectypes.OperatorID, err error) {
+	if b.DutyRecorder == nil || b.State == nil || b.State.StartingDuty == nil {
+		return
+	}
+
+	record := &dutyhistory.Record{
+		Role:         role,
+		Slot:         b.State.StartingDuty.DutySlot(),
+		DecidedAt:    b.decidedAt,
+		Participants: participants,
+		Submitted:    err == nil,
+	}
+	if b.State.RunningInstance != nil {
+		if !b.decidedAt.IsZero() {
+			record.Round = b.State.RunningInstance.State.Round
+		}
+		for _, round := range b.State.RunningInstance.Rounds() {
+			record.Rounds = append(record.Rounds, dutyhistory.RoundStart{Round: round.Round, StartedAt: round.Time})
+		}
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	b.DutyRecorder.RecordDuty(pubKey, record)
+}
+
+// recordDutyFailure records the failure of the running duty for each of its validators.
+func (b *BaseRunner) recordDutyFailure(err error) {
+	if b.DutyRecorder == nil || b.State == nil {
+		return
+	}
+
+	switch duty := b.State.StartingDuty.(type) {
+	case *spectypes.ValidatorDuty:
+		b.recordDuty(spectypes.ValidatorPK(duty.PubKey), duty.Type, nil, err)
+	case *spectypes.CommitteeDuty:
+		for _, validatorDuty := range duty.ValidatorDuties {
+			b.recordDuty(spectypes.ValidatorPK(validatorDuty.PubKey), validatorDuty.Type, nil, err)
+		}
+	}
+}
+
+// recordDecided records the decision of the running duty for each of its validators.
+func (b *BaseRunner) recordDecided(decidedMsg *spectypes.SignedSSVMessage) {
+	recorder, ok := b.DutyRecorder.(DecidedRecorder)
+	if !ok || b.State == nil || b.State.StartingDuty == nil {
+		return
+	}
+
+	signers := slices.Clone(decidedMsg.OperatorIDs)
+	slices.Sort(signers)
+	slot := b.State.StartingDuty.DutySlot()
+	switch duty := b.State.StartingDuty.(type) {
+	case *spectypes.ValidatorDuty:
+		recorder.RecordDecided(spectypes.ValidatorPK(duty.PubKey), duty.Type, slot, signers)
+	case *spectypes.CommitteeDuty:
+		for _, validatorDuty := range duty.ValidatorDuties {
+			recorder.RecordDecided(spectypes.ValidatorPK(validatorDuty.PubKey), validatorDuty.Type, slot, signers)
+		}
+	}
+}
+
+// recordValidatorDutySubmission records the outcome of submitting the signed root of the running validator duty.
+func (b *BaseRunner) recordValidatorDutySubmission(root [32]byte, err error) {
+	if b.DutyRecorder == nil || b.State == nil {
+		return
+	}
+	if duty, ok := b.State.StartingDuty.(*spectypes.ValidatorDuty); ok {
+		b.recordDuty(spectypes.ValidatorPK(duty.PubKey), duty.Type, postConsensusSigners(b.State, duty.ValidatorIndex, root), err)
+	}
+}
+
+// recordLateSigners records the signers of post-consensus messages for the completed duty,
+// which arrived after the quorum was reached.
+func (b *BaseRunner) recordLateSigners(signedMsg *spectypes.PartialSignatureMessages) {
+	recorder, ok := b.DutyRecorder.(LateSignerRecorder)
+	if !ok || b.State == nil || !b.State.Finished || b.State.StartingDuty == nil {
+		return
+	}
+	if signedMsg.Type != spectypes.PostConsensusPartialSig || signedMsg.Slot != b.State.StartingDuty.DutySlot() {
+		return
+	}
+
+	for _, msg := range signedMsg.Messages {
+		share, ok := b.Share[msg.ValidatorIndex]
+		if !ok {
+			continue
+		}
+		switch duty := b.State.StartingDuty.(type) {
+		case *spectypes.ValidatorDuty:
+			if duty.ValidatorIndex == msg.ValidatorIndex {
+				recorder.RecordLateSigner(share.ValidatorPubKey, duty.Type, signedMsg.Slot, msg.Signer)
+			}
+		case *spectypes.CommitteeDuty:
+			for _, validatorDuty := range duty.ValidatorDuties {
+				if validatorDuty.ValidatorIndex == msg.ValidatorIndex {
+					recorder.RecordLateSigner(share.ValidatorPubKey, validatorDuty.Type, signedMsg.Slot, msg.Signer)
+				}
+			}
+		}
+	}
+}
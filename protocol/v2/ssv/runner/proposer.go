@@ -122,7 +122,9 @@ func (r *ProposerRunner) ProcessPreConsensus(logger *zap.Logger, signedMsg *spec
 			fields.PreConsensusTime(r.metrics.GetPreConsensusTime()),
 			fields.BlockTime(time.Since(start)),
 			zap.Error(err))
-		return errors.Wrap(err, "failed to get beacon block")
+		err = errors.Wrap(err, "failed to get beacon block")
+		r.BaseRunner.recordDutyFailure(err)
+		return err
 	}
 
 	// Log essentials about the retrieved block.
@@ -293,7 +295,9 @@ func (r *ProposerRunner) ProcessPostConsensus(logger *zap.Logger, signedMsg *spe
 				logger.Error("❌ could not submit blinded Beacon block",
 					fields.SubmissionTime(time.Since(start)),
 					zap.Error(err))
-				return errors.Wrap(err, "could not submit to Beacon chain reconstructed signed blinded Beacon block")
+				err = errors.Wrap(err, "could not submit to Beacon chain reconstructed signed blinded Beacon block")
+				r.BaseRunner.recordValidatorDutySubmission(root, err)
+				return err
 			}
 		} else {
 			vBlk, _, err := validatorConsensusData.GetBlockData()
@@ -311,13 +315,16 @@ func (r *ProposerRunner) ProcessPostConsensus(logger *zap.Logger, signedMsg *spe
 				logger.Error("❌ could not submit Beacon block",
 					fields.SubmissionTime(time.Since(start)),
 					zap.Error(err))
-				return errors.Wrap(err, "could not submit to Beacon chain reconstructed signed Beacon block")
+				err = errors.Wrap(err, "could not submit to Beacon chain reconstructed signed Beacon block")
+				r.BaseRunner.recordValidatorDutySubmission(root, err)
+				return err
 			}
 		}
 
 		endSubmission()
 		r.metrics.EndDutyFullFlow(r.GetState().RunningInstance.State.Round)
 		r.metrics.RoleSubmitted()
+		r.BaseRunner.recordValidatorDutySubmission(root, nil)
 
 		logger.Info("✅ successfully submitted block proposal",
 			fields.Slot(validatorConsensusData.Duty.Slot),
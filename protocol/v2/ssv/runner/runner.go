@@ -3,6 +3,7 @@ package runner
 import (
 	"encoding/json"
 	"sync"
+	"time"
 
 	"github.com/attestantio/go-eth2-client/spec/phase0"
 	ssz "github.com/ferranbt/fastssz"
@@ -63,9 +64,14 @@ type BaseRunner struct {
 
 	// implementation vars
 	TimeoutF TimeoutF `json:"-"`
+	// DutyRecorder is nil unless the duty history is enabled.
+	DutyRecorder DutyRecorder `json:"-"`
 
 	// highestDecidedSlot holds the highest decided duty slot and gets updated after each decided is reached
 	highestDecidedSlot phase0.Slot
+
+	// decidedAt is the time consensus was reached for the running duty, or zero if it wasn't.
+	decidedAt time.Time
 }
 
 func (b *BaseRunner) Encode() ([]byte, error) {
@@ -117,6 +123,7 @@ func (b *BaseRunner) baseSetupForNewDuty(duty spectypes.Duty, quorum uint64) {
 	// b.State but currently does not write to it
 	b.mtx.Lock() // writes to b.State
 	b.State = state
+	b.decidedAt = time.Time{}
 	b.mtx.Unlock()
 }
 
@@ -229,6 +236,8 @@ func (b *BaseRunner) baseConsensusMsgProcessing(logger *zap.Logger, runner Runne
 
 	// update the highest decided slot
 	b.highestDecidedSlot = b.State.StartingDuty.DutySlot()
+	b.decidedAt = time.Now()
+	b.recordDecided(decidedMsg)
 
 	return true, decidedValue, nil
 }
@@ -236,6 +245,7 @@ func (b *BaseRunner) baseConsensusMsgProcessing(logger *zap.Logger, runner Runne
 // basePostConsensusMsgProcessing is a base func that all runner implementation can call for processing a post-consensus msg
 func (b *BaseRunner) basePostConsensusMsgProcessing(logger *zap.Logger, runner Runner, signedMsg *spectypes.PartialSignatureMessages) (bool, [][32]byte, error) {
 	if err := b.ValidatePostConsensusMsg(runner, signedMsg); err != nil {
+		b.recordLateSigners(signedMsg)
 		return false, nil, errors.Wrap(err, "invalid post-consensus message")
 	}
 
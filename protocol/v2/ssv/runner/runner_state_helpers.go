@@ -2,7 +2,9 @@ package runner
 
 import (
 	"encoding/hex"
+	"sort"
 
+	"github.com/attestantio/go-eth2-client/spec/phase0"
 	"github.com/ssvlabs/ssv-spec/ssv"
 	spectypes "github.com/ssvlabs/ssv-spec/types"
 )
@@ -48,3 +50,14 @@ func getPostConsensusProposerSigners(state *State, root [32]byte) []spectypes.Op
 
 	return signers
 }
+
+// postConsensusSigners returns the operators which signed the given root for the given validator, in ascending order.
+func postConsensusSigners(state *State, validatorIndex phase0.ValidatorIndex, root [32]byte) []spectypes.OperatorID {
+	sigs := state.PostConsensusContainer.Signatures[validatorIndex][ssv.SigningRoot(hex.EncodeToString(root[:]))]
+	signers := make([]spectypes.OperatorID, 0, len(sigs))
+	for op := range sigs {
+		signers = append(signers, op)
+	}
+	sort.Slice(signers, func(i, j int) bool { return signers[i] < signers[j] })
+	return signers
+}
@@ -137,7 +137,9 @@ func (r *SyncCommitteeAggregatorRunner) ProcessPreConsensus(logger *zap.Logger,
 	r.metrics.PauseDutyFullFlow()
 	contributions, ver, err := r.GetBeaconNode().GetSyncCommitteeContribution(duty.DutySlot(), selectionProofs, subnets)
 	if err != nil {
-		return errors.Wrap(err, "could not get sync committee contribution")
+		err = errors.Wrap(err, "could not get sync committee contribution")
+		r.BaseRunner.recordDutyFailure(err)
+		return err
 	}
 	r.metrics.EndBeaconData()
 	r.metrics.ContinueDutyFullFlow()
@@ -296,12 +298,15 @@ func (r *SyncCommitteeAggregatorRunner) ProcessPostConsensus(logger *zap.Logger,
 				logger.Error("❌ could not submit to Beacon chain reconstructed contribution and proof",
 					fields.SubmissionTime(time.Since(start)),
 					zap.Error(err))
-				return errors.Wrap(err, "could not submit to Beacon chain reconstructed contribution and proof")
+				err = errors.Wrap(err, "could not submit to Beacon chain reconstructed contribution and proof")
+				r.BaseRunner.recordValidatorDutySubmission(root, err)
+				return err
 			}
 
 			submissionEnd()
 			r.metrics.EndDutyFullFlow(r.GetState().RunningInstance.State.Round)
 			r.metrics.RoleSubmitted()
+			r.BaseRunner.recordValidatorDutySubmission(root, nil)
 			logger.Debug("✅ successfully submitted sync committee aggregator",
 				fields.SubmissionTime(time.Since(start)),
 			)
@@ -3,6 +3,7 @@ package validator
 import (
 	"context"
 	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"sync"
@@ -46,6 +47,26 @@ type Committee struct {
 
 	dutyGuard      *CommitteeDutyGuard
 	CreateRunnerFn CommitteeRunnerFunc
+
+	metrics         Metrics
+	queueDropPolicy queue.DropPolicy
+}
+
+// CommitteeOption configures a Committee.
+type CommitteeOption func(*Committee)
+
+// WithCommitteeMetrics sets the metrics reporter of the committee's queues.
+func WithCommitteeMetrics(metrics Metrics) CommitteeOption {
+	return func(c *Committee) {
+		c.metrics = metrics
+	}
+}
+
+// WithQueueDropPolicy sets the drop policy of the committee's queues.
+func WithQueueDropPolicy(policy queue.DropPolicy) CommitteeOption {
+	return func(c *Committee) {
+		c.queueDropPolicy = policy
+	}
 }
 
 // NewCommittee creates a new cluster
@@ -57,11 +78,12 @@ func NewCommittee(
 	committeeMember *spectypes.CommitteeMember,
 	createRunnerFn CommitteeRunnerFunc,
 	shares map[phase0.ValidatorIndex]*spectypes.Share,
+	opts ...CommitteeOption,
 ) *Committee {
 	if shares == nil {
 		shares = make(map[phase0.ValidatorIndex]*spectypes.Share)
 	}
-	return &Committee{
+	c := &Committee{
 		logger:          logger,
 		BeaconNetwork:   beaconNetwork,
 		ctx:             ctx,
@@ -72,7 +94,24 @@ func NewCommittee(
 		CommitteeMember: committeeMember,
 		CreateRunnerFn:  createRunnerFn,
 		dutyGuard:       NewCommitteeDutyGuard(),
+		metrics:         NopMetrics{},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// newQueue returns a queue for the messages of a slot.
+func (c *Committee) newQueue() queue.Queue {
+	var committeeID string
+	if c.CommitteeMember != nil {
+		committeeID = hex.EncodeToString(c.CommitteeMember.CommitteeID[:])
 	}
+	return queue.WithMetrics(queue.New(1000, // TODO alan: get queue opts from options
+		queue.WithDropPolicy(c.queueDropPolicy),
+		queue.WithLabeledMetrics(c.metrics, committeeID, spectypes.RoleCommittee.String()),
+	), c.metrics)
 }
 
 func (c *Committee) AddShare(share *spectypes.Share) {
@@ -167,7 +206,7 @@ func (c *Committee) StartDuty(logger *zap.Logger, duty *spectypes.CommitteeDuty)
 	_, queueExists := c.Queues[duty.Slot]
 	if !queueExists {
 		c.Queues[duty.Slot] = queueContainer{
-			Q: queue.WithMetrics(queue.New(1000), nil), // TODO alan: get queue opts from options
+			Q: c.newQueue(),
 			queueState: &queue.State{
 				HasRunningInstance: false,
 				Height:             qbft.Height(duty.Slot),
@@ -22,6 +22,7 @@ type Metrics interface {
 	ValidatorUnknown(publicKey []byte)
 
 	queue.Metrics
+	queue.LabeledMetrics
 }
 
 type NopMetrics struct{}
@@ -40,6 +41,8 @@ func (n NopMetrics) ValidatorUnknown([]byte)                               {}
 func (n NopMetrics) IncomingQueueMessage(spectypes.MessageID)              {}
 func (n NopMetrics) OutgoingQueueMessage(spectypes.MessageID)              {}
 func (n NopMetrics) DroppedQueueMessage(spectypes.MessageID)               {}
+func (n NopMetrics) QueueMessageDropped(string, string, string, bool)      {}
+func (n NopMetrics) QueueDepth(string, string, int)                        {}
 func (n NopMetrics) MessageQueueSize(int)                                  {}
 func (n NopMetrics) MessageQueueCapacity(int)                              {}
 func (n NopMetrics) MessageTimeInQueue(spectypes.MessageID, time.Duration) {}
@@ -14,7 +14,10 @@ import (
 	"github.com/ssvlabs/ssv/networkconfig"
 	genesisbeacon "github.com/ssvlabs/ssv/protocol/genesis/blockchain/beacon"
 	"github.com/ssvlabs/ssv/protocol/v2/blockchain/beacon"
+	"github.com/ssvlabs/ssv/protocol/v2/qbft"
 	qbftctrl "github.com/ssvlabs/ssv/protocol/v2/qbft/controller"
+	"github.com/ssvlabs/ssv/protocol/v2/qbft/roundtimer"
+	"github.com/ssvlabs/ssv/protocol/v2/ssv/queue"
 	"github.com/ssvlabs/ssv/protocol/v2/ssv/runner"
 	ssvtypes "github.com/ssvlabs/ssv/protocol/v2/types"
 )
@@ -39,10 +42,17 @@ type Options struct {
 	FullNode          bool
 	Exporter          bool
 	QueueSize         int
+	QueueDropPolicy   queue.DropPolicy
+	TimeoutPolicy     roundtimer.TimeoutPolicy
 	GasLimit          uint64
 	MessageValidator  validation.MessageValidator
 	Metrics           Metrics
 	Graffiti          []byte
+	DutyRecorder      runner.DutyRecorder
+
+	// PerformanceRecorder is optional.
+	PerformanceRecorder qbft.PerformanceRecorder
+
 	GenesisOptions
 }
 
@@ -0,0 +1,60 @@
+package validator
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	spectypes "github.com/ssvlabs/ssv-spec/types"
+
+	"github.com/ssvlabs/ssv/protocol/v2/ssv/queue"
+)
+
+// QueueDump is a snapshot of the messages in a queue.
+type QueueDump struct {
+	Role spectypes.RunnerRole
+	// Slot is the slot of a committee queue, since they are per slot.
+	Slot phase0.Slot
+	// ValidatorPubKey is the validator of a validator queue.
+	ValidatorPubKey spectypes.ValidatorPK
+	Messages        []*queue.SSVMessage
+}
+
+// DumpQueues returns the messages in the committee's queues, by slot.
+func (c *Committee) DumpQueues() []QueueDump {
+	c.mtx.RLock()
+	queues := make(map[phase0.Slot]queueContainer, len(c.Queues))
+	for slot, q := range c.Queues {
+		queues[slot] = q
+	}
+	c.mtx.RUnlock()
+
+	dumps := make([]QueueDump, 0, len(queues))
+	for slot, q := range queues {
+		dumps = append(dumps, QueueDump{
+			Role:     spectypes.RoleCommittee,
+			Slot:     slot,
+			Messages: q.Q.Dump(),
+		})
+	}
+	slices.SortFunc(dumps, func(a, b QueueDump) int {
+		return cmp.Compare(a.Slot, b.Slot)
+	})
+	return dumps
+}
+
+// DumpQueues returns the messages in the validator's queues, by role.
+func (v *Validator) DumpQueues() []QueueDump {
+	dumps := make([]QueueDump, 0, len(v.Queues))
+	for role, q := range v.Queues {
+		dumps = append(dumps, QueueDump{
+			Role:            role,
+			ValidatorPubKey: v.Share.ValidatorPubKey,
+			Messages:        q.Q.Dump(),
+		})
+	}
+	slices.SortFunc(dumps, func(a, b QueueDump) int {
+		return cmp.Compare(a.Role, b.Role)
+	})
+	return dumps
+}
@@ -2,6 +2,7 @@ package validator
 
 import (
 	"context"
+	"encoding/hex"
 	"fmt"
 	"sync"
 
@@ -78,6 +79,12 @@ func NewValidator(pctx context.Context, cancel func(), options Options) *Validat
 		messageValidator: options.MessageValidator,
 	}
 
+	var committeeID string
+	if options.SSVShare != nil {
+		id := options.SSVShare.CommitteeID()
+		committeeID = hex.EncodeToString(id[:])
+	}
+
 	for _, dutyRunner := range options.DutyRunners {
 		// Set timeout function.
 		dutyRunner.GetBaseRunner().TimeoutF = v.onTimeout
@@ -86,7 +93,10 @@ func NewValidator(pctx context.Context, cancel func(), options Options) *Validat
 		role := dutyRunner.GetBaseRunner().RunnerRoleType
 
 		v.Queues[role] = queueContainer{
-			Q: queue.WithMetrics(queue.New(options.QueueSize), options.Metrics),
+			Q: queue.WithMetrics(queue.New(options.QueueSize,
+				queue.WithDropPolicy(options.QueueDropPolicy),
+				queue.WithLabeledMetrics(options.Metrics, committeeID, role.String()),
+			), options.Metrics),
 			queueState: &queue.State{
 				HasRunningInstance: false,
 				Height:             0,
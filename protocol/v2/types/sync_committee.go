@@ -0,0 +1,13 @@
+package types
+
+import (
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	spectypes "github.com/ssvlabs/ssv-spec/types"
+)
+
+// SyncCommitteeBlockRoot is the block root a sync committee message signs, along with the slot of the message,
+// which remote signers require. It has the same hash tree root as the block root alone.
+type SyncCommitteeBlockRoot struct {
+	spectypes.SSZBytes
+	Slot phase0.Slot
+}
@@ -0,0 +1,282 @@
+// Package audit checks the registry state which is synced from the contract events for consistency,
+// by replaying the events into an in-memory database and comparing the result with the node's database.
+//
+// Discrepancies are usually left behind by failed event handling, such as malformed events whose errors
+// were logged but otherwise ignored. Most of them can be repaired in place while the node isn't running,
+// except for the shares of the node's own operator, whose secrets can only be recovered from the events.
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ssvlabs/ssv/eth/executionclient"
+	nodestorage "github.com/ssvlabs/ssv/operator/storage"
+	"github.com/ssvlabs/ssv/storage/basedb"
+	"github.com/ssvlabs/ssv/storage/kv"
+)
+
+// maxCatchUps is the number of times the replay catches up with the node's last processed block,
+// which may advance while replaying when the node is running.
+const maxCatchUps = 5
+
+var (
+	// ErrNotSynced is returned when the node didn't sync registry events yet.
+	ErrNotSynced = errors.New("no registry events were synced")
+
+	// ErrInProgress is returned when starting an audit while another one is running.
+	ErrInProgress = errors.New("registry audit is already in progress")
+
+	// ErrFromBlockAhead is returned when the requested block is after the node's last processed block.
+	ErrFromBlockAhead = errors.New("from block is after the last processed block")
+)
+
+// LogFetcher fetches the logs emitted by the contract.
+type LogFetcher interface {
+	FetchLogs(ctx context.Context, fromBlock, toBlock uint64) (logs <-chan executionclient.BlockLogs, errors <-chan error)
+}
+
+// EventHandler handles the fetched logs, without executing the resulting tasks when executeTasks is false.
+type EventHandler interface {
+	HandleBlockEventsStream(logs <-chan executionclient.BlockLogs, executeTasks bool) (uint64, error)
+}
+
+// HandlerFactory returns an EventHandler which persists the events into the given in-memory database and storage.
+// It must not touch anything outside of them, such as the node's key manager or validators.
+type HandlerFactory func(db basedb.Database, store nodestorage.Storage) (EventHandler, error)
+
+// Status is the status of the audit started last with Start.
+type Status struct {
+	Running   bool      `json:"running"`
+	StartedAt time.Time `json:"started_at,omitempty"`
+	Report    *Report   `json:"report,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// Auditor audits the registry state of a node's storage.
+type Auditor struct {
+	logger        *zap.Logger
+	store         nodestorage.Storage
+	fetcher       LogFetcher
+	newHandler    HandlerFactory
+	syncOffset    uint64
+	ownOperatorID func() uint64
+
+	mu     sync.Mutex
+	status Status
+}
+
+// New returns an Auditor of the given storage, whose events are synced from syncOffset.
+// ownOperatorID returns the ID of the node's operator, or 0 if it isn't registered.
+func New(
+	logger *zap.Logger,
+	store nodestorage.Storage,
+	fetcher LogFetcher,
+	newHandler HandlerFactory,
+	syncOffset uint64,
+	ownOperatorID func() uint64,
+) *Auditor {
+	return &Auditor{
+		logger:        logger.Named("RegistryAuditor"),
+		store:         store,
+		fetcher:       fetcher,
+		newHandler:    newHandler,
+		syncOffset:    syncOffset,
+		ownOperatorID: ownOperatorID,
+	}
+}
+
+// Start runs an audit from the given block in the background, without repairing.
+// Its progress and report are returned by Status.
+func (a *Auditor) Start(ctx context.Context, fromBlock uint64) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.status.Running {
+		return ErrInProgress
+	}
+	if err := a.checkFromBlock(fromBlock); err != nil {
+		return err
+	}
+	a.status = Status{Running: true, StartedAt: time.Now()}
+
+	go func() {
+		report, err := a.Audit(ctx, fromBlock, false)
+
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		a.status.Running = false
+		a.status.Report = report
+		if err != nil {
+			a.status.Error = err.Error()
+		}
+	}()
+	return nil
+}
+
+// Status returns the status of the audit started last.
+func (a *Auditor) Status() Status {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.status
+}
+
+// Audit replays the registry events from the given block up to the node's last processed block,
+// and reports how the node's registry state differs from the replayed one.
+// A fromBlock of 0 replays from the sync offset.
+//
+// A replay from a later block than the sync offset is partial: entities which were created before it
+// are missing from the replayed state, so only the ones found in both are compared.
+//
+// With repair, the repairable discrepancies are fixed in the node's storage, which must not be in use.
+func (a *Auditor) Audit(ctx context.Context, fromBlock uint64, repair bool) (*Report, error) {
+	if fromBlock == 0 {
+		fromBlock = a.syncOffset
+	}
+	if err := a.checkFromBlock(fromBlock); err != nil {
+		return nil, err
+	}
+
+	db, err := kv.NewInMemory(a.logger, basedb.Options{Ctx: ctx})
+	if err != nil {
+		return nil, fmt.Errorf("could not create in-memory db: %w", err)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			a.logger.Warn("could not close in-memory db", zap.Error(err))
+		}
+	}()
+	replayed, err := nodestorage.NewNodeStorage(a.logger, db)
+	if err != nil {
+		return nil, fmt.Errorf("could not create replay storage: %w", err)
+	}
+	handler, err := a.newHandler(db, replayed)
+	if err != nil {
+		return nil, fmt.Errorf("could not create replay event handler: %w", err)
+	}
+
+	a.logger.Info("auditing registry", zap.Uint64("from_block", fromBlock), zap.Bool("repair", repair))
+	start := time.Now()
+
+	nextBlock := fromBlock
+	for i := 0; i <= maxCatchUps; i++ {
+		toBlock, err := a.lastProcessedBlock(nil)
+		if err != nil {
+			return nil, err
+		}
+		if toBlock >= nextBlock {
+			if err := a.replay(ctx, handler, nextBlock, toBlock); err != nil {
+				return nil, err
+			}
+			nextBlock = toBlock + 1
+		}
+
+		report, done, err := a.compare(replayed, fromBlock, nextBlock-1, repair)
+		if err != nil {
+			return nil, err
+		}
+		if done {
+			a.logger.Info("audited registry",
+				zap.Uint64("from_block", report.FromBlock),
+				zap.Uint64("to_block", report.ToBlock),
+				zap.Int("discrepancies", len(report.Discrepancies)),
+				zap.Duration("took", time.Since(start)))
+			return report, nil
+		}
+	}
+	return nil, fmt.Errorf("last processed block kept advancing after %d catch-ups", maxCatchUps)
+}
+
+// compare compares the replayed storage with the node's one, unless the node's last processed block
+// isn't the replayed one anymore.
+func (a *Auditor) compare(replayed nodestorage.Storage, fromBlock, toBlock uint64, repair bool) (*Report, bool, error) {
+	var txn basedb.ReadTxn
+	var rwTxn basedb.Txn
+	if repair {
+		rwTxn = a.store.Begin()
+		txn = rwTxn
+	} else {
+		txn = a.store.BeginRead()
+	}
+	defer txn.Discard()
+
+	lastProcessedBlock, err := a.lastProcessedBlock(txn)
+	if err != nil {
+		return nil, false, err
+	}
+	if lastProcessedBlock != toBlock {
+		return nil, false, nil
+	}
+
+	report := &Report{
+		FromBlock: fromBlock,
+		ToBlock:   toBlock,
+		Partial:   fromBlock > a.syncOffset,
+	}
+	fixes, err := diff(report, replayed, a.store, txn, a.ownOperatorID())
+	if err != nil {
+		return nil, false, err
+	}
+	if !repair || len(fixes) == 0 {
+		return report, true, nil
+	}
+
+	for i, fix := range fixes {
+		if fix == nil {
+			continue
+		}
+		if err := fix(rwTxn); err != nil {
+			return nil, false, fmt.Errorf("could not repair %s %s: %w", report.Discrepancies[i].Kind, report.Discrepancies[i].Key, err)
+		}
+	}
+	if err := rwTxn.Commit(); err != nil {
+		return nil, false, fmt.Errorf("could not commit repairs: %w", err)
+	}
+	for i, fix := range fixes {
+		report.Discrepancies[i].Repaired = fix != nil
+	}
+	return report, true, nil
+}
+
+func (a *Auditor) replay(ctx context.Context, handler EventHandler, fromBlock, toBlock uint64) error {
+	logs, fetchErrors := a.fetcher.FetchLogs(ctx, fromBlock, toBlock)
+	lastProcessedBlock, err := handler.HandleBlockEventsStream(logs, false)
+	if err != nil {
+		return fmt.Errorf("could not replay events: %w", err)
+	}
+	if err := <-fetchErrors; err != nil {
+		return fmt.Errorf("could not fetch logs: %w", err)
+	}
+	if lastProcessedBlock != toBlock {
+		return fmt.Errorf("replayed up to block %d instead of %d", lastProcessedBlock, toBlock)
+	}
+	return nil
+}
+
+func (a *Auditor) checkFromBlock(fromBlock uint64) error {
+	lastProcessedBlock, err := a.lastProcessedBlock(nil)
+	if err != nil {
+		return err
+	}
+	if fromBlock > lastProcessedBlock {
+		return fmt.Errorf("%w: %d > %d", ErrFromBlockAhead, fromBlock, lastProcessedBlock)
+	}
+	return nil
+}
+
+func (a *Auditor) lastProcessedBlock(r basedb.Reader) (uint64, error) {
+	lastProcessedBlock, found, err := a.store.GetLastProcessedBlock(r)
+	if err != nil {
+		return 0, fmt.Errorf("could not get last processed block: %w", err)
+	}
+	if !found || lastProcessedBlock == nil {
+		return 0, ErrNotSynced
+	}
+	return lastProcessedBlock.Uint64(), nil
+}
@@ -0,0 +1,186 @@
+package audit
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	spectypes "github.com/ssvlabs/ssv-spec/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ssvlabs/ssv/eth/executionclient"
+	"github.com/ssvlabs/ssv/logging"
+	nodestorage "github.com/ssvlabs/ssv/operator/storage"
+	ssvtypes "github.com/ssvlabs/ssv/protocol/v2/types"
+	registrystorage "github.com/ssvlabs/ssv/registry/storage"
+	"github.com/ssvlabs/ssv/storage/basedb"
+	"github.com/ssvlabs/ssv/storage/kv"
+)
+
+var owner = common.HexToAddress("0x97a6C1f3aaB5427B901fb135ED492749191C0f1F")
+
+func newTestStorage(t *testing.T) nodestorage.Storage {
+	logger := logging.TestLogger(t)
+	db, err := kv.NewInMemory(logger, basedb.Options{})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	store, err := nodestorage.NewNodeStorage(logger, db)
+	require.NoError(t, err)
+	return store
+}
+
+func testShare(pubKey byte, signers ...spectypes.OperatorID) *ssvtypes.SSVShare {
+	share := &ssvtypes.SSVShare{
+		Share:    spectypes.Share{ValidatorPubKey: spectypes.ValidatorPK{pubKey}},
+		Metadata: ssvtypes.Metadata{OwnerAddress: owner},
+	}
+	for _, signer := range signers {
+		share.Committee = append(share.Committee, &spectypes.ShareMember{Signer: signer})
+	}
+	return share
+}
+
+// applyRegistry saves the registry state which the contract events of the test produce.
+func applyRegistry(t *testing.T, store nodestorage.Storage, rw basedb.ReadWriter) {
+	for id := uint64(1); id <= 5; id++ {
+		_, err := store.SaveOperatorData(rw, &registrystorage.OperatorData{ID: id, PublicKey: []byte{byte(id)}, OwnerAddress: owner})
+		require.NoError(t, err)
+	}
+	require.NoError(t, store.BumpNonce(rw, owner))
+	require.NoError(t, store.Shares().Save(rw,
+		testShare(1, 1, 2, 3, 4),
+		testShare(2, 2, 3, 4, 5),
+		testShare(3, 1, 2, 3, 4),
+		testShare(4, 2, 3, 4, 5),
+	))
+}
+
+// testFetcher emits a single block of the given number.
+type testFetcher struct {
+	calls [][2]uint64
+}
+
+func (f *testFetcher) FetchLogs(_ context.Context, fromBlock, toBlock uint64) (<-chan executionclient.BlockLogs, <-chan error) {
+	f.calls = append(f.calls, [2]uint64{fromBlock, toBlock})
+	logs := make(chan executionclient.BlockLogs, 1)
+	errs := make(chan error, 1)
+	logs <- executionclient.BlockLogs{BlockNumber: toBlock}
+	close(logs)
+	close(errs)
+	return logs, errs
+}
+
+// testHandler applies the test registry state on the first block it handles.
+type testHandler struct {
+	t       *testing.T
+	store   nodestorage.Storage
+	applied bool
+}
+
+func (h *testHandler) HandleBlockEventsStream(logs <-chan executionclient.BlockLogs, _ bool) (uint64, error) {
+	var lastProcessedBlock uint64
+	for block := range logs {
+		txn := h.store.Begin()
+		if !h.applied {
+			applyRegistry(h.t, h.store, txn)
+			h.applied = true
+		}
+		require.NoError(h.t, h.store.SaveLastProcessedBlock(txn, new(big.Int).SetUint64(block.BlockNumber)))
+		require.NoError(h.t, txn.Commit())
+		lastProcessedBlock = block.BlockNumber
+	}
+	return lastProcessedBlock, nil
+}
+
+func TestAudit(t *testing.T) {
+	const ownOperatorID = 5
+
+	live := newTestStorage(t)
+	applyRegistry(t, live, nil)
+	require.NoError(t, live.SaveLastProcessedBlock(nil, big.NewInt(100)))
+
+	// Drift the live state from the events.
+	require.NoError(t, live.DeleteOperatorData(nil, 3))
+	_, err := live.SaveOperatorData(nil, &registrystorage.OperatorData{ID: 6, PublicKey: []byte{6}, OwnerAddress: owner})
+	require.NoError(t, err)
+	for _, pubKey := range []spectypes.ValidatorPK{{1}, {2}} {
+		require.NoError(t, live.Shares().Delete(nil, pubKey[:]))
+	}
+	liquidated := testShare(3, 1, 2, 3, 4)
+	liquidated.Liquidated = true
+	require.NoError(t, live.Shares().Save(nil, liquidated, testShare(5, 1, 2, 3, 4)))
+	require.NoError(t, live.DeleteRecipientData(nil, owner))
+	_, err = live.SaveRecipientData(nil, &registrystorage.RecipientData{Owner: owner, FeeRecipient: [20]byte{1}})
+	require.NoError(t, err)
+
+	fetcher := &testFetcher{}
+	auditor := New(logging.TestLogger(t), live, fetcher, func(db basedb.Database, store nodestorage.Storage) (EventHandler, error) {
+		return &testHandler{t: t, store: store}, nil
+	}, 10, func() uint64 { return ownOperatorID })
+
+	_, err = auditor.Audit(context.Background(), 101, false)
+	require.ErrorIs(t, err, ErrFromBlockAhead)
+
+	report, err := auditor.Audit(context.Background(), 0, false)
+	require.NoError(t, err)
+	require.Equal(t, [][2]uint64{{10, 100}}, fetcher.calls)
+	require.EqualValues(t, 10, report.FromBlock)
+	require.EqualValues(t, 100, report.ToBlock)
+	require.False(t, report.Partial)
+
+	type summary struct {
+		Kind       Kind
+		Key        string
+		Issue      Issue
+		Repairable bool
+	}
+	var summaries []summary
+	for _, d := range report.Discrepancies {
+		require.False(t, d.Repaired)
+		require.NotEmpty(t, d.Action)
+		summaries = append(summaries, summary{d.Kind, d.Key, d.Issue, d.Repairable})
+	}
+	require.Equal(t, []summary{
+		{KindOperator, "3", IssueMissing, true},
+		{KindOperator, "6", IssueUnexpected, true},
+		{KindShare, "01" + strings.Repeat("00", 47), IssueMissing, true},
+		// Own shares can't be repaired.
+		{KindShare, "02" + strings.Repeat("00", 47), IssueMissing, false},
+		{KindLiquidation, "03" + strings.Repeat("00", 47), IssueMismatch, true},
+		{KindShare, "05" + strings.Repeat("00", 47), IssueUnexpected, true},
+		{KindRecipient, owner.Hex(), IssueMismatch, true},
+	}, summaries)
+
+	// A partial audit only compares the replayed entities.
+	report, err = auditor.Audit(context.Background(), 50, false)
+	require.NoError(t, err)
+	require.True(t, report.Partial)
+	require.Len(t, report.Discrepancies, 5)
+
+	report, err = auditor.Audit(context.Background(), 0, true)
+	require.NoError(t, err)
+	for _, d := range report.Discrepancies {
+		require.Equal(t, d.Repairable, d.Repaired)
+	}
+
+	// Only the own share is left.
+	report, err = auditor.Audit(context.Background(), 0, false)
+	require.NoError(t, err)
+	require.Len(t, report.Discrepancies, 1)
+	require.Equal(t, KindShare, report.Discrepancies[0].Kind)
+	require.Equal(t, resyncAction, report.Discrepancies[0].Action)
+	nonce, err := live.GetNextNonce(nil, owner)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, nonce)
+
+	require.NoError(t, auditor.Start(context.Background(), 0))
+	require.Eventually(t, func() bool {
+		return !auditor.Status().Running
+	}, 5*time.Second, 10*time.Millisecond)
+	status := auditor.Status()
+	require.Empty(t, status.Error)
+	require.Len(t, status.Report.Discrepancies, 1)
+}
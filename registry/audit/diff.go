@@ -0,0 +1,350 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	spectypes "github.com/ssvlabs/ssv-spec/types"
+
+	nodestorage "github.com/ssvlabs/ssv/operator/storage"
+	ssvtypes "github.com/ssvlabs/ssv/protocol/v2/types"
+	registrystorage "github.com/ssvlabs/ssv/registry/storage"
+	"github.com/ssvlabs/ssv/storage/basedb"
+)
+
+// Kind is the kind of registry entity of a discrepancy.
+type Kind string
+
+const (
+	KindOperator    Kind = "operator"
+	KindShare       Kind = "share"
+	KindLiquidation Kind = "liquidation"
+	KindRecipient   Kind = "recipient"
+)
+
+// Issue is how the node's entity differs from the replayed one.
+type Issue string
+
+const (
+	// IssueMissing means the entity is missing from the node's storage.
+	IssueMissing Issue = "missing"
+	// IssueUnexpected means the entity is in the node's storage, but not in the replayed one.
+	IssueUnexpected Issue = "unexpected"
+	// IssueMismatch means the entity differs between the node's and the replayed storage.
+	IssueMismatch Issue = "mismatch"
+)
+
+// Report is the result of an audit.
+type Report struct {
+	FromBlock uint64 `json:"from_block"`
+	ToBlock   uint64 `json:"to_block"`
+	// Partial is set when the replay didn't start from the sync offset, in which case
+	// unexpected entities and nonces aren't reported.
+	Partial       bool          `json:"partial"`
+	Operators     int           `json:"operators"`
+	Shares        int           `json:"shares"`
+	Recipients    int           `json:"recipients"`
+	Discrepancies []Discrepancy `json:"discrepancies"`
+}
+
+// Discrepancy is a difference between the node's registry state and the replayed one.
+type Discrepancy struct {
+	Kind     Kind   `json:"kind"`
+	Key      string `json:"key"`
+	Issue    Issue  `json:"issue"`
+	Expected string `json:"expected,omitempty"`
+	Actual   string `json:"actual,omitempty"`
+	// Action is what fixes the discrepancy.
+	Action     string `json:"action"`
+	Repairable bool   `json:"repairable"`
+	Repaired   bool   `json:"repaired"`
+}
+
+// resyncAction fixes the discrepancies of the node's own shares, whose secrets are only found in the events.
+const resyncAction = "remove the node's database and sync registry events from scratch"
+
+type fix func(rw basedb.ReadWriter) error
+
+// diff adds the discrepancies between the replayed and the live storage to the report,
+// and returns the fix of each of them, which is nil for the ones which can't be repaired.
+func diff(report *Report, replayed, live nodestorage.Storage, liveReader basedb.Reader, ownOperatorID uint64) ([]fix, error) {
+	var fixes []fix
+	add := func(d Discrepancy, f fix) {
+		d.Repairable = f != nil
+		report.Discrepancies = append(report.Discrepancies, d)
+		fixes = append(fixes, f)
+	}
+
+	if err := diffOperators(report, replayed, live, liveReader, add); err != nil {
+		return nil, err
+	}
+	diffShares(report, replayed, live, liveReader, ownOperatorID, add)
+	if err := diffRecipients(report, replayed, live, liveReader, add); err != nil {
+		return nil, err
+	}
+	return fixes, nil
+}
+
+func diffOperators(report *Report, replayed, live nodestorage.Storage, liveReader basedb.Reader, add func(Discrepancy, fix)) error {
+	expected, err := replayed.ListOperators(nil, 0, 0)
+	if err != nil {
+		return fmt.Errorf("could not list replayed operators: %w", err)
+	}
+	actual, err := live.ListOperators(liveReader, 0, 0)
+	if err != nil {
+		return fmt.Errorf("could not list operators: %w", err)
+	}
+	report.Operators = len(actual)
+
+	actualByID := make(map[spectypes.OperatorID]registrystorage.OperatorData, len(actual))
+	for _, od := range actual {
+		actualByID[od.ID] = od
+	}
+	for _, od := range expected {
+		od := od
+		key := strconv.FormatUint(od.ID, 10)
+		actualOD, found := actualByID[od.ID]
+		delete(actualByID, od.ID)
+		switch {
+		case !found:
+			add(Discrepancy{Kind: KindOperator, Key: key, Issue: IssueMissing, Expected: formatOperator(od), Action: "save the operator"},
+				func(rw basedb.ReadWriter) error {
+					_, err := live.SaveOperatorData(rw, &od)
+					return err
+				})
+		case !bytes.Equal(od.PublicKey, actualOD.PublicKey) || od.OwnerAddress != actualOD.OwnerAddress:
+			add(Discrepancy{Kind: KindOperator, Key: key, Issue: IssueMismatch, Expected: formatOperator(od), Actual: formatOperator(actualOD), Action: "overwrite the operator"},
+				func(rw basedb.ReadWriter) error {
+					if err := live.DeleteOperatorData(rw, od.ID); err != nil {
+						return err
+					}
+					_, err := live.SaveOperatorData(rw, &od)
+					return err
+				})
+		}
+	}
+	if report.Partial {
+		return nil
+	}
+	for _, od := range sortedValues(actualByID) {
+		id := od.ID
+		add(Discrepancy{Kind: KindOperator, Key: strconv.FormatUint(id, 10), Issue: IssueUnexpected, Actual: formatOperator(od), Action: "delete the operator"},
+			func(rw basedb.ReadWriter) error {
+				return live.DeleteOperatorData(rw, id)
+			})
+	}
+	return nil
+}
+
+func diffShares(report *Report, replayed, live nodestorage.Storage, liveReader basedb.Reader, ownOperatorID uint64, add func(Discrepancy, fix)) {
+	expected := replayed.Shares().List(nil)
+	actual := live.Shares().List(liveReader)
+	report.Shares = len(actual)
+
+	actualByPubKey := make(map[string]*ssvtypes.SSVShare, len(actual))
+	for _, share := range actual {
+		actualByPubKey[hex.EncodeToString(share.ValidatorPubKey[:])] = share
+	}
+	sort.Slice(expected, func(i, j int) bool {
+		return bytes.Compare(expected[i].ValidatorPubKey[:], expected[j].ValidatorPubKey[:]) < 0
+	})
+	for _, share := range expected {
+		key := hex.EncodeToString(share.ValidatorPubKey[:])
+		actualShare, found := actualByPubKey[key]
+		delete(actualByPubKey, key)
+		own := share.BelongsToOperator(ownOperatorID) || (found && actualShare.BelongsToOperator(ownOperatorID))
+
+		switch {
+		case !found:
+			d := Discrepancy{Kind: KindShare, Key: key, Issue: IssueMissing, Expected: formatShare(share)}
+			if own {
+				d.Action = resyncAction
+				add(d, nil)
+				break
+			}
+			d.Action = "save the share"
+			add(d, saveShare(live, share, nil))
+		case !equalShares(share, actualShare):
+			d := Discrepancy{Kind: KindShare, Key: key, Issue: IssueMismatch, Expected: formatShare(share), Actual: formatShare(actualShare)}
+			if own {
+				d.Action = resyncAction
+				add(d, nil)
+				break
+			}
+			d.Action = "overwrite the share"
+			add(d, saveShare(live, share, actualShare))
+		case share.Liquidated != actualShare.Liquidated:
+			add(Discrepancy{
+				Kind:     KindLiquidation,
+				Key:      key,
+				Issue:    IssueMismatch,
+				Expected: strconv.FormatBool(share.Liquidated),
+				Actual:   strconv.FormatBool(actualShare.Liquidated),
+				Action:   fmt.Sprintf("set the share's liquidated status to %t", share.Liquidated),
+			}, setLiquidated(live, actualShare, share.Liquidated))
+		}
+	}
+	if report.Partial {
+		return
+	}
+
+	keys := make([]string, 0, len(actualByPubKey))
+	for key := range actualByPubKey {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		share := actualByPubKey[key]
+		d := Discrepancy{Kind: KindShare, Key: key, Issue: IssueUnexpected, Actual: formatShare(share)}
+		if share.BelongsToOperator(ownOperatorID) {
+			d.Action = resyncAction
+			add(d, nil)
+			continue
+		}
+		d.Action = "delete the share"
+		pubKey := share.ValidatorPubKey
+		add(d, func(rw basedb.ReadWriter) error {
+			return live.Shares().Delete(rw, pubKey[:])
+		})
+	}
+}
+
+// saveShare saves the registry fields of the expected share, keeping the beacon metadata of the actual one if any.
+func saveShare(live nodestorage.Storage, expected, actual *ssvtypes.SSVShare) fix {
+	share := &ssvtypes.SSVShare{
+		Share:    expected.Share,
+		Metadata: expected.Metadata,
+	}
+	if actual != nil {
+		share.ValidatorIndex = actual.ValidatorIndex
+		share.BeaconMetadata = actual.BeaconMetadata
+	}
+	return func(rw basedb.ReadWriter) error {
+		return live.Shares().Save(rw, share)
+	}
+}
+
+// setLiquidated saves the actual share with the given liquidation status.
+func setLiquidated(live nodestorage.Storage, actual *ssvtypes.SSVShare, liquidated bool) fix {
+	share := &ssvtypes.SSVShare{
+		Share:    actual.Share,
+		Metadata: actual.Metadata,
+	}
+	share.Liquidated = liquidated
+	return func(rw basedb.ReadWriter) error {
+		return live.Shares().Save(rw, share)
+	}
+}
+
+func diffRecipients(report *Report, replayed, live nodestorage.Storage, liveReader basedb.Reader, add func(Discrepancy, fix)) error {
+	expected, err := replayed.ListRecipients(nil)
+	if err != nil {
+		return fmt.Errorf("could not list replayed recipients: %w", err)
+	}
+	actual, err := live.ListRecipients(liveReader)
+	if err != nil {
+		return fmt.Errorf("could not list recipients: %w", err)
+	}
+	report.Recipients = len(actual)
+
+	actualByOwner := make(map[ethcommon.Address]registrystorage.RecipientData, len(actual))
+	for _, rd := range actual {
+		actualByOwner[rd.Owner] = rd
+	}
+	sort.Slice(expected, func(i, j int) bool {
+		return bytes.Compare(expected[i].Owner[:], expected[j].Owner[:]) < 0
+	})
+	for _, rd := range expected {
+		rd := rd
+		key := rd.Owner.Hex()
+		actualRD, found := actualByOwner[rd.Owner]
+		delete(actualByOwner, rd.Owner)
+		switch {
+		case !found:
+			add(Discrepancy{Kind: KindRecipient, Key: key, Issue: IssueMissing, Expected: formatRecipient(rd), Action: "save the recipient"},
+				func(rw basedb.ReadWriter) error {
+					_, err := live.SaveRecipientData(rw, &rd)
+					return err
+				})
+		case rd.FeeRecipient != actualRD.FeeRecipient || (!report.Partial && nonce(rd) != nonce(actualRD)):
+			add(Discrepancy{Kind: KindRecipient, Key: key, Issue: IssueMismatch, Expected: formatRecipient(rd), Actual: formatRecipient(actualRD), Action: "overwrite the recipient"},
+				func(rw basedb.ReadWriter) error {
+					// Deleting first makes sure the nonce is saved even if the fee recipient is the same.
+					if err := live.DeleteRecipientData(rw, rd.Owner); err != nil {
+						return err
+					}
+					_, err := live.SaveRecipientData(rw, &rd)
+					return err
+				})
+		}
+	}
+	if report.Partial {
+		return nil
+	}
+
+	owners := make([]ethcommon.Address, 0, len(actualByOwner))
+	for owner := range actualByOwner {
+		owners = append(owners, owner)
+	}
+	sort.Slice(owners, func(i, j int) bool {
+		return bytes.Compare(owners[i][:], owners[j][:]) < 0
+	})
+	for _, owner := range owners {
+		owner := owner
+		add(Discrepancy{Kind: KindRecipient, Key: owner.Hex(), Issue: IssueUnexpected, Actual: formatRecipient(actualByOwner[owner]), Action: "delete the recipient"},
+			func(rw basedb.ReadWriter) error {
+				return live.DeleteRecipientData(rw, owner)
+			})
+	}
+	return nil
+}
+
+// equalShares compares the fields of the shares which are set by the events, other than the liquidation status.
+func equalShares(a, b *ssvtypes.SSVShare) bool {
+	return a.OwnerAddress == b.OwnerAddress &&
+		slices.EqualFunc(a.Committee, b.Committee, func(x, y *spectypes.ShareMember) bool {
+			return x.Signer == y.Signer && bytes.Equal(x.SharePubKey, y.SharePubKey)
+		})
+}
+
+func formatOperator(od registrystorage.OperatorData) string {
+	return fmt.Sprintf("owner %s, public key %s", od.OwnerAddress.Hex(), od.PublicKey)
+}
+
+func formatShare(share *ssvtypes.SSVShare) string {
+	signers := make([]spectypes.OperatorID, 0, len(share.Committee))
+	for _, member := range share.Committee {
+		signers = append(signers, member.Signer)
+	}
+	return fmt.Sprintf("owner %s, operators %v, liquidated %t", share.OwnerAddress.Hex(), signers, share.Liquidated)
+}
+
+func formatRecipient(rd registrystorage.RecipientData) string {
+	if rd.Nonce == nil {
+		return fmt.Sprintf("fee recipient %s, no nonce", ethcommon.Address(rd.FeeRecipient).Hex())
+	}
+	return fmt.Sprintf("fee recipient %s, nonce %d", ethcommon.Address(rd.FeeRecipient).Hex(), *rd.Nonce)
+}
+
+func nonce(rd registrystorage.RecipientData) int {
+	if rd.Nonce == nil {
+		return -1
+	}
+	return int(*rd.Nonce)
+}
+
+func sortedValues(operators map[spectypes.OperatorID]registrystorage.OperatorData) []registrystorage.OperatorData {
+	values := make([]registrystorage.OperatorData, 0, len(operators))
+	for _, od := range operators {
+		values = append(values, od)
+	}
+	sort.Slice(values, func(i, j int) bool {
+		return values[i].ID < values[j].ID
+	})
+	return values
+}
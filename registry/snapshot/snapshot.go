@@ -0,0 +1,206 @@
+// Package snapshot exports and imports the registry state which is synced from the contract events,
+// so that a new node can continue syncing from the snapshot block instead of replaying all the events.
+//
+// A snapshot file holds the snapshot along with its hash and the signature of the operator which exported it.
+// The importing node must verify the hash against a value it trusts, since the signature only proves who
+// exported the snapshot, not that its state is correct.
+package snapshot
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ssvlabs/ssv/operator/keys"
+	nodestorage "github.com/ssvlabs/ssv/operator/storage"
+	ssvtypes "github.com/ssvlabs/ssv/protocol/v2/types"
+	registrystorage "github.com/ssvlabs/ssv/registry/storage"
+)
+
+// FormatVersion is the supported snapshot format version.
+const FormatVersion = 1
+
+var (
+	// ErrHashMismatch is returned when the snapshot doesn't match its hash or the trusted hash.
+	ErrHashMismatch = errors.New("snapshot hash mismatch")
+
+	// ErrNotEmpty is returned when importing into a node which already synced registry events.
+	ErrNotEmpty = errors.New("registry events were already synced")
+
+	// ErrOwnShares is returned when the snapshot has shares of the importing operator,
+	// whose secrets are only found in the contract events.
+	ErrOwnShares = errors.New("snapshot has shares of this operator")
+)
+
+// Snapshot is the registry state after processing the events up to BlockNumber.
+type Snapshot struct {
+	Version     int                             `json:"version"`
+	Network     string                          `json:"network"`
+	BlockNumber uint64                          `json:"block_number"`
+	Operators   []registrystorage.OperatorData  `json:"operators"`
+	Recipients  []registrystorage.RecipientData `json:"recipients"`
+	// Shares are encoded with registrystorage.EncodeShare.
+	Shares [][]byte `json:"shares"`
+}
+
+// File is a snapshot file.
+type File struct {
+	Snapshot json.RawMessage `json:"snapshot"`
+	// Hash is the hex encoded SHA-256 hash of Snapshot.
+	Hash string `json:"hash"`
+	// PublicKey is the base64 encoded public key of the exporting operator, which signed Hash.
+	PublicKey string `json:"public_key"`
+	Signature []byte `json:"signature"`
+}
+
+// Export returns the registry state of the given storage.
+func Export(store nodestorage.Storage, network string) (*Snapshot, error) {
+	txn := store.BeginRead()
+	defer txn.Discard()
+
+	lastProcessedBlock, found, err := store.GetLastProcessedBlock(txn)
+	if err != nil {
+		return nil, fmt.Errorf("could not get last processed block: %w", err)
+	}
+	if !found || lastProcessedBlock == nil {
+		return nil, errors.New("no registry events were synced")
+	}
+
+	operators, err := store.ListOperators(txn, 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("could not list operators: %w", err)
+	}
+	recipients, err := store.ListRecipients(txn)
+	if err != nil {
+		return nil, fmt.Errorf("could not list recipients: %w", err)
+	}
+	shares := store.Shares().List(txn)
+	encodedShares := make([][]byte, 0, len(shares))
+	for _, share := range shares {
+		encoded, err := registrystorage.EncodeShare(share)
+		if err != nil {
+			return nil, fmt.Errorf("could not encode share: %w", err)
+		}
+		encodedShares = append(encodedShares, encoded)
+	}
+
+	return &Snapshot{
+		Version:     FormatVersion,
+		Network:     network,
+		BlockNumber: lastProcessedBlock.Uint64(),
+		Operators:   operators,
+		Recipients:  recipients,
+		Shares:      encodedShares,
+	}, nil
+}
+
+// Sign returns the snapshot file of the given snapshot, signed by the given operator key.
+func Sign(snapshot *Snapshot, privateKey keys.OperatorPrivateKey) (*File, error) {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal snapshot: %w", err)
+	}
+	hash := sha256.Sum256(data)
+	signature, err := privateKey.Sign(hash[:])
+	if err != nil {
+		return nil, fmt.Errorf("could not sign snapshot: %w", err)
+	}
+	publicKey, err := privateKey.Public().Base64()
+	if err != nil {
+		return nil, fmt.Errorf("could not encode public key: %w", err)
+	}
+	return &File{
+		Snapshot:  data,
+		Hash:      hex.EncodeToString(hash[:]),
+		PublicKey: string(publicKey),
+		Signature: signature,
+	}, nil
+}
+
+// Verify verifies the snapshot against the trusted hash and the signature, and returns it.
+func (f *File) Verify(trustedHash string) (*Snapshot, error) {
+	hash := sha256.Sum256(f.Snapshot)
+	if hex.EncodeToString(hash[:]) != f.Hash {
+		return nil, fmt.Errorf("%w: snapshot doesn't match the hash in the file", ErrHashMismatch)
+	}
+	if !strings.EqualFold(strings.TrimPrefix(trustedHash, "0x"), f.Hash) {
+		return nil, fmt.Errorf("%w: expected %s, got %s", ErrHashMismatch, trustedHash, f.Hash)
+	}
+
+	publicKey, err := keys.PublicKeyFromString(f.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode public key: %w", err)
+	}
+	if err := publicKey.Verify(hash[:], f.Signature); err != nil {
+		return nil, fmt.Errorf("invalid signature: %w", err)
+	}
+
+	snapshot := &Snapshot{}
+	if err := json.Unmarshal(f.Snapshot, snapshot); err != nil {
+		return nil, fmt.Errorf("could not unmarshal snapshot: %w", err)
+	}
+	if snapshot.Version != FormatVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %d", snapshot.Version)
+	}
+	return snapshot, nil
+}
+
+// Import saves the registry state of the snapshot into the given storage, which must not have synced
+// registry events yet, so that syncing continues from the block after the snapshot.
+// ownPublicKey is the base64 encoded public key of the importing operator.
+func Import(store nodestorage.Storage, snapshot *Snapshot, network string, ownPublicKey []byte) error {
+	if snapshot.Network != network {
+		return fmt.Errorf("snapshot is of network %s, not %s", snapshot.Network, network)
+	}
+
+	txn := store.Begin()
+	defer txn.Discard()
+
+	_, found, err := store.GetLastProcessedBlock(txn)
+	if err != nil {
+		return fmt.Errorf("could not get last processed block: %w", err)
+	}
+	if found {
+		return ErrNotEmpty
+	}
+
+	var ownOperatorID uint64
+	for i := range snapshot.Operators {
+		od := &snapshot.Operators[i]
+		if string(od.PublicKey) == string(ownPublicKey) {
+			ownOperatorID = od.ID
+		}
+		if _, err := store.SaveOperatorData(txn, od); err != nil {
+			return fmt.Errorf("could not save operator data: %w", err)
+		}
+	}
+	for i := range snapshot.Recipients {
+		if _, err := store.SaveRecipientData(txn, &snapshot.Recipients[i]); err != nil {
+			return fmt.Errorf("could not save recipient data: %w", err)
+		}
+	}
+
+	shares := make([]*ssvtypes.SSVShare, 0, len(snapshot.Shares))
+	for _, encoded := range snapshot.Shares {
+		share, err := registrystorage.DecodeShare(encoded)
+		if err != nil {
+			return fmt.Errorf("could not decode share: %w", err)
+		}
+		if ownOperatorID != 0 && share.BelongsToOperator(ownOperatorID) {
+			return fmt.Errorf("%w: operator %d", ErrOwnShares, ownOperatorID)
+		}
+		shares = append(shares, share)
+	}
+	if err := store.Shares().Save(txn, shares...); err != nil {
+		return fmt.Errorf("could not save shares: %w", err)
+	}
+
+	if err := store.SaveLastProcessedBlock(txn, new(big.Int).SetUint64(snapshot.BlockNumber)); err != nil {
+		return fmt.Errorf("could not save last processed block: %w", err)
+	}
+	return txn.Commit()
+}
@@ -0,0 +1,97 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	spectypes "github.com/ssvlabs/ssv-spec/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ssvlabs/ssv/logging"
+	"github.com/ssvlabs/ssv/operator/keys"
+	nodestorage "github.com/ssvlabs/ssv/operator/storage"
+	ssvtypes "github.com/ssvlabs/ssv/protocol/v2/types"
+	registrystorage "github.com/ssvlabs/ssv/registry/storage"
+	"github.com/ssvlabs/ssv/storage/basedb"
+	"github.com/ssvlabs/ssv/storage/kv"
+)
+
+func newTestStorage(t *testing.T) nodestorage.Storage {
+	logger := logging.TestLogger(t)
+	db, err := kv.NewInMemory(logger, basedb.Options{})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	store, err := nodestorage.NewNodeStorage(logger, db)
+	require.NoError(t, err)
+	return store
+}
+
+func TestExportImport(t *testing.T) {
+	owner := common.HexToAddress("0x97a6C1f3aaB5427B901fb135ED492749191C0f1F")
+	source := newTestStorage(t)
+	for id := uint64(1); id <= 4; id++ {
+		_, err := source.SaveOperatorData(nil, &registrystorage.OperatorData{ID: id, PublicKey: []byte{byte(id)}, OwnerAddress: owner})
+		require.NoError(t, err)
+	}
+	require.NoError(t, source.BumpNonce(nil, owner))
+	require.NoError(t, source.BumpNonce(nil, owner))
+	share := &ssvtypes.SSVShare{
+		Share: spectypes.Share{
+			ValidatorPubKey: spectypes.ValidatorPK{1, 2, 3},
+			Committee:       []*spectypes.ShareMember{{Signer: 1}, {Signer: 2}, {Signer: 3}, {Signer: 4}},
+		},
+		Metadata: ssvtypes.Metadata{OwnerAddress: owner, Liquidated: true},
+	}
+	require.NoError(t, source.Shares().Save(nil, share))
+	require.NoError(t, source.SaveLastProcessedBlock(nil, big.NewInt(100)))
+
+	snapshot, err := Export(source, "testnet")
+	require.NoError(t, err)
+	require.EqualValues(t, 100, snapshot.BlockNumber)
+	require.Len(t, snapshot.Operators, 4)
+	require.Len(t, snapshot.Recipients, 1)
+	require.Len(t, snapshot.Shares, 1)
+
+	privateKey, err := keys.GeneratePrivateKey()
+	require.NoError(t, err)
+	file, err := Sign(snapshot, privateKey)
+	require.NoError(t, err)
+	data, err := json.Marshal(file)
+	require.NoError(t, err)
+
+	var readFile File
+	require.NoError(t, json.Unmarshal(data, &readFile))
+	_, err = readFile.Verify("0x1234")
+	require.ErrorIs(t, err, ErrHashMismatch)
+	imported, err := readFile.Verify("0x" + file.Hash)
+	require.NoError(t, err)
+
+	tampered := readFile
+	tampered.Snapshot = append(json.RawMessage{}, readFile.Snapshot...)
+	tampered.Snapshot[len(tampered.Snapshot)-2] = '1'
+	_, err = tampered.Verify(file.Hash)
+	require.ErrorIs(t, err, ErrHashMismatch)
+
+	target := newTestStorage(t)
+	require.ErrorContains(t, Import(target, imported, "mainnet", nil), "snapshot is of network testnet")
+	require.ErrorIs(t, Import(target, imported, "testnet", []byte{2}), ErrOwnShares)
+	require.NoError(t, Import(target, imported, "testnet", []byte{5}))
+	require.ErrorIs(t, Import(target, imported, "testnet", nil), ErrNotEmpty)
+
+	lastProcessedBlock, found, err := target.GetLastProcessedBlock(nil)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.EqualValues(t, 100, lastProcessedBlock.Uint64())
+	operators, err := target.ListOperators(nil, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, operators, 4)
+	nonce, err := target.GetNextNonce(nil, owner)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, nonce)
+	importedShare, found := target.Shares().Get(nil, share.ValidatorPubKey[:])
+	require.True(t, found)
+	require.True(t, importedShare.Liquidated)
+	require.Equal(t, owner, importedShare.OwnerAddress)
+}
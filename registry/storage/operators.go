@@ -187,7 +187,7 @@ func (s *operatorsStorage) SaveOperatorData(
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
-	_, found, err := s.getOperatorData(nil, operatorData.ID)
+	_, found, err := s.getOperatorData(rw, operatorData.ID)
 	if err != nil {
 		return found, errors.Wrap(err, "could not get operator data")
 	}
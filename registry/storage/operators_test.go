@@ -193,6 +193,34 @@ func TestStorage_DeleteOperatorAndDropOperators(t *testing.T) {
 
 }
 
+func TestStorage_DeleteAndSaveOperatorInTxn(t *testing.T) {
+	logger := logging.TestLogger(t)
+	db, err := kv.NewInMemory(logger, basedb.Options{})
+	require.NoError(t, err)
+	defer db.Close()
+	operators := storage.NewOperatorsStorage(logger, db, []byte("test"))
+
+	pk, _, err := rsaencryption.GenerateKeys()
+	require.NoError(t, err)
+	operator := &storage.OperatorData{PublicKey: pk, ID: 1}
+	_, err = operators.SaveOperatorData(nil, operator)
+	require.NoError(t, err)
+
+	// Saving must see the deletion in the same transaction, such as when an operator removal is undone.
+	txn := db.Begin()
+	defer txn.Discard()
+	require.NoError(t, operators.DeleteOperatorData(txn, operator.ID))
+	found, err := operators.SaveOperatorData(txn, operator)
+	require.NoError(t, err)
+	require.False(t, found)
+	require.NoError(t, txn.Commit())
+
+	saved, found, err := operators.GetOperatorData(nil, operator.ID)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, operator.PublicKey, saved.PublicKey)
+}
+
 func newOperatorStorageForTest(logger *zap.Logger) (storage.Operators, func()) {
 	db, err := kv.NewInMemory(logger, basedb.Options{})
 	if err != nil {
@@ -66,6 +66,7 @@ type Recipients interface {
 	BumpNonce(rw basedb.ReadWriter, owner common.Address) error
 	SaveRecipientData(rw basedb.ReadWriter, recipientData *RecipientData) (*RecipientData, error)
 	DeleteRecipientData(rw basedb.ReadWriter, owner common.Address) error
+	ListRecipients(r basedb.Reader) ([]RecipientData, error)
 	DropRecipients() error
 	GetRecipientsPrefix() []byte
 }
@@ -224,6 +225,24 @@ func (s *recipientsStorage) DeleteRecipientData(rw basedb.ReadWriter, owner comm
 	return s.db.Using(rw).Delete(s.prefix, buildRecipientKey(owner))
 }
 
+// ListRecipients returns the recipient data of all owners.
+func (s *recipientsStorage) ListRecipients(r basedb.Reader) ([]RecipientData, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	var recipients []RecipientData
+	prefix := bytes.Join([][]byte{s.prefix, recipientsPrefix, []byte("/")}, nil)
+	err := s.db.UsingReader(r).GetAll(prefix, func(i int, obj basedb.Obj) error {
+		var recipientData RecipientData
+		if err := json.Unmarshal(obj.Value, &recipientData); err != nil {
+			return errors.Wrap(err, "could not unmarshal recipient data")
+		}
+		recipients = append(recipients, recipientData)
+		return nil
+	})
+	return recipients, err
+}
+
 func (s *recipientsStorage) DropRecipients() error {
 	s.lock.Lock()
 	defer s.lock.Unlock()
@@ -141,7 +141,7 @@ func (s *sharesStorage) load() error {
 			return fmt.Errorf("failed to deserialize share: %w", err)
 		}
 		val.DomainType = spectypes.DomainType(genesistypes.GetDefaultDomain())
-		share, err := s.storageShareToSpecShare(val)
+		share, err := storageShareToSpecShare(val)
 		if err != nil {
 			return fmt.Errorf("failed to convert storage share to spec share: %w", err)
 		}
@@ -280,7 +280,7 @@ func specShareToStorageShare(share *types.SSVShare) *storageShare {
 	return stShare
 }
 
-func (s *sharesStorage) storageShareToSpecShare(share *storageShare) (*types.SSVShare, error) {
+func storageShareToSpecShare(share *storageShare) (*types.SSVShare, error) {
 	committee := make([]*spectypes.ShareMember, len(share.Committee))
 	for i, c := range share.Committee {
 		committee[i] = &spectypes.ShareMember{
@@ -315,6 +315,20 @@ func (s *sharesStorage) storageShareToSpecShare(share *storageShare) (*types.SSV
 	return specShare, nil
 }
 
+// EncodeShare encodes the share the same way it's stored.
+func EncodeShare(share *types.SSVShare) ([]byte, error) {
+	return specShareToStorageShare(share).Encode()
+}
+
+// DecodeShare decodes a share encoded by EncodeShare.
+func DecodeShare(data []byte) (*types.SSVShare, error) {
+	stShare := &storageShare{}
+	if err := stShare.Decode(data); err != nil {
+		return nil, err
+	}
+	return storageShareToSpecShare(stShare)
+}
+
 func (s *sharesStorage) Delete(rw basedb.ReadWriter, pubKey []byte) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
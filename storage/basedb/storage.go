@@ -18,6 +18,11 @@ type Reader interface {
 	Get(prefix []byte, key []byte) (Obj, bool, error)
 	GetMany(prefix []byte, keys [][]byte, iterator func(Obj) error) error
 	GetAll(prefix []byte, handler func(int, Obj) error) error
+	// GetRange calls the handler with the items under the prefix whose keys are in [from, to), ordered by key.
+	// A nil to ends the range at the end of the prefix.
+	GetRange(prefix []byte, from []byte, to []byte, handler func(Obj) error) error
+	// GetKeys is like GetRange, but only reads the keys.
+	GetKeys(prefix []byte, from []byte, to []byte, handler func(key []byte) error) error
 }
 
 // ReadWrite is a read-write accessor to the database.
@@ -167,6 +167,17 @@ func (b *BadgerDB) GetAll(prefix []byte, handler func(int, basedb.Obj) error) er
 	return err
 }
 
+// GetRange calls the handler with the items under the prefix whose keys are in [from, to), ordered by key.
+// A nil to ends the range at the end of the prefix.
+func (b *BadgerDB) GetRange(prefix []byte, from []byte, to []byte, handler func(basedb.Obj) error) error {
+	return b.db.View(b.rangeGetter(prefix, from, to, handler))
+}
+
+// GetKeys is like GetRange, but only reads the keys.
+func (b *BadgerDB) GetKeys(prefix []byte, from []byte, to []byte, handler func(key []byte) error) error {
+	return b.db.View(b.keysGetter(prefix, from, to, handler))
+}
+
 // CountPrefix return the object count for all keys under specified prefix(bucket)
 func (b *BadgerDB) CountPrefix(prefix []byte) (int64, error) {
 	var res int64
@@ -245,6 +256,28 @@ func (b *BadgerDB) listRawKeys(prefix []byte, txn *badger.Txn) [][]byte {
 	return keys
 }
 
+// listRangeKeys returns the keys under the prefix which are in [from, to), without the prefix.
+func (b *BadgerDB) listRangeKeys(prefix, from, to []byte, txn *badger.Txn) [][]byte {
+	var keys [][]byte
+
+	opt := badger.DefaultIteratorOptions
+	opt.Prefix = prefix
+	opt.PrefetchValues = false
+
+	it := txn.NewIterator(opt)
+	defer it.Close()
+
+	for it.Seek(append(append([]byte{}, prefix...), from...)); it.ValidForPrefix(prefix); it.Next() {
+		key := it.Item().KeyCopy(nil)[len(prefix):]
+		if to != nil && bytes.Compare(key, to) >= 0 {
+			break
+		}
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
 // Update is a gateway to badger db Update function
 // creating and managing a read-write transaction
 func (b *BadgerDB) Update(fn func(basedb.Txn) error) error {
@@ -280,6 +313,38 @@ func (b *BadgerDB) allGetter(prefix []byte, handler func(int, basedb.Obj) error)
 	}
 }
 
+func (b *BadgerDB) rangeGetter(prefix, from, to []byte, handler func(basedb.Obj) error) func(txn *badger.Txn) error {
+	return func(txn *badger.Txn) error {
+		// Like allGetter, the keys are listed first and the values are fetched afterwards.
+		for _, key := range b.listRangeKeys(prefix, from, to, txn) {
+			item, err := txn.Get(append(append([]byte{}, prefix...), key...))
+			if err != nil {
+				return err
+			}
+			value, err := item.ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			if err := handler(basedb.Obj{Key: key, Value: value}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func (b *BadgerDB) keysGetter(prefix, from, to []byte, handler func(key []byte) error) func(txn *badger.Txn) error {
+	return func(txn *badger.Txn) error {
+		// The keys are listed first, so that the handler can delete them in the same transaction.
+		for _, key := range b.listRangeKeys(prefix, from, to, txn) {
+			if err := handler(key); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
 func (b *BadgerDB) manyGetter(prefix []byte, keys [][]byte, iterator func(basedb.Obj) error) func(txn *badger.Txn) error {
 	return func(txn *badger.Txn) error {
 		var value, cp []byte
@@ -140,6 +140,40 @@ func TestBadgerDb_GetMany(t *testing.T) {
 	require.Equal(t, 4, len(results))
 }
 
+func TestBadgerDb_GetRange(t *testing.T) {
+	logger := logging.TestLogger(t)
+	db, err := NewInMemory(logger, basedb.Options{})
+	require.NoError(t, err)
+	defer db.Close()
+
+	prefix := []byte("prefix")
+	for i := byte(0); i < 10; i++ {
+		require.NoError(t, db.Set(prefix, []byte{i}, []byte{i + 100}))
+	}
+	require.NoError(t, db.Set([]byte("prefiy"), []byte{0}, []byte{0}))
+
+	var objs []basedb.Obj
+	require.NoError(t, db.GetRange(prefix, []byte{3}, []byte{6}, func(obj basedb.Obj) error {
+		objs = append(objs, obj)
+		return nil
+	}))
+	require.Equal(t, []basedb.Obj{{Key: []byte{3}, Value: []byte{103}}, {Key: []byte{4}, Value: []byte{104}}, {Key: []byte{5}, Value: []byte{105}}}, objs)
+
+	// Deleting the listed keys in the same transaction.
+	txn := db.Begin()
+	var keys [][]byte
+	require.NoError(t, txn.GetKeys(prefix, []byte{8}, nil, func(key []byte) error {
+		keys = append(keys, key)
+		return txn.Delete(prefix, key)
+	}))
+	require.NoError(t, txn.Commit())
+	require.Equal(t, [][]byte{{8}, {9}}, keys)
+
+	count, err := db.CountPrefix(prefix)
+	require.NoError(t, err)
+	require.EqualValues(t, 8, count)
+}
+
 func TestBadgerDb_SetMany(t *testing.T) {
 	logger := logging.TestLogger(t)
 	db, err := NewInMemory(logger, basedb.Options{})
@@ -78,6 +78,14 @@ func (t badgerTxn) GetAll(prefix []byte, handler func(int, basedb.Obj) error) er
 	return t.db.allGetter(prefix, handler)(t.txn)
 }
 
+func (t badgerTxn) GetRange(prefix []byte, from []byte, to []byte, handler func(basedb.Obj) error) error {
+	return t.db.rangeGetter(prefix, from, to, handler)(t.txn)
+}
+
+func (t badgerTxn) GetKeys(prefix []byte, from []byte, to []byte, handler func(key []byte) error) error {
+	return t.db.keysGetter(prefix, from, to, handler)(t.txn)
+}
+
 func (t badgerTxn) Delete(prefix []byte, key []byte) error {
 	return t.txn.Delete(append(prefix, key...))
 }